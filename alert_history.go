@@ -0,0 +1,96 @@
+package signalfx
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/detector"
+)
+
+// IncidentAPIURL is the URL for listing incidents across all detectors.
+const IncidentAPIURL = "/v2/incident"
+
+// GetRecentAlertHistory summarizes the incidents raised by every detector in
+// the last lookback, with one AlertSummary per detector rule, most alerts
+// first.  Only incidents of the given severity are counted, or all of them if
+// severity is empty.  An incident is dated by its first event.
+func (c *Client) GetRecentAlertHistory(ctx context.Context, lookback time.Duration, severity detector.Severity) ([]*detector.AlertSummary, error) {
+	since := time.Now().Add(-lookback)
+
+	params := url.Values{}
+	params.Add("includeResolved", "true")
+
+	type ruleKey struct{ detectorID, detectLabel string }
+	summaries := map[ruleKey]*detector.AlertSummary{}
+	resolved := map[ruleKey]int{}
+
+	err := c.forEachPage(ctx, IncidentAPIURL, params, func() (interface{}, func() int) {
+		var page []*detector.Incident
+		return &page, func() int {
+			for _, inc := range page {
+				if severity != "" && inc.Severity != severity {
+					continue
+				}
+				triggered := incidentTriggerTime(inc)
+				if triggered.Before(since) {
+					continue
+				}
+
+				key := ruleKey{inc.DetectorId, inc.DetectLabel}
+				s := summaries[key]
+				if s == nil {
+					s = &detector.AlertSummary{
+						DetectorID:   inc.DetectorId,
+						DetectorName: inc.DetectorName,
+						DetectLabel:  inc.DetectLabel,
+					}
+					summaries[key] = s
+				}
+				s.AlertCount++
+				if triggered.After(s.LastAlertTime) {
+					s.LastAlertTime = triggered
+				}
+				if !inc.Active {
+					resolved[key]++
+				}
+			}
+			return len(page)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*detector.AlertSummary, 0, len(summaries))
+	for key, s := range summaries {
+		s.ResolutionRate = float64(resolved[key]) / float64(s.AlertCount)
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].AlertCount != out[j].AlertCount {
+			return out[i].AlertCount > out[j].AlertCount
+		}
+		if out[i].DetectorName != out[j].DetectorName {
+			return out[i].DetectorName < out[j].DetectorName
+		}
+		return out[i].DetectLabel < out[j].DetectLabel
+	})
+	return out, nil
+}
+
+// incidentTriggerTime is the time of an incident's first event, or the zero
+// time if it has none.
+func incidentTriggerTime(inc *detector.Incident) time.Time {
+	var earliest int64
+	for _, ev := range inc.Events {
+		if earliest == 0 || (ev.Timestamp != 0 && ev.Timestamp < earliest) {
+			earliest = ev.Timestamp
+		}
+	}
+	if earliest == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, earliest*int64(time.Millisecond))
+}
@@ -0,0 +1,72 @@
+package signalfx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/adampetrovic/signalfx-go/detector"
+)
+
+func TestGetRecentAlertHistory(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	now := time.Now()
+	incident := func(detectorID, label string, severity detector.Severity, active bool, ago time.Duration) *detector.Incident {
+		return &detector.Incident{
+			Active:       active,
+			DetectorId:   detectorID,
+			DetectorName: "Detector " + detectorID,
+			DetectLabel:  label,
+			Severity:     severity,
+			Events:       []*detector.Event{{Timestamp: now.Add(-ago).UnixNano() / int64(time.Millisecond)}},
+		}
+	}
+
+	params := url.Values{}
+	params.Add("includeResolved", "true")
+	params.Add("offset", "0")
+	mux.HandleFunc("/v2/incident", func(w http.ResponseWriter, r *http.Request) {
+		verifyRequest(t, "GET", http.StatusOK, params, "")(w, r)
+		json.NewEncoder(w).Encode([]*detector.Incident{
+			incident("a", "CPU high", detector.CRITICAL, false, time.Hour),
+			incident("a", "CPU high", detector.CRITICAL, true, 2*time.Hour),
+			incident("a", "CPU high", detector.CRITICAL, false, 3*time.Hour),
+			incident("a", "CPU high", detector.CRITICAL, false, 30*24*time.Hour),
+			incident("a", "Disk full", detector.CRITICAL, false, time.Hour),
+			incident("b", "Latency", detector.CRITICAL, false, 5*time.Hour),
+			incident("b", "Latency", detector.WARNING, false, 5*time.Hour),
+		})
+	})
+
+	history, err := client.GetRecentAlertHistory(context.Background(), 7*24*time.Hour, detector.CRITICAL)
+	assert.NoError(t, err, "Unexpected error getting alert history")
+	if assert.Len(t, history, 3) {
+		assert.Equal(t, "a", history[0].DetectorID)
+		assert.Equal(t, "CPU high", history[0].DetectLabel)
+		assert.Equal(t, 3, history[0].AlertCount, "Old incidents should be left out")
+		assert.InDelta(t, 2.0/3.0, history[0].ResolutionRate, 0.0001)
+		assert.WithinDuration(t, now.Add(-time.Hour), history[0].LastAlertTime, time.Second)
+
+		assert.Equal(t, "Disk full", history[1].DetectLabel)
+		assert.Equal(t, "Latency", history[2].DetectLabel)
+		assert.Equal(t, 1, history[2].AlertCount, "Other severities should be left out")
+	}
+}
+
+func TestGetRecentAlertHistoryError(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/incident", verifyRequest(t, "GET", http.StatusForbidden, nil, ""))
+
+	history, err := client.GetRecentAlertHistory(context.Background(), time.Hour, "")
+	assert.Error(t, err, "Should have gotten an error from a forbidden request")
+	assert.Nil(t, history)
+}
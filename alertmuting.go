@@ -2,9 +2,7 @@ package signalfx
 
 import (
 	"bytes"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
+	"context"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -17,32 +15,50 @@ const AlertMutingRuleAPIURL = "/v2/alertmuting"
 
 // CreateAlertMutingRule creates an alert muting rule.
 func (c *Client) CreateAlertMutingRule(muteRequest *alertmuting.CreateUpdateAlertMutingRuleRequest) (*alertmuting.AlertMutingRule, error) {
-	payload, err := json.Marshal(muteRequest)
+	return c.createAlertMutingRule(c.ctx, muteRequest)
+}
+
+// CreateAlertMuting creates a muting rule that suppresses matching alerts
+// during a time window.
+func (c *Client) CreateAlertMuting(ctx context.Context, req *alertmuting.CreateRequest) (*alertmuting.MutingRule, error) {
+	muteRequest, err := req.APIRequest()
+	if err != nil {
+		return nil, err
+	}
+	return c.createAlertMutingRule(ctx, muteRequest)
+}
+
+func (c *Client) createAlertMutingRule(ctx context.Context, muteRequest *alertmuting.CreateUpdateAlertMutingRuleRequest) (*alertmuting.AlertMutingRule, error) {
+	payload, err := c.marshal(muteRequest)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.doRequest("POST", AlertMutingRuleAPIURL, nil, bytes.NewReader(payload))
+	resp, err := c.doRequestWithContext(ctx, "POST", AlertMutingRuleAPIURL, nil, bytes.NewReader(payload))
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Bad status %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalRule := &alertmuting.AlertMutingRule{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalRule)
+	err = c.decodeJSON(resp.Body, finalRule)
 
 	return finalRule, err
 }
 
 // DeleteAlertMutingRule deletes an alert muting rule.
 func (c *Client) DeleteAlertMutingRule(name string) error {
-	resp, err := c.doRequest("DELETE", AlertMutingRuleAPIURL+"/"+name, nil, nil)
+	return c.DeleteAlertMuting(c.ctx, name)
+}
+
+// DeleteAlertMuting deletes a muting rule.
+func (c *Client) DeleteAlertMuting(ctx context.Context, id string) error {
+	resp, err := c.doRequestWithContext(ctx, "DELETE", AlertMutingRuleAPIURL+"/"+id, nil, nil)
 
 	if err != nil {
 		return err
@@ -50,8 +66,7 @@ func (c *Client) DeleteAlertMutingRule(name string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return newAPIError(resp)
 	}
 
 	return nil
@@ -59,67 +74,95 @@ func (c *Client) DeleteAlertMutingRule(name string) error {
 
 // GetAlertMutingRule gets an alert muting rule.
 func (c *Client) GetAlertMutingRule(id string) (*alertmuting.AlertMutingRule, error) {
-	resp, err := c.doRequest("GET", AlertMutingRuleAPIURL+"/"+id, nil, nil)
+	return c.GetAlertMuting(c.ctx, id)
+}
+
+// GetAlertMuting gets a muting rule.
+func (c *Client) GetAlertMuting(ctx context.Context, id string) (*alertmuting.MutingRule, error) {
+	resp, err := c.doRequestWithContext(ctx, "GET", AlertMutingRuleAPIURL+"/"+id, nil, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Bad status %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalRule := &alertmuting.AlertMutingRule{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalRule)
+	err = c.decodeJSON(resp.Body, finalRule)
 
 	return finalRule, err
 }
 
 // UpdateAlertMutingRule updates an alert muting rule.
 func (c *Client) UpdateAlertMutingRule(id string, muteRequest *alertmuting.CreateUpdateAlertMutingRuleRequest) (*alertmuting.AlertMutingRule, error) {
-	payload, err := json.Marshal(muteRequest)
+	return c.updateAlertMutingRule(c.ctx, id, muteRequest)
+}
+
+// UpdateAlertMuting replaces a muting rule's filters, window and
+// description.
+func (c *Client) UpdateAlertMuting(ctx context.Context, id string, req *alertmuting.CreateRequest) (*alertmuting.MutingRule, error) {
+	muteRequest, err := req.APIRequest()
+	if err != nil {
+		return nil, err
+	}
+	return c.updateAlertMutingRule(ctx, id, muteRequest)
+}
+
+func (c *Client) updateAlertMutingRule(ctx context.Context, id string, muteRequest *alertmuting.CreateUpdateAlertMutingRuleRequest) (*alertmuting.AlertMutingRule, error) {
+	payload, err := c.marshal(muteRequest)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.doRequest("PUT", AlertMutingRuleAPIURL+"/"+id, nil, bytes.NewReader(payload))
+	resp, err := c.doRequestWithContext(ctx, "PUT", AlertMutingRuleAPIURL+"/"+id, nil, bytes.NewReader(payload))
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Bad status %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalRule := &alertmuting.AlertMutingRule{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalRule)
+	err = c.decodeJSON(resp.Body, finalRule)
 
 	return finalRule, err
 }
 
 // SearchAlertMutingRules searches for alert muting rules given a query string in `name`.
 func (c *Client) SearchAlertMutingRules(include string, limit int, name string, offset int) (*alertmuting.SearchResult, error) {
+	return c.SearchAlertMuting(c.ctx, include, limit, name, offset)
+}
+
+// SearchAlertMuting searches for muting rules whose name matches `name`.
+// include selects rules by their window: "Past", "Ongoing", "Future",
+// "Open" or "All".
+func (c *Client) SearchAlertMuting(ctx context.Context, include string, limit int, name string, offset int) (*alertmuting.SearchResult, error) {
 	params := url.Values{}
 	params.Add("include", include)
 	params.Add("limit", strconv.Itoa(limit))
 	params.Add("name", name)
 	params.Add("offset", strconv.Itoa(offset))
 
-	resp, err := c.doRequest("GET", AlertMutingRuleAPIURL, params, nil)
+	resp, err := c.doRequestWithContext(ctx, "GET", AlertMutingRuleAPIURL, params, nil)
 
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
 	finalRules := &alertmuting.SearchResult{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalRules)
+	err = c.decodeJSON(resp.Body, finalRules)
 
 	return finalRules, err
 }
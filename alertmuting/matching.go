@@ -0,0 +1,21 @@
+package alertmuting
+
+import "time"
+
+// Matches reports whether an alert with dims would be selected by the
+// filter, i.e. whether dims has Property set to PropertyValue, or doesn't
+// when NOT is set.
+func (f *AlertMutingRuleFilter) Matches(dims map[string]string) bool {
+	value, ok := dims[f.Property]
+	return (ok && value == f.PropertyValue) != f.NOT
+}
+
+// IsActive reports whether the rule's muting window contains at. A rule
+// without a stop time stays active indefinitely.
+func (r *AlertMutingRule) IsActive(at time.Time) bool {
+	ms := unixMillis(at)
+	if ms < r.StartTime {
+		return false
+	}
+	return r.StopTime == 0 || ms < r.StopTime
+}
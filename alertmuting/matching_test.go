@@ -0,0 +1,33 @@
+package alertmuting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterMatches(t *testing.T) {
+	dims := map[string]string{"host": "web-1", "env": "prod"}
+
+	assert.True(t, (&Filter{Property: "host", PropertyValue: "web-1"}).Matches(dims))
+	assert.False(t, (&Filter{Property: "host", PropertyValue: "web-2"}).Matches(dims))
+	assert.False(t, (&Filter{Property: "region", PropertyValue: "us"}).Matches(dims))
+
+	assert.False(t, (&Filter{Property: "host", PropertyValue: "web-1", NOT: true}).Matches(dims))
+	assert.True(t, (&Filter{Property: "host", PropertyValue: "web-2", NOT: true}).Matches(dims))
+	assert.True(t, (&Filter{Property: "region", PropertyValue: "us", NOT: true}).Matches(dims), "Missing dimensions aren't equal to anything")
+}
+
+func TestMutingRuleIsActive(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	rule := &MutingRule{StartTime: unixMillis(start), StopTime: unixMillis(start.Add(time.Hour))}
+
+	assert.False(t, rule.IsActive(start.Add(-time.Second)))
+	assert.True(t, rule.IsActive(start))
+	assert.True(t, rule.IsActive(start.Add(59*time.Minute)))
+	assert.False(t, rule.IsActive(start.Add(time.Hour)), "The stop time is exclusive")
+
+	rule.StopTime = 0
+	assert.True(t, rule.IsActive(start.Add(24*365*time.Hour)), "No stop time should mute indefinitely")
+}
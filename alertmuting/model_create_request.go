@@ -0,0 +1,70 @@
+package alertmuting
+
+import (
+	"errors"
+	"time"
+)
+
+// A muting rule as returned by CreateAlertMuting and friends.
+type MutingRule = AlertMutingRule
+
+// A dimension or property matcher that selects which alerts a muting rule
+// suppresses.
+type Filter = AlertMutingRuleFilter
+
+// DetectorIDProperty is the filter property that matches alerts from one
+// detector.
+const DetectorIDProperty = "sf_detectorId"
+
+// A request to create or update a time-window based muting rule.
+type CreateRequest struct {
+	// The dimensions or properties the muted alerts must match
+	Filters []Filter
+	// When muting starts. The zero time starts it immediately.
+	StartTime time.Time
+	// When muting stops. Nil mutes indefinitely.
+	StopTime *time.Time
+	// A description of why alerts are muted, such as the maintenance ticket
+	Description string
+	// The ID of the detector whose alerts are muted. Empty mutes all
+	// detectors. A rule's filters must all match, so it can only match one
+	// detector; use a rule per detector to mute several.
+	DetectorMatchers []string
+}
+
+// APIRequest converts r into the body the alert muting API expects. The
+// detector matcher becomes an sf_detectorId filter. It is an error to have
+// more than one, since the filters are and-ed and no alert could match.
+func (r *CreateRequest) APIRequest() (*CreateUpdateAlertMutingRuleRequest, error) {
+	if r.StopTime != nil && !r.StartTime.IsZero() && !r.StopTime.After(r.StartTime) {
+		return nil, errors.New("stopTime must be after startTime")
+	}
+	if len(r.DetectorMatchers) > 1 {
+		return nil, errors.New("a muting rule can only match one detector; create a rule for each")
+	}
+
+	req := &CreateUpdateAlertMutingRuleRequest{
+		Description: r.Description,
+	}
+	for i := range r.Filters {
+		f := r.Filters[i]
+		req.Filters = append(req.Filters, &f)
+	}
+	for _, id := range r.DetectorMatchers {
+		req.Filters = append(req.Filters, &AlertMutingRuleFilter{
+			Property:      DetectorIDProperty,
+			PropertyValue: id,
+		})
+	}
+	if !r.StartTime.IsZero() {
+		req.StartTime = unixMillis(r.StartTime)
+	}
+	if r.StopTime != nil {
+		req.StopTime = unixMillis(*r.StopTime)
+	}
+	return req, nil
+}
+
+func unixMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,42 @@
+package alertmuting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateRequestAPIRequest(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	stop := start.Add(2 * time.Hour)
+
+	req, err := (&CreateRequest{
+		Filters:          []Filter{{Property: "host", PropertyValue: "web-1"}},
+		StartTime:        start,
+		StopTime:         &stop,
+		Description:      "Maintenance",
+		DetectorMatchers: []string{"Det1"},
+	}).APIRequest()
+	assert.NoError(t, err)
+	assert.Equal(t, &CreateUpdateAlertMutingRuleRequest{
+		Description: "Maintenance",
+		Filters: []*AlertMutingRuleFilter{
+			{Property: "host", PropertyValue: "web-1"},
+			{Property: DetectorIDProperty, PropertyValue: "Det1"},
+		},
+		StartTime: 1577836800000,
+		StopTime:  1577844000000,
+	}, req)
+
+	req, err = (&CreateRequest{Description: "Indefinite"}).APIRequest()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), req.StartTime, "Zero start should be left to the server")
+	assert.Equal(t, int64(0), req.StopTime, "Nil stop should mute indefinitely")
+
+	_, err = (&CreateRequest{StartTime: stop, StopTime: &start}).APIRequest()
+	assert.Error(t, err, "Stop before start should fail")
+
+	_, err = (&CreateRequest{DetectorMatchers: []string{"Det1", "Det2"}}).APIRequest()
+	assert.Error(t, err, "Two detector matchers should fail, since they could never both match")
+}
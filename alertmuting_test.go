@@ -1,10 +1,13 @@
 package signalfx
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/url"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/adampetrovic/signalfx-go/alertmuting"
 	"github.com/stretchr/testify/assert"
@@ -124,3 +127,69 @@ func TestUpdateMissingAlertMutingRule(t *testing.T) {
 	assert.Error(t, err, "Should have gotten an error from an update on a missing alert muting rule")
 	assert.Nil(t, result, "Should have gotten a nil result from an update on a missing alert muting rule")
 }
+
+func TestCreateAlertMuting(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/alertmuting", func(w http.ResponseWriter, r *http.Request) {
+		req := &alertmuting.CreateUpdateAlertMutingRuleRequest{}
+		err := json.NewDecoder(r.Body).Decode(req)
+		assert.NoError(t, err, "Unexpected error decoding body")
+		assert.Equal(t, int64(1577836800000), req.StartTime, "StartTime does not match")
+		assert.Equal(t, int64(0), req.StopTime, "StopTime should be unset")
+		assert.Equal(t, []*alertmuting.AlertMutingRuleFilter{{Property: alertmuting.DetectorIDProperty, PropertyValue: "Det1"}}, req.Filters)
+		verifyRequest(t, "POST", http.StatusCreated, nil, "alertmuting/create_success.json")(w, r)
+	})
+
+	result, err := client.CreateAlertMuting(context.Background(), &alertmuting.CreateRequest{
+		StartTime:        time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		Description:      "string",
+		DetectorMatchers: []string{"Det1"},
+	})
+	assert.NoError(t, err, "Unexpected error creating alert muting rule")
+	assert.Equal(t, "string", result.Description, "Description does not match")
+}
+
+func TestCreateInvalidAlertMuting(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	start := time.Now()
+	stop := start.Add(-time.Hour)
+	result, err := client.CreateAlertMuting(context.Background(), &alertmuting.CreateRequest{StartTime: start, StopTime: &stop})
+	assert.Error(t, err, "Should have gotten an error from a stop before the start")
+	assert.Nil(t, result, "Should have a null alert muting rule on bad create")
+}
+
+func TestAlertMutingWithContext(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/alertmuting/string", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			verifyRequest(t, "GET", http.StatusOK, nil, "alertmuting/get_success.json")(w, r)
+		case "PUT":
+			verifyRequest(t, "PUT", http.StatusOK, nil, "alertmuting/update_success.json")(w, r)
+		default:
+			verifyRequest(t, "DELETE", http.StatusNoContent, nil, "")(w, r)
+		}
+	})
+	mux.HandleFunc("/v2/alertmuting", verifyRequest(t, "GET", http.StatusOK, nil, "alertmuting/search_success.json"))
+
+	ctx := context.Background()
+	rule, err := client.GetAlertMuting(ctx, "string")
+	assert.NoError(t, err, "Unexpected error getting alert muting rule")
+	assert.Equal(t, "string", rule.Description, "Description does not match")
+
+	rule, err = client.UpdateAlertMuting(ctx, "string", &alertmuting.CreateRequest{Description: "string"})
+	assert.NoError(t, err, "Unexpected error updating alert muting rule")
+	assert.Equal(t, "string", rule.Description, "Description does not match")
+
+	results, err := client.SearchAlertMuting(ctx, "All", 10, "string", 0)
+	assert.NoError(t, err, "Unexpected error searching alert muting rules")
+	assert.NotNil(t, results)
+
+	assert.NoError(t, client.DeleteAlertMuting(ctx, "string"), "Unexpected error deleting alert muting rule")
+}
@@ -0,0 +1,97 @@
+package signalfx
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SlowAPICallThreshold is how long an API call can take before it counts
+// towards APICallStats.SlowCalls.
+const SlowAPICallThreshold = time.Second
+
+// APICallStats describes the API calls a client has made since it was created
+// or since ResetAPICallStats.  Only requests that reach the API count, so
+// responses served by the cache from WithCacheMiddleware don't.
+type APICallStats struct {
+	// Calls made
+	TotalCalls int64
+	// Calls made to each endpoint, keyed by method and the first two
+	// segments of the path, e.g. "GET /v2/detector"
+	CallsByEndpoint map[string]int64
+	// Calls that didn't get a response, or got a 4xx or 5xx status
+	FailedCalls int64
+	// The mean time taken to get each response's headers, in milliseconds
+	AverageLatencyMs float64
+	// Calls that took longer than SlowAPICallThreshold
+	SlowCalls int64
+}
+
+type apiCallRecorder struct {
+	lock         sync.Mutex
+	stats        APICallStats
+	totalLatency time.Duration
+}
+
+// do sends req with send and records the call.
+func (r *apiCallRecorder) do(req *http.Request, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	start := time.Now()
+	resp, err := send(req)
+	latency := time.Since(start)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.stats.CallsByEndpoint == nil {
+		r.stats.CallsByEndpoint = make(map[string]int64)
+	}
+	r.stats.TotalCalls++
+	r.stats.CallsByEndpoint[req.Method+" "+apiEndpoint(req.URL.Path)]++
+	if err != nil || resp.StatusCode >= 400 {
+		r.stats.FailedCalls++
+	}
+	if latency > SlowAPICallThreshold {
+		r.stats.SlowCalls++
+	}
+	r.totalLatency += latency
+	return resp, err
+}
+
+// apiEndpoint trims path to its first two segments, so that calls for
+// different IDs count towards the same endpoint.
+func apiEndpoint(path string) string {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 3)
+	if len(parts) > 2 {
+		parts = parts[:2]
+	}
+	return "/" + strings.Join(parts, "/")
+}
+
+// GetAPICallStats returns statistics about the API calls the client has made.
+func (c *Client) GetAPICallStats() APICallStats {
+	r := &c.callStats
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	stats := r.stats
+	stats.CallsByEndpoint = make(map[string]int64, len(r.stats.CallsByEndpoint))
+	for endpoint, n := range r.stats.CallsByEndpoint {
+		stats.CallsByEndpoint[endpoint] = n
+	}
+	if stats.TotalCalls > 0 {
+		stats.AverageLatencyMs = float64(r.totalLatency) / float64(time.Millisecond) / float64(stats.TotalCalls)
+	}
+	return stats
+}
+
+// ResetAPICallStats sets the statistics returned by GetAPICallStats back to
+// zero.
+func (c *Client) ResetAPICallStats() {
+	r := &c.callStats
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.stats = APICallStats{}
+	r.totalLatency = 0
+}
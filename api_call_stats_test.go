@@ -0,0 +1,60 @@
+package signalfx
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPICallStats(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/dashboard/string", verifyRequest(t, "GET", http.StatusOK, nil, "dashboard/get_success.json"))
+	mux.HandleFunc("/v2/dashboard/missing", verifyRequest(t, "GET", http.StatusNotFound, nil, ""))
+	mux.HandleFunc("/v2/detector/string", verifyRequest(t, "GET", http.StatusOK, nil, "detector/get_success.json"))
+
+	assert.Equal(t, int64(0), client.GetAPICallStats().TotalCalls)
+
+	_, err := client.GetDashboard("string")
+	assert.NoError(t, err)
+	_, err = client.GetDashboard("missing")
+	assert.Error(t, err)
+	_, err = client.GetDetector("string")
+	assert.NoError(t, err)
+
+	stats := client.GetAPICallStats()
+	assert.Equal(t, int64(3), stats.TotalCalls)
+	assert.Equal(t, int64(1), stats.FailedCalls)
+	assert.Equal(t, int64(0), stats.SlowCalls)
+	assert.Equal(t, map[string]int64{"GET /v2/dashboard": 2, "GET /v2/detector": 1}, stats.CallsByEndpoint)
+	assert.True(t, stats.AverageLatencyMs > 0)
+
+	stats.CallsByEndpoint["GET /v2/dashboard"] = 100
+	assert.Equal(t, int64(2), client.GetAPICallStats().CallsByEndpoint["GET /v2/dashboard"], "Stats should be a copy")
+
+	client.ResetAPICallStats()
+	assert.Equal(t, APICallStats{CallsByEndpoint: map[string]int64{}}, client.GetAPICallStats())
+}
+
+func TestAPICallStatsSkipsCache(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	setupCache(t, time.Minute, 10)
+
+	mux.HandleFunc("/v2/dashboard/string", verifyRequest(t, "GET", http.StatusOK, nil, "dashboard/get_success.json"))
+
+	for i := 0; i < 3; i++ {
+		_, err := client.GetDashboard("string")
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int64(1), client.GetAPICallStats().TotalCalls, "Cached responses shouldn't count")
+}
+
+func TestAPIEndpoint(t *testing.T) {
+	assert.Equal(t, "/v2/detector", apiEndpoint("/v2/detector/AbC123/events"))
+	assert.Equal(t, "/v2/detector", apiEndpoint("/v2/detector"))
+	assert.Equal(t, "/v2", apiEndpoint("/v2"))
+}
@@ -0,0 +1,38 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// An entry in the organization's audit log, recording one change to one
+// resource.
+type Event struct {
+	// When the change was made
+	Timestamp time.Time `json:"-"`
+	// The ID of the user or token that made the change
+	Actor string `json:"actor,omitempty"`
+	// What was done, e.g. `CREATE`, `UPDATE` or `DELETE`
+	Action string `json:"action,omitempty"`
+	// The kind of resource that changed, e.g. `detector` or `token`
+	ResourceType string `json:"resourceType,omitempty"`
+	// The ID of the resource that changed
+	ResourceID string `json:"resourceId,omitempty"`
+	// The fields that changed, in the form the API reports them
+	Delta json.RawMessage `json:"delta,omitempty"`
+}
+
+// UnmarshalJSON decodes an event, whose timestamp is in milliseconds since
+// the epoch.
+func (e *Event) UnmarshalJSON(b []byte) error {
+	type Alias Event
+	aux := struct {
+		*Alias
+		TimestampMS int64 `json:"timestamp"`
+	}{Alias: (*Alias)(e)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	e.Timestamp = time.Unix(0, aux.TimestampMS*int64(time.Millisecond))
+	return nil
+}
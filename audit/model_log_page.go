@@ -0,0 +1,11 @@
+package audit
+
+// One page of audit log events.
+type LogPage struct {
+	// The events on this page, newest first
+	Events []*Event `json:"results"`
+	// Number of events matching the query across all pages
+	Total int `json:"count"`
+	// The offset to request for the next page, or 0 if this is the last one
+	NextOffset int `json:"-"`
+}
@@ -0,0 +1,54 @@
+package audit
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// QueryParams filters the events returned from the audit log. Zero values
+// are left out of the query.
+type QueryParams struct {
+	// Only events at or after this time
+	StartTime time.Time
+	// Only events before this time
+	EndTime time.Time
+	// Only events caused by this user or token
+	ActorID string
+	// Only events for this kind of resource, e.g. `detector`
+	ResourceType string
+	// Only events with one of these actions
+	EventTypes []string
+	Limit      int
+	Offset     int
+}
+
+// Values returns the query string parameters for the filters that are set.
+func (p *QueryParams) Values() url.Values {
+	params := url.Values{}
+	if p == nil {
+		return params
+	}
+	if !p.StartTime.IsZero() {
+		params.Add("from", strconv.FormatInt(p.StartTime.UnixNano()/int64(time.Millisecond), 10))
+	}
+	if !p.EndTime.IsZero() {
+		params.Add("to", strconv.FormatInt(p.EndTime.UnixNano()/int64(time.Millisecond), 10))
+	}
+	if p.ActorID != "" {
+		params.Add("actor", p.ActorID)
+	}
+	if p.ResourceType != "" {
+		params.Add("resourceType", p.ResourceType)
+	}
+	for _, et := range p.EventTypes {
+		params.Add("eventType", et)
+	}
+	if p.Limit > 0 {
+		params.Add("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Offset > 0 {
+		params.Add("offset", strconv.Itoa(p.Offset))
+	}
+	return params
+}
@@ -0,0 +1,29 @@
+package signalfx
+
+import (
+	"context"
+
+	"github.com/adampetrovic/signalfx-go/audit"
+)
+
+// AuditLogAPIURL is the URL for querying the organization's audit log.
+const AuditLogAPIURL = "/v2/event/audit"
+
+// GetAuditLog gets a page of the organization's audit log matching params,
+// which may be nil. Pass the page's NextOffset as params.Offset to get the
+// next page.
+func (c *Client) GetAuditLog(ctx context.Context, params *audit.QueryParams) (*audit.LogPage, error) {
+	page := &audit.LogPage{}
+	if err := c.getJSON(ctx, AuditLogAPIURL, params.Values(), page); err != nil {
+		return nil, err
+	}
+
+	offset := 0
+	if params != nil {
+		offset = params.Offset
+	}
+	if next := offset + len(page.Events); len(page.Events) > 0 && next < page.Total {
+		page.NextOffset = next
+	}
+	return page, nil
+}
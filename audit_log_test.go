@@ -0,0 +1,70 @@
+package signalfx
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/adampetrovic/signalfx-go/audit"
+)
+
+func TestGetAuditLog(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	params := url.Values{}
+	params.Add("from", "1557900000000")
+	params.Add("actor", "AbC")
+	params.Add("resourceType", "detector")
+	params.Add("eventType", "CREATE")
+	params.Add("limit", "2")
+	mux.HandleFunc("/v2/event/audit", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, []string{"CREATE", "UPDATE"}, r.URL.Query()["eventType"])
+		assert.Empty(t, r.URL.Query().Get("offset"), "Zero offset should be left out")
+		verifyRequest(t, "GET", http.StatusOK, params, "audit/search_success.json")(w, r)
+	})
+
+	page, err := client.GetAuditLog(context.Background(), &audit.QueryParams{
+		StartTime:    time.Unix(1557900000, 0),
+		ActorID:      "AbC",
+		ResourceType: "detector",
+		EventTypes:   []string{"CREATE", "UPDATE"},
+		Limit:        2,
+	})
+	assert.NoError(t, err, "Unexpected error getting audit log")
+	assert.Equal(t, 3, page.Total)
+	assert.Equal(t, 2, page.NextOffset, "Should point at the next page")
+	if assert.Len(t, page.Events, 2) {
+		ev := page.Events[0]
+		assert.Equal(t, time.Unix(1557936000, 0), ev.Timestamp)
+		assert.Equal(t, "UPDATE", ev.Action)
+		assert.Equal(t, "DeT", ev.ResourceID)
+		assert.JSONEq(t, `{"name": {"old": "CPU", "new": "CPU high"}}`, string(ev.Delta))
+	}
+}
+
+func TestGetAuditLogLastPage(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/event/audit", verifyRequest(t, "GET", http.StatusOK, nil, "audit/search_success.json"))
+
+	page, err := client.GetAuditLog(context.Background(), &audit.QueryParams{Offset: 1})
+	assert.NoError(t, err, "Unexpected error getting audit log")
+	assert.Equal(t, 0, page.NextOffset, "Should be the last page")
+}
+
+func TestGetAuditLogError(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/event/audit", verifyRequest(t, "GET", http.StatusForbidden, nil, ""))
+
+	page, err := client.GetAuditLog(context.Background(), nil)
+	assert.Error(t, err, "Should get an error from a forbidden request")
+	assert.Nil(t, page)
+}
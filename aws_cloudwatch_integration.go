@@ -2,9 +2,6 @@ package signalfx
 
 import (
 	"bytes"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
 	"net/http"
 
 	"github.com/adampetrovic/signalfx-go/integration"
@@ -12,7 +9,7 @@ import (
 
 // CreateAWSCloudWatchIntegration creates an AWS CloudWatch integration.
 func (c *Client) CreateAWSCloudWatchIntegration(acwi *integration.AwsCloudWatchIntegration) (*integration.AwsCloudWatchIntegration, error) {
-	payload, err := json.Marshal(acwi)
+	payload, err := c.marshal(acwi)
 	if err != nil {
 		return nil, err
 	}
@@ -25,13 +22,12 @@ func (c *Client) CreateAWSCloudWatchIntegration(acwi *integration.AwsCloudWatchI
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalIntegration := integration.AwsCloudWatchIntegration{}
 
-	err = json.NewDecoder(resp.Body).Decode(&finalIntegration)
+	err = c.decodeJSON(resp.Body, &finalIntegration)
 
 	return &finalIntegration, err
 }
@@ -46,20 +42,19 @@ func (c *Client) GetAWSCloudWatchIntegration(id string) (*integration.AwsCloudWa
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalIntegration := integration.AwsCloudWatchIntegration{}
 
-	err = json.NewDecoder(resp.Body).Decode(&finalIntegration)
+	err = c.decodeJSON(resp.Body, &finalIntegration)
 
 	return &finalIntegration, err
 }
 
 // UpdateAWSCloudWatchIntegration updates an AWS CloudWatch integration.
 func (c *Client) UpdateAWSCloudWatchIntegration(id string, acwi *integration.AwsCloudWatchIntegration) (*integration.AwsCloudWatchIntegration, error) {
-	payload, err := json.Marshal(acwi)
+	payload, err := c.marshal(acwi)
 	if err != nil {
 		return nil, err
 	}
@@ -72,13 +67,12 @@ func (c *Client) UpdateAWSCloudWatchIntegration(id string, acwi *integration.Aws
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalIntegration := integration.AwsCloudWatchIntegration{}
 
-	err = json.NewDecoder(resp.Body).Decode(&finalIntegration)
+	err = c.decodeJSON(resp.Body, &finalIntegration)
 
 	return &finalIntegration, err
 }
@@ -93,8 +87,7 @@ func (c *Client) DeleteAWSCloudWatchIntegration(id string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return newAPIError(resp)
 	}
 
 	return err
@@ -0,0 +1,100 @@
+package signalfx
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/adampetrovic/signalfx-go/chart"
+	"github.com/adampetrovic/signalfx-go/dashboard"
+	"github.com/adampetrovic/signalfx-go/detector"
+)
+
+// BulkGetDetectors gets each of the detectors in ids, running up to the
+// client's MaxConcurrent requests at once, and returns them keyed by ID.
+// Detectors that don't exist are left out of the map rather than being
+// errors.  If other lookups fail, the detectors that were found are returned
+// along with a ResourceErrors for the rest.
+func (c *Client) BulkGetDetectors(ctx context.Context, ids []string) (map[string]*detector.Detector, error) {
+	found := make(map[string]*detector.Detector, len(ids))
+	var lock sync.Mutex
+	err := c.bulkGet(ids, func(id string) error {
+		d, err := c.getDetector(ctx, id)
+		if err != nil {
+			return err
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		found[id] = d
+		return nil
+	})
+	return found, err
+}
+
+// BulkGetDashboards gets each of the dashboards in ids like BulkGetDetectors.
+func (c *Client) BulkGetDashboards(ctx context.Context, ids []string) (map[string]*dashboard.Dashboard, error) {
+	found := make(map[string]*dashboard.Dashboard, len(ids))
+	var lock sync.Mutex
+	err := c.bulkGet(ids, func(id string) error {
+		d, err := c.getDashboard(ctx, id, false)
+		if err != nil {
+			return err
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		found[id] = d
+		return nil
+	})
+	return found, err
+}
+
+// BulkGetCharts gets each of the charts in ids like BulkGetDetectors.
+func (c *Client) BulkGetCharts(ctx context.Context, ids []string) (map[string]*chart.Chart, error) {
+	found := make(map[string]*chart.Chart, len(ids))
+	var lock sync.Mutex
+	err := c.bulkGet(ids, func(id string) error {
+		ch, err := c.getChart(ctx, id, false)
+		if err != nil {
+			return err
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		found[id] = ch
+		return nil
+	})
+	return found, err
+}
+
+// bulkGet calls get once for each distinct ID in ids, concurrently, and
+// returns a ResourceErrors for those that failed other than with a 404.
+func (c *Client) bulkGet(ids []string, get func(id string) error) error {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	failed := ResourceErrors{}
+	var lock sync.Mutex
+	c.forEachConcurrently(len(unique), func(i int) {
+		err := get(unique[i])
+		if err == nil {
+			return
+		}
+		if apiErr, ok := err.(*APIError); ok && apiErr.StatusCode == http.StatusNotFound {
+			return
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+		failed[unique[i]] = err
+	})
+
+	if len(failed) > 0 {
+		return failed
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package signalfx
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkGetDetectors(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	var calls int64
+	for _, id := range []string{"DeTa", "DeTb"} {
+		handle := verifyRequest(t, "GET", http.StatusOK, nil, "detector/get_success.json")
+		mux.HandleFunc("/v2/detector/"+id, func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt64(&calls, 1)
+			handle(w, r)
+		})
+	}
+	mux.HandleFunc("/v2/detector/missing", verifyRequest(t, "GET", http.StatusNotFound, nil, ""))
+
+	result, err := client.BulkGetDetectors(context.Background(), []string{"DeTa", "DeTb", "DeTa", "missing"})
+	assert.NoError(t, err, "Missing detectors shouldn't be errors")
+	assert.Len(t, result, 2)
+	assert.Contains(t, result, "DeTa")
+	assert.Contains(t, result, "DeTb")
+	assert.Equal(t, int64(2), atomic.LoadInt64(&calls), "Duplicate IDs should only be fetched once")
+}
+
+func TestBulkGetDashboardsPartialFailure(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/dashboard/DaSh1", verifyRequest(t, "GET", http.StatusOK, nil, "dashboard/get_success.json"))
+	mux.HandleFunc("/v2/dashboard/DaSh2", verifyRequest(t, "GET", http.StatusInternalServerError, nil, ""))
+
+	result, err := client.BulkGetDashboards(context.Background(), []string{"DaSh1", "DaSh2"})
+	failed, ok := err.(ResourceErrors)
+	if assert.True(t, ok, "Should get a ResourceErrors") {
+		assert.Len(t, failed, 1)
+		assert.Contains(t, failed, "DaSh2")
+	}
+	assert.Len(t, result, 1)
+	assert.Contains(t, result, "DaSh1")
+}
+
+func TestBulkGetChartsConcurrency(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	limited, err := NewClient(TestToken, APIUrl(server.URL), MaxConcurrent(2))
+	assert.NoError(t, err)
+
+	var lock sync.Mutex
+	active, maxActive := 0, 0
+	handle := verifyRequest(t, "GET", http.StatusOK, nil, "chart/get_success.json")
+	mux.HandleFunc("/v2/chart/", func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		active++
+		if active > maxActive {
+			maxActive = active
+		}
+		lock.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		handle(w, r)
+		lock.Lock()
+		active--
+		lock.Unlock()
+	})
+
+	ids := []string{"c1", "c2", "c3", "c4", "c5", "c6"}
+	result, err := limited.BulkGetCharts(context.Background(), ids)
+	assert.NoError(t, err)
+	assert.Len(t, result, len(ids))
+	assert.True(t, maxActive <= 2, "Should run at most MaxConcurrent requests at once, ran %d", maxActive)
+}
@@ -0,0 +1,237 @@
+package signalfx
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheStats describes how well the response cache installed by
+// WithCacheMiddleware is doing.
+type CacheStats struct {
+	// Requests answered from the cache
+	Hits int64
+	// Cacheable requests that had to go to the API
+	Misses int64
+	// Entries dropped to make room for new ones
+	Evictions int64
+	// Entries currently cached
+	Entries int
+}
+
+type cacheableKey struct{}
+
+// cacheable returns a context that lets GET requests using it be answered from
+// the response cache, or when forceRefresh is true, only stores their
+// responses in it.  Only getters whose results are fine to serve for up to the
+// cache's ttl use it, so methods that poll or search always see fresh data.
+func cacheable(ctx context.Context, forceRefresh bool) context.Context {
+	return context.WithValue(ctx, cacheableKey{}, &forceRefresh)
+}
+
+// WithCacheMiddleware caches the successful responses of GetDashboard and
+// GetChart, and their WithContext variants, for ttl.  Other methods, including
+// those that search or poll, are never cached.  At most maxEntries responses
+// are kept, dropping the least recently used.  Requests with a
+// `Cache-Control: no-cache` header or a forceRefresh argument skip the cache,
+// and a successful write to a resource, such as `/v2/dashboard/{id}`, drops
+// every cached response for that kind of resource.
+func WithCacheMiddleware(ttl time.Duration, maxEntries int) ClientParam {
+	return func(client *Client) error {
+		if ttl <= 0 {
+			return errors.New("cache ttl cannot be <= 0")
+		}
+		if maxEntries <= 0 {
+			return errors.New("cache maxEntries cannot be <= 0")
+		}
+		client.cache = newResponseCache(ttl, maxEntries)
+		return nil
+	}
+}
+
+// CacheStats returns the response cache's statistics, which are all zero if
+// WithCacheMiddleware wasn't used.
+func (c *Client) CacheStats() CacheStats {
+	if c.cache == nil {
+		return CacheStats{}
+	}
+	return c.cache.stats()
+}
+
+type cachedResponse struct {
+	key     string
+	prefix  string
+	expires time.Time
+	status  int
+	header  http.Header
+	body    []byte
+}
+
+// responseCache is an LRU cache of response bodies, keyed on the auth token
+// and full URL so that clients using several tokens don't share responses.
+type responseCache struct {
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	lock    sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+	counts  CacheStats
+}
+
+func newResponseCache(ttl time.Duration, maxEntries int) *responseCache {
+	return &responseCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		order:      list.New(),
+		entries:    map[string]*list.Element{},
+	}
+}
+
+func cacheKey(req *http.Request) string {
+	return req.Header.Get(AuthHeaderKey) + " " + req.URL.String()
+}
+
+// resourcePrefix returns the part of path that names the kind of resource,
+// such as `/v2/dashboard` for `/v2/dashboard/{id}`.
+func resourcePrefix(path string) string {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 3)
+	if len(parts) > 2 {
+		parts = parts[:2]
+	}
+	return "/" + strings.Join(parts, "/")
+}
+
+// do sends req through the cache.
+func (rc *responseCache) do(req *http.Request, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	if req.Method != "GET" {
+		resp, err := send(req)
+		if err == nil && resp.StatusCode < 400 {
+			rc.invalidate(resourcePrefix(req.URL.Path))
+		}
+		return resp, err
+	}
+	refresh, ok := req.Context().Value(cacheableKey{}).(*bool)
+	if !ok {
+		return send(req)
+	}
+
+	key := cacheKey(req)
+	if !*refresh && req.Header.Get("Cache-Control") != "no-cache" {
+		if cached := rc.get(key); cached != nil {
+			return cached.response(req), nil
+		}
+	}
+
+	resp, err := send(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	rc.put(&cachedResponse{
+		key:    key,
+		prefix: resourcePrefix(req.URL.Path),
+		status: resp.StatusCode,
+		header: resp.Header,
+		body:   body,
+	})
+	return resp, nil
+}
+
+func (cr *cachedResponse) response(req *http.Request) *http.Response {
+	header := make(http.Header, len(cr.header))
+	for k, v := range cr.header {
+		header[k] = append([]string(nil), v...)
+	}
+	return &http.Response{
+		Status:        http.StatusText(cr.status),
+		StatusCode:    cr.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(cr.body)),
+		ContentLength: int64(len(cr.body)),
+		Request:       req,
+	}
+}
+
+func (rc *responseCache) get(key string) *cachedResponse {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+
+	elem, ok := rc.entries[key]
+	if !ok {
+		rc.counts.Misses++
+		return nil
+	}
+	cached := elem.Value.(*cachedResponse)
+	if rc.now().After(cached.expires) {
+		rc.remove(elem)
+		rc.counts.Misses++
+		return nil
+	}
+	rc.order.MoveToFront(elem)
+	rc.counts.Hits++
+	return cached
+}
+
+func (rc *responseCache) put(cached *cachedResponse) {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+
+	cached.expires = rc.now().Add(rc.ttl)
+	if elem, ok := rc.entries[cached.key]; ok {
+		elem.Value = cached
+		rc.order.MoveToFront(elem)
+		return
+	}
+
+	rc.entries[cached.key] = rc.order.PushFront(cached)
+	for rc.order.Len() > rc.maxEntries {
+		rc.remove(rc.order.Back())
+		rc.counts.Evictions++
+	}
+}
+
+// invalidate drops the cached responses for the resources under prefix.
+func (rc *responseCache) invalidate(prefix string) {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+
+	for _, elem := range rc.entries {
+		if elem.Value.(*cachedResponse).prefix == prefix {
+			rc.remove(elem)
+		}
+	}
+}
+
+// remove drops elem from the cache.  The lock must be held.
+func (rc *responseCache) remove(elem *list.Element) {
+	rc.order.Remove(elem)
+	delete(rc.entries, elem.Value.(*cachedResponse).key)
+}
+
+func (rc *responseCache) stats() CacheStats {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+
+	stats := rc.counts
+	stats.Entries = rc.order.Len()
+	return stats
+}
@@ -0,0 +1,175 @@
+package signalfx
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/dashboard"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupCache(t *testing.T, ttl time.Duration, maxEntries int) *responseCache {
+	assert.NoError(t, WithCacheMiddleware(ttl, maxEntries)(client))
+	return client.cache
+}
+
+func TestCacheMiddleware(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	setupCache(t, time.Minute, 10)
+
+	gets := 0
+	mux.HandleFunc("/v2/dashboard/string", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			gets++
+			verifyRequest(t, "GET", http.StatusOK, nil, "dashboard/get_success.json")(w, r)
+			return
+		}
+		verifyRequest(t, "PUT", http.StatusOK, nil, "dashboard/update_success.json")(w, r)
+	})
+
+	for i := 0; i < 3; i++ {
+		result, err := client.GetDashboard("string")
+		assert.NoError(t, err, "Unexpected error getting dashboard")
+		assert.Equal(t, "string", result.Name, "Name does not match")
+	}
+	assert.Equal(t, 1, gets, "Repeated gets should be served from the cache")
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 1, Entries: 1}, client.CacheStats())
+
+	_, err := client.UpdateDashboard("string", &dashboard.CreateUpdateDashboardRequest{Name: "string"})
+	assert.NoError(t, err, "Unexpected error updating dashboard")
+	assert.Equal(t, 0, client.CacheStats().Entries, "Updates should drop the cached dashboard")
+
+	_, err = client.GetDashboard("string")
+	assert.NoError(t, err, "Unexpected error getting dashboard")
+	assert.Equal(t, 2, gets, "Should get the dashboard again after an update")
+}
+
+func TestCacheMiddlewareForceRefresh(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	setupCache(t, time.Minute, 10)
+
+	gets := 0
+	mux.HandleFunc("/v2/chart/string", func(w http.ResponseWriter, r *http.Request) {
+		gets++
+		verifyRequest(t, "GET", http.StatusOK, nil, "chart/get_success.json")(w, r)
+	})
+
+	_, err := client.GetChart("string")
+	assert.NoError(t, err)
+	_, err = client.GetChartWithContext(context.Background(), "string", true)
+	assert.NoError(t, err)
+	_, err = client.GetChartWithContext(context.Background(), "string", false)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, gets, "Only the forced refresh should skip the cache")
+
+	dashboardGets := 0
+	mux.HandleFunc("/v2/dashboard/string", func(w http.ResponseWriter, r *http.Request) {
+		dashboardGets++
+		verifyRequest(t, "GET", http.StatusOK, nil, "dashboard/get_success.json")(w, r)
+	})
+
+	_, err = client.GetDashboard("string")
+	assert.NoError(t, err)
+	_, err = client.GetDashboardWithContext(context.Background(), "string", false)
+	assert.NoError(t, err)
+	_, err = client.GetDashboardWithContext(context.Background(), "string", true)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, dashboardGets, "Only the forced refresh should skip the cache")
+}
+
+func TestCacheMiddlewareExpiryAndEviction(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	cache := setupCache(t, time.Minute, 1)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	mux.HandleFunc("/v2/chart/a", verifyRequest(t, "GET", http.StatusOK, nil, "chart/get_success.json"))
+	mux.HandleFunc("/v2/chart/b", verifyRequest(t, "GET", http.StatusOK, nil, "chart/get_success.json"))
+	mux.HandleFunc("/v2/chart/missing", verifyRequest(t, "GET", http.StatusNotFound, nil, ""))
+
+	client.GetChart("a")
+	client.GetChart("b")
+	assert.Equal(t, CacheStats{Misses: 2, Evictions: 1, Entries: 1}, client.CacheStats(), "Should only keep the newest entry")
+
+	client.GetChart("b")
+	now = now.Add(2 * time.Minute)
+	client.GetChart("b")
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 3, Evictions: 1, Entries: 1}, client.CacheStats(), "Expired entries should be fetched again")
+
+	_, err := client.GetChart("missing")
+	assert.Error(t, err)
+	_, err = client.GetChart("missing")
+	assert.Error(t, err, "Errors shouldn't be cached")
+}
+
+func TestCacheMiddlewareOptions(t *testing.T) {
+	assert.Equal(t, CacheStats{}, MustNewClient(TestToken).CacheStats(), "No cache should mean no stats")
+
+	_, err := NewClient(TestToken, WithCacheMiddleware(0, 10))
+	assert.Error(t, err, "Should get an error with a zero ttl")
+	_, err = NewClient(TestToken, WithCacheMiddleware(time.Minute, 0))
+	assert.Error(t, err, "Should get an error with no entries")
+}
+
+func TestCacheMiddlewareOnlyCachesGetters(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	setupCache(t, time.Minute, 10)
+
+	searches, groupGets := 0, 0
+	mux.HandleFunc("/v2/dashboard", func(w http.ResponseWriter, r *http.Request) {
+		searches++
+		verifyRequest(t, "GET", http.StatusOK, nil, "dashboard/search_success.json")(w, r)
+	})
+	mux.HandleFunc("/v2/dashboardgroup/string", func(w http.ResponseWriter, r *http.Request) {
+		groupGets++
+		verifyRequest(t, "GET", http.StatusOK, nil, "dashboardgroup/get_success.json")(w, r)
+	})
+
+	for i := 0; i < 2; i++ {
+		_, err := client.SearchDashboard(10, "string", 0, "")
+		assert.NoError(t, err, "Unexpected error searching dashboards")
+		_, err = client.GetDashboardGroup("string")
+		assert.NoError(t, err, "Unexpected error getting dashboard group")
+	}
+	assert.Equal(t, 2, searches, "Searches should not be cached")
+	assert.Equal(t, 2, groupGets, "Only GetDashboard and GetChart should be cached")
+	assert.Equal(t, CacheStats{}, client.CacheStats())
+}
+
+func TestCacheMiddlewareInvalidatesResource(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	setupCache(t, time.Minute, 10)
+
+	mux.HandleFunc("/v2/dashboard/a", verifyRequest(t, "GET", http.StatusOK, nil, "dashboard/get_success.json"))
+	mux.HandleFunc("/v2/dashboard/b", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			verifyRequest(t, "GET", http.StatusOK, nil, "dashboard/get_success.json")(w, r)
+			return
+		}
+		verifyRequest(t, "PUT", http.StatusOK, nil, "dashboard/update_success.json")(w, r)
+	})
+	mux.HandleFunc("/v2/chart/a", verifyRequest(t, "GET", http.StatusOK, nil, "chart/get_success.json"))
+
+	client.GetDashboard("a")
+	client.GetDashboard("b")
+	client.GetChart("a")
+	assert.Equal(t, 3, client.CacheStats().Entries)
+
+	_, err := client.UpdateDashboard("b", &dashboard.CreateUpdateDashboardRequest{Name: "string"})
+	assert.NoError(t, err, "Unexpected error updating dashboard")
+	assert.Equal(t, 1, client.CacheStats().Entries, "Updates should drop every cached dashboard but not charts")
+}
+
+func TestResourcePrefix(t *testing.T) {
+	assert.Equal(t, "/v2/dashboard", resourcePrefix("/v2/dashboard"))
+	assert.Equal(t, "/v2/dashboard", resourcePrefix("/v2/dashboard/abc"))
+	assert.Equal(t, "/v2/dashboard", resourcePrefix("/v2/dashboard/abc/clone"))
+	assert.Equal(t, "/v2/dashboardgroup", resourcePrefix("/v2/dashboardgroup/abc"))
+}
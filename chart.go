@@ -2,23 +2,31 @@ package signalfx
 
 import (
 	"bytes"
-	"encoding/json"
-	"errors"
+	"context"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
+	"time"
 
 	"github.com/adampetrovic/signalfx-go/chart"
+	"github.com/adampetrovic/signalfx-go/idtool"
+	"github.com/adampetrovic/signalfx-go/signalflow"
+	"github.com/adampetrovic/signalfx-go/signalflow/messages"
 )
 
 // ChartAPIURL is the base URL for interacting with charts.
 const ChartAPIURL = "/v2/chart"
 
-// CreateChart creates a chart.
+// CreateChart creates a chart.  The request's options are validated before it
+// is sent.
 func (c *Client) CreateChart(chartRequest *chart.CreateUpdateChartRequest) (*chart.Chart, error) {
-	payload, err := json.Marshal(chartRequest)
+	if err := chartRequest.Validate(); err != nil {
+		return nil, err
+	}
+
+	payload, err := c.marshal(chartRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -30,13 +38,12 @@ func (c *Client) CreateChart(chartRequest *chart.CreateUpdateChartRequest) (*cha
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalChart := &chart.Chart{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalChart)
+	err = c.decodeJSON(resp.Body, finalChart)
 
 	return finalChart, err
 }
@@ -51,7 +58,7 @@ func (c *Client) DeleteChart(id string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.New("Unexpected status code: " + resp.Status)
+		return newAPIError(resp)
 	}
 
 	return nil
@@ -59,7 +66,18 @@ func (c *Client) DeleteChart(id string) error {
 
 // GetChart gets a chart.
 func (c *Client) GetChart(id string) (*chart.Chart, error) {
-	resp, err := c.doRequest("GET", ChartAPIURL+"/"+id, nil, nil)
+	return c.getChart(c.ctx, id, false)
+}
+
+// GetChartWithContext gets a chart using ctx.  If forceRefresh is true, the
+// response cache is skipped, though the response still replaces whatever was
+// cached.
+func (c *Client) GetChartWithContext(ctx context.Context, id string, forceRefresh bool) (*chart.Chart, error) {
+	return c.getChart(ctx, id, forceRefresh)
+}
+
+func (c *Client) getChart(ctx context.Context, id string, forceRefresh bool) (*chart.Chart, error) {
+	resp, err := c.doRequestWithContext(cacheable(ctx, forceRefresh), "GET", ChartAPIURL+"/"+id, nil, nil)
 
 	if err != nil {
 		return nil, err
@@ -67,20 +85,24 @@ func (c *Client) GetChart(id string) (*chart.Chart, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalChart := &chart.Chart{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalChart)
+	err = c.decodeJSON(resp.Body, finalChart)
 
 	return finalChart, err
 }
 
-// UpdateChart updates a chart.
+// UpdateChart updates a chart.  The request's options are validated before it
+// is sent.
 func (c *Client) UpdateChart(id string, chartRequest *chart.CreateUpdateChartRequest) (*chart.Chart, error) {
-	payload, err := json.Marshal(chartRequest)
+	if err := chartRequest.Validate(); err != nil {
+		return nil, err
+	}
+
+	payload, err := c.marshal(chartRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -92,13 +114,12 @@ func (c *Client) UpdateChart(id string, chartRequest *chart.CreateUpdateChartReq
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalChart := &chart.Chart{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalChart)
+	err = c.decodeJSON(resp.Body, finalChart)
 
 	return finalChart, err
 }
@@ -120,7 +141,103 @@ func (c *Client) SearchCharts(limit int, name string, offset int, tags string) (
 
 	finalCharts := &chart.SearchResult{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalCharts)
+	err = c.decodeJSON(resp.Body, finalCharts)
 
 	return finalCharts, err
 }
+
+// GetChartDataFrame runs a chart's program over SignalFlow between startTime
+// and endTime, and returns the data as a table with a row per datapoint.
+func (c *Client) GetChartDataFrame(ctx context.Context, chartID string, startTime, endTime time.Time) (*chart.DataFrame, error) {
+	if !endTime.After(startTime) {
+		return nil, fmt.Errorf("endTime (%s) must be after startTime (%s)", endTime, startTime)
+	}
+
+	ch, err := c.getChart(ctx, chartID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	sf, err := c.signalFlowExecutor()
+	if err != nil {
+		return nil, err
+	}
+	defer sf.Close()
+
+	comp, err := sf.Execute(&signalflow.ExecuteRequest{
+		Program:   ch.ProgramText,
+		Start:     startTime,
+		Stop:      endTime,
+		Immediate: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var points []dataFramePoint
+	metadata := map[idtool.ID]*messages.MetadataProperties{}
+	dimensionKeys := map[string]bool{}
+
+	for {
+		select {
+		case <-ctx.Done():
+			comp.Stop()
+			return nil, ctx.Err()
+		case msg, ok := <-comp.Data():
+			if !ok {
+				if err := comp.Err(); err != nil {
+					return nil, err
+				}
+				return buildDataFrame(points, dimensionKeys, comp.Resolution()), nil
+			}
+			for _, pl := range msg.Payloads {
+				md, ok := metadata[pl.TSID]
+				if !ok {
+					md = comp.TSIDMetadata(pl.TSID)
+					metadata[pl.TSID] = md
+					if md != nil {
+						for k := range md.CustomProperties {
+							dimensionKeys[k] = true
+						}
+					}
+				}
+				points = append(points, dataFramePoint{timestamp: msg.Timestamp(), value: pl.Value(), metadata: md})
+			}
+		}
+	}
+}
+
+type dataFramePoint struct {
+	timestamp time.Time
+	value     interface{}
+	metadata  *messages.MetadataProperties
+}
+
+func buildDataFrame(points []dataFramePoint, dimensionKeys map[string]bool, resolution time.Duration) *chart.DataFrame {
+	keys := make([]string, 0, len(dimensionKeys))
+	for k := range dimensionKeys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	df := &chart.DataFrame{
+		Columns:    append([]string{chart.DataFrameTimestampColumn, chart.DataFrameValueColumn, chart.DataFrameMetricColumn}, keys...),
+		Rows:       make([][]interface{}, len(points)),
+		Resolution: resolution,
+	}
+	for i, p := range points {
+		row := make([]interface{}, len(df.Columns))
+		row[0] = p.timestamp
+		row[1] = p.value
+		if p.metadata != nil {
+			row[2] = p.metadata.Metric
+			for j, k := range keys {
+				if v, ok := p.metadata.CustomProperties[k]; ok {
+					row[3+j] = v
+				}
+			}
+		}
+		df.Rows[i] = row
+	}
+	return df
+}
@@ -0,0 +1,150 @@
+package chart
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Color is a color from the standard SignalFx palette.  Its value is the
+// palette index that the API expects in fields like `paletteIndex`.
+type Color int32
+
+// The colors of the standard SignalFx palette, in palette order.
+const (
+	ColorGray Color = iota
+	ColorBlue
+	ColorAzure
+	ColorNavy
+	ColorBrown
+	ColorOrange
+	ColorYellow
+	ColorMagenta
+	ColorPurple
+	ColorPink
+	ColorViolet
+	ColorLilac
+	ColorIris
+	ColorEmerald
+	ColorGreen
+	ColorAquamarine
+	ColorRed
+	ColorGold
+	ColorGreenYellow
+	ColorChartreuse
+	ColorJade
+)
+
+var paletteHex = []string{
+	"#999999", "#0077c2", "#00b9ff", "#6ca2b7", "#b04600", "#f47e00", "#e5b312",
+	"#bd468d", "#e9008a", "#ff8dd1", "#876ff3", "#a747ff", "#ab99bc", "#007c1d",
+	"#05ce00", "#0dba8f", "#ea1849", "#eac24b", "#e5e517", "#acef7f", "#6bd37e",
+}
+
+var hexColorRegexp = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// ParseColor returns the palette color with the hex value s, e.g. "#ea1849".
+// Charts can only use palette colors, so a well formed value that isn't in
+// the palette is an error too.
+func ParseColor(s string) (Color, error) {
+	if !hexColorRegexp.MatchString(s) {
+		return 0, fmt.Errorf("color %q must be a '#' followed by 6 hex digits", s)
+	}
+	for i, hex := range paletteHex {
+		if strings.EqualFold(hex, s) {
+			return Color(i), nil
+		}
+	}
+	return 0, fmt.Errorf("color %s is not in the SignalFx palette", s)
+}
+
+// FromHex is ParseColor, except that it returns c when s is empty, so that c
+// can be a default for an optional hex value, e.g.
+// ColorGray.FromHex(settings.Color).
+func (c Color) FromHex(s string) (Color, error) {
+	if s == "" {
+		return c, nil
+	}
+	return ParseColor(s)
+}
+
+// Hex returns the hex value of the color, or an empty string if c isn't a
+// palette color.
+func (c Color) Hex() string {
+	if c < 0 || int(c) >= len(paletteHex) {
+		return ""
+	}
+	return paletteHex[c]
+}
+
+// ColorByValue is a set of value ranges and the colors to show them in, as
+// used by List, SingleValue and Heatmap charts.
+type ColorByValue []*SecondaryVisualization
+
+// Apply colors the chart by value using these ranges.
+func (cbv ColorByValue) Apply(o *Options) {
+	o.ColorBy = "Scale"
+	o.ColorScale2 = cbv
+}
+
+// ColorPalette builds a ColorByValue from a set of thresholds.  Values below
+// the lowest threshold use the base color, and each threshold's color applies
+// from that threshold (inclusive) up to the next one.
+type ColorPalette struct {
+	base       Color
+	thresholds map[float64]Color
+}
+
+// NewColorPalette starts a palette where every value is shown in base.
+func NewColorPalette(base Color) *ColorPalette {
+	return &ColorPalette{
+		base:       base,
+		thresholds: make(map[float64]Color),
+	}
+}
+
+// WithThreshold shows values at or above value in color, until the next
+// threshold up.  Setting the same threshold twice keeps the last color.
+func (cp *ColorPalette) WithThreshold(value float64, color Color) *ColorPalette {
+	cp.thresholds[value] = color
+	return cp
+}
+
+// Build returns the ranges for the palette, ordered from lowest to highest.
+func (cp *ColorPalette) Build() ColorByValue {
+	values := make([]float64, 0, len(cp.thresholds))
+	for v := range cp.thresholds {
+		values = append(values, v)
+	}
+	sort.Float64s(values)
+
+	paletteIndex := func(c Color) *int32 {
+		i := int32(c)
+		return &i
+	}
+	bound := func(v float64) *float32 {
+		f := float32(v)
+		return &f
+	}
+
+	ranges := make(ColorByValue, 0, len(values)+1)
+	if len(values) == 0 {
+		return append(ranges, &SecondaryVisualization{PaletteIndex: paletteIndex(cp.base)})
+	}
+	ranges = append(ranges, &SecondaryVisualization{
+		Lt:           bound(values[0]),
+		PaletteIndex: paletteIndex(cp.base),
+	})
+	for i, v := range values {
+		sv := &SecondaryVisualization{
+			Gte:          bound(v),
+			PaletteIndex: paletteIndex(cp.thresholds[v]),
+		}
+		if i+1 < len(values) {
+			sv.Lt = bound(values[i+1])
+		}
+		ranges = append(ranges, sv)
+	}
+	return ranges
+}
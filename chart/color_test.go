@@ -0,0 +1,63 @@
+package chart
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseColor(t *testing.T) {
+	c, err := ParseColor("#EA1849")
+	assert.NoError(t, err)
+	assert.Equal(t, ColorRed, c)
+	assert.Equal(t, "#ea1849", c.Hex())
+
+	_, err = ParseColor("ea1849")
+	assert.Error(t, err, "Missing # should fail")
+	_, err = ParseColor("#ea18")
+	assert.Error(t, err, "Short value should fail")
+	_, err = ParseColor("#123456")
+	assert.Error(t, err, "Color outside the palette should fail")
+	_, err = ParseColor("")
+	assert.Error(t, err, "Empty value should fail")
+
+	assert.Equal(t, "", Color(42).Hex())
+}
+
+func TestColorFromHex(t *testing.T) {
+	c, err := ColorGray.FromHex("#ea1849")
+	assert.NoError(t, err)
+	assert.Equal(t, ColorRed, c)
+
+	c, err = ColorGray.FromHex("")
+	assert.NoError(t, err)
+	assert.Equal(t, ColorGray, c, "Empty value should use the receiver")
+
+	_, err = ColorGray.FromHex("#123456")
+	assert.Error(t, err, "Color outside the palette should fail")
+}
+
+func TestColorPalette(t *testing.T) {
+	cbv := NewColorPalette(ColorGreen).
+		WithThreshold(90, ColorRed).
+		WithThreshold(75, ColorYellow).
+		Build()
+
+	out, _ := json.Marshal(cbv)
+	assert.JSONEq(t, `[
+		{"lt": 75, "paletteIndex": 14},
+		{"gte": 75, "lt": 90, "paletteIndex": 6},
+		{"gte": 90, "paletteIndex": 16}
+	]`, string(out))
+
+	opts := &Options{}
+	cbv.Apply(opts)
+	assert.Equal(t, "Scale", opts.ColorBy)
+	assert.Len(t, opts.ColorScale2, 3)
+}
+
+func TestColorPaletteNoThresholds(t *testing.T) {
+	out, _ := json.Marshal(NewColorPalette(ColorBlue).Build())
+	assert.JSONEq(t, `[{"paletteIndex": 1}]`, string(out))
+}
@@ -0,0 +1,24 @@
+package chart
+
+import "time"
+
+// The columns that start every DataFrame row, before the dimensions.
+const (
+	DataFrameTimestampColumn = "timestamp"
+	DataFrameValueColumn     = "value"
+	DataFrameMetricColumn    = "sf_metric"
+)
+
+// DataFrame is a chart's data as a table with one row per datapoint.
+type DataFrame struct {
+	// The column names: timestamp, value and sf_metric, followed by the
+	// dimension keys of every time series in the data, sorted.
+	Columns []string
+	// One row per datapoint, with a value for each column. The timestamp is a
+	// time.Time, the value is numeric, and the metric and dimensions are
+	// strings, or nil if the datapoint's time series doesn't have that
+	// dimension.
+	Rows [][]interface{}
+	// The resolution the chart's program ran at.
+	Resolution time.Duration
+}
@@ -15,6 +15,8 @@ type TimeDisplayOptions struct {
 	End *int64 `json:"end,omitempty"`
 	// The number of milliseconds to display in the chart. From this value, SignalFx calculates a rolling range with the current time at the right edge of the chart. Use 0 to use the default behavior, which is -15m except for the following metrics --   * **AWS** -- See the metrics listed in the help topic     [Monitor Amazon Web Services](https://docs.signalfx.com/en/latest/integrations/aws-info.htm)   * **GCP** --  See the metrics listed in the help topic     [Monitor Google Cloud Platform (GCP)](https://docs.signalfx.com/en/latest/integrations/google-cloud-platform.html)   * **Microsoft Azure** -- See the metrics listed in the help topic     [Monitor Microsoft Azure](https://docs.signalfx.com/en/latest/integrations/azure-info.html) <br> **Note** This option is only available if `options.type` is `TimeSeriesChart` and `options.time.type` is `relative`.
 	Range *int64 `json:"range,omitempty"`
+	// The number of milliseconds before the current time at which the chart's time range ends. Use this with `range` to show a window that doesn't end at the current time.<br> **Note** This option is only available if `options.type` is `TimeSeriesChart` and `options.time.type` is `relative`.
+	RangeEnd *int64 `json:"rangeEnd,omitempty"`
 	// The timestamp of the first time to display in the chart, in Unix time UTC<br> **Note** This option is only available if `options.type` is `TimeSeriesChart` and `options.time.type` is `absolute`.
 	Start *int64 `json:"start,omitempty"`
 	// Determines whether to use an explicit time range or to show a range of the last *n* milliseconds.<br> **Note** This option is only available if `options.type` is `TimeSeriesChart`.
@@ -0,0 +1,93 @@
+package chart
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// The types of time range a chart can display.
+const (
+	TimeRangeRelative = "relative"
+	TimeRangeAbsolute = "absolute"
+)
+
+// TimeRange is the time range a chart displays.  It is the same type as
+// TimeDisplayOptions, so the constructors below can be assigned directly to
+// Options.Time, but they take Go times and durations instead of raw
+// milliseconds and reject ranges that end before they start.
+type TimeRange = TimeDisplayOptions
+
+func toMillis(d time.Duration) *int64 {
+	ms := d.Nanoseconds() / int64(time.Millisecond)
+	return &ms
+}
+
+func timeToMillis(t time.Time) *int64 {
+	ms := t.UnixNano() / int64(time.Millisecond)
+	return &ms
+}
+
+// Last is a relative time range covering the duration d up to now.
+func Last(d time.Duration) (*TimeRange, error) {
+	return RelativeRange(d, 0)
+}
+
+// Since is an absolute time range from t until the moment Since is called.
+func Since(t time.Time) (*TimeRange, error) {
+	return Between(t, time.Now())
+}
+
+// Between is an absolute time range from start to end.
+func Between(start, end time.Time) (*TimeRange, error) {
+	tr := &TimeRange{
+		Type:  TimeRangeAbsolute,
+		Start: timeToMillis(start),
+		End:   timeToMillis(end),
+	}
+	return tr, tr.Validate()
+}
+
+// RelativeRange is a relative time range that starts startOffset before now
+// and ends endOffset before now, e.g. RelativeRange(2*time.Hour, time.Hour)
+// shows the hour before last.
+func RelativeRange(startOffset, endOffset time.Duration) (*TimeRange, error) {
+	tr := &TimeRange{
+		Type:  TimeRangeRelative,
+		Range: toMillis(startOffset),
+	}
+	if endOffset != 0 {
+		tr.RangeEnd = toMillis(endOffset)
+	}
+	return tr, tr.Validate()
+}
+
+// Validate checks that the fields set match the type of time range and that
+// the range ends after it starts.
+func (tr *TimeDisplayOptions) Validate() error {
+	switch tr.Type {
+	case TimeRangeRelative:
+		if tr.Start != nil || tr.End != nil {
+			return errors.New("start and end cannot be set on a relative time range")
+		}
+		if tr.Range == nil || *tr.Range <= 0 {
+			return errors.New("range must be positive for a relative time range")
+		}
+		if tr.RangeEnd != nil && (*tr.RangeEnd < 0 || *tr.RangeEnd >= *tr.Range) {
+			return fmt.Errorf("rangeEnd must be between 0 and range (%d), got %d", *tr.Range, *tr.RangeEnd)
+		}
+	case TimeRangeAbsolute:
+		if tr.Range != nil || tr.RangeEnd != nil {
+			return errors.New("range and rangeEnd cannot be set on an absolute time range")
+		}
+		if tr.Start == nil || tr.End == nil {
+			return errors.New("start and end must be set for an absolute time range")
+		}
+		if *tr.End <= *tr.Start {
+			return fmt.Errorf("end (%d) must be after start (%d)", *tr.End, *tr.Start)
+		}
+	default:
+		return fmt.Errorf("type must be %q or %q, got %q", TimeRangeRelative, TimeRangeAbsolute, tr.Type)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package chart
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLast(t *testing.T) {
+	tr, err := Last(15 * time.Minute)
+	assert.NoError(t, err)
+	out, _ := json.Marshal(tr)
+	assert.JSONEq(t, `{"type": "relative", "range": 900000}`, string(out))
+
+	_, err = Last(0)
+	assert.Error(t, err, "Zero duration should fail")
+}
+
+func TestSince(t *testing.T) {
+	tr, err := Since(time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, TimeRangeAbsolute, tr.Type)
+	assert.InDelta(t, int64(time.Hour/time.Millisecond), *tr.End-*tr.Start, 1000)
+
+	_, err = Since(time.Now().Add(time.Hour))
+	assert.Error(t, err, "Future start should fail")
+}
+
+func TestBetween(t *testing.T) {
+	start := time.Unix(1557936000, 0)
+	tr, err := Between(start, start.Add(time.Hour))
+	assert.NoError(t, err)
+	out, _ := json.Marshal(tr)
+	assert.JSONEq(t, `{"type": "absolute", "start": 1557936000000, "end": 1557939600000}`, string(out))
+
+	_, err = Between(start, start)
+	assert.Error(t, err, "Empty range should fail")
+	_, err = Between(start, start.Add(-time.Hour))
+	assert.Error(t, err, "Backwards range should fail")
+}
+
+func TestRelativeRange(t *testing.T) {
+	tr, err := RelativeRange(2*time.Hour, time.Hour)
+	assert.NoError(t, err)
+	out, _ := json.Marshal(tr)
+	assert.JSONEq(t, `{"type": "relative", "range": 7200000, "rangeEnd": 3600000}`, string(out))
+
+	_, err = RelativeRange(time.Hour, 2*time.Hour)
+	assert.Error(t, err, "End before start should fail")
+	_, err = RelativeRange(time.Hour, -time.Minute)
+	assert.Error(t, err, "Negative end offset should fail")
+}
+
+func TestTimeRangeValidate(t *testing.T) {
+	ms := int64(1000)
+	assert.Error(t, (&TimeRange{}).Validate(), "Missing type should fail")
+	assert.Error(t, (&TimeRange{Type: "sideways", Range: &ms}).Validate(), "Unknown type should fail")
+	assert.Error(t, (&TimeRange{Type: TimeRangeRelative, Range: &ms, Start: &ms}).Validate(), "Mixed fields should fail")
+	assert.Error(t, (&TimeRange{Type: TimeRangeAbsolute, Start: &ms}).Validate(), "Missing end should fail")
+	assert.Error(t, (&TimeRange{Type: TimeRangeAbsolute, Start: &ms, End: &ms, Range: &ms}).Validate(), "Mixed fields should fail")
+}
@@ -0,0 +1,64 @@
+package chart
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationErrors is every problem Validate found with a chart, so that they
+// can all be fixed at once.
+type ValidationErrors []error
+
+func (ve ValidationErrors) Error() string {
+	msgs := make([]string, len(ve))
+	for i, err := range ve {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Validate checks the options for mistakes that the API would reject, so that
+// they're caught before a request is made.  Every chart must have a valid
+// time range and program options, with a positive minimum resolution if one
+// is set.  Text charts have no program to run, so they need neither.  All
+// problems are returned together as ValidationErrors.
+func (o *Options) Validate() error {
+	var errs ValidationErrors
+	needsProgram := o.Type != "Text"
+
+	if o.Time != nil {
+		if err := o.Time.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("time: %v", err))
+		}
+	} else if needsProgram {
+		errs = append(errs, fmt.Errorf("time must be set for a %s", o.chartType()))
+	}
+
+	if o.ProgramOptions != nil {
+		if mr := o.ProgramOptions.MinimumResolution; mr != nil && *mr <= 0 {
+			errs = append(errs, fmt.Errorf("programOptions: minimumResolution must be positive, got %d", *mr))
+		}
+	} else if needsProgram {
+		errs = append(errs, fmt.Errorf("programOptions must be set for a %s", o.chartType()))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func (o *Options) chartType() string {
+	if o.Type == "" {
+		return "chart"
+	}
+	return o.Type
+}
+
+// Validate checks the request's options, if it has any.
+func (r *CreateUpdateChartRequest) Validate() error {
+	if r.Options == nil {
+		return nil
+	}
+	return r.Options.Validate()
+}
@@ -0,0 +1,39 @@
+package chart
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionsValidate(t *testing.T) {
+	tr, _ := Last(time.Hour)
+	res := int32(60000)
+	opts := &Options{
+		Type:           "TimeSeriesChart",
+		Time:           tr,
+		ProgramOptions: &GeneralOptions{MinimumResolution: &res},
+	}
+	assert.NoError(t, opts.Validate())
+
+	assert.NoError(t, (&Options{Type: "Text"}).Validate(), "Text charts need no time or program options")
+	assert.NoError(t, (&CreateUpdateChartRequest{Name: "no options"}).Validate())
+}
+
+func TestOptionsValidateErrors(t *testing.T) {
+	err := (&Options{Type: "TimeSeriesChart"}).Validate()
+	assert.Len(t, err, 2, "Missing time and program options should both be reported")
+	assert.EqualError(t, err, "time must be set for a TimeSeriesChart; programOptions must be set for a TimeSeriesChart")
+
+	err = (&Options{}).Validate()
+	assert.EqualError(t, err, "time must be set for a chart; programOptions must be set for a chart")
+
+	res := int32(0)
+	err = (&CreateUpdateChartRequest{Options: &Options{
+		Type:           "List",
+		Time:           &TimeRange{Type: TimeRangeRelative},
+		ProgramOptions: &GeneralOptions{MinimumResolution: &res},
+	}}).Validate()
+	assert.Len(t, err, 2, "Invalid time and resolution should both be reported")
+}
@@ -1,12 +1,19 @@
 package signalfx
 
 import (
+	"context"
+	"encoding/binary"
+	"math"
 	"net/http"
 	"net/url"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/adampetrovic/signalfx-go/chart"
+	"github.com/adampetrovic/signalfx-go/idtool"
+	"github.com/adampetrovic/signalfx-go/signalflow"
+	"github.com/adampetrovic/signalfx-go/signalflow/messages"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -36,6 +43,26 @@ func TestBadCreateChart(t *testing.T) {
 	assert.Nil(t, result, "Exepcted nil result creating bad chart")
 }
 
+func TestCreateInvalidChart(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/chart", func(w http.ResponseWriter, r *http.Request) {
+		assert.Fail(t, "Invalid chart should not have been sent")
+	})
+
+	res := int32(0)
+	result, err := client.CreateChart(&chart.CreateUpdateChartRequest{
+		Name: "string",
+		Options: &chart.Options{
+			Type:           "TimeSeriesChart",
+			ProgramOptions: &chart.GeneralOptions{MinimumResolution: &res},
+		},
+	})
+	assert.Error(t, err, "Expected error creating invalid chart")
+	assert.Nil(t, result, "Expected nil result creating invalid chart")
+}
+
 func TestDeleteChart(t *testing.T) {
 	teardown := setup()
 	defer teardown()
@@ -124,3 +151,62 @@ func TestUpdateMissingChart(t *testing.T) {
 	assert.Error(t, err, "Expected error updating chart")
 	assert.Nil(t, result, "Expected nil result updating chart")
 }
+
+func TestGetChartDataFrame(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/chart/string", verifyRequest(t, "GET", http.StatusOK, nil, "chart/get_success.json"))
+
+	fake := signalflow.NewFakeClient()
+	fake.MetadataTimeout = 100 * time.Millisecond
+	client.signalFlowExecutor = func() (signalflow.Executor, error) {
+		return fake, nil
+	}
+
+	resolution, err := messages.ParseMessage([]byte(`{"type": "message", "channel": "ch-1", "message": {"messageCode": "JOB_RUNNING_RESOLUTION", "contents": {"resolutionMs": 60000}}}`), true)
+	assert.NoError(t, err)
+	fake.InjectMessage("ch-1", resolution)
+	fake.InjectMessage("ch-1", &messages.MetadataMessage{TSID: idtool.ID(1), Properties: messages.MetadataProperties{
+		Metric:           "cpu.utilization",
+		CustomProperties: map[string]string{"host": "a"},
+	}})
+	fake.InjectMessage("ch-1", &messages.MetadataMessage{TSID: idtool.ID(2), Properties: messages.MetadataProperties{
+		Metric:           "cpu.utilization",
+		CustomProperties: map[string]string{"host": "b", "region": "us"},
+	}})
+	var val [8]byte
+	binary.BigEndian.PutUint64(val[:], math.Float64bits(12.5))
+	fake.InjectMessage("ch-1", &messages.DataMessage{
+		TimestampedMessage: messages.TimestampedMessage{TimestampMillis: 1557936000000},
+		Payloads: []messages.DataPayload{
+			{Type: messages.ValTypeDouble, TSID: idtool.ID(1), Val: val},
+			{Type: messages.ValTypeDouble, TSID: idtool.ID(2), Val: val},
+		},
+	})
+	fake.InjectMessage("ch-1", &messages.BaseControlMessage{Event: messages.EndOfChannelEvent})
+
+	start := time.Unix(1557936000, 0)
+	df, err := client.GetChartDataFrame(context.Background(), "string", start, start.Add(time.Hour))
+	assert.NoError(t, err, "Unexpected error getting chart data")
+	assert.Equal(t, []string{"timestamp", "value", "sf_metric", "host", "region"}, df.Columns, "Columns do not match")
+	assert.Equal(t, time.Minute, df.Resolution, "Resolution does not match")
+	assert.Equal(t, [][]interface{}{
+		{start, 12.5, "cpu.utilization", "a", nil},
+		{start, 12.5, "cpu.utilization", "b", "us"},
+	}, df.Rows, "Rows do not match")
+
+	executions := fake.RecordedExecutions()
+	assert.Len(t, executions, 1, "Should have executed the chart program")
+	assert.Equal(t, "string", executions[0].Program, "Program does not match")
+	assert.True(t, executions[0].Immediate, "Should have run immediately")
+}
+
+func TestGetChartDataFrameBadRange(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	start := time.Unix(1557936000, 0)
+	_, err := client.GetChartDataFrame(context.Background(), "string", start, start)
+	assert.Error(t, err, "Should have gotten an error for an empty time range")
+}
@@ -1,9 +1,13 @@
 package signalfx
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/adampetrovic/signalfx-go/signalflow"
@@ -12,16 +16,48 @@ import (
 // DefaultAPIURL is the default URL for making API requests
 const DefaultAPIURL = "https://api.signalfx.com"
 
+// DefaultIngestURL is the default URL for sending datapoints
+const DefaultIngestURL = "https://ingest.signalfx.com"
+
 // AuthHeaderKey is the HTTP header used to pass along the auth token
 // Note that while HTTP headers are case insensitive this header is case
 // sensitive on the tests for convenience.
 const AuthHeaderKey = "X-Sf-Token"
 
+// DefaultMaxConcurrent is the default number of requests that bulk operations
+// such as BulkDeleteDetectors have in flight at once.
+const DefaultMaxConcurrent = 10
+
+// DefaultPollInterval is the default time between requests made by methods
+// that wait for something to happen, such as WaitForDetectorAlert.
+const DefaultPollInterval = 10 * time.Second
+
 // Client is a SignalFx API client.
 type Client struct {
 	baseURL    string
+	ingestURL  string
 	httpClient *http.Client
 	authToken  string
+	// The context used by methods that don't take one
+	ctx context.Context
+
+	maxConcurrent int
+	pollInterval  time.Duration
+
+	cache     *responseCache
+	signer    *requestSigner
+	callStats apiCallRecorder
+
+	jsonEncoder JSONEncoder
+	jsonDecoder JSONDecoder
+
+	signalFlowOptions []signalflow.ClientParam
+	// Makes the SignalFlow client used by methods that run programs, so
+	// tests can replace it
+	signalFlowExecutor func() (signalflow.Executor, error)
+
+	defaultDimsLock   sync.RWMutex
+	defaultDimensions map[string]string
 }
 
 // ClientParam is an option for NewClient. Its implementation borrows
@@ -29,49 +65,252 @@ type Client struct {
 // (https://dave.cheney.net/2014/10/17/functional-options-for-friendly-apis).
 type ClientParam func(*Client) error
 
-// NewClient creates a new SignalFx client using the specified token.
+// NewClient creates a new SignalFx client using the specified token.  All
+// options are validated here so that a misconfigured client is reported
+// immediately rather than on first use.
 func NewClient(token string, options ...ClientParam) (*Client, error) {
+	return NewClientWithContext(context.Background(), token, options...)
+}
+
+// NewClientWithContext is like NewClient, but ctx is used by the methods that
+// don't take a context of their own, so that cancelling it cancels any of
+// their requests in flight.  Methods that take a context use theirs instead.
+func NewClientWithContext(ctx context.Context, token string, options ...ClientParam) (*Client, error) {
+	if ctx == nil {
+		return nil, errors.New("context cannot be nil")
+	}
+	if token == "" {
+		return nil, errors.New("token cannot be empty")
+	}
+	return newClient(ctx, token, options)
+}
+
+// NewLoginClient creates a client without a token, for logging in with
+// CreateSessionToken, whose AccessToken can then be passed to NewClient.  The
+// API rejects every other request the client makes.
+func NewLoginClient(options ...ClientParam) (*Client, error) {
+	return newClient(context.Background(), "", options)
+}
+
+func newClient(ctx context.Context, token string, options []ClientParam) (*Client, error) {
 	client := &Client{
-		baseURL: DefaultAPIURL,
+		ctx:       ctx,
+		baseURL:   DefaultAPIURL,
+		ingestURL: DefaultIngestURL,
 		httpClient: &http.Client{
 			Timeout: time.Second * 30,
 		},
-		authToken: token,
+		authToken:     token,
+		maxConcurrent: DefaultMaxConcurrent,
+		pollInterval:  DefaultPollInterval,
+		jsonEncoder:   stdJSON{},
+		jsonDecoder:   stdJSON{},
+	}
+
+	client.signalFlowExecutor = func() (signalflow.Executor, error) {
+		return client.SignalFlow()
 	}
 
 	for _, option := range options {
-		option(client)
+		if err := option(client); err != nil {
+			return nil, err
+		}
 	}
 
 	return client, nil
 }
 
+// MustNewClient is like NewClient but panics if the client cannot be created.
+// It is intended for use in tests and program initialization (e.g. `TestMain`)
+// where the options are known to be valid.
+func MustNewClient(token string, options ...ClientParam) *Client {
+	client, err := NewClient(token, options...)
+	if err != nil {
+		panic(fmt.Sprintf("could not create SignalFx client: %v", err))
+	}
+	return client
+}
+
 // APIUrl sets the URL that our client will communicate with, allowing
 // it to be adjusted to another URL for testing or communication with other
 // SignalFx clusters. Example `"https://api.signalfx.com"`.
 func APIUrl(apiURL string) ClientParam {
 	return func(client *Client) error {
+		u, err := url.Parse(apiURL)
+		if err != nil {
+			return fmt.Errorf("invalid API URL %q: %v", apiURL, err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid API URL %q: must be an absolute http(s) URL", apiURL)
+		}
 		client.baseURL = apiURL
 		return nil
 	}
 }
 
+// IngestURL sets the URL that datapoints are sent to, which defaults to
+// DefaultIngestURL.  Example `"https://ingest.us1.signalfx.com"`.
+func IngestURL(ingestURL string) ClientParam {
+	return func(client *Client) error {
+		u, err := url.Parse(ingestURL)
+		if err != nil {
+			return fmt.Errorf("invalid ingest URL %q: %v", ingestURL, err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid ingest URL %q: must be an absolute http(s) URL", ingestURL)
+		}
+		client.ingestURL = ingestURL
+		return nil
+	}
+}
+
 // HTTPClient sets the `http.Client` that this API client will use to
 // to communicate. This allows you to replace the client or tune it to your
 // needs.
 func HTTPClient(httpClient *http.Client) ClientParam {
 	return func(client *Client) error {
+		if httpClient == nil {
+			return errors.New("HTTPClient cannot be nil")
+		}
 		client.httpClient = httpClient
 		return nil
 	}
 }
 
+// Timeout sets the overall time limit for each request made by the client,
+// which defaults to 30 seconds.  If HTTPClient is also used, this must come
+// after it.  The `http.Client` passed to HTTPClient is copied rather than
+// modified.
+func Timeout(timeout time.Duration) ClientParam {
+	return func(client *Client) error {
+		if timeout <= 0 {
+			return errors.New("Timeout cannot be <= 0")
+		}
+		httpClient := *client.httpClient
+		httpClient.Timeout = timeout
+		client.httpClient = &httpClient
+		return nil
+	}
+}
+
+// MaxConcurrent sets the number of requests that bulk operations such as
+// BulkDeleteDetectors have in flight at once, which defaults to
+// DefaultMaxConcurrent.
+func MaxConcurrent(n int) ClientParam {
+	return func(client *Client) error {
+		if n <= 0 {
+			return errors.New("MaxConcurrent cannot be <= 0")
+		}
+		client.maxConcurrent = n
+		return nil
+	}
+}
+
+// PollInterval sets the time between requests made by methods that wait for
+// something to happen, such as WaitForDetectorAlert, which defaults to
+// DefaultPollInterval.
+func PollInterval(interval time.Duration) ClientParam {
+	return func(client *Client) error {
+		if interval <= 0 {
+			return errors.New("PollInterval cannot be <= 0")
+		}
+		client.pollInterval = interval
+		return nil
+	}
+}
+
+// SignalFlowOptions sets options, such as signalflow.StreamURLForRealm, that
+// are used for every SignalFlow client the client creates, both by the
+// SignalFlow method and by methods that run programs like GetChartDataFrame.
+func SignalFlowOptions(options ...signalflow.ClientParam) ClientParam {
+	return func(client *Client) error {
+		client.signalFlowOptions = append(client.signalFlowOptions, options...)
+		return nil
+	}
+}
+
+// SetDefaultDimensions sets dimensions that are added to every datapoint and
+// event sent by the client with SendDatapoints and SendEvents, such as
+// `service`, `environment` or `cluster`.
+// Dimensions set on an individual datapoint or event take precedence over
+// these defaults.  Calling it again replaces the previous defaults, and a nil
+// map clears them.
+func (c *Client) SetDefaultDimensions(dims map[string]string) {
+	copied := make(map[string]string, len(dims))
+	for k, v := range dims {
+		copied[k] = v
+	}
+
+	c.defaultDimsLock.Lock()
+	c.defaultDimensions = copied
+	c.defaultDimsLock.Unlock()
+}
+
+// withDefaultDimensions returns a new map with the client's default
+// dimensions merged under dims.  dims itself is not modified.
+func (c *Client) withDefaultDimensions(dims map[string]string) map[string]string {
+	c.defaultDimsLock.RLock()
+	defer c.defaultDimsLock.RUnlock()
+
+	out := make(map[string]string, len(c.defaultDimensions)+len(dims))
+	for k, v := range c.defaultDimensions {
+		out[k] = v
+	}
+	for k, v := range dims {
+		out[k] = v
+	}
+	return out
+}
+
+// forEachConcurrently calls fn for each index up to n, with at most
+// c.maxConcurrent calls running at once, and waits for them all to finish.
+func (c *Client) forEachConcurrently(n int, fn func(i int)) {
+	sem := make(chan struct{}, c.maxConcurrent)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
 func (c *Client) doRequest(method string, path string, params url.Values, body io.Reader) (*http.Response, error) {
-	return c.doRequestWithToken(method, path, params, body, c.authToken)
+	return c.doRequestWithContext(c.ctx, method, path, params, body)
 }
 
-func (c *Client) doRequestWithToken(method string, path string, params url.Values, body io.Reader, token string) (*http.Response, error) {
-	destURL, err := url.Parse(c.baseURL)
+func (c *Client) doRequestWithContext(ctx context.Context, method string, path string, params url.Values, body io.Reader) (*http.Response, error) {
+	return c.doRequestWithToken(ctx, method, path, params, body, c.authToken)
+}
+
+func (c *Client) doRequestWithToken(ctx context.Context, method string, path string, params url.Values, body io.Reader, token string) (*http.Response, error) {
+	return c.doRequestToBase(ctx, c.baseURL, method, path, params, body, token)
+}
+
+func (c *Client) doRequestToBase(ctx context.Context, baseURL string, method string, path string, params url.Values, body io.Reader, token string) (*http.Response, error) {
+	req, err := c.newRequest(ctx, baseURL, method, path, params, body, token)
+	if err != nil {
+		return nil, err
+	}
+
+	send := func(req *http.Request) (*http.Response, error) {
+		return c.callStats.do(req, c.httpClient.Do)
+	}
+	if c.cache != nil {
+		return c.cache.do(req, send)
+	}
+	return send(req)
+}
+
+// newRequest builds an authenticated JSON request for path on baseURL.
+func (c *Client) newRequest(ctx context.Context, baseURL string, method string, path string, params url.Values, body io.Reader, token string) (*http.Request, error) {
+	destURL, err := url.Parse(baseURL)
 	if err != nil {
 		return nil, err
 	}
@@ -81,20 +320,29 @@ func (c *Client) doRequestWithToken(method string, path string, params url.Value
 		destURL.RawQuery = params.Encode()
 	}
 	req, err := http.NewRequest(method, destURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
 	if token != "" {
 		req.Header.Set(AuthHeaderKey, token)
 	}
 	req.Header.Set("Content-Type", "application/json")
-	if err != nil {
-		return nil, err
-	}
 
-	return c.httpClient.Do(req)
+	req = req.WithContext(ctx)
+	if c.signer != nil {
+		if err := c.signer.sign(req); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
 }
 
 // SignalFlow creates and returns a SignalFlow client that can be used to
-// execute streaming jobs.
+// execute streaming jobs.  options are applied after any set with
+// SignalFlowOptions.
 func (c *Client) SignalFlow(options ...signalflow.ClientParam) (*signalflow.Client, error) {
-	options = append(options, signalflow.AccessToken(c.authToken))
-	return signalflow.NewClient(options...)
+	all := append([]signalflow.ClientParam{}, c.signalFlowOptions...)
+	all = append(all, options...)
+	all = append(all, signalflow.AccessToken(c.authToken))
+	return signalflow.NewClient(all...)
 }
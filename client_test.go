@@ -1,13 +1,16 @@
 package signalfx
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
+	"github.com/adampetrovic/signalfx-go/signalflow"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -31,7 +34,7 @@ func setup() func() {
 	mux = http.NewServeMux()
 	server = httptest.NewServer(mux)
 
-	client, _ = NewClient(TestToken, APIUrl(server.URL))
+	client = MustNewClient(TestToken, APIUrl(server.URL), IngestURL(server.URL))
 
 	return func() {
 		server.Close()
@@ -70,3 +73,85 @@ func verifyRequest(t *testing.T, method string, status int, params url.Values, r
 		}
 	}
 }
+
+func TestNewClient(t *testing.T) {
+	c, err := NewClient(TestToken, APIUrl("https://api.us1.signalfx.com"), HTTPClient(&http.Client{}), Timeout(5*time.Second))
+	assert.NoError(t, err, "Unexpected error creating client")
+	assert.Equal(t, "https://api.us1.signalfx.com", c.baseURL, "Base URL does not match")
+	assert.Equal(t, 5*time.Second, c.httpClient.Timeout, "Timeout does not match")
+	assert.Equal(t, DefaultMaxConcurrent, c.maxConcurrent, "MaxConcurrent does not match")
+}
+
+func TestTimeoutCopiesHTTPClient(t *testing.T) {
+	httpClient := &http.Client{Timeout: time.Minute}
+	c, err := NewClient(TestToken, HTTPClient(httpClient), Timeout(5*time.Second))
+	assert.NoError(t, err, "Unexpected error creating client")
+	assert.Equal(t, 5*time.Second, c.httpClient.Timeout, "Timeout does not match")
+	assert.Equal(t, time.Minute, httpClient.Timeout, "The caller's HTTP client should not be modified")
+}
+
+func TestNewClientWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c, err := NewClientWithContext(ctx, TestToken)
+	assert.NoError(t, err, "Unexpected error creating client")
+	_, err = c.GetChart("string")
+	assert.Error(t, err, "Should get an error once the client's context is cancelled")
+}
+
+func TestSignalFlowOptions(t *testing.T) {
+	c, err := NewClient(TestToken, SignalFlowOptions(signalflow.StreamURL("%zz")))
+	assert.NoError(t, err, "Options should only be applied when SignalFlow is called")
+
+	_, err = c.SignalFlow()
+	assert.Error(t, err, "Should get an error from the invalid stream URL")
+}
+
+func TestNewClientInvalidOptions(t *testing.T) {
+	_, err := NewClient("")
+	assert.Error(t, err, "Should get an error with an empty token")
+
+	for _, apiURL := range []string{"", "api.signalfx.com", "ftp://api.signalfx.com", "https://", "http://%zz"} {
+		_, err = NewClient(TestToken, APIUrl(apiURL))
+		assert.Error(t, err, "Should get an error with API URL %q", apiURL)
+		_, err = NewClient(TestToken, IngestURL(apiURL))
+		assert.Error(t, err, "Should get an error with ingest URL %q", apiURL)
+	}
+
+	_, err = NewClientWithContext(nil, TestToken)
+	assert.Error(t, err, "Should get an error with a nil context")
+
+	_, err = NewClient(TestToken, Timeout(0))
+	assert.Error(t, err, "Should get an error with a zero timeout")
+
+	_, err = NewClient(TestToken, HTTPClient(nil))
+	assert.Error(t, err, "Should get an error with a nil HTTP client")
+
+	_, err = NewClient(TestToken, MaxConcurrent(0))
+	assert.Error(t, err, "Should get an error with zero max concurrency")
+}
+
+func TestMustNewClient(t *testing.T) {
+	assert.NotNil(t, MustNewClient(TestToken))
+	assert.Panics(t, func() { MustNewClient("") }, "Should panic with an empty token")
+	assert.Panics(t, func() { MustNewClient(TestToken, Timeout(0)) }, "Should panic with an invalid option")
+}
+
+func TestSetDefaultDimensions(t *testing.T) {
+	c := MustNewClient(TestToken)
+	assert.Equal(t, map[string]string{"host": "a"}, c.withDefaultDimensions(map[string]string{"host": "a"}))
+
+	defaults := map[string]string{"service": "api", "environment": "prod"}
+	c.SetDefaultDimensions(defaults)
+	// Changes to the caller's map after the fact shouldn't leak in.
+	defaults["cluster"] = "east"
+
+	dims := map[string]string{"environment": "staging", "host": "a"}
+	merged := c.withDefaultDimensions(dims)
+	assert.Equal(t, map[string]string{"service": "api", "environment": "staging", "host": "a"}, merged, "Request dimensions should override defaults")
+	assert.Equal(t, map[string]string{"environment": "staging", "host": "a"}, dims, "Request dimensions should not be modified")
+
+	c.SetDefaultDimensions(nil)
+	assert.Equal(t, map[string]string{"host": "a"}, c.withDefaultDimensions(map[string]string{"host": "a"}))
+}
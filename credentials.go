@@ -0,0 +1,29 @@
+package signalfx
+
+import (
+	"context"
+
+	"github.com/adampetrovic/signalfx-go/credentials"
+)
+
+// CredentialsAPIURL is the base URL for stored credentials.
+const CredentialsAPIURL = "/v2/credentials"
+
+// GetCredentials gets a stored credential, without its secrets.
+func (c *Client) GetCredentials(ctx context.Context, credentialID string) (*credentials.Credential, error) {
+	cred := &credentials.Credential{}
+	if err := c.getJSON(ctx, CredentialsAPIURL+"/"+credentialID, nil, cred); err != nil {
+		return nil, err
+	}
+	return cred, nil
+}
+
+// CreateCredentials stores a new credential.  The returned Credential has its
+// ID for integrations to reference, but not the secrets.
+func (c *Client) CreateCredentials(ctx context.Context, req *credentials.CreateRequest) (*credentials.Credential, error) {
+	cred := &credentials.Credential{}
+	if err := c.sendJSON(ctx, "POST", CredentialsAPIURL, nil, req, cred); err != nil {
+		return nil, err
+	}
+	return cred, nil
+}
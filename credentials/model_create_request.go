@@ -0,0 +1,18 @@
+package credentials
+
+// CreateRequest stores a new credential.  Only the secret fields used by Type
+// need to be set.  The secrets are write-only: they aren't part of the
+// Credential that is returned.
+type CreateRequest struct {
+	Name string `json:"name"`
+	Type Type   `json:"type"`
+	// The API key for PagerDuty, Opsgenie and VictorOps
+	APIKey string `json:"apiKey,omitempty"`
+	// The incoming webhook URL for Slack
+	WebhookURL string `json:"webhookUrl,omitempty"`
+	// The user and API token for Jira
+	UserEmail string `json:"userEmail,omitempty"`
+	APIToken  string `json:"apiToken,omitempty"`
+	// The shared secret for signing webhook payloads
+	SharedSecret string `json:"sharedSecret,omitempty"`
+}
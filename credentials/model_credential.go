@@ -0,0 +1,61 @@
+package credentials
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Type is the kind of service a credential authenticates with.
+type Type string
+
+// The services credentials can be stored for.
+const (
+	PagerDuty Type = "PAGERDUTY"
+	Slack     Type = "SLACK"
+	Jira      Type = "JIRA"
+	Opsgenie  Type = "OPSGENIE"
+	VictorOps Type = "VICTOROPS"
+	Webhook   Type = "WEBHOOK"
+)
+
+// A stored credential that integrations can reference by ID.  Its secrets are
+// never returned by the API.
+type Credential struct {
+	// The SignalFx-assigned ID of the credential
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	Type Type   `json:"type,omitempty"`
+	// When the credential was created
+	Created time.Time `json:"-"`
+}
+
+type credentialJSON struct {
+	ID      string `json:"id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Type    Type   `json:"type,omitempty"`
+	Created int64  `json:"created,omitempty"`
+}
+
+// MarshalJSON encodes the credential with its creation time in Unix
+// milliseconds, as the API does.
+func (c Credential) MarshalJSON() ([]byte, error) {
+	raw := credentialJSON{ID: c.ID, Name: c.Name, Type: c.Type}
+	if !c.Created.IsZero() {
+		raw.Created = c.Created.UnixNano() / int64(time.Millisecond)
+	}
+	return json.Marshal(raw)
+}
+
+// UnmarshalJSON decodes a credential with its creation time in Unix
+// milliseconds.
+func (c *Credential) UnmarshalJSON(data []byte) error {
+	var raw credentialJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*c = Credential{ID: raw.ID, Name: raw.Name, Type: raw.Type}
+	if raw.Created != 0 {
+		c.Created = time.Unix(0, raw.Created*int64(time.Millisecond)).UTC()
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package signalfx
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/credentials"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCredentials(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/credentials/CrEd1", verifyRequest(t, "GET", http.StatusOK, nil, "credentials/get_success.json"))
+
+	result, err := client.GetCredentials(context.Background(), "CrEd1")
+	assert.NoError(t, err, "Unexpected error getting credentials")
+	assert.Equal(t, "CrEd1", result.ID)
+	assert.Equal(t, credentials.PagerDuty, result.Type)
+	assert.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), result.Created)
+}
+
+func TestCreateCredentials(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/credentials", verifyRequest(t, "POST", http.StatusOK, nil, "credentials/get_success.json"))
+
+	result, err := client.CreateCredentials(context.Background(), &credentials.CreateRequest{
+		Name:   "On-call PagerDuty",
+		Type:   credentials.PagerDuty,
+		APIKey: "secret",
+	})
+	assert.NoError(t, err, "Unexpected error creating credentials")
+	assert.Equal(t, "CrEd1", result.ID)
+}
+
+func TestGetMissingCredentials(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/credentials/CrEd1", verifyRequest(t, "GET", http.StatusNotFound, nil, ""))
+
+	result, err := client.GetCredentials(context.Background(), "CrEd1")
+	assert.Error(t, err, "Should get an error for a missing credential")
+	assert.Nil(t, result)
+}
@@ -2,9 +2,7 @@ package signalfx
 
 import (
 	"bytes"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
+	"context"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -19,7 +17,7 @@ const DashboardAPIURL = "/v2/dashboard"
 
 // CreateDashboard creates a dashboard.
 func (c *Client) CreateDashboard(dashboardRequest *dashboard.CreateUpdateDashboardRequest) (*dashboard.Dashboard, error) {
-	payload, err := json.Marshal(dashboardRequest)
+	payload, err := c.marshal(dashboardRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -31,13 +29,12 @@ func (c *Client) CreateDashboard(dashboardRequest *dashboard.CreateUpdateDashboa
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalDashboard := &dashboard.Dashboard{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalDashboard)
+	err = c.decodeJSON(resp.Body, finalDashboard)
 
 	return finalDashboard, err
 }
@@ -52,8 +49,7 @@ func (c *Client) DeleteDashboard(id string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return newAPIError(resp)
 	}
 
 	return nil
@@ -61,7 +57,18 @@ func (c *Client) DeleteDashboard(id string) error {
 
 // GetDashboard gets a dashboard.
 func (c *Client) GetDashboard(id string) (*dashboard.Dashboard, error) {
-	resp, err := c.doRequest("GET", DashboardAPIURL+"/"+id, nil, nil)
+	return c.getDashboard(c.ctx, id, false)
+}
+
+// GetDashboardWithContext gets a dashboard using ctx.  If forceRefresh is
+// true, the response cache is skipped, though the response still replaces
+// whatever was cached.
+func (c *Client) GetDashboardWithContext(ctx context.Context, id string, forceRefresh bool) (*dashboard.Dashboard, error) {
+	return c.getDashboard(ctx, id, forceRefresh)
+}
+
+func (c *Client) getDashboard(ctx context.Context, id string, forceRefresh bool) (*dashboard.Dashboard, error) {
+	resp, err := c.doRequestWithContext(cacheable(ctx, forceRefresh), "GET", DashboardAPIURL+"/"+id, nil, nil)
 
 	if err != nil {
 		return nil, err
@@ -69,38 +76,40 @@ func (c *Client) GetDashboard(id string) (*dashboard.Dashboard, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalDashboard := &dashboard.Dashboard{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalDashboard)
+	err = c.decodeJSON(resp.Body, finalDashboard)
 
 	return finalDashboard, err
 }
 
 // UpdateDashboard updates a dashboard.
 func (c *Client) UpdateDashboard(id string, dashboardRequest *dashboard.CreateUpdateDashboardRequest) (*dashboard.Dashboard, error) {
-	payload, err := json.Marshal(dashboardRequest)
+	return c.updateDashboard(c.ctx, id, dashboardRequest)
+}
+
+func (c *Client) updateDashboard(ctx context.Context, id string, dashboardRequest *dashboard.CreateUpdateDashboardRequest) (*dashboard.Dashboard, error) {
+	payload, err := c.marshal(dashboardRequest)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.doRequest("PUT", DashboardAPIURL+"/"+id, nil, bytes.NewReader(payload))
+	resp, err := c.doRequestWithContext(ctx, "PUT", DashboardAPIURL+"/"+id, nil, bytes.NewReader(payload))
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalDashboard := &dashboard.Dashboard{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalDashboard)
+	err = c.decodeJSON(resp.Body, finalDashboard)
 
 	return finalDashboard, err
 }
@@ -122,7 +131,7 @@ func (c *Client) SearchDashboard(limit int, name string, offset int, tags string
 
 	finalDashboards := &dashboard.SearchResult{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalDashboards)
+	err = c.decodeJSON(resp.Body, finalDashboards)
 
 	return finalDashboards, err
 }
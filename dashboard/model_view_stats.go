@@ -0,0 +1,42 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ViewStats is how often and how recently a dashboard has been viewed.
+type ViewStats struct {
+	DashboardID string `json:"dashboardId"`
+	// When the dashboard was last viewed, or nil if it never has been
+	LastViewedAt *time.Time `json:"-"`
+	ViewCount    int        `json:"viewCount"`
+}
+
+// UnmarshalJSON decodes view stats, whose last viewed time is in milliseconds
+// since the epoch, or 0 if the dashboard hasn't been viewed.
+func (s *ViewStats) UnmarshalJSON(b []byte) error {
+	type Alias ViewStats
+	aux := struct {
+		*Alias
+		LastViewedMS int64 `json:"lastViewed"`
+	}{Alias: (*Alias)(s)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	s.LastViewedAt = nil
+	if aux.LastViewedMS > 0 {
+		viewed := time.Unix(0, aux.LastViewedMS*int64(time.Millisecond))
+		s.LastViewedAt = &viewed
+	}
+	return nil
+}
+
+// DashboardSummary is a dashboard along with its view stats.
+type DashboardSummary struct {
+	Dashboard
+	// When the dashboard was last viewed, or nil if it never has been or
+	// view stats aren't available
+	LastViewedAt *time.Time `json:"lastViewedAt,omitempty"`
+	ViewCount    int        `json:"viewCount"`
+}
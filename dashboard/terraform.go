@@ -0,0 +1,244 @@
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/adampetrovic/signalfx-go/chart"
+	"github.com/adampetrovic/signalfx-go/internal/hcl"
+)
+
+// RenderMode controls how ToTerraformHCL handles the dashboard's charts.
+type RenderMode int
+
+const (
+	// RenderInline writes a chart block for each chart that refers to the
+	// existing chart by ID, leaving the charts themselves out of Terraform.
+	RenderInline RenderMode = iota
+	// RenderSeparate also writes a resource for each chart, such as a
+	// signalfx_time_chart, and refers to it from the chart block.
+	RenderSeparate
+)
+
+// The Terraform resource for each chart type.
+var terraformChartResources = map[string]string{
+	"TimeSeriesChart": "signalfx_time_chart",
+	"SingleValue":     "signalfx_single_value_chart",
+	"List":            "signalfx_list_chart",
+	"Heatmap":         "signalfx_heatmap_chart",
+	"TableChart":      "signalfx_table_chart",
+	"Text":            "signalfx_text_chart",
+	"Event":           "signalfx_event_feed_chart",
+}
+
+// ToTerraformHCL returns a signalfx_dashboard resource for the dashboard, for
+// importing it into Terraform, with its charts referred to by ID as for
+// RenderInline.
+func (d *Dashboard) ToTerraformHCL() (string, error) {
+	return d.TerraformHCL(RenderInline, nil)
+}
+
+// TerraformHCL returns a signalfx_dashboard resource for the dashboard, named
+// after it.  For RenderSeparate, charts must have every chart on the
+// dashboard keyed by ID, and a resource is written before the dashboard for
+// each one with its program and common options.  Durations are converted
+// from the API's milliseconds to the seconds that Terraform uses.
+func (d *Dashboard) TerraformHCL(mode RenderMode, charts map[string]*chart.Chart) (string, error) {
+	if d.Name == "" {
+		return "", errors.New("dashboard has no name")
+	}
+
+	var out strings.Builder
+	chartIDs := make(map[string]interface{}, len(d.Charts))
+	if mode == RenderSeparate {
+		names := map[string]bool{}
+		for _, dc := range d.Charts {
+			if _, ok := chartIDs[dc.ChartId]; ok {
+				continue
+			}
+			c, ok := charts[dc.ChartId]
+			if !ok {
+				return "", fmt.Errorf("chart %s isn't in charts", dc.ChartId)
+			}
+			res, ref, err := terraformChart(c, names)
+			if err != nil {
+				return "", fmt.Errorf("chart %s: %v", dc.ChartId, err)
+			}
+			out.WriteString(res.String() + "\n")
+			chartIDs[dc.ChartId] = hcl.Expr(ref + ".id")
+		}
+	} else {
+		for _, dc := range d.Charts {
+			chartIDs[dc.ChartId] = dc.ChartId
+		}
+	}
+
+	res := hcl.NewBlock("resource", "signalfx_dashboard", hcl.Identifier(d.Name))
+	res.Attr("name", d.Name)
+	if d.Description != "" {
+		res.Attr("description", d.Description)
+	}
+	res.Attr("dashboard_group", d.GroupId)
+	if d.ChartDensity != nil && *d.ChartDensity != DEFAULT {
+		res.Attr("charts_resolution", strings.ToLower(string(*d.ChartDensity)))
+	}
+	if d.MaxDelayOverride != nil {
+		res.Attr("max_delay_override", *d.MaxDelayOverride/1000)
+	}
+	if len(d.Tags) > 0 {
+		res.Attr("tags", d.Tags)
+	}
+	if aw := d.AuthorizedWriters; aw != nil {
+		if len(aw.Teams) > 0 {
+			res.Attr("authorized_writer_teams", aw.Teams)
+		}
+		if len(aw.Users) > 0 {
+			res.Attr("authorized_writer_users", aw.Users)
+		}
+	}
+
+	if f := d.Filters; f != nil {
+		if t := f.Time; t != nil {
+			if err := terraformTimeFilter(res, t); err != nil {
+				return "", err
+			}
+		}
+		for _, v := range f.Variables {
+			variable := res.Block("variable")
+			variable.Attr("property", v.Property)
+			variable.Attr("alias", v.Alias)
+			if v.Description != "" {
+				variable.Attr("description", v.Description)
+			}
+			variable.Attr("values", []string(v.Value))
+			if v.Required {
+				variable.Attr("value_required", true)
+			}
+			if len(v.PreferredSuggestions) > 0 {
+				variable.Attr("values_suggested", v.PreferredSuggestions)
+			}
+			if v.Restricted {
+				variable.Attr("restricted_suggestions", true)
+			}
+			if v.ReplaceOnly {
+				variable.Attr("replace_only", true)
+			}
+			if v.ApplyIfExists {
+				variable.Attr("apply_if_exist", true)
+			}
+		}
+		for _, s := range f.Sources {
+			filter := res.Block("filter")
+			filter.Attr("property", s.Property)
+			if s.NOT {
+				filter.Attr("negated", true)
+			}
+			filter.Attr("values", []string(s.Value))
+			if s.ApplyIfExists {
+				filter.Attr("apply_if_exist", true)
+			}
+		}
+	}
+
+	for _, dc := range d.Charts {
+		res.Block("chart").
+			Attr("chart_id", chartIDs[dc.ChartId]).
+			Attr("row", dc.Row).
+			Attr("column", dc.Column).
+			Attr("width", dc.Width).
+			Attr("height", dc.Height)
+	}
+
+	out.WriteString(res.String())
+	return out.String(), nil
+}
+
+// terraformTimeFilter adds the dashboard's time range, which is either
+// relative, like "-1h", or absolute, in milliseconds.
+func terraformTimeFilter(res *hcl.Block, t *ChartsFiltersTime) error {
+	start, end := string(t.Start), string(t.End)
+	if start == "" {
+		return nil
+	}
+	startMs, err := strconv.ParseInt(start, 10, 64)
+	if err != nil {
+		res.Attr("time_range", start)
+		return nil
+	}
+	res.Attr("start_time", startMs/1000)
+	if end != "" && end != "Now" {
+		endMs, err := strconv.ParseInt(end, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid time filter end %q", end)
+		}
+		res.Attr("end_time", endMs/1000)
+	}
+	return nil
+}
+
+// terraformChart returns the resource for a chart, named after it with a
+// suffix if the name is already in names, and the resource's address.
+func terraformChart(c *chart.Chart, names map[string]bool) (*hcl.Block, string, error) {
+	if c.Options == nil {
+		return nil, "", errors.New("chart has no options")
+	}
+	typ, ok := terraformChartResources[c.Options.Type]
+	if !ok {
+		return nil, "", fmt.Errorf("unsupported chart type %q", c.Options.Type)
+	}
+
+	base := hcl.Identifier(c.Name)
+	name := base
+	for n := 2; names[typ+"."+name]; n++ {
+		name = base + "_" + strconv.Itoa(n)
+	}
+	names[typ+"."+name] = true
+
+	o := c.Options
+	res := hcl.NewBlock("resource", typ, name)
+	res.Attr("name", c.Name)
+	if c.Description != "" {
+		res.Attr("description", c.Description)
+	}
+	if o.Type == "Text" {
+		res.Attr("markdown", o.Markdown)
+		return res, typ + "." + name, nil
+	}
+	res.Attr("program_text", c.ProgramText)
+
+	switch o.Type {
+	case "TimeSeriesChart":
+		if o.DefaultPlotType != "" {
+			res.Attr("plot_type", o.DefaultPlotType)
+		}
+		if o.Stacked {
+			res.Attr("stacked", true)
+		}
+		if o.ShowEventLines {
+			res.Attr("show_event_lines", true)
+		}
+		if o.Time != nil && o.Time.Range != nil {
+			res.Attr("time_range", *o.Time.Range/1000)
+		}
+	case "SingleValue", "List":
+		if o.ColorBy != "" {
+			res.Attr("color_by", o.ColorBy)
+		}
+		if o.MaximumPrecision != nil {
+			res.Attr("max_precision", *o.MaximumPrecision)
+		}
+		if o.Type == "List" && o.SortBy != "" {
+			res.Attr("sort_by", o.SortBy)
+		}
+	case "Heatmap", "TableChart":
+		if len(o.GroupBy) > 0 {
+			res.Attr("group_by", o.GroupBy)
+		}
+	}
+	if o.UnitPrefix != "" && o.Type != "Event" {
+		res.Attr("unit_prefix", o.UnitPrefix)
+	}
+	return res, typ + "." + name, nil
+}
@@ -0,0 +1,140 @@
+package dashboard
+
+import (
+	"testing"
+
+	"github.com/adampetrovic/signalfx-go/chart"
+	"github.com/adampetrovic/signalfx-go/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func testDashboard() *Dashboard {
+	density := HIGH
+	return &Dashboard{
+		Name:         "Hosts",
+		GroupId:      "GrOuP1",
+		ChartDensity: &density,
+		Filters: &ChartsFilters{
+			Time: &ChartsFiltersTime{Start: "-1h", End: "Now"},
+			Variables: []*ChartsWebUiFilter{{
+				Property: "host",
+				Alias:    "Host",
+				Value:    util.StringOrSlice{"a"},
+				Required: true,
+			}},
+			Sources: []*ChartsSingleFilter{{
+				Property: "env",
+				NOT:      true,
+				Value:    util.StringOrSlice{"dev", "test"},
+			}},
+		},
+		Charts: []*DashboardChart{
+			{ChartId: "ChA", Row: 0, Column: 0, Width: 6, Height: 1},
+			{ChartId: "ChB", Row: 0, Column: 6, Width: 6, Height: 1},
+		},
+	}
+}
+
+func TestDashboardToTerraformHCL(t *testing.T) {
+	hcl, err := testDashboard().ToTerraformHCL()
+	assert.NoError(t, err)
+	assert.Equal(t, `resource "signalfx_dashboard" "hosts" {
+  name              = "Hosts"
+  dashboard_group   = "GrOuP1"
+  charts_resolution = "high"
+  time_range        = "-1h"
+
+  variable {
+    property       = "host"
+    alias          = "Host"
+    values         = ["a"]
+    value_required = true
+  }
+
+  filter {
+    property = "env"
+    negated  = true
+    values   = ["dev", "test"]
+  }
+
+  chart {
+    chart_id = "ChA"
+    row      = 0
+    column   = 0
+    width    = 6
+    height   = 1
+  }
+
+  chart {
+    chart_id = "ChB"
+    row      = 0
+    column   = 6
+    width    = 6
+    height   = 1
+  }
+}
+`, hcl)
+}
+
+func TestDashboardTerraformHCLSeparate(t *testing.T) {
+	d := testDashboard()
+	d.Filters = nil
+	d.ChartDensity = nil
+	charts := map[string]*chart.Chart{
+		"ChA": {Name: "CPU", ProgramText: "data('cpu.utilization').publish()", Options: &chart.Options{Type: "TimeSeriesChart", DefaultPlotType: "LineChart"}},
+		"ChB": {Name: "Notes", Options: &chart.Options{Type: "Text", Markdown: "Some **notes**"}},
+	}
+
+	hcl, err := d.TerraformHCL(RenderSeparate, charts)
+	assert.NoError(t, err)
+	assert.Equal(t, `resource "signalfx_time_chart" "cpu" {
+  name         = "CPU"
+  program_text = "data('cpu.utilization').publish()"
+  plot_type    = "LineChart"
+}
+
+resource "signalfx_text_chart" "notes" {
+  name     = "Notes"
+  markdown = "Some **notes**"
+}
+
+resource "signalfx_dashboard" "hosts" {
+  name            = "Hosts"
+  dashboard_group = "GrOuP1"
+
+  chart {
+    chart_id = signalfx_time_chart.cpu.id
+    row      = 0
+    column   = 0
+    width    = 6
+    height   = 1
+  }
+
+  chart {
+    chart_id = signalfx_text_chart.notes.id
+    row      = 0
+    column   = 6
+    width    = 6
+    height   = 1
+  }
+}
+`, hcl)
+
+	delete(charts, "ChB")
+	_, err = d.TerraformHCL(RenderSeparate, charts)
+	assert.Error(t, err, "Should need every chart")
+}
+
+func TestTerraformChartNames(t *testing.T) {
+	names := map[string]bool{}
+	c := &chart.Chart{Name: "CPU", Options: &chart.Options{Type: "List"}}
+	_, first, err := terraformChart(c, names)
+	assert.NoError(t, err)
+	_, second, err := terraformChart(c, names)
+	assert.NoError(t, err)
+	assert.Equal(t, "signalfx_list_chart.cpu", first)
+	assert.Equal(t, "signalfx_list_chart.cpu_2", second)
+
+	_, _, err = terraformChart(&chart.Chart{Name: "x", Options: &chart.Options{Type: "Pie"}}, names)
+	assert.Error(t, err, "Should reject unknown chart types")
+}
@@ -0,0 +1,22 @@
+package dashboard
+
+// UpdateRequest returns a request that would recreate the dashboard as it
+// is, for read-modify-write updates.
+func (d *Dashboard) UpdateRequest() *CreateUpdateDashboardRequest {
+	req := &CreateUpdateDashboardRequest{
+		AuthorizedWriters:     d.AuthorizedWriters,
+		Charts:                d.Charts,
+		Description:           d.Description,
+		EventOverlays:         d.EventOverlays,
+		Filters:               d.Filters,
+		GroupId:               d.GroupId,
+		MaxDelayOverride:      d.MaxDelayOverride,
+		Name:                  d.Name,
+		SelectedEventOverlays: d.SelectedEventOverlays,
+		Tags:                  d.Tags,
+	}
+	if d.ChartDensity != nil {
+		req.ChartDensity = *d.ChartDensity
+	}
+	return req
+}
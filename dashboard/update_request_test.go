@@ -0,0 +1,28 @@
+package dashboard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDashboardUpdateRequest(t *testing.T) {
+	density := DashboardChartDensity("HIGH")
+	d := &Dashboard{
+		Id:           "Dash1",
+		Name:         "Overview",
+		GroupId:      "Group1",
+		ChartDensity: &density,
+		Charts:       []*DashboardChart{{ChartId: "Chart1"}},
+		Tags:         []string{"prod"},
+	}
+
+	req := d.UpdateRequest()
+	assert.Equal(t, "Overview", req.Name)
+	assert.Equal(t, "Group1", req.GroupId)
+	assert.Equal(t, density, req.ChartDensity)
+	assert.Equal(t, d.Charts, req.Charts)
+	assert.Equal(t, d.Tags, req.Tags)
+
+	assert.Equal(t, DashboardChartDensity(""), (&Dashboard{}).UpdateRequest().ChartDensity)
+}
@@ -0,0 +1,48 @@
+package dashboard_group
+
+import (
+	"strings"
+
+	"github.com/adampetrovic/signalfx-go/dashboard"
+)
+
+// FindDashboard looks through dashboards, which the caller has fetched, for
+// one in the group named name, ignoring case. Dashboards that aren't in the
+// group are skipped, so it's fine to pass every dashboard in the org.
+func (g *DashboardGroup) FindDashboard(name string, dashboards ...*dashboard.Dashboard) (*dashboard.Dashboard, bool) {
+	for _, d := range g.members(dashboards) {
+		if strings.EqualFold(d.Name, name) {
+			return d, true
+		}
+	}
+	return nil, false
+}
+
+// FindDashboardByID looks through dashboards, which the caller has fetched,
+// for the group's dashboard with the given ID.
+func (g *DashboardGroup) FindDashboardByID(id string, dashboards ...*dashboard.Dashboard) (*dashboard.Dashboard, bool) {
+	for _, d := range g.members(dashboards) {
+		if d.Id == id {
+			return d, true
+		}
+	}
+	return nil, false
+}
+
+// members returns the dashboards that belong to the group, in group order.
+func (g *DashboardGroup) members(dashboards []*dashboard.Dashboard) []*dashboard.Dashboard {
+	byID := make(map[string]*dashboard.Dashboard, len(dashboards))
+	for _, d := range dashboards {
+		if d != nil {
+			byID[d.Id] = d
+		}
+	}
+
+	var out []*dashboard.Dashboard
+	for _, id := range g.Dashboards {
+		if d, ok := byID[id]; ok {
+			out = append(out, d)
+		}
+	}
+	return out
+}
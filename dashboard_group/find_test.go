@@ -0,0 +1,34 @@
+package dashboard_group
+
+import (
+	"testing"
+
+	"github.com/adampetrovic/signalfx-go/dashboard"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindDashboard(t *testing.T) {
+	g := &DashboardGroup{Dashboards: []string{"A", "B"}}
+	dashboards := []*dashboard.Dashboard{
+		{Id: "A", Name: "Overview"},
+		{Id: "B", Name: "Latency"},
+		{Id: "C", Name: "Errors"},
+	}
+
+	d, ok := g.FindDashboard("latency", dashboards...)
+	assert.True(t, ok)
+	assert.Equal(t, "B", d.Id)
+
+	_, ok = g.FindDashboard("Errors", dashboards...)
+	assert.False(t, ok, "Dashboards outside the group shouldn't be found")
+
+	_, ok = g.FindDashboard("Overview")
+	assert.False(t, ok, "Nothing can be found without dashboards")
+
+	d, ok = g.FindDashboardByID("A", dashboards...)
+	assert.True(t, ok)
+	assert.Equal(t, "Overview", d.Name)
+
+	_, ok = g.FindDashboardByID("C", dashboards...)
+	assert.False(t, ok, "Dashboards outside the group shouldn't be found")
+}
@@ -0,0 +1,41 @@
+package dashboard_group
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Snapshot is a time-limited link to a view of a dashboard group that can be
+// opened without a SignalFx account.
+type Snapshot struct {
+	Id      string `json:"id,omitempty"`
+	GroupId string `json:"groupId,omitempty"`
+	// The shareable link
+	URL     string    `json:"url,omitempty"`
+	Created time.Time `json:"-"`
+	// When the link stops working
+	Expires time.Time `json:"-"`
+}
+
+// UnmarshalJSON decodes a snapshot, whose times are in milliseconds since the
+// epoch.
+func (s *Snapshot) UnmarshalJSON(b []byte) error {
+	type Alias Snapshot
+	aux := struct {
+		*Alias
+		CreatedMS int64 `json:"created"`
+		ExpiresMS int64 `json:"expires"`
+	}{Alias: (*Alias)(s)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	s.Created = time.Unix(0, aux.CreatedMS*int64(time.Millisecond)).UTC()
+	s.Expires = time.Unix(0, aux.ExpiresMS*int64(time.Millisecond)).UTC()
+	return nil
+}
+
+// SnapshotSearchResult is a page of snapshots.
+type SnapshotSearchResult struct {
+	Count   int32       `json:"count,omitempty"`
+	Results []*Snapshot `json:"results,omitempty"`
+}
@@ -0,0 +1,82 @@
+package signalfx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/dashboard_group"
+)
+
+// DashboardGroupSnapshotAPIURL is the base URL for sharing links to dashboard
+// groups.
+const DashboardGroupSnapshotAPIURL = "/v2/dashboardgroupsnapshot"
+
+// GetDashboardGroupSnapshotURL creates a link to a dashboard group that people
+// without a SignalFx account can open, and that stops working after ttl.  Use
+// CreateDashboardGroupSnapshot instead to get the snapshot's ID, which
+// DeleteDashboardGroupSnapshot takes to revoke the link early.
+func (c *Client) GetDashboardGroupSnapshotURL(ctx context.Context, groupID string, ttl time.Duration) (string, error) {
+	snapshot, err := c.CreateDashboardGroupSnapshot(ctx, groupID, ttl)
+	if err != nil {
+		return "", err
+	}
+	return snapshot.URL, nil
+}
+
+// CreateDashboardGroupSnapshot creates a snapshot of a dashboard group whose
+// URL people without a SignalFx account can open, and that stops working
+// after ttl.
+func (c *Client) CreateDashboardGroupSnapshot(ctx context.Context, groupID string, ttl time.Duration) (*dashboard_group.Snapshot, error) {
+	if ttl < time.Millisecond {
+		return nil, errors.New("snapshot ttl must be at least a millisecond")
+	}
+
+	req := struct {
+		GroupId string `json:"groupId"`
+		TTL     int64  `json:"ttl"`
+	}{groupID, int64(ttl / time.Millisecond)}
+	snapshot := &dashboard_group.Snapshot{}
+	if err := c.sendJSON(ctx, "POST", DashboardGroupSnapshotAPIURL, nil, req, snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// ListDashboardGroupSnapshots gets the snapshots of a dashboard group that
+// haven't expired.
+func (c *Client) ListDashboardGroupSnapshots(ctx context.Context, groupID string) ([]*dashboard_group.Snapshot, error) {
+	params := url.Values{}
+	params.Add("groupId", groupID)
+
+	snapshots := []*dashboard_group.Snapshot{}
+	err := c.forEachPage(ctx, DashboardGroupSnapshotAPIURL, params, func() (interface{}, func() int) {
+		page := &dashboard_group.SnapshotSearchResult{}
+		return page, func() int {
+			snapshots = append(snapshots, page.Results...)
+			return len(page.Results)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+// DeleteDashboardGroupSnapshot deletes a snapshot, so that its link stops
+// working before it expires.
+func (c *Client) DeleteDashboardGroupSnapshot(ctx context.Context, snapshotID string) error {
+	resp, err := c.doRequestWithContext(ctx, "DELETE", DashboardGroupSnapshotAPIURL+"/"+snapshotID, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return newAPIError(resp)
+	}
+
+	return nil
+}
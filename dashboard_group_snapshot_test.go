@@ -0,0 +1,65 @@
+package signalfx
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDashboardGroupSnapshotURL(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/dashboardgroupsnapshot", verifyRequest(t, "POST", http.StatusOK, nil, "dashboardgroup/create_snapshot_success.json"))
+
+	result, err := client.GetDashboardGroupSnapshotURL(context.Background(), "GrOuP1", 24*time.Hour)
+	assert.NoError(t, err, "Unexpected error creating snapshot")
+	assert.Equal(t, "https://app.signalfx.com/#/snapshot/SnAp1", result, "Snapshot URL does not match")
+
+	_, err = client.GetDashboardGroupSnapshotURL(context.Background(), "GrOuP1", 0)
+	assert.Error(t, err, "Should reject a zero ttl")
+}
+
+func TestCreateDashboardGroupSnapshot(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/dashboardgroupsnapshot", verifyRequest(t, "POST", http.StatusOK, nil, "dashboardgroup/create_snapshot_success.json"))
+
+	result, err := client.CreateDashboardGroupSnapshot(context.Background(), "GrOuP1", 24*time.Hour)
+	assert.NoError(t, err, "Unexpected error creating snapshot")
+	assert.Equal(t, "SnAp1", result.Id, "Snapshot ID does not match")
+	assert.Equal(t, "https://app.signalfx.com/#/snapshot/SnAp1", result.URL, "Snapshot URL does not match")
+}
+
+func TestListDashboardGroupSnapshots(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	params := url.Values{}
+	params.Add("groupId", "GrOuP1")
+	params.Add("limit", "100")
+	params.Add("offset", "0")
+	mux.HandleFunc("/v2/dashboardgroupsnapshot", verifyRequest(t, "GET", http.StatusOK, params, "dashboardgroup/search_snapshots_success.json"))
+
+	result, err := client.ListDashboardGroupSnapshots(context.Background(), "GrOuP1")
+	assert.NoError(t, err, "Unexpected error listing snapshots")
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, "SnAp1", result[0].Id)
+		assert.Equal(t, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), result[0].Expires)
+	}
+}
+
+func TestDeleteDashboardGroupSnapshot(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/dashboardgroupsnapshot/SnAp1", verifyRequest(t, "DELETE", http.StatusNoContent, nil, ""))
+
+	err := client.DeleteDashboardGroupSnapshot(context.Background(), "SnAp1")
+	assert.NoError(t, err, "Unexpected error deleting snapshot")
+}
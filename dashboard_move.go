@@ -0,0 +1,50 @@
+package signalfx
+
+import (
+	"context"
+	"log"
+
+	"github.com/adampetrovic/signalfx-go/dashboard"
+)
+
+// MoveDashboard moves a dashboard into another dashboard group. The
+// dashboard is fetched, bypassing any response cache, and written back whole
+// with only its group changed. If the target group already contains a
+// dashboard with the same name, the move still happens and a warning is
+// logged. That check is best effort: dashboards in the target group that
+// can't be read are left out of it.
+func (c *Client) MoveDashboard(ctx context.Context, dashboardID string, targetGroupID string) (*dashboard.Dashboard, error) {
+	// Writing back a stale copy would undo changes made since it was cached
+	current, err := c.getDashboard(ctx, dashboardID, true)
+	if err != nil {
+		return nil, err
+	}
+	if current.GroupId == targetGroupID {
+		return current, nil
+	}
+
+	if clash := c.findDashboardNameClash(ctx, targetGroupID, current.Name); clash != nil {
+		log.Printf("Moving dashboard %s to dashboard group %s, which already has a dashboard named %q (%s)",
+			dashboardID, targetGroupID, clash.Name, clash.Id)
+	}
+
+	req := current.UpdateRequest()
+	req.GroupId = targetGroupID
+	return c.updateDashboard(ctx, dashboardID, req)
+}
+
+// findDashboardNameClash returns the dashboard in the group named name, if
+// there is one. The group or dashboards that can't be read are skipped.
+func (c *Client) findDashboardNameClash(ctx context.Context, groupID string, name string) *dashboard.Dashboard {
+	group, err := c.getDashboardGroup(ctx, groupID)
+	if err != nil {
+		return nil
+	}
+
+	members := make([]*dashboard.Dashboard, len(group.Dashboards))
+	c.forEachConcurrently(len(group.Dashboards), func(i int) {
+		members[i], _ = c.getDashboard(ctx, group.Dashboards[i], false)
+	})
+	clash, _ := group.FindDashboard(name, members...)
+	return clash
+}
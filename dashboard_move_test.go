@@ -0,0 +1,121 @@
+package signalfx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/dashboard"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupMoveDashboard(t *testing.T, otherName string) *dashboard.CreateUpdateDashboardRequest {
+	sent := &dashboard.CreateUpdateDashboardRequest{}
+	mux.HandleFunc("/v2/dashboard/D1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(sent), "Unexpected error decoding body")
+			w.Write([]byte(`{"id": "D1", "name": "Latency", "groupId": "G2", "tags": ["prod"]}`))
+			return
+		}
+		w.Write([]byte(`{"id": "D1", "name": "Latency", "groupId": "G1", "tags": ["prod"]}`))
+	})
+	mux.HandleFunc("/v2/dashboardgroup/G2", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": "G2", "name": "Team", "dashboards": ["D2"]}`))
+	})
+	mux.HandleFunc("/v2/dashboard/D2", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": "D2", "name": "` + otherName + `", "groupId": "G2"}`))
+	})
+	return sent
+}
+
+func TestMoveDashboard(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	sent := setupMoveDashboard(t, "Errors")
+
+	result, err := client.MoveDashboard(context.Background(), "D1", "G2")
+	assert.NoError(t, err, "Unexpected error moving dashboard")
+	assert.Equal(t, "G2", result.GroupId, "GroupId does not match")
+	assert.Equal(t, "G2", sent.GroupId, "Should have sent the new group")
+	assert.Equal(t, []string{"prod"}, sent.Tags, "Should have sent the rest of the dashboard unchanged")
+}
+
+func TestMoveDashboardNameClash(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	setupMoveDashboard(t, "latency")
+
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	result, err := client.MoveDashboard(context.Background(), "D1", "G2")
+	assert.NoError(t, err, "A name clash should not be an error")
+	assert.Contains(t, logged.String(), `already has a dashboard named "latency" (D2)`, "Should warn about the name clash")
+	assert.Equal(t, "G2", result.GroupId, "Dashboard should still be moved")
+}
+
+func TestMoveDashboardUnreadableGroupMember(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	sent := &dashboard.CreateUpdateDashboardRequest{}
+	mux.HandleFunc("/v2/dashboard/D1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(sent), "Unexpected error decoding body")
+		}
+		w.Write([]byte(`{"id": "D1", "name": "Latency", "groupId": "G1"}`))
+	})
+	mux.HandleFunc("/v2/dashboardgroup/G2", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": "G2", "name": "Team", "dashboards": ["D2"]}`))
+	})
+	mux.HandleFunc("/v2/dashboard/D2", verifyRequest(t, "GET", http.StatusForbidden, nil, ""))
+
+	_, err := client.MoveDashboard(context.Background(), "D1", "G2")
+	assert.NoError(t, err, "An unreadable dashboard in the target group shouldn't stop the move")
+	assert.Equal(t, "G2", sent.GroupId, "Should have sent the new group")
+}
+
+func TestMoveDashboardSkipsCache(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	setupCache(t, time.Minute, 10)
+
+	name := "Latency"
+	sent := &dashboard.CreateUpdateDashboardRequest{}
+	mux.HandleFunc("/v2/dashboard/D1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(sent), "Unexpected error decoding body")
+		}
+		w.Write([]byte(`{"id": "D1", "name": "` + name + `", "groupId": "G1"}`))
+	})
+	mux.HandleFunc("/v2/dashboardgroup/G2", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": "G2", "name": "Team", "dashboards": []}`))
+	})
+
+	_, err := client.GetDashboard("D1")
+	assert.NoError(t, err, "Unexpected error getting dashboard")
+	name = "Renamed"
+
+	_, err = client.MoveDashboard(context.Background(), "D1", "G2")
+	assert.NoError(t, err, "Unexpected error moving dashboard")
+	assert.Equal(t, "Renamed", sent.Name, "Should have written back the current dashboard, not the cached one")
+}
+
+func TestMoveMissingDashboard(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/dashboard/D1", verifyRequest(t, "GET", http.StatusNotFound, nil, ""))
+
+	result, err := client.MoveDashboard(context.Background(), "D1", "G2")
+	assert.Error(t, err, "Should get an error for a missing dashboard")
+	assert.Nil(t, result, "Should get nil result")
+}
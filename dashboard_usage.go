@@ -0,0 +1,96 @@
+package signalfx
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/dashboard"
+)
+
+// DashboardViewStatsAPIURL is the URL for getting how much each dashboard
+// is viewed.
+const DashboardViewStatsAPIURL = "/v2/dashboard/viewstats"
+
+// GetDashboardViewStats gets the view stats of every dashboard that has
+// been viewed.
+func (c *Client) GetDashboardViewStats(ctx context.Context) ([]*dashboard.ViewStats, error) {
+	stats := []*dashboard.ViewStats{}
+	err := c.forEachPage(ctx, DashboardViewStatsAPIURL, nil, func() (interface{}, func() int) {
+		var page struct {
+			Results []*dashboard.ViewStats `json:"results"`
+		}
+		return &page, func() int {
+			stats = append(stats, page.Results...)
+			return len(page.Results)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// FindUnusedDashboards finds the dashboards that haven't been used in the
+// last maxAge, least recently used first.  A dashboard was last used when it
+// was last viewed, according to GetDashboardViewStats, or created if it has
+// never been viewed.  If view stats aren't available, when the dashboard was
+// last updated is used instead, which can flag dashboards that are viewed
+// but rarely changed.
+func (c *Client) FindUnusedDashboards(ctx context.Context, maxAge time.Duration) ([]*dashboard.DashboardSummary, error) {
+	cutoff := time.Now().Add(-maxAge)
+
+	haveStats := true
+	stats, err := c.GetDashboardViewStats(ctx)
+	if err != nil {
+		apiErr, ok := err.(*APIError)
+		if !ok || (apiErr.StatusCode != http.StatusNotFound && apiErr.StatusCode != http.StatusNotImplemented) {
+			return nil, err
+		}
+		haveStats = false
+	}
+	statsByID := make(map[string]*dashboard.ViewStats, len(stats))
+	for _, s := range stats {
+		statsByID[s.DashboardID] = s
+	}
+
+	unused := []*dashboard.DashboardSummary{}
+	lastUsed := map[*dashboard.DashboardSummary]time.Time{}
+	err = c.forEachPage(ctx, DashboardAPIURL, nil, func() (interface{}, func() int) {
+		page := &dashboard.SearchResult{}
+		return page, func() int {
+			for _, d := range page.Results {
+				summary := &dashboard.DashboardSummary{Dashboard: d}
+				used := millisToTime(d.LastUpdated)
+				if haveStats {
+					used = millisToTime(d.Created)
+					if s := statsByID[d.Id]; s != nil {
+						summary.LastViewedAt = s.LastViewedAt
+						summary.ViewCount = s.ViewCount
+						if s.LastViewedAt != nil && s.LastViewedAt.After(used) {
+							used = *s.LastViewedAt
+						}
+					}
+				}
+				if used.Before(cutoff) {
+					unused = append(unused, summary)
+					lastUsed[summary] = used
+				}
+			}
+			return len(page.Results)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(unused, func(i, j int) bool {
+		return lastUsed[unused[i]].Before(lastUsed[unused[j]])
+	})
+	return unused, nil
+}
+
+func millisToTime(ms int64) time.Time {
+	return time.Unix(0, ms*int64(time.Millisecond))
+}
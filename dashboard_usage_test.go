@@ -0,0 +1,55 @@
+package signalfx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindUnusedDashboards(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/dashboard/viewstats", verifyRequest(t, "GET", http.StatusOK, nil, "dashboard/viewstats_success.json"))
+	mux.HandleFunc("/v2/dashboard", verifyRequest(t, "GET", http.StatusOK, nil, "dashboard/search_success.json"))
+
+	result, err := client.FindUnusedDashboards(context.Background(), 90*24*time.Hour)
+	assert.NoError(t, err, "Unexpected error finding unused dashboards")
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, "string", result[0].Id)
+		assert.Equal(t, 3, result[0].ViewCount)
+		assert.Equal(t, int64(1500000000), result[0].LastViewedAt.Unix())
+	}
+}
+
+func TestFindUnusedDashboardsRecentlyViewed(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/dashboard/viewstats", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"results": [{"dashboardId": "string", "lastViewed": %d, "viewCount": 1}]}`, time.Now().UnixNano()/int64(time.Millisecond))
+	})
+	mux.HandleFunc("/v2/dashboard", verifyRequest(t, "GET", http.StatusOK, nil, "dashboard/search_success.json"))
+
+	result, err := client.FindUnusedDashboards(context.Background(), 90*24*time.Hour)
+	assert.NoError(t, err, "Unexpected error finding unused dashboards")
+	assert.Empty(t, result, "A recently viewed dashboard isn't unused")
+}
+
+func TestFindUnusedDashboardsWithoutViewStats(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/dashboard/viewstats", verifyRequest(t, "GET", http.StatusNotFound, nil, ""))
+	mux.HandleFunc("/v2/dashboard", verifyRequest(t, "GET", http.StatusOK, nil, "dashboard/search_success.json"))
+
+	result, err := client.FindUnusedDashboards(context.Background(), 90*24*time.Hour)
+	assert.NoError(t, err, "Should fall back to when dashboards were updated")
+	if assert.Len(t, result, 1) {
+		assert.Nil(t, result[0].LastViewedAt)
+	}
+}
@@ -2,9 +2,7 @@ package signalfx
 
 import (
 	"bytes"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
+	"context"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -19,7 +17,7 @@ const DashboardGroupAPIURL = "/v2/dashboardgroup"
 
 // CreateDashboardGroup creates a dashboard.
 func (c *Client) CreateDashboardGroup(dashboardGroupRequest *dashboard_group.CreateUpdateDashboardGroupRequest, skipImplicitDashboard bool) (*dashboard_group.DashboardGroup, error) {
-	payload, err := json.Marshal(dashboardGroupRequest)
+	payload, err := c.marshal(dashboardGroupRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -36,13 +34,12 @@ func (c *Client) CreateDashboardGroup(dashboardGroupRequest *dashboard_group.Cre
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalDashboardGroup := &dashboard_group.DashboardGroup{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalDashboardGroup)
+	err = c.decodeJSON(resp.Body, finalDashboardGroup)
 
 	return finalDashboardGroup, err
 }
@@ -57,8 +54,7 @@ func (c *Client) DeleteDashboardGroup(id string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return newAPIError(resp)
 	}
 
 	return nil
@@ -66,7 +62,11 @@ func (c *Client) DeleteDashboardGroup(id string) error {
 
 // GetDashboardGroup gets a dashboard group.
 func (c *Client) GetDashboardGroup(id string) (*dashboard_group.DashboardGroup, error) {
-	resp, err := c.doRequest("GET", DashboardGroupAPIURL+"/"+id, nil, nil)
+	return c.getDashboardGroup(c.ctx, id)
+}
+
+func (c *Client) getDashboardGroup(ctx context.Context, id string) (*dashboard_group.DashboardGroup, error) {
+	resp, err := c.doRequestWithContext(ctx, "GET", DashboardGroupAPIURL+"/"+id, nil, nil)
 
 	if err != nil {
 		return nil, err
@@ -74,20 +74,19 @@ func (c *Client) GetDashboardGroup(id string) (*dashboard_group.DashboardGroup,
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalDashboardGroup := &dashboard_group.DashboardGroup{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalDashboardGroup)
+	err = c.decodeJSON(resp.Body, finalDashboardGroup)
 
 	return finalDashboardGroup, err
 }
 
 // UpdateDashboardGroup updates a dashboard group.
 func (c *Client) UpdateDashboardGroup(id string, dashboardGroupRequest *dashboard_group.CreateUpdateDashboardGroupRequest) (*dashboard_group.DashboardGroup, error) {
-	payload, err := json.Marshal(dashboardGroupRequest)
+	payload, err := c.marshal(dashboardGroupRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -99,13 +98,12 @@ func (c *Client) UpdateDashboardGroup(id string, dashboardGroupRequest *dashboar
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalDashboardGroup := &dashboard_group.DashboardGroup{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalDashboardGroup)
+	err = c.decodeJSON(resp.Body, finalDashboardGroup)
 
 	return finalDashboardGroup, err
 }
@@ -126,7 +124,7 @@ func (c *Client) SearchDashboardGroups(limit int, name string, offset int) (*das
 
 	finalDashboardGroups := &dashboard_group.SearchResult{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalDashboardGroups)
+	err = c.decodeJSON(resp.Body, finalDashboardGroups)
 
 	return finalDashboardGroups, err
 }
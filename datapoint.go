@@ -0,0 +1,178 @@
+package signalfx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/signalfx/golib/v3/datapoint"
+)
+
+// DatapointAPIURL is the ingest URL for sending datapoints.
+const DatapointAPIURL = "/v2/datapoint"
+
+// DatapointError describes why a single datapoint passed to SendDatapoints
+// wasn't accepted.
+type DatapointError struct {
+	// The position of the datapoint in the slice passed to SendDatapoints
+	Index     int
+	Datapoint *datapoint.Datapoint
+	Err       error
+}
+
+func (e *DatapointError) Error() string {
+	metric := ""
+	if e.Datapoint != nil {
+		metric = e.Datapoint.Metric
+	}
+	return fmt.Sprintf("datapoint %d (%s): %v", e.Index, metric, e.Err)
+}
+
+// DatapointErrors is returned by SendDatapoints when some of the datapoints
+// weren't accepted.  The rest were sent successfully.
+type DatapointErrors []*DatapointError
+
+func (e DatapointErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d datapoints failed: %s", len(e), strings.Join(msgs, "; "))
+}
+
+type ingestDatapoint struct {
+	Metric     string            `json:"metric"`
+	Value      interface{}       `json:"value"`
+	Dimensions map[string]string `json:"dimensions,omitempty"`
+	Timestamp  int64             `json:"timestamp,omitempty"`
+}
+
+var ingestMetricTypes = map[datapoint.MetricType]string{
+	datapoint.Gauge:   "gauge",
+	datapoint.Count:   "counter",
+	datapoint.Counter: "cumulative_counter",
+}
+
+// SendDatapoints sends datapoints to the ingest API, adding the client's
+// default dimensions to each.  Datapoints that can't be sent, such as ones
+// with an unsupported metric type or a NaN value, and ones the API rejects
+// are reported in a DatapointErrors; the others are still sent.  Any other
+// failure means none of the datapoints were accepted.
+func (c *Client) SendDatapoints(ctx context.Context, dps []*datapoint.Datapoint) error {
+	var failed DatapointErrors
+	fail := func(i int, err error) {
+		failed = append(failed, &DatapointError{Index: i, Datapoint: dps[i], Err: err})
+	}
+
+	body := map[string][]*ingestDatapoint{}
+	var sent []int
+	for i, dp := range dps {
+		if dp == nil {
+			fail(i, errors.New("datapoint is nil"))
+			continue
+		}
+		idp, err := c.toIngestDatapoint(dp)
+		if err != nil {
+			fail(i, err)
+			continue
+		}
+		typ := ingestMetricTypes[dp.MetricType]
+		body[typ] = append(body[typ], idp)
+		sent = append(sent, i)
+	}
+
+	if len(sent) > 0 {
+		rejected, err := c.postDatapoints(ctx, body)
+		if err != nil {
+			return err
+		}
+		if len(rejected) > 0 {
+			for _, i := range sent {
+				if msg, ok := rejected[dps[i].Metric]; ok {
+					fail(i, errors.New(msg))
+				}
+			}
+		}
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+	sort.Slice(failed, func(i, j int) bool { return failed[i].Index < failed[j].Index })
+	return failed
+}
+
+func (c *Client) toIngestDatapoint(dp *datapoint.Datapoint) (*ingestDatapoint, error) {
+	if dp.Metric == "" {
+		return nil, errors.New("metric name is empty")
+	}
+	if _, ok := ingestMetricTypes[dp.MetricType]; !ok {
+		return nil, fmt.Errorf("unsupported metric type %s", dp.MetricType)
+	}
+
+	idp := &ingestDatapoint{
+		Metric:     dp.Metric,
+		Dimensions: c.withDefaultDimensions(dp.Dimensions),
+	}
+	switch v := dp.Value.(type) {
+	case datapoint.IntValue:
+		idp.Value = v.Int()
+	case datapoint.FloatValue:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("value %v can't be sent", f)
+		}
+		idp.Value = f
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", dp.Value)
+	}
+	if !dp.Timestamp.IsZero() {
+		idp.Timestamp = dp.Timestamp.UnixNano() / int64(time.Millisecond)
+	}
+	return idp, nil
+}
+
+// postDatapoints sends body to the ingest API.  The API responds 200 with "OK"
+// if everything was accepted, or with an object mapping each metric name to
+// "OK" or the reason its datapoints were rejected; the rejected metrics are
+// returned.  Any other status is an error, whatever its body.
+func (c *Client) postDatapoints(ctx context.Context, body map[string][]*ingestDatapoint) (map[string]string, error) {
+	payload, err := c.marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequestToBase(ctx, c.ingestURL, "POST", DatapointAPIURL, nil, bytes.NewReader(payload), c.authToken)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	message, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var perMetric map[string]string
+	if bytes.HasPrefix(bytes.TrimSpace(message), []byte("{")) && c.unmarshal(message, &perMetric) == nil {
+		rejected := map[string]string{}
+		for metric, status := range perMetric {
+			if status != "OK" {
+				rejected[metric] = status
+			}
+		}
+		return rejected, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,130 @@
+package signalfx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/signalfx/golib/v3/datapoint"
+)
+
+// DatapointQueryAPIURL is the URL for querying raw datapoints.
+const DatapointQueryAPIURL = "/v1/datapoint/query"
+
+type datapointQueryResponse struct {
+	Count   int `json:"count"`
+	Results []struct {
+		Metric     string            `json:"metric"`
+		Dimensions map[string]string `json:"dimensions"`
+		Timestamp  int64             `json:"timestamp"`
+		Value      json.Number       `json:"value"`
+	} `json:"results"`
+}
+
+// GetDatapointsByQuery fetches the datapoints of `metric` between
+// `startTime` and `endTime` from time series whose dimensions match
+// `filters`, rolled up to `resolution`.  Filter values may contain `*`
+// wildcards.  All pages of results are fetched before returning; use
+// StreamDatapointsByQuery to process them as they arrive.
+func (c *Client) GetDatapointsByQuery(ctx context.Context, metric string, filters map[string]string, startTime, endTime time.Time, resolution time.Duration) ([]*datapoint.Datapoint, error) {
+	dpCh, errCh := c.StreamDatapointsByQuery(ctx, metric, filters, startTime, endTime, resolution)
+
+	var out []*datapoint.Datapoint
+	for dp := range dpCh {
+		out = append(out, dp)
+	}
+	if err := <-errCh; err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StreamDatapointsByQuery is like GetDatapointsByQuery but sends the
+// datapoints on the returned channel, fetching the next page once the
+// current one has been read.  The datapoint channel is closed when all of
+// them have been sent or the query fails, after which the error channel
+// yields the error, if any.  Cancel `ctx` to stop early.
+func (c *Client) StreamDatapointsByQuery(ctx context.Context, metric string, filters map[string]string, startTime, endTime time.Time, resolution time.Duration) (<-chan *datapoint.Datapoint, <-chan error) {
+	dpCh := make(chan *datapoint.Datapoint)
+	errCh := make(chan error, 1)
+
+	go func() {
+		defer close(errCh)
+		defer close(dpCh)
+
+		if !endTime.After(startTime) {
+			errCh <- fmt.Errorf("endTime %v must be after startTime %v", endTime, startTime)
+			return
+		}
+
+		params := url.Values{}
+		params.Add("query", datapointQuery(metric, filters))
+		params.Add("startMs", strconv.FormatInt(startTime.UnixNano()/int64(time.Millisecond), 10))
+		params.Add("endMs", strconv.FormatInt(endTime.UnixNano()/int64(time.Millisecond), 10))
+		params.Add("resolution", strconv.FormatInt(resolution.Nanoseconds()/int64(time.Millisecond), 10))
+
+		p := c.newPager(ctx, DatapointQueryAPIURL, params)
+		for !p.done {
+			page := &datapointQueryResponse{}
+			if err := p.fetch(page, func() int { return len(page.Results) }); err != nil {
+				errCh <- err
+				return
+			}
+
+			for _, r := range page.Results {
+				var value datapoint.Value
+				if i, err := r.Value.Int64(); err == nil {
+					value = datapoint.NewIntValue(i)
+				} else if f, err := r.Value.Float64(); err == nil {
+					value = datapoint.NewFloatValue(f)
+				} else {
+					errCh <- fmt.Errorf("invalid value %q for metric %s", r.Value, r.Metric)
+					return
+				}
+
+				dp := datapoint.New(r.Metric, r.Dimensions, value, datapoint.Gauge, time.Unix(0, r.Timestamp*int64(time.Millisecond)))
+				if ctx.Err() != nil {
+					errCh <- ctx.Err()
+					return
+				}
+				select {
+				case dpCh <- dp:
+				case <-ctx.Done():
+					errCh <- ctx.Err()
+					return
+				}
+			}
+		}
+	}()
+
+	return dpCh, errCh
+}
+
+// datapointQuery builds a search query for metric and filters, escaping
+// everything in the filter values except wildcards.
+func datapointQuery(metric string, filters map[string]string) string {
+	keys := make([]string, 0, len(filters))
+	for k := range filters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	terms := []string{"sf_metric:" + escapeQueryTerm(metric)}
+	for _, k := range keys {
+		terms = append(terms, escapeQueryTerm(k)+":"+escapeQueryTerm(filters[k]))
+	}
+	return strings.Join(terms, " AND ")
+}
+
+var queryTermEscaper = strings.NewReplacer(
+	`\`, `\\`, `:`, `\:`, ` `, `\ `, `(`, `\(`, `)`, `\)`, `"`, `\"`,
+)
+
+func escapeQueryTerm(term string) string {
+	return queryTermEscaper.Replace(term)
+}
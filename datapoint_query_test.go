@@ -0,0 +1,79 @@
+package signalfx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/signalfx/golib/v3/datapoint"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDatapointsByQuery(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/datapoint/query", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, `sf_metric:cpu.utilization AND env:prod AND host:web\ *`, q.Get("query"))
+		assert.Equal(t, "1557936000000", q.Get("startMs"))
+		assert.Equal(t, "1557939600000", q.Get("endMs"))
+		assert.Equal(t, "60000", q.Get("resolution"))
+
+		// A full first page followed by a partial one
+		count := 100
+		if q.Get("offset") != "0" {
+			count = 1
+		}
+		results := make([]string, count)
+		for i := range results {
+			results[i] = fmt.Sprintf(`{"metric": "cpu.utilization", "dimensions": {"host": "web 1"}, "timestamp": 1557936000000, "value": %d.5}`, i)
+		}
+		w.Write([]byte(`{"count": 101, "results": [` + strings.Join(results, ",") + `]}`))
+	})
+
+	start := time.Unix(1557936000, 0)
+	filters := map[string]string{"host": "web *", "env": "prod"}
+	result, err := client.GetDatapointsByQuery(context.Background(), "cpu.utilization", filters, start, start.Add(time.Hour), time.Minute)
+	assert.NoError(t, err, "Unexpected error querying datapoints")
+	if assert.Len(t, result, 101, "Should have fetched both pages") {
+		assert.Equal(t, "web 1", result[0].Dimensions["host"])
+		assert.Equal(t, datapoint.NewFloatValue(0.5), result[0].Value)
+		assert.Equal(t, start, result[0].Timestamp)
+	}
+}
+
+func TestGetDatapointsByQueryError(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/datapoint/query", verifyRequest(t, "GET", http.StatusBadRequest, nil, ""))
+
+	start := time.Unix(1557936000, 0)
+	result, err := client.GetDatapointsByQuery(context.Background(), "cpu.utilization", nil, start, start.Add(time.Hour), time.Minute)
+	assert.Error(t, err, "Should have gotten an error from a bad query")
+	assert.Nil(t, result, "Should have gotten a nil result from a bad query")
+}
+
+func TestStreamDatapointsByQueryCancel(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/datapoint/query", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"count": 2, "results": [{"metric": "m", "timestamp": 1, "value": 1}, {"metric": "m", "timestamp": 2, "value": 2}]}`))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	start := time.Unix(1557936000, 0)
+	dpCh, errCh := client.StreamDatapointsByQuery(ctx, "m", nil, start, start.Add(time.Hour), time.Minute)
+	dp := <-dpCh
+	assert.Equal(t, datapoint.NewIntValue(1), dp.Value)
+	cancel()
+
+	for range dpCh {
+	}
+	assert.Equal(t, context.Canceled, <-errCh)
+}
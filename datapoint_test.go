@@ -0,0 +1,104 @@
+package signalfx
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/signalfx/golib/v3/datapoint"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendDatapoints(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	client.SetDefaultDimensions(map[string]string{"env": "prod", "host": "default"})
+
+	mux.HandleFunc("/v2/datapoint", func(w http.ResponseWriter, r *http.Request) {
+		body := map[string][]map[string]interface{}{}
+		err := json.NewDecoder(r.Body).Decode(&body)
+		assert.NoError(t, err, "Unexpected error decoding body")
+
+		assert.Len(t, body["gauge"], 1)
+		assert.Equal(t, "cpu.utilization", body["gauge"][0]["metric"])
+		assert.Equal(t, 12.5, body["gauge"][0]["value"])
+		assert.Equal(t, float64(1577836800000), body["gauge"][0]["timestamp"])
+		assert.Equal(t, map[string]interface{}{"env": "prod", "host": "web-1"}, body["gauge"][0]["dimensions"])
+		assert.Equal(t, float64(3), body["counter"][0]["value"])
+		assert.Equal(t, "bytes.total", body["cumulative_counter"][0]["metric"])
+		assert.NotContains(t, body["counter"][0], "timestamp", "Zero timestamps should be left to the server")
+
+		w.Write([]byte(`"OK"`))
+	})
+
+	err := client.SendDatapoints(context.Background(), []*datapoint.Datapoint{
+		datapoint.New("cpu.utilization", map[string]string{"host": "web-1"}, datapoint.NewFloatValue(12.5), datapoint.Gauge, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+		datapoint.New("requests", nil, datapoint.NewIntValue(3), datapoint.Count, time.Time{}),
+		datapoint.New("bytes.total", nil, datapoint.NewIntValue(1024), datapoint.Counter, time.Time{}),
+	})
+	assert.NoError(t, err, "Unexpected error sending datapoints")
+}
+
+func TestSendDatapointsPartialFailure(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/datapoint", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"cpu.utilization": "OK", "bad metric": "invalid metric name"}`))
+	})
+
+	dps := []*datapoint.Datapoint{
+		datapoint.New("cpu.utilization", nil, datapoint.NewFloatValue(1), datapoint.Gauge, time.Time{}),
+		datapoint.New("bad metric", nil, datapoint.NewFloatValue(1), datapoint.Gauge, time.Time{}),
+		datapoint.New("nan", nil, datapoint.NewFloatValue(math.NaN()), datapoint.Gauge, time.Time{}),
+		datapoint.New("rate", nil, datapoint.NewIntValue(1), datapoint.Rate, time.Time{}),
+	}
+	err := client.SendDatapoints(context.Background(), dps)
+
+	failed, ok := err.(DatapointErrors)
+	if assert.True(t, ok, "Should get DatapointErrors, got %v", err) && assert.Len(t, failed, 3) {
+		assert.Equal(t, 1, failed[0].Index)
+		assert.Equal(t, "invalid metric name", failed[0].Err.Error())
+		assert.Equal(t, dps[2], failed[1].Datapoint)
+		assert.Equal(t, 3, failed[2].Index)
+	}
+}
+
+func TestSendDatapointsBadStatus(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/datapoint", verifyRequest(t, "POST", http.StatusUnauthorized, nil, ""))
+
+	err := client.SendDatapoints(context.Background(), []*datapoint.Datapoint{
+		datapoint.New("cpu.utilization", nil, datapoint.NewFloatValue(1), datapoint.Gauge, time.Time{}),
+	})
+	apiErr, ok := err.(*APIError)
+	if assert.True(t, ok, "Should get an *APIError, got %v", err) {
+		assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	}
+}
+
+func TestSendDatapointsBadStatusWithJSONBody(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/datapoint", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"message": "invalid token"}`))
+	})
+
+	err := client.SendDatapoints(context.Background(), []*datapoint.Datapoint{
+		datapoint.New("cpu.utilization", nil, datapoint.NewFloatValue(1), datapoint.Gauge, time.Time{}),
+	})
+	apiErr, ok := err.(*APIError)
+	if assert.True(t, ok, "Should get an *APIError, got %v", err) {
+		assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+		assert.Equal(t, `{"message": "invalid token"}`, apiErr.Body)
+	}
+}
@@ -2,12 +2,14 @@ package signalfx
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/adampetrovic/signalfx-go/detector"
 )
@@ -17,49 +19,91 @@ const DetectorAPIURL = "/v2/detector"
 
 // CreateDetector creates a detector.
 func (c *Client) CreateDetector(detectorRequest *detector.CreateUpdateDetectorRequest) (*detector.Detector, error) {
-	payload, err := json.Marshal(detectorRequest)
+	return c.createDetector(c.ctx, detectorRequest)
+}
+
+func (c *Client) createDetector(ctx context.Context, detectorRequest *detector.CreateUpdateDetectorRequest) (*detector.Detector, error) {
+	payload, err := c.marshal(detectorRequest)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.doRequest("POST", DetectorAPIURL, nil, bytes.NewReader(payload))
+	resp, err := c.doRequestWithContext(ctx, "POST", DetectorAPIURL, nil, bytes.NewReader(payload))
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Bad status %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalDetector := &detector.Detector{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalDetector)
+	err = c.decodeJSON(resp.Body, finalDetector)
 
 	return finalDetector, err
 }
 
 // DeleteDetector deletes a detector.
 func (c *Client) DeleteDetector(id string) error {
-	resp, err := c.doRequest("DELETE", DetectorAPIURL+"/"+id, nil, nil)
+	_, err := c.deleteDetector(c.ctx, id)
+	return err
+}
+
+// deleteDetector also returns the response status, so that callers can tell
+// a missing detector from other failures.
+func (c *Client) deleteDetector(ctx context.Context, id string) (int, error) {
+	resp, err := c.doRequestWithContext(ctx, "DELETE", DetectorAPIURL+"/"+id, nil, nil)
 
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return resp.StatusCode, newAPIError(resp)
 	}
 
-	return nil
+	return resp.StatusCode, nil
+}
+
+// BulkDeleteDetectors deletes each of the detectors in ids, running up to the
+// client's MaxConcurrent deletions at once. Detectors that don't exist are
+// reported in NotFound rather than Failed. The error is only non-nil if ctx
+// is done before every deletion was attempted.
+func (c *Client) BulkDeleteDetectors(ctx context.Context, ids []string) (*detector.BulkDeleteResult, error) {
+	result := &detector.BulkDeleteResult{
+		Deleted:  []string{},
+		Failed:   map[string]error{},
+		NotFound: []string{},
+	}
+	var lock sync.Mutex
+
+	c.forEachConcurrently(len(ids), func(i int) {
+		id := ids[i]
+		status, err := c.deleteDetector(ctx, id)
+
+		lock.Lock()
+		defer lock.Unlock()
+		switch {
+		case err == nil:
+			result.Deleted = append(result.Deleted, id)
+		case status == http.StatusNotFound:
+			result.NotFound = append(result.NotFound, id)
+		default:
+			result.Failed[id] = err
+		}
+	})
+
+	sort.Strings(result.Deleted)
+	sort.Strings(result.NotFound)
+	return result, ctx.Err()
 }
 
 // DisableDetector disables a detector.
 func (c *Client) DisableDetector(id string, labels []string) error {
-	payload, err := json.Marshal(labels)
+	payload, err := c.marshal(labels)
 	if err != nil {
 		return err
 	}
@@ -71,8 +115,7 @@ func (c *Client) DisableDetector(id string, labels []string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return newAPIError(resp)
 	}
 
 	return nil
@@ -80,7 +123,7 @@ func (c *Client) DisableDetector(id string, labels []string) error {
 
 // EnableDetector enables a detector.
 func (c *Client) EnableDetector(id string, labels []string) error {
-	payload, err := json.Marshal(labels)
+	payload, err := c.marshal(labels)
 	if err != nil {
 		return err
 	}
@@ -92,8 +135,7 @@ func (c *Client) EnableDetector(id string, labels []string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return newAPIError(resp)
 	}
 
 	return nil
@@ -101,29 +143,86 @@ func (c *Client) EnableDetector(id string, labels []string) error {
 
 // GetDetector gets a detector.
 func (c *Client) GetDetector(id string) (*detector.Detector, error) {
-	resp, err := c.doRequest("GET", DetectorAPIURL+"/"+id, nil, nil)
+	return c.getDetector(c.ctx, id)
+}
+
+func (c *Client) getDetector(ctx context.Context, id string) (*detector.Detector, error) {
+	resp, err := c.doRequestWithContext(ctx, "GET", DetectorAPIURL+"/"+id, nil, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Bad status %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalDetector := &detector.Detector{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalDetector)
+	err = c.decodeJSON(resp.Body, finalDetector)
 	if err != nil {
 		fmt.Printf("+%v", err)
 	}
 	return finalDetector, err
 }
 
+// GetDetectorSignalFlowProgram gets just the program text of a detector.  It
+// asks the API for only that field, which keeps the response small for
+// detectors with many rules, and falls back to getting the whole detector if
+// the API doesn't support filtering fields.
+func (c *Client) GetDetectorSignalFlowProgram(ctx context.Context, detectorID string) (string, error) {
+	params := url.Values{}
+	params.Add("fields", "programText")
+
+	resp, err := c.doRequestWithContext(ctx, "GET", DetectorAPIURL+"/"+detectorID, params, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var program struct {
+			ProgramText string `json:"programText"`
+		}
+		if err := c.decodeJSON(resp.Body, &program); err != nil {
+			return "", err
+		}
+		if program.ProgramText != "" {
+			return program.ProgramText, nil
+		}
+		// The filter may have been misread and the program left out
+	case http.StatusBadRequest:
+	default:
+		return "", newAPIError(resp)
+	}
+
+	full, err := c.getDetector(ctx, detectorID)
+	if err != nil {
+		return "", err
+	}
+	return full.ProgramText, nil
+}
+
+// CloneDetector creates a copy of the detector sourceID called newName, with
+// its program and rules changed as described by opts, which may be nil.
+func (c *Client) CloneDetector(ctx context.Context, sourceID string, newName string, opts *detector.CloneOptions) (*detector.Detector, error) {
+	source, err := c.getDetector(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	detectorRequest, err := detector.CloneRequest(source, newName, opts)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to clone detector %s: %s", sourceID, err)
+	}
+
+	return c.createDetector(ctx, detectorRequest)
+}
+
 // UpdateDetector updates a detector.
 func (c *Client) UpdateDetector(id string, detectorRequest *detector.CreateUpdateDetectorRequest) (*detector.Detector, error) {
-	payload, err := json.Marshal(detectorRequest)
+	payload, err := c.marshal(detectorRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -135,13 +234,12 @@ func (c *Client) UpdateDetector(id string, detectorRequest *detector.CreateUpdat
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Bad status %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalDetector := &detector.Detector{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalDetector)
+	err = c.decodeJSON(resp.Body, finalDetector)
 
 	return finalDetector, err
 }
@@ -154,16 +252,150 @@ func (c *Client) SearchDetectors(limit int, name string, offset int, tags string
 	params.Add("offset", strconv.Itoa(offset))
 	params.Add("tags", tags)
 
-	resp, err := c.doRequest("GET", DetectorAPIURL, params, nil)
+	return c.searchDetectors(c.ctx, params)
+}
+
+func (c *Client) searchDetectors(ctx context.Context, params url.Values) (*detector.SearchResults, error) {
+	resp, err := c.doRequestWithContext(ctx, "GET", DetectorAPIURL, params, nil)
 
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
 	finalDetectors := &detector.SearchResults{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalDetectors)
+	err = c.decodeJSON(resp.Body, finalDetectors)
 
 	return finalDetectors, err
 }
+
+// searchPageSize is the number of results requested per page when fetching
+// every result of a search.
+const searchPageSize = 100
+
+// SearchDetectorsByProgram finds the detectors whose program references
+// metricName as a string literal, e.g. data('metricName'). It pages through
+// every detector in the organization and filters them client-side, since the
+// API can't search program text.
+func (c *Client) SearchDetectorsByProgram(ctx context.Context, metricName string) ([]*detector.Detector, error) {
+	literals := []string{"'" + metricName + "'", `"` + metricName + `"`}
+
+	var matches []*detector.Detector
+	err := c.forEachPage(ctx, DetectorAPIURL, nil, func() (interface{}, func() int) {
+		page := &detector.SearchResults{}
+		return page, func() int {
+			for i := range page.Results {
+				d := &page.Results[i]
+				for _, literal := range literals {
+					if strings.Contains(d.ProgramText, literal) {
+						matches = append(matches, d)
+						break
+					}
+				}
+			}
+			return len(page.Results)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// GetDetectorEvents gets the alert events raised by a detector, filtered by
+// params, which may be nil.
+func (c *Client) GetDetectorEvents(ctx context.Context, detectorID string, params *detector.EventQueryParams) ([]*detector.Event, error) {
+	resp, err := c.doRequestWithContext(ctx, "GET", DetectorAPIURL+"/"+detectorID+"/events", params.Values(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var finalEvents []*detector.Event
+
+	err = c.decodeJSON(resp.Body, &finalEvents)
+
+	return finalEvents, err
+}
+
+// DefaultDetectorEventPageSize is the number of events DetectorEventIterator
+// fetches per request when params doesn't set a limit.
+const DefaultDetectorEventPageSize = 100
+
+// DetectorEventIterator pages through a detector's events. Call Next until it
+// returns false, then check Err.
+type DetectorEventIterator struct {
+	client     *Client
+	ctx        context.Context
+	detectorID string
+	params     detector.EventQueryParams
+
+	page    []*detector.Event
+	current *detector.Event
+	last    bool
+	err     error
+}
+
+// DetectorEvents returns an iterator over all of a detector's events matching
+// params, which may be nil. params.Offset is where iteration starts and
+// params.Limit is the page size.
+func (c *Client) DetectorEvents(ctx context.Context, detectorID string, params *detector.EventQueryParams) *DetectorEventIterator {
+	it := &DetectorEventIterator{
+		client:     c,
+		ctx:        ctx,
+		detectorID: detectorID,
+	}
+	if params != nil {
+		it.params = *params
+	}
+	if it.params.Limit <= 0 {
+		it.params.Limit = DefaultDetectorEventPageSize
+	}
+	return it
+}
+
+// Next advances to the next event, fetching another page if needed. It
+// returns false when there are no more events or a request failed.
+func (it *DetectorEventIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if len(it.page) == 0 {
+		if it.last {
+			return false
+		}
+		page, err := it.client.GetDetectorEvents(it.ctx, it.detectorID, &it.params)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page = page
+		it.params.Offset += len(page)
+		it.last = len(page) < it.params.Limit
+		if len(page) == 0 {
+			return false
+		}
+	}
+	it.current = it.page[0]
+	it.page = it.page[1:]
+	return true
+}
+
+// Event returns the event Next advanced to.
+func (it *DetectorEventIterator) Event() *detector.Event {
+	return it.current
+}
+
+// Err returns the error that stopped iteration, if any.
+func (it *DetectorEventIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,244 @@
+package detector
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CloneOptions controls how a detector is changed when it's cloned.
+type CloneOptions struct {
+	// Replaces the source detector's program when set.
+	OverrideProgram string
+	// Removes the notifications from every rule of the clone.
+	ClearNotifications bool
+	// Dimensions that every data() call in the program is filtered on, in
+	// addition to any filter it already has.
+	AddDimensions map[string]string
+}
+
+// CloneRequest builds the request to create a copy of source called name.
+// The source detector is not modified. opts may be nil.
+func CloneRequest(source *Detector, name string, opts *CloneOptions) (*CreateUpdateDetectorRequest, error) {
+	if opts == nil {
+		opts = &CloneOptions{}
+	}
+
+	program := source.ProgramText
+	if opts.OverrideProgram != "" {
+		program = opts.OverrideProgram
+	}
+	program, err := AddDataFilters(program, opts.AddDimensions)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]*Rule, len(source.Rules))
+	for i, r := range source.Rules {
+		rule := *r
+		if opts.ClearNotifications {
+			rule.Notifications = nil
+		}
+		rules[i] = &rule
+	}
+
+	customProperties, err := customPropertiesString(source.CustomProperties)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreateUpdateDetectorRequest{
+		AuthorizedWriters:    source.AuthorizedWriters,
+		CustomProperties:     customProperties,
+		Description:          source.Description,
+		MaxDelay:             source.MaxDelay,
+		Name:                 name,
+		ProgramText:          program,
+		Rules:                rules,
+		Tags:                 source.Tags,
+		Teams:                source.Teams,
+		VisualizationOptions: source.VisualizationOptions,
+	}, nil
+}
+
+// customPropertiesString converts a detector's custom properties to the
+// string that CreateUpdateDetectorRequest takes, encoding them as JSON unless
+// they already are a string.
+func customPropertiesString(props *interface{}) (string, error) {
+	if props == nil || *props == nil {
+		return "", nil
+	}
+	if s, ok := (*props).(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(*props)
+	if err != nil {
+		return "", fmt.Errorf("could not encode custom properties: %v", err)
+	}
+	return string(data), nil
+}
+
+// AddDataFilters adds a filter on each of dims to every data() call in the
+// SignalFlow program. A call that already has a filter, passed either as the
+// `filter` keyword argument or as the second positional argument, keeps it
+// and has the new filters and-ed onto it.
+func AddDataFilters(program string, dims map[string]string) (string, error) {
+	if len(dims) == 0 {
+		return program, nil
+	}
+
+	keys := make([]string, 0, len(dims))
+	for k := range dims {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	filters := make([]string, len(keys))
+	for i, k := range keys {
+		filters[i] = fmt.Sprintf("filter(%s, %s)", quoteSignalFlow(k), quoteSignalFlow(dims[k]))
+	}
+	filterExpr := strings.Join(filters, " and ")
+
+	var out strings.Builder
+	last := 0
+	for i := 0; i < len(program); i++ {
+		switch c := program[i]; {
+		case c == '\'' || c == '"':
+			end, err := skipString(program, i)
+			if err != nil {
+				return "", err
+			}
+			i = end
+		case c == '#':
+			for i < len(program) && program[i] != '\n' {
+				i++
+			}
+		case isDataCall(program, i):
+			open := strings.IndexByte(program[i:], '(') + i
+			closing, args, err := scanArgs(program, open)
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(program[last : open+1])
+			merged := false
+			for j, arg := range args {
+				if j > 0 {
+					out.WriteByte(',')
+				}
+				keyword := keywordArg(arg)
+				if keyword == "filter" || (keyword == "" && j == 1) {
+					// Keep the keyword or leading space, and and the
+					// filters onto the value
+					split := 0
+					if keyword != "" {
+						split = strings.IndexByte(arg, '=') + 1
+					}
+					split += len(arg[split:]) - len(strings.TrimLeft(arg[split:], " \t\n"))
+					arg = arg[:split] + andFilter(arg[split:], filterExpr)
+					merged = true
+				}
+				out.WriteString(arg)
+			}
+			if !merged {
+				if strings.TrimSpace(program[open+1:closing]) != "" {
+					out.WriteString(", ")
+				}
+				out.WriteString("filter=" + filterExpr)
+			}
+			last = closing
+			i = closing
+		}
+	}
+	out.WriteString(program[last:])
+	return out.String(), nil
+}
+
+// andFilter ands filterExpr onto the existing filter expression value.
+func andFilter(value, filterExpr string) string {
+	value = strings.TrimSpace(value)
+	if value == "None" {
+		return filterExpr
+	}
+	return fmt.Sprintf("(%s) and %s", value, filterExpr)
+}
+
+// keywordArg returns the name of the keyword argument arg, or "" if it's a
+// positional argument.
+func keywordArg(arg string) string {
+	arg = strings.TrimLeft(arg, " \t\n")
+	end := 0
+	for end < len(arg) && isIdentByte(arg[end]) && arg[end] != '.' {
+		end++
+	}
+	rest := strings.TrimLeft(arg[end:], " \t")
+	if end == 0 || !strings.HasPrefix(rest, "=") || strings.HasPrefix(rest, "==") {
+		return ""
+	}
+	return arg[:end]
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '.' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// isDataCall reports whether a call to the data() function starts at i.
+func isDataCall(program string, i int) bool {
+	if !strings.HasPrefix(program[i:], "data") || (i > 0 && isIdentByte(program[i-1])) {
+		return false
+	}
+	rest := strings.TrimLeft(program[i+len("data"):], " \t")
+	return strings.HasPrefix(rest, "(")
+}
+
+// skipString returns the index of the quote that closes the string starting
+// at i.
+func skipString(program string, i int) (int, error) {
+	quote := program[i]
+	for j := i + 1; j < len(program); j++ {
+		switch program[j] {
+		case '\\':
+			j++
+		case quote:
+			return j, nil
+		}
+	}
+	return 0, fmt.Errorf("unterminated string at offset %d", i)
+}
+
+// scanArgs returns the index of the parenthesis that closes the one at open,
+// and the raw text of each top level argument between them.
+func scanArgs(program string, open int) (int, []string, error) {
+	depth := 0
+	start := open + 1
+	var args []string
+	for j := open; j < len(program); j++ {
+		switch program[j] {
+		case '\'', '"':
+			end, err := skipString(program, j)
+			if err != nil {
+				return 0, nil, err
+			}
+			j = end
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+			if depth == 0 {
+				if strings.TrimSpace(program[start:j]) != "" {
+					args = append(args, program[start:j])
+				}
+				return j, args, nil
+			}
+		case ',':
+			if depth == 1 {
+				args = append(args, program[start:j])
+				start = j + 1
+			}
+		}
+	}
+	return 0, nil, fmt.Errorf("unbalanced parentheses in call at offset %d", open)
+}
+
+func quoteSignalFlow(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,75 @@
+package detector
+
+import (
+	"testing"
+
+	"github.com/adampetrovic/signalfx-go/notification"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddDataFilters(t *testing.T) {
+	dims := map[string]string{"env": "prod", "region": "us-east-1"}
+
+	out, err := AddDataFilters("A = data('cpu.utilization').mean().publish('A')", dims)
+	assert.NoError(t, err)
+	assert.Equal(t, "A = data('cpu.utilization', filter=filter('env', 'prod') and filter('region', 'us-east-1')).mean().publish('A')", out)
+
+	out, err = AddDataFilters(`A = data("mem", filter=filter('host', 'a') or filter('host', 'b'), rollup='max')
+# data('ignored')
+B = metadata('x'); C = data ( 'disk' )`, map[string]string{"env": "it's"})
+	assert.NoError(t, err)
+	assert.Equal(t, `A = data("mem", filter=(filter('host', 'a') or filter('host', 'b')) and filter('env', 'it\'s'), rollup='max')
+# data('ignored')
+B = metadata('x'); C = data ( 'disk' , filter=filter('env', 'it\'s'))`, out)
+
+	_, err = AddDataFilters("data('cpu'", dims)
+	assert.Error(t, err, "Unbalanced call should fail")
+	_, err = AddDataFilters("data('cpu)", dims)
+	assert.Error(t, err, "Unterminated string should fail")
+
+	out, err = AddDataFilters("data('cpu', filter('host', 'a'), rollup='max')", map[string]string{"env": "prod"})
+	assert.NoError(t, err)
+	assert.Equal(t, "data('cpu', (filter('host', 'a')) and filter('env', 'prod'), rollup='max')", out, "Positional filters should be merged")
+
+	out, err = AddDataFilters("data('cpu', filter = None)", map[string]string{"env": "prod"})
+	assert.NoError(t, err)
+	assert.Equal(t, "data('cpu', filter = filter('env', 'prod'))", out)
+
+	out, err = AddDataFilters("data('cpu')", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "data('cpu')", out)
+}
+
+func TestCloneRequest(t *testing.T) {
+	var props interface{} = map[string]interface{}{"owner": "sre"}
+	source := &Detector{
+		AuthorizedWriters: &AuthorizedWriters{Teams: []string{"T1"}, Users: []string{"U1"}},
+		CustomProperties:  &props,
+		Id:                "ABC",
+		Name:              "CPU",
+		ProgramText:       "detect(when(data('cpu') > 90)).publish('high')",
+		Tags:              []string{"canonical"},
+		Rules: []*Rule{{
+			DetectLabel:   "high",
+			Notifications: []*notification.Notification{{Type: "Email"}},
+		}},
+	}
+
+	req, err := CloneRequest(source, "CPU (staging)", &CloneOptions{
+		ClearNotifications: true,
+		AddDimensions:      map[string]string{"env": "staging"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "CPU (staging)", req.Name)
+	assert.Equal(t, "detect(when(data('cpu', filter=filter('env', 'staging')) > 90)).publish('high')", req.ProgramText)
+	assert.Nil(t, req.Rules[0].Notifications)
+	assert.Len(t, source.Rules[0].Notifications, 1, "Source should not be modified")
+	assert.Equal(t, []string{"canonical"}, req.Tags)
+	assert.Equal(t, source.AuthorizedWriters, req.AuthorizedWriters)
+	assert.Equal(t, `{"owner":"sre"}`, req.CustomProperties)
+
+	req, err = CloneRequest(source, "Other", &CloneOptions{OverrideProgram: "detect(when(data('mem') > 1)).publish('high')"})
+	assert.NoError(t, err)
+	assert.Equal(t, "detect(when(data('mem') > 1)).publish('high')", req.ProgramText)
+	assert.Len(t, req.Rules[0].Notifications, 1)
+}
@@ -0,0 +1,89 @@
+package detector
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// Matches detector('id'), detector("id") and detector(id='id') in a program.
+var detectorCallRegexp = regexp.MustCompile(`\bdetector\s*\(\s*(?:\w+\s*=\s*)?['"]([^'"]+)['"]`)
+
+// DependencyGraph returns the detectors referenced by this detector's
+// program, as an adjacency list keyed by detector ID. It returns an error if
+// the detector references itself.
+func (d *Detector) DependencyGraph() (map[string][]string, error) {
+	return BuildDependencyGraph([]*Detector{d})
+}
+
+// BuildDependencyGraph parses the program of each detector for detector()
+// calls and returns an adjacency list from each detector's ID to the IDs it
+// references, in the order they first appear. It returns an error if the
+// references form a cycle.
+func BuildDependencyGraph(detectors []*Detector) (map[string][]string, error) {
+	graph := make(map[string][]string, len(detectors))
+	for _, d := range detectors {
+		seen := make(map[string]bool)
+		deps := []string{}
+		for _, match := range detectorCallRegexp.FindAllStringSubmatch(d.ProgramText, -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				deps = append(deps, match[1])
+			}
+		}
+		graph[d.Id] = deps
+	}
+
+	if cycle := findCycle(graph); cycle != nil {
+		return graph, fmt.Errorf("cyclic detector dependency: %v", cycle)
+	}
+	return graph, nil
+}
+
+// findCycle returns the IDs making up a cycle in the graph, or nil.
+func findCycle(graph map[string][]string) []string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(graph))
+	var path []string
+
+	var visit func(id string) []string
+	visit = func(id string) []string {
+		switch state[id] {
+		case visiting:
+			for i, p := range path {
+				if p == id {
+					return append(append([]string{}, path[i:]...), id)
+				}
+			}
+		case done:
+			return nil
+		}
+		state[id] = visiting
+		path = append(path, id)
+		for _, dep := range graph[id] {
+			if cycle := visit(dep); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = done
+		return nil
+	}
+
+	// Visit in a stable order so the reported cycle is deterministic
+	ids := make([]string, 0, len(graph))
+	for id := range graph {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if cycle := visit(id); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package detector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependencyGraph(t *testing.T) {
+	d := &Detector{
+		Id: "A",
+		ProgramText: `a = detector('B').publish()
+b = detector(id="C")
+c = detector( 'B' )
+detect(when(a > 1)).publish('x')`,
+	}
+	graph, err := d.DependencyGraph()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"A": {"B", "C"}}, graph)
+
+	d.ProgramText = "detector('A')"
+	_, err = d.DependencyGraph()
+	assert.Error(t, err, "Self reference should fail")
+}
+
+func TestBuildDependencyGraph(t *testing.T) {
+	detectors := []*Detector{
+		{Id: "A", ProgramText: "detector('B')"},
+		{Id: "B", ProgramText: "detector('C')"},
+		{Id: "C", ProgramText: "data('cpu').publish()"},
+	}
+	graph, err := BuildDependencyGraph(detectors)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"B"}, graph["A"])
+	assert.Equal(t, []string{}, graph["C"])
+
+	detectors[2].ProgramText = "detector('A')"
+	_, err = BuildDependencyGraph(detectors)
+	assert.EqualError(t, err, "cyclic detector dependency: [A B C A]")
+}
@@ -0,0 +1,63 @@
+package detector
+
+import (
+	"context"
+	"strings"
+
+	"github.com/adampetrovic/signalfx-go/notification"
+	"github.com/adampetrovic/signalfx-go/organization"
+)
+
+// TeamMemberGetter gets the members of a team.  *signalfx.Client implements
+// it.
+type TeamMemberGetter interface {
+	GetTeamMembers(ctx context.Context, teamID string) ([]*organization.Member, error)
+}
+
+// EffectiveNotifications returns the individual email recipients of the
+// rule's notifications.  Team and TeamEmail notifications are expanded to an
+// email notification for each member of the team, using client to look up the
+// members.  Addresses are deduplicated case-insensitively, keeping the first
+// spelling seen, and are in the order they first appear.  Notifications to
+// other services are left out.
+func (r *Rule) EffectiveNotifications(ctx context.Context, client TeamMemberGetter) ([]*notification.EmailNotification, error) {
+	emails := []*notification.EmailNotification{}
+	seen := make(map[string]bool)
+	add := func(address string) {
+		key := strings.ToLower(address)
+		if address == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		emails = append(emails, &notification.EmailNotification{Type: "Email", Email: address})
+	}
+
+	expanded := make(map[string]bool)
+	for _, n := range r.Notifications {
+		var teamID string
+		switch v := n.Value.(type) {
+		case *notification.EmailNotification:
+			add(v.Email)
+			continue
+		case *notification.TeamNotification:
+			teamID = v.Team
+		case *notification.TeamEmailNotification:
+			teamID = v.Team
+		default:
+			continue
+		}
+		if expanded[teamID] {
+			continue
+		}
+		expanded[teamID] = true
+
+		members, err := client.GetTeamMembers(ctx, teamID)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range members {
+			add(m.Email)
+		}
+	}
+	return emails, nil
+}
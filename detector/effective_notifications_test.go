@@ -0,0 +1,57 @@
+package detector
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/adampetrovic/signalfx-go/notification"
+	"github.com/adampetrovic/signalfx-go/organization"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTeams map[string][]string
+
+func (f fakeTeams) GetTeamMembers(ctx context.Context, teamID string) ([]*organization.Member, error) {
+	emails, ok := f[teamID]
+	if !ok {
+		return nil, errors.New("no such team")
+	}
+	members := []*organization.Member{}
+	for _, e := range emails {
+		members = append(members, &organization.Member{Email: e})
+	}
+	return members, nil
+}
+
+func TestEffectiveNotifications(t *testing.T) {
+	rule := &Rule{Notifications: []*notification.Notification{
+		{Type: "Email", Value: &notification.EmailNotification{Type: "Email", Email: "oncall@example.com"}},
+		{Type: "Team", Value: &notification.TeamNotification{Type: "Team", Team: "TeAmA"}},
+		{Type: "Slack", Value: &notification.SlackNotification{Type: "Slack"}},
+		{Type: "TeamEmail", Value: &notification.TeamEmailNotification{Type: "TeamEmail", Team: "TeAmB"}},
+	}}
+	teams := fakeTeams{
+		"TeAmA": {"alice@example.com", "OnCall@example.com"},
+		"TeAmB": {"bob@example.com", "alice@example.com"},
+	}
+
+	emails, err := rule.EffectiveNotifications(context.Background(), teams)
+	assert.NoError(t, err)
+	addresses := []string{}
+	for _, e := range emails {
+		assert.Equal(t, "Email", e.Type)
+		addresses = append(addresses, e.Email)
+	}
+	assert.Equal(t, []string{"oncall@example.com", "alice@example.com", "bob@example.com"}, addresses)
+}
+
+func TestEffectiveNotificationsError(t *testing.T) {
+	rule := &Rule{Notifications: []*notification.Notification{
+		{Type: "Team", Value: &notification.TeamNotification{Type: "Team", Team: "missing"}},
+	}}
+
+	emails, err := rule.EffectiveNotifications(context.Background(), fakeTeams{})
+	assert.Error(t, err, "Should fail when a team can't be looked up")
+	assert.Nil(t, emails)
+}
@@ -0,0 +1,35 @@
+package detector
+
+import "time"
+
+// How often a detector would have alerted over a period of time.
+type AlertFrequencyEstimate struct {
+	// Number of times a rule would have triggered
+	TotalAlerts int `json:"totalAlerts"`
+	// TotalAlerts averaged over the days in the period
+	AlertsPerDay float64 `json:"alertsPerDay"`
+	// Average time from one alert to the next, or 0 if there were fewer than
+	// two
+	MeanTimeBetweenAlerts time.Duration `json:"meanTimeBetweenAlerts"`
+	// How noisy the detector would be, from 0 (silent) to 1. A detector that
+	// alerts AlertNoiseHalfRate times a day scores 0.5.
+	NoiseScore float64 `json:"noiseScore"`
+}
+
+// AlertNoiseHalfRate is the number of alerts per day that gives a NoiseScore
+// of 0.5.
+const AlertNoiseHalfRate = 1.0
+
+// NewAlertFrequencyEstimate works out an estimate from the times alerts
+// would have triggered, in order, over a period of length lookback.
+func NewAlertFrequencyEstimate(alertTimes []time.Time, lookback time.Duration) *AlertFrequencyEstimate {
+	est := &AlertFrequencyEstimate{TotalAlerts: len(alertTimes)}
+	if days := lookback.Hours() / 24; days > 0 {
+		est.AlertsPerDay = float64(est.TotalAlerts) / days
+	}
+	if len(alertTimes) > 1 {
+		est.MeanTimeBetweenAlerts = alertTimes[len(alertTimes)-1].Sub(alertTimes[0]) / time.Duration(len(alertTimes)-1)
+	}
+	est.NoiseScore = est.AlertsPerDay / (est.AlertsPerDay + AlertNoiseHalfRate)
+	return est
+}
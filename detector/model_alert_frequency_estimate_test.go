@@ -0,0 +1,20 @@
+package detector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAlertFrequencyEstimate(t *testing.T) {
+	start := time.Unix(1557936000, 0)
+	est := NewAlertFrequencyEstimate([]time.Time{start, start.Add(time.Hour), start.Add(5 * time.Hour)}, 3*24*time.Hour)
+	assert.Equal(t, 3, est.TotalAlerts)
+	assert.Equal(t, 1.0, est.AlertsPerDay)
+	assert.Equal(t, 150*time.Minute, est.MeanTimeBetweenAlerts)
+	assert.Equal(t, 0.5, est.NoiseScore)
+
+	est = NewAlertFrequencyEstimate(nil, 24*time.Hour)
+	assert.Equal(t, &AlertFrequencyEstimate{}, est, "A silent detector should score 0")
+}
@@ -0,0 +1,18 @@
+package detector
+
+import "time"
+
+// A summary of the incidents raised by one of a detector's rules over a
+// period of time.
+type AlertSummary struct {
+	DetectorID   string `json:"detectorId"`
+	DetectorName string `json:"detectorName,omitempty"`
+	// The detect label of the rule that raised the incidents
+	DetectLabel string `json:"detectLabel,omitempty"`
+	// Number of incidents raised in the period
+	AlertCount int `json:"alertCount"`
+	// When the most recent incident was raised
+	LastAlertTime time.Time `json:"lastAlertTime"`
+	// The fraction of the incidents that have been resolved, from 0 to 1
+	ResolutionRate float64 `json:"resolutionRate"`
+}
@@ -0,0 +1,11 @@
+package detector
+
+// The outcome of deleting several detectors at once.
+type BulkDeleteResult struct {
+	// IDs of the detectors that were deleted
+	Deleted []string
+	// Errors for the detectors that couldn't be deleted, keyed by ID
+	Failed map[string]error
+	// IDs of the detectors that didn't exist
+	NotFound []string
+}
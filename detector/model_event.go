@@ -0,0 +1,65 @@
+package detector
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// An alert event raised by one of a detector's rules.
+type Event struct {
+	// The dimensions of the time series that triggered the event.
+	AffectedDimensions map[string]string `json:"dimensions,omitempty"`
+	// The description of the rule that raised the event.
+	Description string `json:"description,omitempty"`
+	// The detect label of the rule that raised the event.
+	DetectLabel string `json:"detectLabel,omitempty"`
+	// System-defined identifier for the event
+	Id string `json:"id,omitempty"`
+	// Identifier of the incident the event belongs to
+	IncidentId string `json:"incidentId,omitempty"`
+	// The values of the detector's inputs when the event was raised, keyed by publish label.
+	InputValues map[string]float64 `json:"inputValues,omitempty"`
+	Severity    Severity           `json:"severity,omitempty"`
+	// The alert state the event moved the incident to, e.g. `ANOMALOUS`, `OK` or `STOPPED`.
+	Status string `json:"anomalyState,omitempty"`
+	// The time of the event in milliseconds (UTC) relative to the Unix epoch.
+	Timestamp int64 `json:"timestamp,omitempty"`
+}
+
+// EventQueryParams filters the events returned for a detector. Zero values
+// are left out of the query.
+type EventQueryParams struct {
+	// Only events at or after this time
+	StartTime time.Time
+	// Only events before this time
+	EndTime time.Time
+	Offset  int
+	Limit   int
+	// Only events belonging to this incident
+	IncidentID string
+}
+
+// Values returns the query string parameters for the filters that are set.
+func (p *EventQueryParams) Values() url.Values {
+	params := url.Values{}
+	if p == nil {
+		return params
+	}
+	if !p.StartTime.IsZero() {
+		params.Add("from", strconv.FormatInt(p.StartTime.UnixNano()/int64(time.Millisecond), 10))
+	}
+	if !p.EndTime.IsZero() {
+		params.Add("to", strconv.FormatInt(p.EndTime.UnixNano()/int64(time.Millisecond), 10))
+	}
+	if p.Offset > 0 {
+		params.Add("offset", strconv.Itoa(p.Offset))
+	}
+	if p.Limit > 0 {
+		params.Add("limit", strconv.Itoa(p.Limit))
+	}
+	if p.IncidentID != "" {
+		params.Add("incidentId", p.IncidentID)
+	}
+	return params
+}
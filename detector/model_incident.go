@@ -0,0 +1,23 @@
+package detector
+
+// An incident is a period during which one of a detector's rules was
+// triggered, made up of the events that opened and closed it.
+type Incident struct {
+	// Whether the incident is still ongoing
+	Active bool `json:"active"`
+	// The current alert state of the incident, e.g. `ANOMALOUS` or `OK`.
+	AnomalyState string `json:"anomalyState,omitempty"`
+	// The detect label of the rule that raised the incident.
+	DetectLabel  string `json:"detectLabel,omitempty"`
+	DetectorId   string `json:"detectorId,omitempty"`
+	DetectorName string `json:"detectorName,omitempty"`
+	// The events that make up the incident, oldest first.
+	Events []*Event `json:"events,omitempty"`
+	// System-defined identifier for the incident
+	IncidentId string   `json:"incidentId,omitempty"`
+	Severity   Severity `json:"severity,omitempty"`
+	// Whether a notification was sent when the incident was triggered
+	TriggeredNotificationSent bool `json:"triggeredNotificationSent"`
+	// Whether the incident was triggered while a muting rule applied
+	TriggeredWhileMuted bool `json:"triggeredWhileMuted"`
+}
@@ -0,0 +1,78 @@
+package detector
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Groups a rule's alerts so that many time series crossing a threshold at
+// once send a few notifications instead of one each.
+type NotificationDigest struct {
+	// Dimension keys to group alerts by. Alerts with the same values for
+	// these dimensions share a notification. Empty groups all alerts together.
+	GroupBy []string
+	// The most alerts to include in one notification. Zero means no limit.
+	MaxAlertsPerMessage int
+	// How long to collect alerts before sending a notification
+	DigestInterval time.Duration
+}
+
+type notificationDigestJSON struct {
+	GroupBy             []string `json:"groupBy,omitempty"`
+	MaxAlertsPerMessage int      `json:"maxAlertsPerMessage,omitempty"`
+	DigestInterval      int64    `json:"digestInterval,omitempty"`
+}
+
+// MarshalJSON encodes the digest with its interval in milliseconds, as the
+// API expects.
+func (d NotificationDigest) MarshalJSON() ([]byte, error) {
+	return json.Marshal(notificationDigestJSON{
+		GroupBy:             d.GroupBy,
+		MaxAlertsPerMessage: d.MaxAlertsPerMessage,
+		DigestInterval:      int64(d.DigestInterval / time.Millisecond),
+	})
+}
+
+// UnmarshalJSON decodes a digest with its interval in milliseconds.
+func (d *NotificationDigest) UnmarshalJSON(data []byte) error {
+	var raw notificationDigestJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	d.GroupBy = raw.GroupBy
+	d.MaxAlertsPerMessage = raw.MaxAlertsPerMessage
+	d.DigestInterval = time.Duration(raw.DigestInterval) * time.Millisecond
+	return nil
+}
+
+var stringLiteralRegexp = regexp.MustCompile(`'([^'\\]*)'|"([^"\\]*)"`)
+
+// Validate checks the digest's settings, and that every GroupBy dimension is
+// mentioned in programText, the program of the detector the rule belongs to.
+// Alerts can only be grouped by dimensions the program keeps, so a key the
+// program never names is almost certainly a typo.
+func (d *NotificationDigest) Validate(programText string) error {
+	if d.DigestInterval <= 0 {
+		return errors.New("digestInterval must be positive")
+	}
+	if d.DigestInterval%time.Millisecond != 0 {
+		return fmt.Errorf("digestInterval must be a whole number of milliseconds, got %s", d.DigestInterval)
+	}
+	if d.MaxAlertsPerMessage < 0 {
+		return fmt.Errorf("maxAlertsPerMessage cannot be negative, got %d", d.MaxAlertsPerMessage)
+	}
+
+	literals := map[string]bool{}
+	for _, m := range stringLiteralRegexp.FindAllStringSubmatch(programText, -1) {
+		literals[m[1]+m[2]] = true
+	}
+	for _, key := range d.GroupBy {
+		if !literals[key] {
+			return fmt.Errorf("groupBy dimension %q isn't used by the detector's program", key)
+		}
+	}
+	return nil
+}
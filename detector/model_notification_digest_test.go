@@ -0,0 +1,49 @@
+package detector
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const digestProgram = `A = data('cpu.utilization', filter=filter("env", 'prod')).mean(by=['host', 'cluster'])
+detect(when(A > 90)).publish('CPU high')`
+
+func TestNotificationDigestJSON(t *testing.T) {
+	r := &Rule{
+		DetectLabel: "CPU high",
+		NotificationDigest: &NotificationDigest{
+			GroupBy:             []string{"cluster"},
+			MaxAlertsPerMessage: 20,
+			DigestInterval:      5 * time.Minute,
+		},
+	}
+
+	data, err := json.Marshal(r)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"detectLabel": "CPU high", "notificationDigest": {"groupBy": ["cluster"], "maxAlertsPerMessage": 20, "digestInterval": 300000}}`, string(data))
+
+	decoded := &Rule{}
+	assert.NoError(t, json.Unmarshal(data, decoded))
+	assert.Equal(t, r, decoded)
+}
+
+func TestNotificationDigestValidate(t *testing.T) {
+	d := &NotificationDigest{GroupBy: []string{"host", "cluster", "env"}, DigestInterval: time.Minute}
+	assert.NoError(t, d.Validate(digestProgram))
+
+	d.GroupBy = []string{"region"}
+	assert.Error(t, d.Validate(digestProgram), "Dimensions the program doesn't use should fail")
+	d.GroupBy = nil
+
+	d.DigestInterval = 0
+	assert.Error(t, d.Validate(digestProgram), "Zero interval should fail")
+	d.DigestInterval = time.Microsecond
+	assert.Error(t, d.Validate(digestProgram), "Sub-millisecond interval should fail")
+	d.DigestInterval = time.Minute
+
+	d.MaxAlertsPerMessage = -1
+	assert.Error(t, d.Validate(digestProgram), "Negative max should fail")
+}
@@ -10,6 +10,11 @@
 package detector
 
 import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
 	"github.com/adampetrovic/signalfx-go/notification"
 )
 
@@ -27,8 +32,122 @@ type Rule struct {
 	ParameterizedBody string `json:"parameterizedBody,omitempty"`
 	// Custom notification message *subject* for this rule. The message is displayed when the alert is triggered. The content is plain text. Escape quote characters with a backslash, and indicate a newline with the \"\\n\" string. To insert a SignalFx variable value, specify its name in curly brackets. Double curly brackets (\"{{ }}\") specify a variable that's inserted in place, but some characters in the result may trigger unintended results in SignalFx or the notification server. Triple curly brackets specify a variable that SignalFx escapes as needed so that characters such as quotation marks and angle brackets render properly in notification messages. If you\\'re unsure which style of variable to use, use triple braces, so that all content renders properly. SignalFx does provide recommendations for the notation style to use with each supported variable. For more information see the custom notification messages section of the [Detectors Overview](https://developers.signalfx.com/v2/reference.html#detectors-overview). A full list of available variables with their default notation recommendation is available in the [Message variables](https://docs.signalfx.com/en/latest/detect-alert/set-up-detectors.html#message-variables) section of the \"Set Up Detectors\" topic in the SignalFx User Guide.
 	ParameterizedSubject string `json:"parameterizedSubject,omitempty"`
+	// How to batch this rule's alerts into fewer notifications. Nil sends one notification per alert.
+	NotificationDigest *NotificationDigest `json:"notificationDigest,omitempty"`
 	// URL that you can refer to with the SignalFx `{{runbookURL}}` variable in the `parameterizedBody` or `parameterizedSubject` field.
 	RunbookUrl string   `json:"runbookUrl,omitempty"`
 	Severity   Severity `json:"severity,omitempty"`
 	Tip        string   `json:"tip,omitempty"`
 }
+
+// MaxDetectLabelLength is the longest detect label a rule may reference.
+const MaxDetectLabelLength = 128
+
+// The message variables documented for custom notification messages. Nested
+// variables such as `dimensions.host` or `inputs.A.value` are checked against
+// their first segment.
+var messageVariables = map[string]bool{
+	"anomalous":         true,
+	"anomalyState":      true,
+	"detectorId":        true,
+	"detectorName":      true,
+	"detectorUrl":       true,
+	"dimensions":        true,
+	"event_annotations": true,
+	"imageUrl":          true,
+	"incidentId":        true,
+	"inputs":            true,
+	"normal":            true,
+	"readableRule":      true,
+	"ruleName":          true,
+	"ruleSeverity":      true,
+	"runbookUrl":        true,
+	"timestamp":         true,
+	"tip":               true,
+}
+
+// The block helpers allowed in custom notification messages.
+var messageHelpers = map[string]bool{
+	"each":   true,
+	"if":     true,
+	"unless": true,
+	"with":   true,
+}
+
+var messageTagRegexp = regexp.MustCompile(`\{\{\{?([^{}]*?)\}?\}\}`)
+
+// RuleWarning is returned by Rule.Validate when the rule is accepted by the
+// API but probably isn't what was intended. Check for it with a type
+// assertion to treat it as non-fatal.
+type RuleWarning struct {
+	Message string
+}
+
+func (w *RuleWarning) Error() string {
+	return w.Message
+}
+
+// Validate checks the rule for mistakes the API won't catch until the alert
+// fires. It returns an error if the detect label is missing or too long, a
+// message references an undocumented variable, or the runbook URL isn't
+// HTTPS. If the rule is otherwise valid but has a Critical or Major severity
+// and nowhere to send notifications, it returns a *RuleWarning.
+func (r *Rule) Validate() error {
+	if r.DetectLabel == "" {
+		return fmt.Errorf("detectLabel must be set")
+	}
+	if len(r.DetectLabel) > MaxDetectLabelLength {
+		return fmt.Errorf("detectLabel must be at most %d characters, got %d", MaxDetectLabelLength, len(r.DetectLabel))
+	}
+	if err := validateMessageTemplate("parameterizedSubject", r.ParameterizedSubject); err != nil {
+		return err
+	}
+	if err := validateMessageTemplate("parameterizedBody", r.ParameterizedBody); err != nil {
+		return err
+	}
+	if r.RunbookUrl != "" {
+		u, err := url.Parse(r.RunbookUrl)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("runbookUrl must be an HTTPS URL, got %q", r.RunbookUrl)
+		}
+	}
+	if (r.Severity == CRITICAL || r.Severity == MAJOR) && len(r.Notifications) == 0 {
+		return &RuleWarning{
+			Message: fmt.Sprintf("rule %q has severity %s but no notifications", r.DetectLabel, r.Severity),
+		}
+	}
+	return nil
+}
+
+func validateMessageTemplate(field, template string) error {
+	for _, match := range messageTagRegexp.FindAllStringSubmatch(template, -1) {
+		tag := strings.TrimSpace(match[1])
+		if tag == "" || tag == "else" || strings.HasPrefix(tag, "/") || strings.HasPrefix(tag, "!") {
+			continue
+		}
+		if strings.HasPrefix(tag, "#") || strings.HasPrefix(tag, "^") {
+			parts := strings.Fields(tag[1:])
+			if len(parts) == 0 || !messageHelpers[parts[0]] {
+				return fmt.Errorf("%s uses unknown helper in {{%s}}", field, tag)
+			}
+			for _, arg := range parts[1:] {
+				if !isMessageVariable(arg) {
+					return fmt.Errorf("%s uses unknown variable %q in {{%s}}", field, arg, tag)
+				}
+			}
+			continue
+		}
+		if !isMessageVariable(tag) {
+			return fmt.Errorf("%s uses unknown variable {{%s}}", field, tag)
+		}
+	}
+	return nil
+}
+
+func isMessageVariable(name string) bool {
+	// Loop variables inside an each block
+	if name == "this" || strings.HasPrefix(name, "this.") || strings.HasPrefix(name, "@") {
+		return true
+	}
+	return messageVariables[strings.SplitN(name, ".", 2)[0]]
+}
@@ -0,0 +1,62 @@
+package detector
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/adampetrovic/signalfx-go/notification"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleValidate(t *testing.T) {
+	rule := &Rule{
+		DetectLabel:          "CPU high",
+		Severity:             CRITICAL,
+		Notifications:        []*notification.Notification{{Type: "Email"}},
+		ParameterizedSubject: "{{ruleSeverity}} Alert: {{{ruleName}}} {{{detectorName}}}",
+		ParameterizedBody:    "{{#if anomalous}}Triggered at {{timestamp}} on {{dimensions.host}}{{else}}Cleared{{/if}}\n{{#each dimensions}}{{@key}}={{this}}{{/each}}",
+		RunbookUrl:           "https://runbooks.example.com/cpu",
+	}
+	assert.NoError(t, rule.Validate())
+}
+
+func TestRuleValidateErrors(t *testing.T) {
+	valid := func() *Rule {
+		return &Rule{DetectLabel: "label", Severity: INFO}
+	}
+
+	r := valid()
+	r.DetectLabel = ""
+	assert.Error(t, r.Validate(), "Empty detect label should fail")
+
+	r = valid()
+	r.DetectLabel = strings.Repeat("a", MaxDetectLabelLength+1)
+	assert.Error(t, r.Validate(), "Long detect label should fail")
+
+	r = valid()
+	r.ParameterizedBody = "Value is {{inputs.A.value}} on {{hostname}}"
+	assert.Error(t, r.Validate(), "Unknown variable should fail")
+
+	r = valid()
+	r.ParameterizedSubject = "{{#loop dimensions}}x{{/loop}}"
+	assert.Error(t, r.Validate(), "Unknown helper should fail")
+
+	r = valid()
+	r.RunbookUrl = "http://runbooks.example.com"
+	assert.Error(t, r.Validate(), "Plain HTTP runbook should fail")
+
+	r = valid()
+	r.RunbookUrl = "runbooks"
+	assert.Error(t, r.Validate(), "Relative runbook should fail")
+}
+
+func TestRuleValidateWarning(t *testing.T) {
+	r := &Rule{DetectLabel: "label", Severity: MAJOR}
+	err := r.Validate()
+	assert.Error(t, err)
+	_, ok := err.(*RuleWarning)
+	assert.True(t, ok, "Missing notifications should only warn")
+
+	r.Severity = MINOR
+	assert.NoError(t, r.Validate())
+}
@@ -0,0 +1,142 @@
+package detector
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// RuleBuilder builds a rule for a common alert condition along with the
+// SignalFlow detect clause that the rule's detect label refers to.
+type RuleBuilder struct {
+	label       string
+	severity    Severity
+	description string
+	program     string
+	err         error
+}
+
+// NewRuleBuilder starts a rule with the given detect label and severity.
+func NewRuleBuilder(label string, severity Severity) *RuleBuilder {
+	return &RuleBuilder{
+		label:    label,
+		severity: severity,
+	}
+}
+
+// WhenAbove alerts when metric stays above threshold for duration. A zero
+// duration alerts as soon as the threshold is crossed.
+func (b *RuleBuilder) WhenAbove(metric string, threshold float64, duration time.Duration) *RuleBuilder {
+	return b.whenThreshold(metric, ">", "above", threshold, duration)
+}
+
+// WhenBelow alerts when metric stays below threshold for duration. A zero
+// duration alerts as soon as the threshold is crossed.
+func (b *RuleBuilder) WhenBelow(metric string, threshold float64, duration time.Duration) *RuleBuilder {
+	return b.whenThreshold(metric, "<", "below", threshold, duration)
+}
+
+func (b *RuleBuilder) whenThreshold(metric, op, word string, threshold float64, duration time.Duration) *RuleBuilder {
+	if duration < 0 {
+		return b.fail(fmt.Errorf("duration must not be negative, got %s", duration))
+	}
+	lasting := ""
+	if duration > 0 {
+		lasting = fmt.Sprintf(", lasting='%s'", signalFlowDuration(duration))
+	}
+	value := strconv.FormatFloat(threshold, 'g', -1, 64)
+	return b.setCondition(metric,
+		fmt.Sprintf("detect(when(data(%s) %s %s%s)).publish(%s)", quoteSignalFlow(metric), op, value, lasting, quoteSignalFlow(b.label)),
+		fmt.Sprintf("The value of %s is %s %s.", metric, word, value))
+}
+
+// WhenOutlier alerts when metric is more than deviations standard deviations
+// away from its mean over the preceding window.
+func (b *RuleBuilder) WhenOutlier(metric string, deviations float64, window time.Duration) *RuleBuilder {
+	if deviations <= 0 {
+		return b.fail(fmt.Errorf("deviations must be positive, got %g", deviations))
+	}
+	if window <= 0 {
+		return b.fail(fmt.Errorf("window must be positive, got %s", window))
+	}
+	// The stream and its statistics are inlined rather than assigned to
+	// variables, so that the clause can't clash with names already in the
+	// detector's program, or with another outlier rule's
+	data := "data(" + quoteSignalFlow(metric) + ")"
+	over := signalFlowDuration(window)
+	mean := fmt.Sprintf("%s.mean(over='%s')", data, over)
+	stddev := fmt.Sprintf("%s.stddev(over='%s')", data, over)
+	n := strconv.FormatFloat(deviations, 'g', -1, 64)
+	return b.setCondition(metric,
+		fmt.Sprintf("detect(when(%s > %s + %s*%s) or when(%s < %s - %s*%s)).publish(%s)",
+			data, mean, n, stddev, data, mean, n, stddev, quoteSignalFlow(b.label)),
+		fmt.Sprintf("The value of %s is more than %s standard deviations from its mean over %s.", metric, n, over))
+}
+
+func (b *RuleBuilder) setCondition(metric, program, description string) *RuleBuilder {
+	switch {
+	case metric == "":
+		return b.fail(errors.New("metric must be set"))
+	case b.program != "":
+		return b.fail(errors.New("a rule can only have one condition"))
+	}
+	b.program = program
+	b.description = description
+	return b
+}
+
+func (b *RuleBuilder) fail(err error) *RuleBuilder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+// Build returns the rule, or the first error made while building it.
+func (b *RuleBuilder) Build() (*Rule, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.program == "" {
+		return nil, errors.New("a rule needs a condition")
+	}
+	if b.label == "" {
+		return nil, errors.New("a rule needs a detect label")
+	}
+	return &Rule{
+		Description: b.description,
+		DetectLabel: b.label,
+		Severity:    b.severity,
+	}, nil
+}
+
+// Program returns the SignalFlow detect clause for the rule's condition, to
+// be included in the detector's program.
+func (b *RuleBuilder) Program() (string, error) {
+	if _, err := b.Build(); err != nil {
+		return "", err
+	}
+	return b.program, nil
+}
+
+// signalFlowDuration formats d in the largest SignalFlow unit that divides it
+// evenly, e.g. 90*time.Minute is "90m" and 2*time.Hour is "2h".
+func signalFlowDuration(d time.Duration) string {
+	units := []struct {
+		suffix string
+		size   time.Duration
+	}{
+		{"w", 7 * 24 * time.Hour},
+		{"d", 24 * time.Hour},
+		{"h", time.Hour},
+		{"m", time.Minute},
+		{"s", time.Second},
+	}
+	for _, u := range units {
+		if d%u.size == 0 {
+			return strconv.FormatInt(int64(d/u.size), 10) + u.suffix
+		}
+	}
+	return strconv.FormatInt(int64(d/time.Millisecond), 10) + "ms"
+}
@@ -0,0 +1,75 @@
+package detector
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleBuilderWhenAbove(t *testing.T) {
+	b := NewRuleBuilder("CPU high", CRITICAL).WhenAbove("cpu.utilization", 90, 5*time.Minute)
+	rule, err := b.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "CPU high", rule.DetectLabel)
+	assert.Equal(t, CRITICAL, rule.Severity)
+	assert.Equal(t, "The value of cpu.utilization is above 90.", rule.Description)
+
+	program, err := b.Program()
+	assert.NoError(t, err)
+	assert.Equal(t, "detect(when(data('cpu.utilization') > 90, lasting='5m')).publish('CPU high')", program)
+}
+
+func TestRuleBuilderWhenBelow(t *testing.T) {
+	program, err := NewRuleBuilder("low", MINOR).WhenBelow("disk.free", 0.5, 0).Program()
+	assert.NoError(t, err)
+	assert.Equal(t, "detect(when(data('disk.free') < 0.5)).publish('low')", program)
+}
+
+func TestRuleBuilderWhenOutlier(t *testing.T) {
+	program, err := NewRuleBuilder("odd", WARNING).WhenOutlier("latency", 3, 90*time.Minute).Program()
+	assert.NoError(t, err)
+	assert.Equal(t, "detect(when(data('latency') > data('latency').mean(over='90m') + 3*data('latency').stddev(over='90m'))"+
+		" or when(data('latency') < data('latency').mean(over='90m') - 3*data('latency').stddev(over='90m'))).publish('odd')", program)
+}
+
+func TestRuleBuilderTwoOutliers(t *testing.T) {
+	latency, err := NewRuleBuilder("slow", WARNING).WhenOutlier("latency", 3, time.Hour).Program()
+	assert.NoError(t, err)
+	errs, err := NewRuleBuilder("failing", MAJOR).WhenOutlier("errors", 2.5, 10*time.Minute).Program()
+	assert.NoError(t, err)
+
+	// Both clauses go in the same program, along with the user's own streams
+	program := "A = data('requests').publish('A')\n" + latency + "\n" + errs
+	for _, line := range strings.Split(program, "\n")[1:] {
+		assert.True(t, strings.HasPrefix(line, "detect("), "Rule clauses should not assign variables: %s", line)
+	}
+	assert.Contains(t, latency, "data('latency').stddev(over='1h')")
+	assert.Contains(t, errs, "2.5*data('errors').stddev(over='10m')")
+	assert.Contains(t, errs, ".publish('failing')")
+}
+
+func TestRuleBuilderErrors(t *testing.T) {
+	_, err := NewRuleBuilder("x", INFO).Build()
+	assert.Error(t, err, "Missing condition should fail")
+	_, err = NewRuleBuilder("", INFO).WhenAbove("m", 1, 0).Build()
+	assert.Error(t, err, "Missing label should fail")
+	_, err = NewRuleBuilder("x", INFO).WhenAbove("", 1, 0).Build()
+	assert.Error(t, err, "Missing metric should fail")
+	_, err = NewRuleBuilder("x", INFO).WhenAbove("m", 1, -time.Second).Build()
+	assert.Error(t, err, "Negative duration should fail")
+	_, err = NewRuleBuilder("x", INFO).WhenOutlier("m", 0, time.Hour).Build()
+	assert.Error(t, err, "Zero deviations should fail")
+	_, err = NewRuleBuilder("x", INFO).WhenOutlier("m", 3, 0).Build()
+	assert.Error(t, err, "Zero window should fail")
+	_, err = NewRuleBuilder("x", INFO).WhenAbove("m", 1, 0).WhenBelow("m", 0, 0).Program()
+	assert.Error(t, err, "Two conditions should fail")
+}
+
+func TestSignalFlowDuration(t *testing.T) {
+	assert.Equal(t, "2w", signalFlowDuration(14*24*time.Hour))
+	assert.Equal(t, "1d", signalFlowDuration(24*time.Hour))
+	assert.Equal(t, "30s", signalFlowDuration(30*time.Second))
+	assert.Equal(t, "1500ms", signalFlowDuration(1500*time.Millisecond))
+}
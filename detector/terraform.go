@@ -0,0 +1,143 @@
+package detector
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/adampetrovic/signalfx-go/internal/hcl"
+	"github.com/adampetrovic/signalfx-go/notification"
+)
+
+// ToTerraformHCL returns a signalfx_detector resource for the detector, for
+// importing it into Terraform.  The resource is named after the detector.
+// Durations are converted from the API's milliseconds to the seconds that
+// Terraform uses.
+func (d *Detector) ToTerraformHCL() (string, error) {
+	if d.Name == "" {
+		return "", errors.New("detector has no name")
+	}
+	if d.ProgramText == "" {
+		return "", errors.New("detector has no program")
+	}
+
+	res := hcl.NewBlock("resource", "signalfx_detector", hcl.Identifier(d.Name))
+	res.Attr("name", d.Name)
+	if d.Description != "" {
+		res.Attr("description", d.Description)
+	}
+	res.Attr("program_text", d.ProgramText)
+	if d.MaxDelay != nil {
+		res.Attr("max_delay", *d.MaxDelay/1000)
+	}
+	if len(d.Tags) > 0 {
+		res.Attr("tags", d.Tags)
+	}
+	if len(d.Teams) > 0 {
+		res.Attr("teams", d.Teams)
+	}
+	if aw := d.AuthorizedWriters; aw != nil {
+		if len(aw.Teams) > 0 {
+			res.Attr("authorized_writer_teams", aw.Teams)
+		}
+		if len(aw.Users) > 0 {
+			res.Attr("authorized_writer_users", aw.Users)
+		}
+	}
+	if vo := d.VisualizationOptions; vo != nil {
+		if vo.DisableSampling {
+			res.Attr("disable_sampling", true)
+		}
+		if vo.ShowDataMarkers {
+			res.Attr("show_data_markers", true)
+		}
+		if vo.ShowEventLines {
+			res.Attr("show_event_lines", true)
+		}
+		if t := vo.Time; t != nil {
+			if t.Range != nil {
+				res.Attr("time_range", *t.Range/1000)
+			}
+			if t.Start != nil {
+				res.Attr("start_time", *t.Start/1000)
+			}
+			if t.End != nil {
+				res.Attr("end_time", *t.End/1000)
+			}
+		}
+	}
+
+	for _, r := range d.Rules {
+		rule := res.Block("rule")
+		if r.Description != "" {
+			rule.Attr("description", r.Description)
+		}
+		rule.Attr("detect_label", r.DetectLabel)
+		rule.Attr("severity", string(r.Severity))
+		if r.Disabled {
+			rule.Attr("disabled", true)
+		}
+		if len(r.Notifications) > 0 {
+			notifications := make([]string, len(r.Notifications))
+			for i, n := range r.Notifications {
+				s, err := terraformNotification(n)
+				if err != nil {
+					return "", fmt.Errorf("rule %q: %v", r.DetectLabel, err)
+				}
+				notifications[i] = s
+			}
+			rule.Attr("notifications", notifications)
+		}
+		if r.ParameterizedSubject != "" {
+			rule.Attr("parameterized_subject", r.ParameterizedSubject)
+		}
+		if r.ParameterizedBody != "" {
+			rule.Attr("parameterized_body", r.ParameterizedBody)
+		}
+		if r.RunbookUrl != "" {
+			rule.Attr("runbook_url", r.RunbookUrl)
+		}
+		if r.Tip != "" {
+			rule.Attr("tip", r.Tip)
+		}
+	}
+
+	return res.String(), nil
+}
+
+// terraformNotification returns the comma-separated form of a notification
+// that the Terraform provider uses, e.g. "Slack,credentialId,channel".
+func terraformNotification(n *notification.Notification) (string, error) {
+	switch v := n.Value.(type) {
+	case *notification.EmailNotification:
+		return "Email," + v.Email, nil
+	case *notification.TeamNotification:
+		return "Team," + v.Team, nil
+	case *notification.TeamEmailNotification:
+		return "TeamEmail," + v.Team, nil
+	case *notification.SlackNotification:
+		return "Slack," + v.CredentialId + "," + v.Channel, nil
+	case *notification.VictorOpsNotification:
+		return "VictorOps," + v.CredentialId + "," + v.RoutingKey, nil
+	case *notification.WebhookNotification:
+		return "Webhook," + v.CredentialId + "," + v.Secret + "," + v.Url, nil
+	case *notification.OpsgenieNotification:
+		// The provider also takes the credential's name, which the API
+		// doesn't return
+		return "Opsgenie," + v.CredentialId + ",," + v.ResponderName + "," + v.ResponderId + "," + v.ResponderType, nil
+	case *notification.PagerDutyNotification:
+		return "PagerDuty," + v.CredentialId, nil
+	case *notification.BigPandaNotification:
+		return "BigPanda," + v.CredentialId, nil
+	case *notification.JiraNotification:
+		return "Jira," + v.CredentialId, nil
+	case *notification.Office365Notification:
+		return "Office365," + v.CredentialId, nil
+	case *notification.ServiceNowNotification:
+		return "ServiceNow," + v.CredentialId, nil
+	case *notification.XMattersNotification:
+		return "XMatters," + v.CredentialId, nil
+	case *notification.AmazonEventBrigeNotification:
+		return "AmazonEventBridge," + v.CredentialId, nil
+	}
+	return "", fmt.Errorf("unsupported %s notification %T", n.Type, n.Value)
+}
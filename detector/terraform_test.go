@@ -0,0 +1,87 @@
+package detector
+
+import (
+	"testing"
+
+	"github.com/adampetrovic/signalfx-go/notification"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectorToTerraformHCL(t *testing.T) {
+	maxDelay := int32(30000)
+	timeRange := int64(3600000)
+	d := &Detector{
+		Name:        "CPU high (prod)",
+		Description: `Alerts when "cpu" is high`,
+		ProgramText: "A = data('cpu.utilization').mean(by=['host'])\ndetect(when(A > 90)).publish('CPU high')",
+		MaxDelay:    &maxDelay,
+		Tags:        []string{"prod"},
+		AuthorizedWriters: &AuthorizedWriters{
+			Teams: []string{"TeAm1"},
+		},
+		VisualizationOptions: &Visualization{
+			ShowDataMarkers: true,
+			Time:            &Time{Type: "relative", Range: &timeRange},
+		},
+		Rules: []*Rule{{
+			DetectLabel: "CPU high",
+			Severity:    CRITICAL,
+			Notifications: []*notification.Notification{
+				{Type: "Email", Value: &notification.EmailNotification{Type: "Email", Email: "oncall@example.com"}},
+				{Type: "Slack", Value: &notification.SlackNotification{Type: "Slack", CredentialId: "CrEd1", Channel: "alerts"}},
+			},
+			ParameterizedSubject: "{{ruleSeverity}} ${host}",
+			RunbookUrl:           "https://runbooks.example.com/cpu",
+		}, {
+			DetectLabel: "CPU high",
+			Severity:    INFO,
+			Disabled:    true,
+		}},
+	}
+
+	hcl, err := d.ToTerraformHCL()
+	assert.NoError(t, err)
+	assert.Equal(t, `resource "signalfx_detector" "cpu_high_prod" {
+  name         = "CPU high (prod)"
+  description  = "Alerts when \"cpu\" is high"
+  program_text = <<EOF
+A = data('cpu.utilization').mean(by=['host'])
+detect(when(A > 90)).publish('CPU high')
+EOF
+  max_delay               = 30
+  tags                    = ["prod"]
+  authorized_writer_teams = ["TeAm1"]
+  show_data_markers       = true
+  time_range              = 3600
+
+  rule {
+    detect_label          = "CPU high"
+    severity              = "Critical"
+    notifications         = ["Email,oncall@example.com", "Slack,CrEd1,alerts"]
+    parameterized_subject = "{{ruleSeverity}} $${host}"
+    runbook_url           = "https://runbooks.example.com/cpu"
+  }
+
+  rule {
+    detect_label = "CPU high"
+    severity     = "Info"
+    disabled     = true
+  }
+}
+`, hcl)
+}
+
+func TestDetectorToTerraformHCLErrors(t *testing.T) {
+	_, err := (&Detector{ProgramText: "detect(when(data('x') > 1)).publish('x')"}).ToTerraformHCL()
+	assert.Error(t, err, "Should need a name")
+
+	_, err = (&Detector{Name: "x"}).ToTerraformHCL()
+	assert.Error(t, err, "Should need a program")
+
+	_, err = (&Detector{
+		Name:        "x",
+		ProgramText: "detect(when(data('x') > 1)).publish('x')",
+		Rules:       []*Rule{{DetectLabel: "x", Notifications: []*notification.Notification{{Type: "Carrier pigeon"}}}},
+	}).ToTerraformHCL()
+	assert.Error(t, err, "Should reject unknown notifications")
+}
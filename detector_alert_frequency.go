@@ -0,0 +1,72 @@
+package signalfx
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/detector"
+	"github.com/adampetrovic/signalfx-go/signalflow"
+)
+
+// EstimateAlertFrequency replays a detector's program over the last lookback
+// of data and reports how often its rules would have triggered, to catch
+// noisy detectors before they page anyone.  Each time series that becomes
+// anomalous counts as an alert, whether or not the detector's notifications
+// or muting rules would have let it through.
+func (c *Client) EstimateAlertFrequency(ctx context.Context, detectorID string, lookback time.Duration) (*detector.AlertFrequencyEstimate, error) {
+	if lookback <= 0 {
+		return nil, errors.New("lookback must be > 0")
+	}
+
+	det, err := c.getDetector(ctx, detectorID)
+	if err != nil {
+		return nil, err
+	}
+
+	sf, err := c.signalFlowExecutor()
+	if err != nil {
+		return nil, err
+	}
+	defer sf.Close()
+
+	end := time.Now()
+	comp, err := sf.Execute(&signalflow.ExecuteRequest{
+		Program:   det.ProgramText,
+		Start:     end.Add(-lookback),
+		Stop:      end,
+		Immediate: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var alertTimes []time.Time
+	data, events := comp.Data(), comp.Events()
+	for data != nil || events != nil {
+		select {
+		case <-ctx.Done():
+			comp.Stop()
+			return nil, ctx.Err()
+		case _, ok := <-data:
+			if !ok {
+				data = nil
+			}
+		case ev, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			if ev.IsAnomalous() {
+				alertTimes = append(alertTimes, ev.Timestamp())
+			}
+		}
+	}
+	if err := comp.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(alertTimes, func(i, j int) bool { return alertTimes[i].Before(alertTimes[j]) })
+	return detector.NewAlertFrequencyEstimate(alertTimes, lookback), nil
+}
@@ -0,0 +1,63 @@
+package signalfx
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/idtool"
+	"github.com/adampetrovic/signalfx-go/signalflow"
+	"github.com/adampetrovic/signalfx-go/signalflow/messages"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateAlertFrequency(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/detector/string", verifyRequest(t, "GET", http.StatusOK, nil, "detector/get_success.json"))
+
+	fake := signalflow.NewFakeClient()
+	client.signalFlowExecutor = func() (signalflow.Executor, error) {
+		return fake, nil
+	}
+	event := func(tsid idtool.ID, ms uint64, state string) *messages.EventMessage {
+		return &messages.EventMessage{
+			TimestampedMessage: messages.TimestampedMessage{TimestampMillis: ms},
+			TSID:               tsid,
+			Properties:         map[string]interface{}{"is": state},
+		}
+	}
+	fake.InjectMessage("ch-1", event(1, 1557936000000, "anomalous"))
+	fake.InjectMessage("ch-1", event(1, 1557936600000, "ok"))
+	fake.InjectMessage("ch-1", event(2, 1557943200000, "anomalous"))
+	fake.InjectMessage("ch-1", &messages.DataMessage{})
+	fake.InjectMessage("ch-1", &messages.BaseControlMessage{Event: messages.EndOfChannelEvent})
+
+	est, err := client.EstimateAlertFrequency(context.Background(), "string", 24*time.Hour)
+	assert.NoError(t, err, "Unexpected error estimating alert frequency")
+	assert.Equal(t, 2, est.TotalAlerts, "Clearing events shouldn't count")
+	assert.Equal(t, 2.0, est.AlertsPerDay)
+	assert.Equal(t, 2*time.Hour, est.MeanTimeBetweenAlerts)
+
+	executions := fake.RecordedExecutions()
+	if assert.Len(t, executions, 1) {
+		assert.Equal(t, "string", executions[0].Program)
+		assert.Equal(t, 24*time.Hour, executions[0].Stop.Sub(executions[0].Start))
+	}
+}
+
+func TestEstimateAlertFrequencyErrors(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/detector/missing", verifyRequest(t, "GET", http.StatusNotFound, nil, ""))
+
+	_, err := client.EstimateAlertFrequency(context.Background(), "missing", 0)
+	assert.Error(t, err, "Should reject a zero lookback")
+
+	est, err := client.EstimateAlertFrequency(context.Background(), "missing", time.Hour)
+	assert.Error(t, err, "Should get an error for a missing detector")
+	assert.Nil(t, est)
+}
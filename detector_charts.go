@@ -0,0 +1,146 @@
+package signalfx
+
+import (
+	"context"
+	"sort"
+
+	"github.com/adampetrovic/signalfx-go/chart"
+	"github.com/adampetrovic/signalfx-go/dashboard"
+	"github.com/adampetrovic/signalfx-go/dashboard_group"
+	"github.com/adampetrovic/signalfx-go/detector"
+	"github.com/adampetrovic/signalfx-go/signalflow"
+)
+
+// GetChartsByDetector finds the charts whose programs read any of the metrics
+// that a detector's program reads.  They are sorted by the name of the
+// dashboard group each chart is in, with charts that aren't on a dashboard
+// last, then by chart name.  Metrics are compared by name, so a wildcard in
+// one program only matches the same wildcard in the other.
+//
+// There's no API for searching programs, so every chart, dashboard and
+// dashboard group is fetched.
+func (c *Client) GetChartsByDetector(ctx context.Context, detectorID string) ([]*chart.Chart, error) {
+	program, err := c.GetDetectorSignalFlowProgram(ctx, detectorID)
+	if err != nil {
+		return nil, err
+	}
+	metrics := signalflow.MetricNames(program)
+
+	charts := []*chart.Chart{}
+	if len(metrics) == 0 {
+		return charts, nil
+	}
+	err = c.forEachPage(ctx, ChartAPIURL, nil, func() (interface{}, func() int) {
+		page := &chart.SearchResult{}
+		return page, func() int {
+			for _, ch := range page.Results {
+				if readsAnyMetric(ch.ProgramText, metrics) {
+					charts = append(charts, ch)
+				}
+			}
+			return len(page.Results)
+		}
+	})
+	if err != nil || len(charts) == 0 {
+		return charts, err
+	}
+
+	groupNames, err := c.chartDashboardGroupNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(charts, func(i, j int) bool {
+		gi, gj := groupNames[charts[i].Id], groupNames[charts[j].Id]
+		if gi != gj {
+			return gj == "" || (gi != "" && gi < gj)
+		}
+		return charts[i].Name < charts[j].Name
+	})
+	return charts, nil
+}
+
+// GetDetectorsByChart finds the detectors whose programs read any of the
+// metrics that a chart's program reads, sorted by name.  Metrics are compared
+// as for GetChartsByDetector.
+func (c *Client) GetDetectorsByChart(ctx context.Context, chartID string) ([]*detector.Detector, error) {
+	ch, err := c.getChart(ctx, chartID, false)
+	if err != nil {
+		return nil, err
+	}
+	metrics := signalflow.MetricNames(ch.ProgramText)
+
+	detectors := []*detector.Detector{}
+	if len(metrics) == 0 {
+		return detectors, nil
+	}
+	err = c.forEachPage(ctx, DetectorAPIURL, nil, func() (interface{}, func() int) {
+		page := &detector.SearchResults{}
+		return page, func() int {
+			for i := range page.Results {
+				if readsAnyMetric(page.Results[i].ProgramText, metrics) {
+					detectors = append(detectors, &page.Results[i])
+				}
+			}
+			return len(page.Results)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(detectors, func(i, j int) bool { return detectors[i].Name < detectors[j].Name })
+	return detectors, nil
+}
+
+func readsAnyMetric(program string, metrics []string) bool {
+	for _, name := range signalflow.MetricNames(program) {
+		for _, m := range metrics {
+			if name == m {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// chartDashboardGroupNames maps the ID of every chart on a dashboard to the
+// name of the dashboard's group.  A chart on dashboards in several groups
+// gets the first group name alphabetically.
+func (c *Client) chartDashboardGroupNames(ctx context.Context) (map[string]string, error) {
+	groupNames := map[string]string{}
+	err := c.forEachPage(ctx, DashboardGroupAPIURL, nil, func() (interface{}, func() int) {
+		page := &dashboard_group.SearchResult{}
+		return page, func() int {
+			for _, g := range page.Results {
+				groupNames[g.Id] = g.Name
+			}
+			return len(page.Results)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	chartGroups := map[string]string{}
+	err = c.forEachPage(ctx, DashboardAPIURL, nil, func() (interface{}, func() int) {
+		page := &dashboard.SearchResult{}
+		return page, func() int {
+			for _, d := range page.Results {
+				name := groupNames[d.GroupId]
+				if name == "" {
+					continue
+				}
+				for _, dc := range d.Charts {
+					if existing, ok := chartGroups[dc.ChartId]; !ok || name < existing {
+						chartGroups[dc.ChartId] = name
+					}
+				}
+			}
+			return len(page.Results)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return chartGroups, nil
+}
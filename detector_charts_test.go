@@ -0,0 +1,76 @@
+package signalfx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func respondWith(body string) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}
+}
+
+func TestGetChartsByDetector(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/detector/DeT1", respondWith(`{"id": "DeT1", "programText": "A = data('cpu.utilization').publish(label='A')"}`))
+	mux.HandleFunc("/v2/chart", respondWith(`{"count": 4, "results": [
+		{"id": "ChA", "name": "CPU", "programText": "data('cpu.utilization').mean().publish()"},
+		{"id": "ChB", "name": "Memory", "programText": "data('memory.utilization').publish()"},
+		{"id": "ChC", "name": "Both", "programText": "data('memory.free').publish(); data(\"cpu.utilization\").publish()"},
+		{"id": "ChD", "name": "Loose CPU", "programText": "data('cpu.utilization').publish()"}
+	]}`))
+	mux.HandleFunc("/v2/dashboardgroup", respondWith(`{"count": 2, "results": [
+		{"id": "GrZ", "name": "Zebra"},
+		{"id": "GrA", "name": "Alpha"}
+	]}`))
+	mux.HandleFunc("/v2/dashboard", respondWith(`{"count": 2, "results": [
+		{"id": "DaZ", "groupId": "GrZ", "charts": [{"chartId": "ChA"}]},
+		{"id": "DaA", "groupId": "GrA", "charts": [{"chartId": "ChB"}, {"chartId": "ChC"}]}
+	]}`))
+
+	result, err := client.GetChartsByDetector(context.Background(), "DeT1")
+	assert.NoError(t, err, "Unexpected error getting charts by detector")
+	ids := []string{}
+	for _, ch := range result {
+		ids = append(ids, ch.Id)
+	}
+	assert.Equal(t, []string{"ChC", "ChA", "ChD"}, ids, "Should be sorted by group name with charts off dashboards last")
+}
+
+func TestGetDetectorsByChart(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/chart/ChA", respondWith(`{"id": "ChA", "programText": "data(metric='cpu.utilization').publish()"}`))
+	mux.HandleFunc("/v2/detector", respondWith(`{"count": 3, "results": [
+		{"id": "DeB", "name": "Busy", "programText": "detect(when(data('cpu.utilization') > 90))"},
+		{"id": "DeM", "name": "Memory", "programText": "detect(when(data('memory.utilization') > 90))"},
+		{"id": "DeA", "name": "Alive", "programText": "detect(when(data('cpu.utilization') < 1))"}
+	]}`))
+
+	result, err := client.GetDetectorsByChart(context.Background(), "ChA")
+	assert.NoError(t, err, "Unexpected error getting detectors by chart")
+	if assert.Len(t, result, 2) {
+		assert.Equal(t, "DeA", result[0].Id)
+		assert.Equal(t, "DeB", result[1].Id)
+	}
+}
+
+func TestGetChartsByDetectorNotFound(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/detector/DeT1", verifyRequest(t, "GET", http.StatusNotFound, nil, ""))
+
+	result, err := client.GetChartsByDetector(context.Background(), "DeT1")
+	assert.Error(t, err, "Should get an error for a missing detector")
+	assert.Nil(t, result)
+}
@@ -0,0 +1,105 @@
+package signalfx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/detector"
+)
+
+// GetDetectorIncidents gets a detector's active incidents, and its resolved
+// ones too if includeResolved is set.
+func (c *Client) GetDetectorIncidents(ctx context.Context, detectorID string, includeResolved bool) ([]*detector.Incident, error) {
+	params := url.Values{}
+	if includeResolved {
+		params.Add("includeResolved", "true")
+	}
+
+	resp, err := c.doRequestWithContext(ctx, "GET", DetectorAPIURL+"/"+detectorID+"/incidents", params, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var finalIncidents []*detector.Incident
+
+	err = c.decodeJSON(resp.Body, &finalIncidents)
+
+	return finalIncidents, err
+}
+
+// WaitForDetectorAlert polls a detector's active incidents every
+// PollInterval until one matches condition, and returns it.  A nil condition
+// matches any active incident.  It gives up when ctx is done or a request
+// fails.
+func (c *Client) WaitForDetectorAlert(ctx context.Context, detectorID string, condition func(*detector.Incident) bool) (*detector.Incident, error) {
+	var found *detector.Incident
+	err := c.poll(ctx, func() (bool, error) {
+		incidents, err := c.GetDetectorIncidents(ctx, detectorID, false)
+		if err != nil {
+			return false, err
+		}
+		for _, inc := range incidents {
+			if inc.Active && (condition == nil || condition(inc)) {
+				found = inc
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	return found, err
+}
+
+// WaitForDetectorResolution polls a detector's incidents every PollInterval
+// until the one with incidentID is no longer active, and returns it.  It
+// gives up when ctx is done, a request fails or the incident doesn't exist.
+func (c *Client) WaitForDetectorResolution(ctx context.Context, detectorID string, incidentID string) (*detector.Incident, error) {
+	var resolved *detector.Incident
+	err := c.poll(ctx, func() (bool, error) {
+		incidents, err := c.GetDetectorIncidents(ctx, detectorID, true)
+		if err != nil {
+			return false, err
+		}
+		for _, inc := range incidents {
+			if inc.IncidentId == incidentID {
+				if inc.Active {
+					return false, nil
+				}
+				resolved = inc
+				return true, nil
+			}
+		}
+		return false, fmt.Errorf("detector %s has no incident %s", detectorID, incidentID)
+	})
+	return resolved, err
+}
+
+// poll calls check every PollInterval, starting immediately, until it
+// returns true or an error, or ctx is done.  A request cut short by ctx
+// returns ctx's error.
+func (c *Client) poll(ctx context.Context, check func() (bool, error)) error {
+	for {
+		done, err := check()
+		if err != nil && ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil || done {
+			return err
+		}
+
+		t := time.NewTimer(c.pollInterval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
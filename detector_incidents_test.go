@@ -0,0 +1,91 @@
+package signalfx
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/detector"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDetectorIncidents(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	params := url.Values{}
+	params.Add("includeResolved", "true")
+	mux.HandleFunc("/v2/detector/DeT1/incidents", verifyRequest(t, "GET", http.StatusOK, params, "detector/incidents_success.json"))
+
+	result, err := client.GetDetectorIncidents(context.Background(), "DeT1", true)
+	assert.NoError(t, err, "Unexpected error getting incidents")
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, "InC1", result[0].IncidentId)
+		assert.Equal(t, detector.CRITICAL, result[0].Severity)
+		assert.Len(t, result[0].Events, 1)
+	}
+}
+
+func TestWaitForDetectorAlert(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	client.pollInterval = time.Millisecond
+
+	var calls int32
+	mux.HandleFunc("/v2/detector/DeT1/incidents", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.Write([]byte(`[]`))
+			return
+		}
+		verifyRequest(t, "GET", http.StatusOK, nil, "detector/incidents_success.json")(w, r)
+	})
+
+	inc, err := client.WaitForDetectorAlert(context.Background(), "DeT1", func(inc *detector.Incident) bool {
+		return inc.Severity == detector.CRITICAL
+	})
+	assert.NoError(t, err, "Unexpected error waiting for alert")
+	assert.Equal(t, "InC1", inc.IncidentId)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestWaitForDetectorAlertCancel(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	client.pollInterval = time.Millisecond
+
+	mux.HandleFunc("/v2/detector/DeT1/incidents", verifyRequest(t, "GET", http.StatusOK, nil, "detector/incidents_success.json"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	inc, err := client.WaitForDetectorAlert(ctx, "DeT1", func(inc *detector.Incident) bool {
+		return inc.Severity == detector.MINOR
+	})
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Nil(t, inc)
+}
+
+func TestWaitForDetectorResolution(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	client.pollInterval = time.Millisecond
+
+	var calls int32
+	mux.HandleFunc("/v2/detector/DeT1/incidents", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "true", r.URL.Query().Get("includeResolved"))
+		if atomic.AddInt32(&calls, 1) < 2 {
+			w.Write([]byte(`[{"active": true, "incidentId": "InC1"}]`))
+			return
+		}
+		w.Write([]byte(`[{"active": false, "anomalyState": "OK", "incidentId": "InC1"}]`))
+	})
+
+	inc, err := client.WaitForDetectorResolution(context.Background(), "DeT1", "InC1")
+	assert.NoError(t, err, "Unexpected error waiting for resolution")
+	assert.Equal(t, "OK", inc.AnomalyState)
+
+	_, err = client.WaitForDetectorResolution(context.Background(), "DeT1", "InC2")
+	assert.Error(t, err, "Missing incidents should be an error")
+}
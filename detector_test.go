@@ -1,10 +1,13 @@
 package signalfx
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/url"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/adampetrovic/signalfx-go/detector"
 	"github.com/stretchr/testify/assert"
@@ -36,6 +39,35 @@ func TestCreateBadDetector(t *testing.T) {
 	assert.Nil(t, result, "Should have a null detector on bad create")
 }
 
+func TestCloneDetector(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/detector/string", verifyRequest(t, "GET", http.StatusOK, nil, "detector/get_success.json"))
+	mux.HandleFunc("/v2/detector", func(w http.ResponseWriter, r *http.Request) {
+		req := &detector.CreateUpdateDetectorRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(req), "Unexpected error decoding clone request")
+		assert.Equal(t, "copy", req.Name, "Name does not match")
+		assert.Nil(t, req.Rules[0].Notifications, "Notifications should be cleared")
+		verifyRequest(t, "POST", http.StatusOK, nil, "detector/create_success.json")(w, r)
+	})
+
+	result, err := client.CloneDetector(context.Background(), "string", "copy", &detector.CloneOptions{ClearNotifications: true})
+	assert.NoError(t, err, "Unexpected error cloning detector")
+	assert.NotNil(t, result, "Should have gotten a detector")
+}
+
+func TestCloneMissingDetector(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/detector/string", verifyRequest(t, "GET", http.StatusNotFound, nil, ""))
+
+	result, err := client.CloneDetector(context.Background(), "string", "copy", nil)
+	assert.Error(t, err, "Should have gotten an error from a missing detector")
+	assert.Nil(t, result, "Should have gotten a nil result from a missing detector")
+}
+
 func TestDeleteDetector(t *testing.T) {
 	teardown := setup()
 	defer teardown()
@@ -107,6 +139,36 @@ func TestGetDetector(t *testing.T) {
 	assert.Equal(t, result.Name, "string", "Name does not match")
 }
 
+func TestGetDetectorSignalFlowProgram(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	params := url.Values{}
+	params.Add("fields", "programText")
+	mux.HandleFunc("/v2/detector/string", verifyRequest(t, "GET", http.StatusOK, params, "detector/get_success.json"))
+
+	program, err := client.GetDetectorSignalFlowProgram(context.Background(), "string")
+	assert.NoError(t, err, "Unexpected error getting program")
+	assert.Equal(t, "string", program)
+}
+
+func TestGetDetectorSignalFlowProgramWithoutFieldFilter(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/detector/string", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fields") != "" {
+			verifyRequest(t, "GET", http.StatusBadRequest, nil, "")(w, r)
+			return
+		}
+		verifyRequest(t, "GET", http.StatusOK, nil, "detector/get_success.json")(w, r)
+	})
+
+	program, err := client.GetDetectorSignalFlowProgram(context.Background(), "string")
+	assert.NoError(t, err, "Should fall back to getting the whole detector")
+	assert.Equal(t, "string", program)
+}
+
 func TestGetMissingDetector(t *testing.T) {
 	teardown := setup()
 	defer teardown()
@@ -164,3 +226,123 @@ func TestUpdateMissingDetector(t *testing.T) {
 	assert.Error(t, err, "Should have gotten an error from an update on a missing detector")
 	assert.Nil(t, result, "Should have gotten a nil result from an update on a missing detector")
 }
+
+func TestGetDetectorEvents(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	params := url.Values{}
+	params.Add("from", "1557936000000")
+	params.Add("limit", "10")
+	params.Add("incidentId", "InC1")
+
+	mux.HandleFunc("/v2/detector/string/events", verifyRequest(t, "GET", http.StatusOK, params, "detector/events_success.json"))
+
+	results, err := client.GetDetectorEvents(context.Background(), "string", &detector.EventQueryParams{
+		StartTime:  time.Unix(1557936000, 0),
+		Limit:      10,
+		IncidentID: "InC1",
+	})
+	assert.NoError(t, err, "Unexpected error getting detector events")
+	assert.Len(t, results, 2, "Incorrect number of events")
+	assert.Equal(t, detector.CRITICAL, results[0].Severity, "Severity does not match")
+	assert.Equal(t, "ANOMALOUS", results[0].Status, "Status does not match")
+	assert.Equal(t, "web-1", results[0].AffectedDimensions["host"], "Dimensions do not match")
+	assert.Equal(t, 97.5, results[0].InputValues["A"], "Input values do not match")
+}
+
+func TestGetMissingDetectorEvents(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/detector/string/events", verifyRequest(t, "GET", http.StatusNotFound, nil, ""))
+
+	results, err := client.GetDetectorEvents(context.Background(), "string", nil)
+	assert.Error(t, err, "Should have gotten an error from a missing detector")
+	assert.Nil(t, results, "Should have gotten a nil result from a missing detector")
+}
+
+func TestDetectorEventIterator(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	var offsets []string
+	mux.HandleFunc("/v2/detector/string/events", func(w http.ResponseWriter, r *http.Request) {
+		offsets = append(offsets, r.URL.Query().Get("offset"))
+		if r.URL.Query().Get("offset") == "" {
+			verifyRequest(t, "GET", http.StatusOK, nil, "detector/events_success.json")(w, r)
+		} else {
+			w.Write([]byte("[]"))
+		}
+	})
+
+	it := client.DetectorEvents(context.Background(), "string", &detector.EventQueryParams{Limit: 2})
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Event().Id)
+	}
+	assert.NoError(t, it.Err(), "Unexpected error iterating events")
+	assert.Equal(t, []string{"EvT1", "EvT2"}, ids, "Events do not match")
+	assert.Equal(t, []string{"", "2"}, offsets, "Should have requested a second page")
+}
+
+func TestSearchDetectorsByProgram(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/detector", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "100", r.URL.Query().Get("limit"), "Incorrect page size")
+		w.Write([]byte(`{"count": 3, "results": [
+			{"id": "A", "programText": "detect(when(data('cpu.utilization') > 90)).publish('x')"},
+			{"id": "B", "programText": "detect(when(data(\"cpu.utilization\") > 50)).publish('x')"},
+			{"id": "C", "programText": "detect(when(data('cpu.utilization.max') > 90)).publish('x')"}
+		]}`))
+	})
+
+	results, err := client.SearchDetectorsByProgram(context.Background(), "cpu.utilization")
+	assert.NoError(t, err, "Unexpected error searching detectors")
+	assert.Len(t, results, 2, "Incorrect number of results")
+	assert.Equal(t, "A", results[0].Id, "Id does not match")
+	assert.Equal(t, "B", results[1].Id, "Id does not match")
+}
+
+func TestSearchDetectorsByProgramBadStatus(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/detector", verifyRequest(t, "GET", http.StatusInternalServerError, nil, ""))
+
+	_, err := client.SearchDetectorsByProgram(context.Background(), "cpu.utilization")
+	assert.Error(t, err, "Should have gotten an error from a failed search")
+}
+
+func TestBulkDeleteDetectors(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/detector/a", verifyRequest(t, "DELETE", http.StatusNoContent, nil, ""))
+	mux.HandleFunc("/v2/detector/b", verifyRequest(t, "DELETE", http.StatusNoContent, nil, ""))
+	mux.HandleFunc("/v2/detector/gone", verifyRequest(t, "DELETE", http.StatusNotFound, nil, ""))
+	mux.HandleFunc("/v2/detector/locked", verifyRequest(t, "DELETE", http.StatusForbidden, nil, ""))
+
+	result, err := client.BulkDeleteDetectors(context.Background(), []string{"b", "gone", "a", "locked"})
+	assert.NoError(t, err, "Unexpected error bulk deleting detectors")
+	assert.Equal(t, []string{"a", "b"}, result.Deleted, "Deleted does not match")
+	assert.Equal(t, []string{"gone"}, result.NotFound, "NotFound does not match")
+	assert.Len(t, result.Failed, 1, "Failed does not match")
+	assert.Error(t, result.Failed["locked"], "Should have an error for the locked detector")
+}
+
+func TestBulkDeleteDetectorsCanceled(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := client.BulkDeleteDetectors(ctx, []string{"a"})
+	assert.Error(t, err, "Should have gotten an error from a canceled context")
+	assert.Len(t, result.Failed, 1, "Deletion should have failed")
+}
+
+var _ detector.TeamMemberGetter = &Client{}
@@ -0,0 +1,15 @@
+// Package dimension contains models for updating dimensions in bulk, and for
+// dimensions that apply across all of an organization's metric time series.
+package dimension
+
+// GlobalDimensionRequest creates a dimension whose properties are applied to
+// every metric time series that has it.
+type GlobalDimensionRequest struct {
+	DimensionKey   string            `json:"key"`
+	DimensionValue string            `json:"value"`
+	Properties     map[string]string `json:"customProperties,omitempty"`
+	// Whether to also apply the properties to time series that already
+	// exist, rather than only to new ones.  This runs as a job in the
+	// background.
+	ApplyToExistingMTS bool `json:"applyToExistingMTS,omitempty"`
+}
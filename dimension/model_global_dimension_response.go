@@ -0,0 +1,8 @@
+package dimension
+
+// GlobalDimensionResponse is the result of creating a global dimension.
+type GlobalDimensionResponse struct {
+	// The ID of the job applying the properties to existing time series, if
+	// ApplyToExistingMTS was set
+	JobID string `json:"jobId,omitempty"`
+}
@@ -0,0 +1,25 @@
+package dimension
+
+// A set of custom properties and tags to add to a single dimension. Existing
+// properties and tags that aren't mentioned are left alone.
+type PropertyUpdate struct {
+	// The dimension name (key) of the dimension to update
+	DimensionKey string `json:"dimensionKey"`
+	// The dimension value of the dimension to update
+	DimensionValue string `json:"dimensionValue"`
+	// Custom properties to add or overwrite
+	Properties map[string]string `json:"properties,omitempty"`
+	// Tags to add
+	Tags []string `json:"tags,omitempty"`
+}
+
+// The outcome of applying several property updates at once.
+type BulkUpdateResult struct {
+	// Number of updates that were applied
+	Succeeded int
+	// Number of updates that weren't applied
+	Failed int
+	// The error for each update, in the same order as the updates. Updates
+	// that were applied have a nil error.
+	Errors []error
+}
@@ -0,0 +1,109 @@
+package signalfx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/adampetrovic/signalfx-go/dimension"
+	"github.com/adampetrovic/signalfx-go/metrics_metadata"
+)
+
+// DimensionPropertyConflictRetries is how many times DeleteDimensionProperty
+// retries after its update conflicts with someone else's.
+const DimensionPropertyConflictRetries = 5
+
+// BulkAddDimensionProperties adds the custom properties and tags in each of
+// updates to its dimension, leaving any others on the dimension in place.
+// Each update is its own request, and up to the client's MaxConcurrent run at
+// once. Updates left unsent because ctx is done fail with ctx's error. The
+// error is only non-nil if ctx is done before every update was attempted.
+func (c *Client) BulkAddDimensionProperties(ctx context.Context, updates []dimension.PropertyUpdate) (*dimension.BulkUpdateResult, error) {
+	result := &dimension.BulkUpdateResult{
+		Errors: make([]error, len(updates)),
+	}
+
+	c.forEachConcurrently(len(updates), func(i int) {
+		result.Errors[i] = c.addDimensionProperties(ctx, &updates[i])
+	})
+
+	for _, err := range result.Errors {
+		if err == nil {
+			result.Succeeded++
+		} else {
+			result.Failed++
+		}
+	}
+	return result, ctx.Err()
+}
+
+// addDimensionProperties PATCHes a single dimension, which merges the given
+// properties and tags into its existing ones.
+func (c *Client) addDimensionProperties(ctx context.Context, update *dimension.PropertyUpdate) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if update.DimensionKey == "" || update.DimensionValue == "" {
+		return errors.New("dimension key and value must not be empty")
+	}
+
+	payload, err := c.marshal(&metrics_metadata.Dimension{
+		CustomProperties: update.Properties,
+		Tags:             update.Tags,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.doRequestWithContext(ctx, "PATCH", DimensionAPIURL+"/"+update.DimensionKey+"/"+update.DimensionValue, nil, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(resp)
+	}
+
+	return nil
+}
+
+// DeleteDimensionProperty removes the custom property propertyKey from a
+// dimension, leaving its other properties and tags in place. It fetches the
+// dimension and PUTs it back without the property; if the dimension changed in
+// between and the API responds 409 Conflict, it starts over, up to
+// DimensionPropertyConflictRetries times. Deleting a property the dimension
+// doesn't have is not an error.
+func (c *Client) DeleteDimensionProperty(ctx context.Context, dimKey, dimValue, propertyKey string) error {
+	return c.deleteDimensionProperty(ctx, dimKey, dimValue, propertyKey, false)
+}
+
+// DeleteDimensionPropertyCAS is DeleteDimensionProperty without the retries:
+// if the dimension changed between fetching and updating it, the 409 Conflict
+// *APIError is returned straight away.
+func (c *Client) DeleteDimensionPropertyCAS(ctx context.Context, dimKey, dimValue, propertyKey string) error {
+	return c.deleteDimensionProperty(ctx, dimKey, dimValue, propertyKey, true)
+}
+
+func (c *Client) deleteDimensionProperty(ctx context.Context, dimKey, dimValue, propertyKey string, cas bool) error {
+	for attempt := 0; ; attempt++ {
+		dim, err := c.getDimension(ctx, dimKey, dimValue)
+		if err != nil {
+			return err
+		}
+		if _, ok := dim.CustomProperties[propertyKey]; !ok {
+			return nil
+		}
+		delete(dim.CustomProperties, propertyKey)
+
+		_, err = c.updateDimension(ctx, dimKey, dimValue, dim)
+		if err == nil {
+			return nil
+		}
+		apiErr, ok := err.(*APIError)
+		if !ok || apiErr.StatusCode != http.StatusConflict || cas || attempt >= DimensionPropertyConflictRetries {
+			return err
+		}
+	}
+}
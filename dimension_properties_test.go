@@ -0,0 +1,146 @@
+package signalfx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/adampetrovic/signalfx-go/dimension"
+	"github.com/adampetrovic/signalfx-go/metrics_metadata"
+)
+
+func TestBulkAddDimensionProperties(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/dimension/host/a", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PATCH", r.Method, "Incorrect HTTP method")
+		dim := &metrics_metadata.Dimension{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(dim))
+		assert.Equal(t, map[string]string{"env": "prod"}, dim.CustomProperties)
+		assert.Equal(t, []string{"web"}, dim.Tags)
+		verifyRequest(t, "PATCH", http.StatusOK, nil, "metrics_metadata/get_dimension_success.json")(w, r)
+	})
+	mux.HandleFunc("/v2/dimension/host/locked", verifyRequest(t, "PATCH", http.StatusForbidden, nil, ""))
+
+	result, err := client.BulkAddDimensionProperties(context.Background(), []dimension.PropertyUpdate{
+		{DimensionKey: "host", DimensionValue: "a", Properties: map[string]string{"env": "prod"}, Tags: []string{"web"}},
+		{DimensionKey: "host", DimensionValue: "locked"},
+		{DimensionKey: "host"},
+	})
+	assert.NoError(t, err, "Unexpected error bulk updating dimensions")
+	assert.Equal(t, 1, result.Succeeded, "Succeeded does not match")
+	assert.Equal(t, 2, result.Failed, "Failed does not match")
+	assert.NoError(t, result.Errors[0])
+	assert.Error(t, result.Errors[1], "Should have an error for the locked dimension")
+	assert.Error(t, result.Errors[2], "Should have an error for the missing value")
+}
+
+func TestBulkAddDimensionPropertiesConcurrency(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	var calls, running, maxRunning int32
+	mux.HandleFunc("/v2/dimension/host/", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	updates := make([]dimension.PropertyUpdate, 501)
+	for i := range updates {
+		updates[i] = dimension.PropertyUpdate{DimensionKey: "host", DimensionValue: strconv.Itoa(i)}
+	}
+
+	result, err := client.BulkAddDimensionProperties(context.Background(), updates)
+	assert.NoError(t, err, "Unexpected error bulk updating dimensions")
+	assert.Equal(t, len(updates), result.Succeeded, "Every update should have been applied")
+	assert.Equal(t, int32(len(updates)), atomic.LoadInt32(&calls))
+	assert.True(t, atomic.LoadInt32(&maxRunning) <= int32(client.maxConcurrent), "Should run at most MaxConcurrent updates at once")
+}
+
+func TestBulkAddDimensionPropertiesCanceled(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := client.BulkAddDimensionProperties(ctx, []dimension.PropertyUpdate{{DimensionKey: "host", DimensionValue: "a"}})
+	assert.Error(t, err, "Should have gotten an error from a canceled context")
+	assert.Equal(t, 1, result.Failed, "Update should have failed")
+}
+
+// dimensionPropertyServer serves a dimension with env and team properties,
+// answering the first conflicts PUTs with 409 Conflict.
+func dimensionPropertyServer(t *testing.T, conflicts int32) *int32 {
+	var puts int32
+	mux.HandleFunc("/v2/dimension/host/a", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case "GET":
+			_, _ = w.Write([]byte(`{"key":"host","value":"a","customProperties":{"env":"prod","team":"web"},"lastUpdated":1}`))
+		case "PUT":
+			dim := &metrics_metadata.Dimension{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(dim))
+			assert.Equal(t, map[string]string{"team": "web"}, dim.CustomProperties, "Only env should be removed")
+			if atomic.AddInt32(&puts, 1) <= conflicts {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(dim)
+		default:
+			assert.Fail(t, "Unexpected method "+r.Method)
+		}
+	})
+	return &puts
+}
+
+func TestDeleteDimensionProperty(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	puts := dimensionPropertyServer(t, 2)
+
+	err := client.DeleteDimensionProperty(context.Background(), "host", "a", "env")
+	assert.NoError(t, err, "Unexpected error deleting property")
+	assert.Equal(t, int32(3), atomic.LoadInt32(puts), "Conflicting updates should be retried")
+}
+
+func TestDeleteDimensionPropertyCAS(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	puts := dimensionPropertyServer(t, 1)
+
+	err := client.DeleteDimensionPropertyCAS(context.Background(), "host", "a", "env")
+	if assert.Error(t, err, "Should have gotten the conflict") {
+		assert.Equal(t, http.StatusConflict, err.(*APIError).StatusCode)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(puts), "CAS updates should not be retried")
+}
+
+func TestDeleteDimensionPropertyMissing(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	puts := dimensionPropertyServer(t, 0)
+
+	err := client.DeleteDimensionProperty(context.Background(), "host", "a", "nope")
+	assert.NoError(t, err, "Deleting a missing property should succeed")
+	assert.Equal(t, int32(0), atomic.LoadInt32(puts), "Nothing should have been updated")
+}
@@ -0,0 +1,128 @@
+package signalfx
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIError is returned by client methods when the API responds with an
+// unexpected status code.
+type APIError struct {
+	// The HTTP status code of the response
+	StatusCode int
+	// The body of the response, which usually explains what went wrong
+	Body string
+	// The API path that was requested, e.g. "/v2/detector/abc"
+	Resource string
+	// The HTTP method of the request, e.g. "GET"
+	Operation string
+	// How long the API asked to wait before retrying, from the response's
+	// Retry-After header, or 0 if it didn't send one
+	RetryAfter time.Duration
+}
+
+// Sentinel errors for the common failure classes.  An *APIError unwraps to
+// the matching one, so callers on Go 1.13+ can write
+// errors.Is(err, signalfx.ErrNotFound).  ErrServerError matches every 5xx
+// status.
+var (
+	ErrNotFound     = &APIError{StatusCode: http.StatusNotFound}
+	ErrConflict     = &APIError{StatusCode: http.StatusConflict}
+	ErrUnauthorized = &APIError{StatusCode: http.StatusUnauthorized}
+	ErrForbidden    = &APIError{StatusCode: http.StatusForbidden}
+	ErrRateLimit    = &APIError{StatusCode: http.StatusTooManyRequests}
+	ErrServerError  = &APIError{StatusCode: http.StatusInternalServerError}
+)
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("Bad status %d", e.StatusCode)
+	if e.Body != "" {
+		msg += ": " + e.Body
+	}
+	if e.Operation != "" || e.Resource != "" {
+		msg = e.Operation + " " + e.Resource + ": " + msg
+	}
+	return msg
+}
+
+// Unwrap returns the sentinel error for e's status code, or nil if there
+// isn't one.
+func (e *APIError) Unwrap() error {
+	var sentinel *APIError
+	switch {
+	case e.StatusCode == http.StatusNotFound:
+		sentinel = ErrNotFound
+	case e.StatusCode == http.StatusConflict:
+		sentinel = ErrConflict
+	case e.StatusCode == http.StatusUnauthorized:
+		sentinel = ErrUnauthorized
+	case e.StatusCode == http.StatusForbidden:
+		sentinel = ErrForbidden
+	case e.StatusCode == http.StatusTooManyRequests:
+		sentinel = ErrRateLimit
+	case e.StatusCode >= 500 && e.StatusCode < 600:
+		sentinel = ErrServerError
+	}
+	if sentinel == nil || sentinel == e {
+		return nil
+	}
+	return sentinel
+}
+
+// newAPIError builds an APIError from an unexpected response, consuming its
+// body.
+func newAPIError(resp *http.Response) error {
+	message, _ := ioutil.ReadAll(resp.Body)
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Body:       string(message),
+	}
+	if resp.Request != nil {
+		apiErr.Operation = resp.Request.Method
+		apiErr.Resource = resp.Request.URL.Path
+	}
+	apiErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	return apiErr
+}
+
+// parseRetryAfter returns the wait in a Retry-After header, which is either a
+// number of seconds or an HTTP date, or 0 if it's empty, invalid or past.
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// ResourceErrors is returned alongside partial results by methods that fetch
+// several resources, for the ones that couldn't be fetched.  It is keyed by
+// resource ID.
+type ResourceErrors map[string]error
+
+func (e ResourceErrors) Error() string {
+	ids := make([]string, 0, len(e))
+	for id := range e {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	msgs := make([]string, len(ids))
+	for i, id := range ids {
+		msgs[i] = id + ": " + e[id].Error()
+	}
+	return fmt.Sprintf("%d resources failed: %s", len(e), strings.Join(msgs, "; "))
+}
@@ -0,0 +1,59 @@
+package signalfx
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIErrorFromRequest(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/detector/string", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("no such detector"))
+	})
+
+	_, err := client.GetDetector("string")
+	apiErr, ok := err.(*APIError)
+	if assert.True(t, ok, "Should get an *APIError") {
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+		assert.Equal(t, "no such detector", apiErr.Body)
+		assert.Equal(t, "/v2/detector/string", apiErr.Resource)
+		assert.Equal(t, "GET", apiErr.Operation)
+		assert.Equal(t, "GET /v2/detector/string: Bad status 404: no such detector", apiErr.Error())
+		assert.Equal(t, ErrNotFound, apiErr.Unwrap())
+	}
+}
+
+func TestAPIErrorUnwrap(t *testing.T) {
+	assert.Equal(t, ErrConflict, (&APIError{StatusCode: 409}).Unwrap())
+	assert.Equal(t, ErrUnauthorized, (&APIError{StatusCode: 401}).Unwrap())
+	assert.Equal(t, ErrForbidden, (&APIError{StatusCode: 403}).Unwrap())
+	assert.Equal(t, ErrRateLimit, (&APIError{StatusCode: 429}).Unwrap())
+	assert.Equal(t, ErrServerError, (&APIError{StatusCode: 503}).Unwrap(), "Every 5xx should be a server error")
+	assert.Nil(t, (&APIError{StatusCode: 400}).Unwrap(), "Unclassified statuses have no sentinel")
+	assert.Nil(t, ErrNotFound.Unwrap(), "Sentinels shouldn't unwrap to themselves")
+	assert.Equal(t, "Bad status 404", ErrNotFound.Error())
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, 120*time.Second, parseRetryAfter("120", now))
+	assert.Equal(t, 30*time.Second, parseRetryAfter("Wed, 01 Jan 2020 00:00:30 GMT", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("Tue, 31 Dec 2019 23:59:00 GMT", now), "Past dates mean no wait")
+	assert.Equal(t, time.Duration(0), parseRetryAfter("", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("-5", now))
+}
+
+func TestResourceErrors(t *testing.T) {
+	err := ResourceErrors{
+		"b": &APIError{StatusCode: 500},
+		"a": &APIError{StatusCode: 403},
+	}
+	assert.Equal(t, "2 resources failed: a: Bad status 403; b: Bad status 500", err.Error())
+}
@@ -0,0 +1,74 @@
+package signalfx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/signalfx/com_signalfx_metrics_protobuf"
+	"github.com/signalfx/golib/v3/event"
+)
+
+// EventAPIURL is the ingest URL for sending custom events.
+const EventAPIURL = "/v2/event"
+
+type ingestEvent struct {
+	Category   string                 `json:"category"`
+	EventType  string                 `json:"eventType"`
+	Dimensions map[string]string      `json:"dimensions,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+	Timestamp  int64                  `json:"timestamp,omitempty"`
+}
+
+// SendEvents sends custom events to the ingest API, adding the client's
+// default dimensions to each.  Events with a category the API doesn't know
+// are sent as USER_DEFINED.  The API accepts or rejects the events as a
+// whole, so nothing is sent if any of them is nil or has no event type.
+func (c *Client) SendEvents(ctx context.Context, events []*event.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	body := make([]*ingestEvent, len(events))
+	for i, ev := range events {
+		if ev == nil {
+			return fmt.Errorf("event %d is nil", i)
+		}
+		if ev.EventType == "" {
+			return fmt.Errorf("event %d has no event type", i)
+		}
+
+		category, ok := com_signalfx_metrics_protobuf.EventCategory_name[int32(ev.Category)]
+		if !ok {
+			category = com_signalfx_metrics_protobuf.EventCategory_name[int32(event.USERDEFINED)]
+		}
+		body[i] = &ingestEvent{
+			Category:   category,
+			EventType:  ev.EventType,
+			Dimensions: c.withDefaultDimensions(ev.Dimensions),
+			Properties: ev.Properties,
+		}
+		if !ev.Timestamp.IsZero() {
+			body[i].Timestamp = ev.Timestamp.UnixNano() / int64(time.Millisecond)
+		}
+	}
+
+	payload, err := c.marshal(body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.doRequestToBase(ctx, c.ingestURL, "POST", EventAPIURL, nil, bytes.NewReader(payload), c.authToken)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(resp)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package signalfx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/signalfx/golib/v3/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendEvents(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	client.SetDefaultDimensions(map[string]string{"env": "prod", "host": "default"})
+
+	mux.HandleFunc("/v2/event", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		var body []map[string]interface{}
+		err := json.NewDecoder(r.Body).Decode(&body)
+		assert.NoError(t, err, "Unexpected error decoding body")
+
+		if assert.Len(t, body, 2) {
+			assert.Equal(t, "deploy", body[0]["eventType"])
+			assert.Equal(t, "USER_DEFINED", body[0]["category"])
+			assert.Equal(t, float64(1577836800000), body[0]["timestamp"])
+			assert.Equal(t, map[string]interface{}{"env": "prod", "host": "web-1"}, body[0]["dimensions"])
+			assert.Equal(t, map[string]interface{}{"version": "1.2.3"}, body[0]["properties"])
+			assert.Equal(t, "USER_DEFINED", body[1]["category"], "Unknown categories should be sent as USER_DEFINED")
+			assert.NotContains(t, body[1], "timestamp", "Zero timestamps should be left to the server")
+		}
+
+		w.Write([]byte(`"OK"`))
+	})
+
+	err := client.SendEvents(context.Background(), []*event.Event{
+		event.NewWithProperties("deploy", event.USERDEFINED, map[string]string{"host": "web-1"}, map[string]interface{}{"version": "1.2.3"}, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+		event.New("restart", event.Category(42), nil, time.Time{}),
+	})
+	assert.NoError(t, err, "Unexpected error sending events")
+}
+
+func TestSendEventsBadInput(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/event", func(w http.ResponseWriter, r *http.Request) {
+		assert.Fail(t, "Invalid events should not have been sent")
+	})
+
+	err := client.SendEvents(context.Background(), []*event.Event{event.New("deploy", event.USERDEFINED, nil, time.Time{}), nil})
+	assert.EqualError(t, err, "event 1 is nil")
+	err = client.SendEvents(context.Background(), []*event.Event{event.New("", event.USERDEFINED, nil, time.Time{})})
+	assert.EqualError(t, err, "event 0 has no event type")
+}
+
+func TestSendEventsBadStatus(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/event", verifyRequest(t, "POST", http.StatusUnauthorized, nil, ""))
+
+	err := client.SendEvents(context.Background(), []*event.Event{event.New("deploy", event.USERDEFINED, nil, time.Time{})})
+	apiErr, ok := err.(*APIError)
+	if assert.True(t, ok, "Should get an *APIError, got %v", err) {
+		assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	}
+}
@@ -0,0 +1,42 @@
+package signalfx_test
+
+import (
+	"fmt"
+	"log"
+
+	signalfx "github.com/adampetrovic/signalfx-go"
+	"github.com/adampetrovic/signalfx-go/integration"
+)
+
+func ExampleClient_CreateNewRelicIntegration() {
+	client, err := signalfx.NewClient("your-token-here")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	nri := &integration.NewRelicIntegration{
+		Type:            integration.NEW_RELIC,
+		Name:            "New Relic",
+		Enabled:         true,
+		ApiKey:          "your-new-relic-api-key",
+		Products:        []string{"APM"},
+		IncludedMetrics: []string{"Apdex/score"},
+	}
+	// Catch mistakes locally before sending the request.
+	if err := nri.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	created, err := client.CreateNewRelicIntegration(nri)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	services, err := client.GetIntegrationServiceList(created.Id)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, s := range services {
+		fmt.Println(s)
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/adampetrovic/signalfx-go/examples/jsoniter
+
+go 1.12
+
+require (
+	github.com/adampetrovic/signalfx-go v0.0.0
+	github.com/json-iterator/go v1.1.12
+)
+
+replace github.com/adampetrovic/signalfx-go => ../..
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/adampetrovic/signalfx-go/detector"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// searchResponse returns a detector search response with 100 detectors, like
+// the one SearchDetectors gets with a limit of 100.
+func searchResponse(b *testing.B) []byte {
+	data, err := ioutil.ReadFile("../../testdata/fixtures/detector/search_success.json")
+	if err != nil {
+		b.Fatal(err)
+	}
+	one := &detector.SearchResults{}
+	if err := json.Unmarshal(data, one); err != nil {
+		b.Fatal(err)
+	}
+
+	results := &detector.SearchResults{Count: 100}
+	for i := 0; i < 100; i++ {
+		results.Results = append(results.Results, one.Results[0])
+	}
+	data, err = json.Marshal(results)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return data
+}
+
+func benchmarkDecode(b *testing.B, unmarshal func([]byte, interface{}) error) {
+	data := searchResponse(b)
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := unmarshal(data, &detector.SearchResults{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeEncodingJSON(b *testing.B) {
+	benchmarkDecode(b, json.Unmarshal)
+}
+
+func BenchmarkDecodeJsoniter(b *testing.B) {
+	benchmarkDecode(b, jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal)
+}
@@ -2,9 +2,6 @@ package signalfx
 
 import (
 	"bytes"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
 	"net/http"
 
 	"github.com/adampetrovic/signalfx-go/integration"
@@ -12,7 +9,7 @@ import (
 
 // CreateGCPIntegration creates a GCP integration.
 func (c *Client) CreateGCPIntegration(gcpi *integration.GCPIntegration) (*integration.GCPIntegration, error) {
-	payload, err := json.Marshal(gcpi)
+	payload, err := c.marshal(gcpi)
 	if err != nil {
 		return nil, err
 	}
@@ -25,13 +22,12 @@ func (c *Client) CreateGCPIntegration(gcpi *integration.GCPIntegration) (*integr
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalIntegration := integration.GCPIntegration{}
 
-	err = json.NewDecoder(resp.Body).Decode(&finalIntegration)
+	err = c.decodeJSON(resp.Body, &finalIntegration)
 
 	return &finalIntegration, err
 }
@@ -46,20 +42,19 @@ func (c *Client) GetGCPIntegration(id string) (*integration.GCPIntegration, erro
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalIntegration := integration.GCPIntegration{}
 
-	err = json.NewDecoder(resp.Body).Decode(&finalIntegration)
+	err = c.decodeJSON(resp.Body, &finalIntegration)
 
 	return &finalIntegration, err
 }
 
 // UpdateGCPIntegration updates a GCP integration.
 func (c *Client) UpdateGCPIntegration(id string, gcpi *integration.GCPIntegration) (*integration.GCPIntegration, error) {
-	payload, err := json.Marshal(gcpi)
+	payload, err := c.marshal(gcpi)
 	if err != nil {
 		return nil, err
 	}
@@ -72,13 +67,12 @@ func (c *Client) UpdateGCPIntegration(id string, gcpi *integration.GCPIntegratio
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalIntegration := integration.GCPIntegration{}
 
-	err = json.NewDecoder(resp.Body).Decode(&finalIntegration)
+	err = c.decodeJSON(resp.Body, &finalIntegration)
 
 	return &finalIntegration, err
 }
@@ -93,8 +87,7 @@ func (c *Client) DeleteGCPIntegration(id string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return newAPIError(resp)
 	}
 
 	return err
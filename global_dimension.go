@@ -0,0 +1,47 @@
+package signalfx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/adampetrovic/signalfx-go/dimension"
+)
+
+// GlobalDimensionAPIURL is the URL for creating global dimensions.
+const GlobalDimensionAPIURL = "/v2/dimension/global"
+
+// CreateGlobalDimension creates a dimension whose properties are propagated to
+// all metric time series that have it.  If req.ApplyToExistingMTS is set, the
+// API accepts the request and applies the properties to existing time series
+// in the background, and the ID of that job is returned for polling;
+// otherwise the job ID is empty.
+func (c *Client) CreateGlobalDimension(ctx context.Context, req *dimension.GlobalDimensionRequest) (string, error) {
+	if req.DimensionKey == "" || req.DimensionValue == "" {
+		return "", errors.New("dimension key and value must not be empty")
+	}
+
+	payload, err := c.marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.doRequestWithContext(ctx, "POST", GlobalDimensionAPIURL, nil, bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return "", nil
+	case http.StatusAccepted:
+		result := &dimension.GlobalDimensionResponse{}
+		if err := c.decodeJSON(resp.Body, result); err != nil {
+			return "", err
+		}
+		return result.JobID, nil
+	}
+	return "", newAPIError(resp)
+}
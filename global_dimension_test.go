@@ -0,0 +1,54 @@
+package signalfx
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/adampetrovic/signalfx-go/dimension"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateGlobalDimension(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/dimension/global", verifyRequest(t, "POST", http.StatusOK, nil, ""))
+
+	jobID, err := client.CreateGlobalDimension(context.Background(), &dimension.GlobalDimensionRequest{
+		DimensionKey:   "env",
+		DimensionValue: "prod",
+		Properties:     map[string]string{"owner": "sre"},
+	})
+	assert.NoError(t, err, "Unexpected error creating global dimension")
+	assert.Equal(t, "", jobID)
+}
+
+func TestCreateGlobalDimensionApplyToExisting(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/dimension/global", verifyRequest(t, "POST", http.StatusAccepted, nil, "dimension/create_global_accepted.json"))
+
+	jobID, err := client.CreateGlobalDimension(context.Background(), &dimension.GlobalDimensionRequest{
+		DimensionKey:       "env",
+		DimensionValue:     "prod",
+		Properties:         map[string]string{"owner": "sre"},
+		ApplyToExistingMTS: true,
+	})
+	assert.NoError(t, err, "Unexpected error creating global dimension")
+	assert.Equal(t, "JoB1", jobID)
+}
+
+func TestCreateGlobalDimensionBad(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/dimension/global", verifyRequest(t, "POST", http.StatusBadRequest, nil, ""))
+
+	_, err := client.CreateGlobalDimension(context.Background(), &dimension.GlobalDimensionRequest{DimensionKey: "env"})
+	assert.Error(t, err, "Should need a value")
+
+	_, err = client.CreateGlobalDimension(context.Background(), &dimension.GlobalDimensionRequest{DimensionKey: "env", DimensionValue: "prod"})
+	assert.Error(t, err, "Should get an error from bad request")
+}
@@ -4,9 +4,12 @@ go 1.12
 
 require (
 	github.com/davecgh/go-spew v1.1.1
+	github.com/golang/protobuf v1.3.2
 	github.com/gorilla/websocket v1.4.0
 	github.com/mauricelam/genny v0.0.0-20190320071652-0800202903e5
+	github.com/signalfx/com_signalfx_metrics_protobuf v0.0.0-20190222193949-1fb69526e884
 	github.com/signalfx/golib/v3 v3.0.0
 	github.com/stretchr/testify v1.4.0
-	golang.org/x/tools v0.0.0-20190319232107-3f1ed9edd1b4
+	golang.org/x/tools v0.0.0-20190524140312-2c0ae7006135
+	google.golang.org/grpc v1.27.0
 )
@@ -1,10 +1,11 @@
 package signalfx
 
 import (
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
+	"context"
 	"net/http"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/integration"
 )
 
 // IntegrationAPIURL is the base URL for interacting with intergrations.
@@ -20,8 +21,7 @@ func (c *Client) DeleteIntegration(id string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return newAPIError(resp)
 	}
 
 	return nil
@@ -37,13 +37,162 @@ func (c *Client) GetIntegration(id string) (map[string]interface{}, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalIntegration := make(map[string]interface{})
 
-	err = json.NewDecoder(resp.Body).Decode(&finalIntegration)
+	err = c.decodeJSON(resp.Body, &finalIntegration)
 
 	return finalIntegration, err
 }
+
+// GetIntegrationServiceList lists the services (metric namespaces) that an
+// integration is able to collect data from.
+func (c *Client) GetIntegrationServiceList(id string) ([]string, error) {
+	resp, err := c.doRequest("GET", IntegrationAPIURL+"/"+id+"/servicelist", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var services []string
+
+	err = c.decodeJSON(resp.Body, &services)
+
+	return services, err
+}
+
+// ValidateIntegration asks SignalFx to check that an integration can actually
+// talk to the external system it is configured for.  Unlike the `Validate`
+// methods on the integration models, this makes an API call, and a result
+// with `Valid` set to false is not considered an error.
+func (c *Client) ValidateIntegration(ctx context.Context, id string) (*integration.ValidationResult, error) {
+	resp, err := c.doRequestWithContext(ctx, "PUT", IntegrationAPIURL+"/"+id+"/validate", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	result := &integration.ValidationResult{}
+
+	err = c.decodeJSON(resp.Body, result)
+
+	return result, err
+}
+
+// IntegrationSyncTimeout is the longest SyncNow waits for a sync to finish.
+const IntegrationSyncTimeout = 10 * time.Minute
+
+// SyncNow starts an out-of-schedule sync of a cloud integration, then polls
+// every PollInterval until it finishes and returns the outcome.  It gives up
+// after IntegrationSyncTimeout, or sooner if ctx is done.  A sync that
+// finishes with errors is not itself an error; check the result's
+// ErrorCount.
+func (c *Client) SyncNow(ctx context.Context, integrationID string) (*integration.SyncResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, IntegrationSyncTimeout)
+	defer cancel()
+
+	started, err := c.startIntegrationSync(ctx, integrationID)
+	if err != nil {
+		return nil, err
+	}
+	if started.Done() {
+		return started, nil
+	}
+
+	var result *integration.SyncResult
+	err = c.poll(ctx, func() (bool, error) {
+		result = &integration.SyncResult{}
+		if err := c.getJSON(ctx, IntegrationAPIURL+"/"+integrationID+"/sync/"+started.Id, nil, result); err != nil {
+			return false, err
+		}
+		return result.Done(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) startIntegrationSync(ctx context.Context, integrationID string) (*integration.SyncResult, error) {
+	resp, err := c.doRequestWithContext(ctx, "POST", IntegrationAPIURL+"/"+integrationID+"/sync", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return nil, newAPIError(resp)
+	}
+
+	result := &integration.SyncResult{}
+
+	err = c.decodeJSON(resp.Body, result)
+
+	return result, err
+}
+
+// GetSyncStatus gets a summary of the recent syncs of a data collection
+// integration.
+func (c *Client) GetSyncStatus(ctx context.Context, integrationID string) (*integration.SyncStatus, error) {
+	status := &integration.SyncStatus{}
+	if err := c.getJSON(ctx, IntegrationAPIURL+"/"+integrationID+"/syncstatus", nil, status); err != nil {
+		return nil, err
+	}
+	if status.IntegrationID == "" {
+		status.IntegrationID = integrationID
+	}
+	return status, nil
+}
+
+// GetIntegrationHealth gets the sync status of every data collection
+// integration in the organization (AWS CloudWatch, Azure, GCP and New Relic),
+// running up to the client's MaxConcurrent requests at once.  If any status
+// can't be fetched, the first error is returned.
+func (c *Client) GetIntegrationHealth(ctx context.Context) ([]*integration.SyncStatus, error) {
+	var ids []string
+	err := c.forEachPage(ctx, IntegrationAPIURL, nil, func() (interface{}, func() int) {
+		var page struct {
+			Results []struct {
+				Id   string           `json:"id"`
+				Type integration.Type `json:"type"`
+			} `json:"results"`
+		}
+		return &page, func() int {
+			for _, i := range page.Results {
+				switch i.Type {
+				case integration.AWS_CLOUD_WATCH, integration.AZURE, integration.GCP, integration.NEW_RELIC:
+					ids = append(ids, i.Id)
+				}
+			}
+			return len(page.Results)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*integration.SyncStatus, len(ids))
+	errs := make([]error, len(ids))
+	c.forEachConcurrently(len(ids), func(i int) {
+		statuses[i], errs[i] = c.GetSyncStatus(ctx, ids[i])
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return statuses, nil
+}
@@ -9,11 +9,12 @@
 
 package integration
 
-// AzureEnvironment : Enumerated string that tells SignalFx what type of Azure integration this is. The allowed values are \"AZURE_US_GOVERNMENT\" and \"AZURE\". If you don't specify a value in a **POST** request, SignalFx defaults to \"AZURE\".<br> If it's not already set, this property doesn't appear in a response body.
+// AzureEnvironment : Enumerated string that tells SignalFx what type of Azure integration this is. The allowed values are \"AZURE_US_GOVERNMENT\", \"AZURE_CHINA\" and \"AZURE\". If you don't specify a value in a **POST** request, SignalFx defaults to \"AZURE\".<br> If it's not already set, this property doesn't appear in a response body.
 type AzureEnvironment string
 
 // List of AzureEnvironment
 const (
 	AZURE_DEFAULT       AzureEnvironment = "AZURE"
 	AZURE_US_GOVERNMENT AzureEnvironment = "AZURE_US_GOVERNMENT"
+	AZURE_CHINA         AzureEnvironment = "AZURE_CHINA"
 )
@@ -9,6 +9,11 @@
 
 package integration
 
+import (
+	"fmt"
+	"regexp"
+)
+
 // Specifies the data collection integration between Microsoft Azure and SignalFx, in the form of a JSON object.
 type AzureIntegration struct {
 	// The creation date and time for the integration object, in Unix time UTC-relative. The system sets this value, and you can't modify it.
@@ -36,6 +41,47 @@ type AzureIntegration struct {
 	Services []AzureService `json:"services,omitempty"`
 	// List of Azure subscriptions that SignalFx should monitor, in the form of a JSON array
 	Subscriptions []string `json:"subscriptions,omitempty"`
+	// List of rules that narrow down which Azure resources and metrics SignalFx collects. If you don't specify any rules, SignalFx collects all metrics from all resources of the selected services.
+	ResourceFilterRules []ResourceFilterRule `json:"resourceFilterRules,omitempty"`
 	// Azure ID of the Azure tenant. To learn how to get this ID, see the topic (https://docs.signalfx.com/en/latest/getting-started/send-data.html#connect-to-microsoft-azure)[Connect to Microsoft Azure] in the product documentation.
 	TenantId string `json:"tenantId,omitempty"`
 }
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Validate checks the integration for problems that SignalFx would reject,
+// so they can be caught before making a request.
+func (azi *AzureIntegration) Validate() error {
+	if azi.Type != AZURE {
+		return fmt.Errorf("type must be %q, got %q", AZURE, azi.Type)
+	}
+	if !uuidRegexp.MatchString(azi.TenantId) {
+		return fmt.Errorf("tenantId must be a UUID, got %q", azi.TenantId)
+	}
+	if !uuidRegexp.MatchString(azi.AppId) {
+		return fmt.Errorf("appId must be a UUID, got %q", azi.AppId)
+	}
+
+	switch azi.AzureEnvironment {
+	case "", AZURE_DEFAULT, AZURE_US_GOVERNMENT, AZURE_CHINA:
+	default:
+		return fmt.Errorf("unknown azureEnvironment %q", azi.AzureEnvironment)
+	}
+
+	if azi.PollRate != nil && *azi.PollRate != OneMinutely && *azi.PollRate != FiveMinutely {
+		return fmt.Errorf("pollRate must be %d or %d, got %d", OneMinutely, FiveMinutely, *azi.PollRate)
+	}
+
+	for _, s := range azi.Services {
+		if _, ok := AzureServiceNames[string(s)]; !ok {
+			return fmt.Errorf("unsupported Azure service %q", s)
+		}
+	}
+
+	for i := range azi.ResourceFilterRules {
+		if err := azi.ResourceFilterRules[i].Validate(); err != nil {
+			return fmt.Errorf("resourceFilterRules[%d]: %v", i, err)
+		}
+	}
+	return nil
+}
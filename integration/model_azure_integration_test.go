@@ -0,0 +1,48 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAzureIntegrationValidate(t *testing.T) {
+	azi := &AzureIntegration{
+		Type:             AZURE,
+		TenantId:         "8bd5a1b5-4e5c-4e5e-9c6e-0c5e0f0a1b2c",
+		AppId:            "0a1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d",
+		AzureEnvironment: AZURE_US_GOVERNMENT,
+		Services:         []AzureService{AZURE_COMPUTE_VIRTUALMACHINES},
+		ResourceFilterRules: []ResourceFilterRule{
+			{ResourceType: "microsoft.compute/virtualmachines", RegexFilter: "^prod-"},
+		},
+	}
+	assert.NoError(t, azi.Validate(), "Valid integration should pass")
+
+	azi.TenantId = "not-a-uuid"
+	assert.Error(t, azi.Validate(), "Bad tenant ID should fail")
+	azi.TenantId = "8bd5a1b5-4e5c-4e5e-9c6e-0c5e0f0a1b2c"
+
+	azi.AppId = ""
+	assert.Error(t, azi.Validate(), "Missing app ID should fail")
+	azi.AppId = "0a1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d"
+
+	azi.AzureEnvironment = "MARS"
+	assert.Error(t, azi.Validate(), "Unknown environment should fail")
+	azi.AzureEnvironment = AZURE_CHINA
+
+	azi.Services = []AzureService{"microsoft.nope/nope"}
+	assert.Error(t, azi.Validate(), "Unknown service should fail")
+	azi.Services = nil
+
+	azi.ResourceFilterRules[0].RegexFilter = "(["
+	assert.Error(t, azi.Validate(), "Bad regex should fail")
+	azi.ResourceFilterRules[0].RegexFilter = ""
+
+	azi.ResourceFilterRules[0].ResourceType = ""
+	assert.Error(t, azi.Validate(), "Missing resource type should fail")
+	azi.ResourceFilterRules = nil
+
+	azi.Type = GCP
+	assert.Error(t, azi.Validate(), "Wrong type should fail")
+}
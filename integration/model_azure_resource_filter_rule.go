@@ -0,0 +1,33 @@
+package integration
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// ResourceFilterRule limits the data SignalFx collects from an Azure
+// integration to resources of a given type, optionally further narrowed by a
+// regular expression on the resource name and a list of metric names.
+type ResourceFilterRule struct {
+	// The Azure resource type the rule applies to, e.g. "microsoft.compute/virtualmachines".
+	ResourceType string `json:"resourceType"`
+	// Regular expression that the resource name must match for its metrics to be collected. If empty, all resources of the type match.
+	RegexFilter string `json:"regexFilter,omitempty"`
+	// Names of the metrics to collect from matching resources. If empty, all metrics are collected.
+	MetricNames []string `json:"metricNames,omitempty"`
+}
+
+// Validate checks that the rule names a resource type and that its filter is
+// a valid regular expression.
+func (r *ResourceFilterRule) Validate() error {
+	if r.ResourceType == "" {
+		return errors.New("resourceType must be set")
+	}
+	if r.RegexFilter != "" {
+		if _, err := regexp.Compile(r.RegexFilter); err != nil {
+			return fmt.Errorf("invalid regexFilter: %v", err)
+		}
+	}
+	return nil
+}
@@ -9,6 +9,8 @@
 
 package integration
 
+import "fmt"
+
 // Specifies the data collection integration between Google Cloud Platform and SignalFx, in the form of a JSON object.
 type GCPIntegration struct {
 	// The creation date and time for the integration object, in Unix time UTC-relative. The system sets this value, and you can't modify it.
@@ -34,3 +36,66 @@ type GCPIntegration struct {
 	// List of GCP metadata names that you want SignalFx to collect from the data incoming from the GCP integration, in the form of a JSON array. Refer to Google's GCP documentation to find out the names you want to whitelist.
 	Whitelist []string `json:"whitelist,omitempty"`
 }
+
+// SupportedGCPServices returns the GCP services that SignalFx knows how to
+// pull metrics from.
+func SupportedGCPServices() []string {
+	return []string{
+		"appengine",
+		"bigquery",
+		"bigtable",
+		"cloudfunctions",
+		"cloudiot",
+		"cloudsql",
+		"cloudtasks",
+		"compute",
+		"container",
+		"dataflow",
+		"datastore",
+		"firebasedatabase",
+		"firebasehosting",
+		"interconnect",
+		"loadbalancing",
+		"logging",
+		"ml",
+		"monitoring",
+		"pubsub",
+		"router",
+		"serviceruntime",
+		"spanner",
+		"storage",
+		"vpn",
+	}
+}
+
+// Validate checks the integration for problems that SignalFx would reject,
+// so they can be caught before making a request.
+func (gcpi *GCPIntegration) Validate() error {
+	if gcpi.Type != GCP {
+		return fmt.Errorf("type must be %q, got %q", GCP, gcpi.Type)
+	}
+
+	if gcpi.PollRate != nil && *gcpi.PollRate != OneMinutely && *gcpi.PollRate != FiveMinutely {
+		return fmt.Errorf("pollRate must be %d or %d, got %d", OneMinutely, FiveMinutely, *gcpi.PollRate)
+	}
+
+	supported := make(map[string]bool)
+	for _, s := range SupportedGCPServices() {
+		supported[s] = true
+	}
+	for _, s := range gcpi.Services {
+		if !supported[s] {
+			return fmt.Errorf("unsupported GCP service %q", s)
+		}
+	}
+
+	for i, p := range gcpi.ProjectServiceKeys {
+		if p == nil {
+			return fmt.Errorf("projectServiceKeys[%d] is nil", i)
+		}
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("projectServiceKeys[%d]: %v", i, err)
+		}
+	}
+	return nil
+}
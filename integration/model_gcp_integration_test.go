@@ -0,0 +1,50 @@
+package integration
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const serviceAccountKey = `{"type": "service_account", "project_id": "my-project"}`
+
+func TestGCPIntegrationValidate(t *testing.T) {
+	rate := OneMinutely
+	gcpi := &GCPIntegration{
+		Type:     GCP,
+		PollRate: &rate,
+		Services: []string{"compute", "pubsub"},
+		ProjectServiceKeys: []*GCPProject{
+			{ProjectId: "my-project", ProjectKey: serviceAccountKey},
+			{ProjectId: "other-project", ProjectKey: base64.StdEncoding.EncodeToString([]byte(serviceAccountKey))},
+		},
+	}
+	assert.NoError(t, gcpi.Validate(), "Valid integration should pass")
+
+	gcpi.Services = []string{"compute", "teleporter"}
+	assert.Error(t, gcpi.Validate(), "Unsupported service should fail")
+	gcpi.Services = nil
+
+	badRate := PollRate(1000)
+	gcpi.PollRate = &badRate
+	assert.Error(t, gcpi.Validate(), "Unsupported poll rate should fail")
+	gcpi.PollRate = nil
+
+	gcpi.Type = AZURE
+	assert.Error(t, gcpi.Validate(), "Wrong type should fail")
+}
+
+func TestGCPProjectValidate(t *testing.T) {
+	assert.NoError(t, (&GCPProject{ProjectId: "p", ProjectKey: serviceAccountKey}).Validate())
+	assert.Error(t, (&GCPProject{ProjectKey: serviceAccountKey}).Validate(), "Missing project ID should fail")
+	assert.Error(t, (&GCPProject{ProjectId: "p"}).Validate(), "Missing key should fail")
+	assert.Error(t, (&GCPProject{ProjectId: "p", ProjectKey: "not base64!"}).Validate(), "Garbage key should fail")
+	assert.Error(t, (&GCPProject{ProjectId: "p", ProjectKey: base64.StdEncoding.EncodeToString([]byte("nope"))}).Validate(), "Non-JSON key should fail")
+	assert.Error(t, (&GCPProject{ProjectId: "p", ProjectKey: `{"type": "authorized_user"}`}).Validate(), "Wrong key type should fail")
+}
+
+func TestSupportedGCPServices(t *testing.T) {
+	assert.Contains(t, SupportedGCPServices(), "compute")
+	assert.Len(t, SupportedGCPServices(), 24)
+}
@@ -9,6 +9,14 @@
 
 package integration
 
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
 // Properties of a GCP project, in the form of a JSON object. Contains the GCP project ID and GCP service account key for a GCP project that you want SignalFx to monitor.
 type GCPProject struct {
 	// GCP project ID you specified when you created your GCP project
@@ -16,3 +24,35 @@ type GCPProject struct {
 	// Google Service Account Key generated for the project. This property is a JSON string; you must properly escape special characters before you send it to SignalFx.<br> **NOTE:** To ensure security, SignalFx doesn't return the value of this property in a response object.
 	ProjectKey string `json:"projectKey,omitempty"`
 }
+
+// Validate checks that the project has an ID and that its key is a Google
+// service account key.  The key may be given either as the raw JSON or as
+// base64-encoded JSON.
+func (p *GCPProject) Validate() error {
+	if p.ProjectId == "" {
+		return errors.New("projectId must be set")
+	}
+	if p.ProjectKey == "" {
+		return errors.New("projectKey must be set")
+	}
+
+	keyJSON := []byte(p.ProjectKey)
+	if !strings.HasPrefix(strings.TrimSpace(p.ProjectKey), "{") {
+		var err error
+		keyJSON, err = base64.StdEncoding.DecodeString(p.ProjectKey)
+		if err != nil {
+			return fmt.Errorf("projectKey is neither JSON nor valid base64: %v", err)
+		}
+	}
+
+	var key struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(keyJSON, &key); err != nil {
+		return fmt.Errorf("projectKey is not valid JSON: %v", err)
+	}
+	if key.Type != "service_account" {
+		return fmt.Errorf("projectKey must be a service account key, got type %q", key.Type)
+	}
+	return nil
+}
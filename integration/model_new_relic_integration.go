@@ -9,6 +9,11 @@
 
 package integration
 
+import (
+	"errors"
+	"fmt"
+)
+
 // Specifies the data collection integration between NewRelic and SignalFx, in the form of a JSON object.
 type NewRelicIntegration struct {
 	// The creation date and time for the integration object, in Unix time UTC-relative. The system sets this value, and you can't modify it.
@@ -33,4 +38,30 @@ type NewRelicIntegration struct {
 	ApmFilters    NewRelicIntegrationFilterObject `json:"apmFilters,omitempty"`
 	MobileFilters NewRelicIntegrationFilterObject `json:"mobileFilters,omitempty"`
 	ServerFilters NewRelicIntegrationFilterObject `json:"serverFilters,omitempty"`
+	// List of NewRelic metric names to import. If empty, SignalFx imports all metrics that pass the product filters.
+	IncludedMetrics []string `json:"includedMetrics,omitempty"`
+}
+
+// Validate checks the integration for problems that SignalFx would reject,
+// so they can be caught before making a request.
+func (nri *NewRelicIntegration) Validate() error {
+	if nri.Type != NEW_RELIC {
+		return fmt.Errorf("type must be %q, got %q", NEW_RELIC, nri.Type)
+	}
+	if nri.ApiKey == "" {
+		return errors.New("apiKey must be set")
+	}
+	for _, p := range nri.Products {
+		switch p {
+		case "APM", "Mobile", "Servers":
+		default:
+			return fmt.Errorf("unsupported NewRelic product %q", p)
+		}
+	}
+	for _, m := range nri.IncludedMetrics {
+		if m == "" {
+			return errors.New("includedMetrics cannot contain empty names")
+		}
+	}
+	return nil
 }
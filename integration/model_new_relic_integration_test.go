@@ -0,0 +1,47 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRelicIntegrationValidate(t *testing.T) {
+	nri := &NewRelicIntegration{
+		Type:            NEW_RELIC,
+		ApiKey:          "abc123",
+		Products:        []string{"APM", "Servers"},
+		IncludedMetrics: []string{"Apdex/score"},
+	}
+	assert.NoError(t, nri.Validate(), "Valid integration should pass")
+
+	nri.Products = []string{"Browser"}
+	assert.Error(t, nri.Validate(), "Unknown product should fail")
+	nri.Products = nil
+
+	nri.IncludedMetrics = []string{""}
+	assert.Error(t, nri.Validate(), "Empty metric name should fail")
+	nri.IncludedMetrics = nil
+
+	nri.ApiKey = ""
+	assert.Error(t, nri.Validate(), "Missing API key should fail")
+}
+
+func TestParseIntegration(t *testing.T) {
+	out, err := ParseIntegration([]byte(`{"type": "NewRelic", "name": "nr", "includedMetrics": ["Apdex/score"]}`))
+	assert.NoError(t, err)
+	nri, ok := out.(*NewRelicIntegration)
+	assert.True(t, ok, "Should have parsed a NewRelicIntegration")
+	assert.Equal(t, "nr", nri.Name)
+	assert.Equal(t, []string{"Apdex/score"}, nri.IncludedMetrics)
+
+	out, err = ParseIntegration([]byte(`{"type": "GCP"}`))
+	assert.NoError(t, err)
+	assert.IsType(t, &GCPIntegration{}, out)
+
+	_, err = ParseIntegration([]byte(`{"type": "Carrier Pigeon"}`))
+	assert.Error(t, err, "Unknown type should fail")
+
+	_, err = ParseIntegration([]byte(`not json`))
+	assert.Error(t, err, "Bad JSON should fail")
+}
@@ -0,0 +1,54 @@
+package integration
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SyncResult describes a single run of an integration pulling data from the
+// external system it points at.
+type SyncResult struct {
+	// System-defined identifier for the sync
+	Id string `json:"id,omitempty"`
+	// When the sync started
+	StartedAt time.Time `json:"-"`
+	// When the sync finished, or the zero time if it's still running
+	CompletedAt time.Time `json:"-"`
+	// Number of datapoints the sync ingested
+	MetricsIngested int64 `json:"metricsIngested"`
+	// Number of problems encountered during the sync
+	ErrorCount int `json:"errorCount"`
+	// Descriptions of the problems, which may be fewer than ErrorCount
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Done reports whether the sync has finished.
+func (r *SyncResult) Done() bool {
+	return !r.CompletedAt.IsZero()
+}
+
+// UnmarshalJSON decodes a sync result, whose times are in milliseconds since
+// the epoch.
+func (r *SyncResult) UnmarshalJSON(b []byte) error {
+	type Alias SyncResult
+	aux := struct {
+		*Alias
+		StartedAtMS   int64 `json:"startedAt"`
+		CompletedAtMS int64 `json:"completedAt"`
+	}{Alias: (*Alias)(r)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	r.StartedAt = fromEpochMillis(aux.StartedAtMS)
+	r.CompletedAt = fromEpochMillis(aux.CompletedAtMS)
+	return nil
+}
+
+// fromEpochMillis converts ms since the epoch to a time, leaving 0 as the
+// zero time.
+func fromEpochMillis(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ms*int64(time.Millisecond))
+}
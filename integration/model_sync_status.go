@@ -0,0 +1,49 @@
+package integration
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// UnhealthyConsecutiveFailures is how many syncs in a row can fail before an
+// integration is considered unhealthy.
+const UnhealthyConsecutiveFailures = 3
+
+// SyncStatus summarizes the recent syncs of a data collection integration.
+type SyncStatus struct {
+	// The integration the status is for
+	IntegrationID string `json:"integrationId,omitempty"`
+	// When the last sync finished
+	LastSyncTime time.Time `json:"-"`
+	// When the next scheduled sync will start
+	NextSyncTime time.Time `json:"-"`
+	// How long the last sync took
+	LastSyncDuration time.Duration `json:"-"`
+	// Why the last sync failed, if it did
+	LastSyncError string `json:"lastSyncError,omitempty"`
+	// Number of syncs in a row that have failed, up to and including the last
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+	// Set unless more than UnhealthyConsecutiveFailures syncs in a row have
+	// failed
+	IsHealthy bool `json:"-"`
+}
+
+// UnmarshalJSON decodes a sync status, whose times and duration are in
+// milliseconds, and works out whether the integration is healthy.
+func (s *SyncStatus) UnmarshalJSON(b []byte) error {
+	type Alias SyncStatus
+	aux := struct {
+		*Alias
+		LastSyncTimeMS     int64 `json:"lastSyncTime"`
+		NextSyncTimeMS     int64 `json:"nextSyncTime"`
+		LastSyncDurationMS int64 `json:"lastSyncDurationMs"`
+	}{Alias: (*Alias)(s)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	s.LastSyncTime = fromEpochMillis(aux.LastSyncTimeMS)
+	s.NextSyncTime = fromEpochMillis(aux.NextSyncTimeMS)
+	s.LastSyncDuration = time.Duration(aux.LastSyncDurationMS) * time.Millisecond
+	s.IsHealthy = s.ConsecutiveFailures <= UnhealthyConsecutiveFailures
+	return nil
+}
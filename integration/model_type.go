@@ -21,6 +21,7 @@ const (
 	BIG_PANDA Type = "BigPanda"
 	GCP Type = "GCP"
 	GOOGLE_SAML Type = "GoogleSaml"
+	JIRA Type = "Jira"
 	OFFICE365 Type = "Office365"
 	OKTA Type = "Okta"
 	ONE_LOGIN Type = "OneLogin"
@@ -0,0 +1,13 @@
+package integration
+
+// ValidationResult is SignalFx's verdict on whether an integration is able to
+// work with the external system it points at, e.g. whether the AWS role it
+// uses has the policies that SignalFx needs.
+type ValidationResult struct {
+	// Whether the integration passed validation.
+	Valid bool `json:"valid"`
+	// Problems that will stop the integration from working.
+	Errors []string `json:"errors,omitempty"`
+	// Problems that may degrade the integration, e.g. missing optional permissions.
+	Warnings []string `json:"warnings,omitempty"`
+}
@@ -0,0 +1,68 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ParseIntegration decodes the JSON of a single integration into the model
+// that matches its `type`, e.g. a *NewRelicIntegration for "NewRelic".  This
+// is useful when the type isn't known ahead of time, such as when listing or
+// fetching integrations by ID.
+func ParseIntegration(data []byte) (interface{}, error) {
+	var typ struct {
+		Type Type `json:"type"`
+	}
+	if err := json.Unmarshal(data, &typ); err != nil {
+		return nil, err
+	}
+
+	var out interface{}
+	switch typ.Type {
+	case ADFS:
+		out = &AdfsIntegrationModel{}
+	case AWS_CLOUD_WATCH:
+		out = &AwsCloudWatchIntegration{}
+	case AZURE:
+		out = &AzureIntegration{}
+	case AZURE_AD:
+		out = &AzureActiveDirectoryIntegrationModel{}
+	case BIG_PANDA:
+		out = &BigPandaIntegration{}
+	case BITIUM:
+		out = &BitiumIntegration{}
+	case GCP:
+		out = &GCPIntegration{}
+	case GOOGLE_SAML:
+		out = &GoogleCloudIdentityIntegration{}
+	case JIRA:
+		out = &JiraIntegration{}
+	case NEW_RELIC:
+		out = &NewRelicIntegration{}
+	case OFFICE365:
+		out = &Office365Integration{}
+	case OKTA:
+		out = &OktaIntegration{}
+	case ONE_LOGIN:
+		out = &OneLoginIntegration{}
+	case OPSGENIE:
+		out = &OpsgenieIntegration{}
+	case PAGER_DUTY:
+		out = &PagerDutyIntegration{}
+	case PING_ONE:
+		out = &PingOneIntegration{}
+	case SERVICE_NOW:
+		out = &ServiceNowIntegration{}
+	case SLACK:
+		out = &SlackIntegration{}
+	case VICTOR_OPS:
+		out = &VictorOpsIntegration{}
+	case WEBHOOK:
+		out = &WebhookIntegration{}
+	case X_MATTERS:
+		out = &XMattersIntegration{}
+	default:
+		return nil, fmt.Errorf("Unknown integration type %v", typ.Type)
+	}
+	return out, json.Unmarshal(data, out)
+}
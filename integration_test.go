@@ -1,8 +1,10 @@
 package signalfx
 
 import (
+	"context"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -49,3 +51,135 @@ func TestGetMissingIntegration(t *testing.T) {
 	assert.Error(t, err, "Should get an error getting missing integration")
 	assert.Nil(t, result, "Should get a nil result from a missing integration")
 }
+
+func TestGetIntegrationServiceList(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/integration/string/servicelist", verifyRequest(t, "GET", http.StatusOK, nil, "integration/servicelist_success.json"))
+
+	result, err := client.GetIntegrationServiceList("string")
+	assert.NoError(t, err, "Unexpected error getting integration service list")
+	assert.Equal(t, []string{"APM", "Mobile"}, result, "Services do not match")
+}
+
+func TestGetMissingIntegrationServiceList(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/integration/string/servicelist", verifyRequest(t, "GET", http.StatusNotFound, nil, ""))
+
+	result, err := client.GetIntegrationServiceList("string")
+	assert.Error(t, err, "Should get an error getting missing integration service list")
+	assert.Nil(t, result, "Should get a nil result from a missing integration")
+}
+
+func TestValidateIntegration(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/integration/string/validate", verifyRequest(t, "PUT", http.StatusOK, nil, "integration/validate_success.json"))
+
+	result, err := client.ValidateIntegration(context.Background(), "string")
+	assert.NoError(t, err, "Unexpected error validating integration")
+	assert.False(t, result.Valid, "Should not be valid")
+	assert.Len(t, result.Errors, 1, "Should have an error")
+	assert.Len(t, result.Warnings, 1, "Should have a warning")
+}
+
+func TestValidateMissingIntegration(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/integration/string/validate", verifyRequest(t, "PUT", http.StatusNotFound, nil, ""))
+
+	result, err := client.ValidateIntegration(context.Background(), "string")
+	assert.Error(t, err, "Should get an error validating missing integration")
+	assert.Nil(t, result, "Should get a nil result from a missing integration")
+}
+
+func TestSyncNow(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	client.pollInterval = time.Millisecond
+
+	mux.HandleFunc("/v2/integration/string/sync", verifyRequest(t, "POST", http.StatusAccepted, nil, "integration/sync_started.json"))
+	polls := 0
+	mux.HandleFunc("/v2/integration/string/sync/SyNc", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		if polls < 3 {
+			verifyRequest(t, "GET", http.StatusOK, nil, "integration/sync_started.json")(w, r)
+			return
+		}
+		verifyRequest(t, "GET", http.StatusOK, nil, "integration/sync_success.json")(w, r)
+	})
+
+	result, err := client.SyncNow(context.Background(), "string")
+	assert.NoError(t, err, "Unexpected error syncing integration")
+	assert.Equal(t, 3, polls, "Should poll until the sync is done")
+	assert.Equal(t, time.Unix(1557936000, 0), result.StartedAt)
+	assert.Equal(t, time.Unix(1557936090, 0), result.CompletedAt)
+	assert.Equal(t, int64(12345), result.MetricsIngested)
+	assert.Equal(t, 2, result.ErrorCount)
+	assert.Len(t, result.Errors, 2)
+}
+
+func TestSyncNowMissingIntegration(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/integration/string/sync", verifyRequest(t, "POST", http.StatusNotFound, nil, ""))
+
+	result, err := client.SyncNow(context.Background(), "string")
+	assert.Error(t, err, "Should get an error syncing missing integration")
+	assert.Nil(t, result, "Should get a nil result from a missing integration")
+}
+
+func TestGetSyncStatus(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/integration/string/syncstatus", verifyRequest(t, "GET", http.StatusOK, nil, "integration/sync_status_success.json"))
+
+	status, err := client.GetSyncStatus(context.Background(), "string")
+	assert.NoError(t, err, "Unexpected error getting sync status")
+	assert.Equal(t, "string", status.IntegrationID)
+	assert.Equal(t, time.Unix(1557936000, 0), status.LastSyncTime)
+	assert.Equal(t, time.Unix(1557936300, 0), status.NextSyncTime)
+	assert.Equal(t, 45*time.Second, status.LastSyncDuration)
+	assert.Equal(t, 4, status.ConsecutiveFailures)
+	assert.False(t, status.IsHealthy, "Should be unhealthy after 4 failures")
+}
+
+func TestGetIntegrationHealth(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/integration", verifyRequest(t, "GET", http.StatusOK, nil, "integration/search_success.json"))
+	mux.HandleFunc("/v2/integration/AwS/syncstatus", verifyRequest(t, "GET", http.StatusOK, nil, "integration/sync_status_success.json"))
+	mux.HandleFunc("/v2/integration/GcP/syncstatus", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"consecutiveFailures": 0}`))
+	})
+
+	statuses, err := client.GetIntegrationHealth(context.Background())
+	assert.NoError(t, err, "Unexpected error getting integration health")
+	if assert.Len(t, statuses, 2, "Only data collection integrations should be included") {
+		assert.Equal(t, "AwS", statuses[0].IntegrationID)
+		assert.False(t, statuses[0].IsHealthy)
+		assert.Equal(t, "GcP", statuses[1].IntegrationID)
+		assert.True(t, statuses[1].IsHealthy)
+	}
+}
+
+func TestGetIntegrationHealthError(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/integration", verifyRequest(t, "GET", http.StatusOK, nil, "integration/search_success.json"))
+	mux.HandleFunc("/v2/integration/AwS/syncstatus", verifyRequest(t, "GET", http.StatusOK, nil, "integration/sync_status_success.json"))
+	mux.HandleFunc("/v2/integration/GcP/syncstatus", verifyRequest(t, "GET", http.StatusForbidden, nil, ""))
+
+	statuses, err := client.GetIntegrationHealth(context.Background())
+	assert.Error(t, err, "Should get an error when a status can't be fetched")
+	assert.Nil(t, statuses)
+}
@@ -0,0 +1,227 @@
+// Package hcl writes the subset of HCL needed to describe SignalFx resources
+// in Terraform configuration.
+package hcl
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Expr is an attribute value written as is, such as a reference to another
+// resource.
+type Expr string
+
+// Block is a block of attributes and nested blocks, written in the order they
+// were added.
+type Block struct {
+	typ    string
+	labels []string
+	items  []interface{}
+}
+
+type attr struct {
+	name  string
+	value interface{}
+}
+
+// NewBlock makes a block, e.g. NewBlock("resource", "signalfx_detector",
+// "cpu").
+func NewBlock(typ string, labels ...string) *Block {
+	return &Block{typ: typ, labels: labels}
+}
+
+// Attr adds an attribute.  value can be a string, bool, int, int32, int64,
+// float64, []string, map[string]string or Expr.  Strings with newlines are
+// written as heredocs.
+func (b *Block) Attr(name string, value interface{}) *Block {
+	b.items = append(b.items, attr{name, value})
+	return b
+}
+
+// Block adds a nested block and returns it.
+func (b *Block) Block(typ string, labels ...string) *Block {
+	nested := NewBlock(typ, labels...)
+	b.items = append(b.items, nested)
+	return nested
+}
+
+// String renders the block as terraform fmt would, with a trailing newline.
+func (b *Block) String() string {
+	var sb strings.Builder
+	b.write(&sb, "")
+	return sb.String()
+}
+
+func (b *Block) write(sb *strings.Builder, indent string) {
+	sb.WriteString(indent + b.typ)
+	for _, l := range b.labels {
+		sb.WriteString(" " + Quote(l))
+	}
+	sb.WriteString(" {\n")
+
+	inner := indent + "  "
+	for i := 0; i < len(b.items); {
+		switch item := b.items[i].(type) {
+		case *Block:
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			item.write(sb, inner)
+			if i+1 < len(b.items) {
+				if _, ok := b.items[i+1].(attr); ok {
+					sb.WriteString("\n")
+				}
+			}
+			i++
+		case attr:
+			// Like terraform fmt, align the equals signs of consecutive
+			// single-line attributes
+			j, width := i, 0
+			for ; j < len(b.items); j++ {
+				a, ok := b.items[j].(attr)
+				if !ok {
+					break
+				}
+				if len(a.name) > width {
+					width = len(a.name)
+				}
+				if strings.Contains(value(a.value, inner), "\n") {
+					j++
+					break
+				}
+			}
+			for ; i < j; i++ {
+				a := b.items[i].(attr)
+				fmt.Fprintf(sb, "%s%-*s = %s\n", inner, width, a.name, value(a.value, inner))
+			}
+		}
+	}
+	sb.WriteString(indent + "}\n")
+}
+
+func value(v interface{}, indent string) string {
+	switch v := v.(type) {
+	case Expr:
+		return string(v)
+	case string:
+		if strings.Contains(v, "\n") {
+			return heredoc(v)
+		}
+		return Quote(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case []string:
+		quoted := make([]string, len(v))
+		for i, s := range v {
+			quoted[i] = Quote(s)
+		}
+		return "[" + strings.Join(quoted, ", ") + "]"
+	case map[string]string:
+		if len(v) == 0 {
+			return "{}"
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		width := 0
+		for _, k := range keys {
+			if len(Quote(k)) > width {
+				width = len(Quote(k))
+			}
+		}
+		var sb strings.Builder
+		sb.WriteString("{\n")
+		for _, k := range keys {
+			fmt.Fprintf(&sb, "%s  %-*s = %s\n", indent, width, Quote(k), Quote(v[k]))
+		}
+		sb.WriteString(indent + "}")
+		return sb.String()
+	}
+	panic(fmt.Sprintf("hcl: unsupported attribute value %T", v))
+}
+
+// Quote returns s as a quoted HCL string, escaping template sequences so that
+// they are taken literally.
+func Quote(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '$', '%':
+			sb.WriteByte(c)
+			if i+1 < len(s) && s[i+1] == '{' {
+				sb.WriteByte(c)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// heredoc returns s as a heredoc, with a marker that doesn't appear as a line
+// of s.
+func heredoc(s string) string {
+	s = strings.NewReplacer("${", "$${", "%{", "%%{").Replace(s)
+	marker := "EOF"
+	for n := 1; containsLine(s, marker); n++ {
+		marker = "EOF" + strconv.Itoa(n)
+	}
+	if !strings.HasSuffix(s, "\n") {
+		s += "\n"
+	}
+	return "<<" + marker + "\n" + s + marker
+}
+
+func containsLine(s, line string) bool {
+	for _, l := range strings.Split(s, "\n") {
+		if strings.TrimSpace(l) == line {
+			return true
+		}
+	}
+	return false
+}
+
+// Identifier turns name into a valid Terraform resource name by lowercasing
+// it and replacing anything other than letters, digits and underscores with
+// underscores.
+func Identifier(name string) string {
+	var sb strings.Builder
+	lastUnderscore := false
+	for _, r := range strings.ToLower(name) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' {
+			sb.WriteRune(r)
+			lastUnderscore = r == '_'
+		} else if !lastUnderscore {
+			sb.WriteByte('_')
+			lastUnderscore = true
+		}
+	}
+	id := strings.Trim(sb.String(), "_")
+	if id == "" || id[0] >= '0' && id[0] <= '9' {
+		id = "_" + id
+	}
+	return id
+}
@@ -0,0 +1,44 @@
+package hcl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuote(t *testing.T) {
+	assert.Equal(t, `"plain"`, Quote("plain"))
+	assert.Equal(t, `"a \"b\" \\ c\n"`, Quote("a \"b\" \\ c\n"))
+	assert.Equal(t, `"$${x} %%{if} $5 100%"`, Quote("${x} %{if} $5 100%"))
+}
+
+func TestHeredocMarker(t *testing.T) {
+	b := NewBlock("x").Attr("text", "a\nEOF\nb")
+	assert.Equal(t, "x {\n  text = <<EOF1\na\nEOF\nb\nEOF1\n}\n", b.String())
+}
+
+func TestIdentifier(t *testing.T) {
+	assert.Equal(t, "cpu_high_prod", Identifier("CPU high (prod)"))
+	assert.Equal(t, "_99th_percentile", Identifier("99th percentile"))
+	assert.Equal(t, "_", Identifier("!!!"))
+}
+
+func TestNestedBlocks(t *testing.T) {
+	b := NewBlock("resource", "a", "b")
+	b.Attr("ref", Expr("a.b.id"))
+	b.Block("inner").Attr("tags", map[string]string{"k": "v", "long": "w"})
+	b.Attr("n", 1.5)
+	assert.Equal(t, `resource "a" "b" {
+  ref = a.b.id
+
+  inner {
+    tags = {
+      "k"    = "v"
+      "long" = "w"
+    }
+  }
+
+  n = 1.5
+}
+`, b.String())
+}
@@ -2,9 +2,6 @@ package signalfx
 
 import (
 	"bytes"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
 	"net/http"
 
 	"github.com/adampetrovic/signalfx-go/integration"
@@ -12,7 +9,7 @@ import (
 
 // CreateJiraIntegration creates an Jira integration.
 func (c *Client) CreateJiraIntegration(ji *integration.JiraIntegration) (*integration.JiraIntegration, error) {
-	payload, err := json.Marshal(ji)
+	payload, err := c.marshal(ji)
 	if err != nil {
 		return nil, err
 	}
@@ -25,13 +22,12 @@ func (c *Client) CreateJiraIntegration(ji *integration.JiraIntegration) (*integr
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalIntegration := integration.JiraIntegration{}
 
-	err = json.NewDecoder(resp.Body).Decode(&finalIntegration)
+	err = c.decodeJSON(resp.Body, &finalIntegration)
 
 	return &finalIntegration, err
 }
@@ -46,20 +42,19 @@ func (c *Client) GetJiraIntegration(id string) (*integration.JiraIntegration, er
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalIntegration := integration.JiraIntegration{}
 
-	err = json.NewDecoder(resp.Body).Decode(&finalIntegration)
+	err = c.decodeJSON(resp.Body, &finalIntegration)
 
 	return &finalIntegration, err
 }
 
 // UpdateJiraIntegration updates an Jira integration.
 func (c *Client) UpdateJiraIntegration(id string, ji *integration.JiraIntegration) (*integration.JiraIntegration, error) {
-	payload, err := json.Marshal(ji)
+	payload, err := c.marshal(ji)
 	if err != nil {
 		return nil, err
 	}
@@ -72,13 +67,12 @@ func (c *Client) UpdateJiraIntegration(id string, ji *integration.JiraIntegratio
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalIntegration := integration.JiraIntegration{}
 
-	err = json.NewDecoder(resp.Body).Decode(&finalIntegration)
+	err = c.decodeJSON(resp.Body, &finalIntegration)
 
 	return &finalIntegration, err
 }
@@ -93,8 +87,7 @@ func (c *Client) DeleteJiraIntegration(id string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return newAPIError(resp)
 	}
 
 	return err
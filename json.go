@@ -0,0 +1,76 @@
+package signalfx
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+)
+
+// JSONEncoder encodes request bodies.  Its method has the same signature as
+// json.Marshal.
+type JSONEncoder interface {
+	Marshal(v interface{}) ([]byte, error)
+}
+
+// JSONDecoder decodes response bodies.  Its method has the same signature as
+// json.Unmarshal.
+type JSONDecoder interface {
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// stdJSON is the default JSONEncoder and JSONDecoder, backed by
+// encoding/json.
+type stdJSON struct{}
+
+func (stdJSON) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (stdJSON) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// WithJSONEncoder sets the encoder used for request bodies, e.g. one that
+// can represent NaN and infinite values that encoding/json rejects.  Any
+// json.Marshaler implementations on the request types must still be honored
+// for requests to be encoded correctly.
+func WithJSONEncoder(enc JSONEncoder) ClientParam {
+	return func(client *Client) error {
+		if enc == nil {
+			return errors.New("JSONEncoder cannot be nil")
+		}
+		client.jsonEncoder = enc
+		return nil
+	}
+}
+
+// WithJSONDecoder sets the decoder used for response bodies, e.g. a faster
+// drop-in replacement for encoding/json such as
+// jsoniter.ConfigCompatibleWithStandardLibrary.
+func WithJSONDecoder(dec JSONDecoder) ClientParam {
+	return func(client *Client) error {
+		if dec == nil {
+			return errors.New("JSONDecoder cannot be nil")
+		}
+		client.jsonDecoder = dec
+		return nil
+	}
+}
+
+func (c *Client) marshal(v interface{}) ([]byte, error) {
+	return c.jsonEncoder.Marshal(v)
+}
+
+func (c *Client) unmarshal(data []byte, v interface{}) error {
+	return c.jsonDecoder.Unmarshal(data, v)
+}
+
+// decodeJSON reads all of r and decodes it into v.
+func (c *Client) decodeJSON(r io.Reader, v interface{}) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return c.unmarshal(data, v)
+}
@@ -0,0 +1,48 @@
+package signalfx
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/adampetrovic/signalfx-go/detector"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingJSON struct {
+	marshals, unmarshals int
+}
+
+func (cj *countingJSON) Marshal(v interface{}) ([]byte, error) {
+	cj.marshals++
+	return json.Marshal(v)
+}
+
+func (cj *countingJSON) Unmarshal(data []byte, v interface{}) error {
+	cj.unmarshals++
+	return json.Unmarshal(data, v)
+}
+
+func TestWithJSONEncoderDecoder(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	cj := &countingJSON{}
+	assert.NoError(t, WithJSONEncoder(cj)(client))
+	assert.NoError(t, WithJSONDecoder(cj)(client))
+
+	mux.HandleFunc("/v2/detector", verifyRequest(t, "POST", http.StatusOK, nil, "detector/create_success.json"))
+
+	result, err := client.CreateDetector(&detector.CreateUpdateDetectorRequest{Name: "string"})
+	assert.NoError(t, err, "Unexpected error creating detector")
+	assert.Equal(t, "string", result.Name)
+	assert.Equal(t, 1, cj.marshals, "Request should use the custom encoder")
+	assert.Equal(t, 1, cj.unmarshals, "Response should use the custom decoder")
+}
+
+func TestWithNilJSONEncoderDecoder(t *testing.T) {
+	_, err := NewClient("token", WithJSONEncoder(nil))
+	assert.Error(t, err, "Should reject a nil encoder")
+	_, err = NewClient("token", WithJSONDecoder(nil))
+	assert.Error(t, err, "Should reject a nil decoder")
+}
@@ -2,9 +2,7 @@ package signalfx
 
 import (
 	"bytes"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
+	"context"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -26,45 +24,51 @@ const TagAPIURL = "/v2/tag"
 
 // GetDimension gets a dimension.
 func (c *Client) GetDimension(key string, value string) (*metrics_metadata.Dimension, error) {
-	resp, err := c.doRequest("GET", DimensionAPIURL+"/"+key+"/"+value, nil, nil)
+	return c.getDimension(c.ctx, key, value)
+}
+
+func (c *Client) getDimension(ctx context.Context, key string, value string) (*metrics_metadata.Dimension, error) {
+	resp, err := c.doRequestWithContext(ctx, "GET", DimensionAPIURL+"/"+key+"/"+value, nil, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Bad status %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalDimension := &metrics_metadata.Dimension{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalDimension)
+	err = c.decodeJSON(resp.Body, finalDimension)
 
 	return finalDimension, err
 }
 
 // UpdateDimension updates a dimension.
 func (c *Client) UpdateDimension(key string, value string, dim *metrics_metadata.Dimension) (*metrics_metadata.Dimension, error) {
-	payload, err := json.Marshal(dim)
+	return c.updateDimension(c.ctx, key, value, dim)
+}
+
+func (c *Client) updateDimension(ctx context.Context, key string, value string, dim *metrics_metadata.Dimension) (*metrics_metadata.Dimension, error) {
+	payload, err := c.marshal(dim)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.doRequest("PUT", DimensionAPIURL+"/"+key+"/"+value, nil, bytes.NewReader(payload))
+	resp, err := c.doRequestWithContext(ctx, "PUT", DimensionAPIURL+"/"+key+"/"+value, nil, bytes.NewReader(payload))
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Bad status %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalDimension := &metrics_metadata.Dimension{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalDimension)
+	err = c.decodeJSON(resp.Body, finalDimension)
 
 	return finalDimension, err
 }
@@ -86,7 +90,7 @@ func (c *Client) SearchDimension(query string, orderBy string, limit int, offset
 
 	finalDimensions := &metrics_metadata.DimensionQueryResponseModel{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalDimensions)
+	err = c.decodeJSON(resp.Body, finalDimensions)
 
 	return finalDimensions, err
 }
@@ -108,7 +112,7 @@ func (c *Client) SearchMetric(query string, orderBy string, limit int, offset in
 
 	finalMetrics := &metrics_metadata.RetrieveMetricMetadataResponseModel{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalMetrics)
+	err = c.decodeJSON(resp.Body, finalMetrics)
 
 	return finalMetrics, err
 }
@@ -122,33 +126,35 @@ func (c *Client) GetMetric(name string) (*metrics_metadata.Metric, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Bad status %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalMetric := &metrics_metadata.Metric{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalMetric)
+	err = c.decodeJSON(resp.Body, finalMetric)
 
 	return finalMetric, err
 }
 
 // GetMetricTimeSeries retrieves a metric time series by id.
 func (c *Client) GetMetricTimeSeries(id string) (*metrics_metadata.MetricTimeSeries, error) {
-	resp, err := c.doRequest("GET", MetricTimeSeriesAPIURL+"/"+id, nil, nil)
+	return c.getMetricTimeSeries(c.ctx, id)
+}
+
+func (c *Client) getMetricTimeSeries(ctx context.Context, id string) (*metrics_metadata.MetricTimeSeries, error) {
+	resp, err := c.doRequestWithContext(ctx, "GET", MetricTimeSeriesAPIURL+"/"+id, nil, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Bad status %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalMetricTimeSeries := &metrics_metadata.MetricTimeSeries{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalMetricTimeSeries)
+	err = c.decodeJSON(resp.Body, finalMetricTimeSeries)
 	return finalMetricTimeSeries, err
 }
 
@@ -169,7 +175,7 @@ func (c *Client) SearchMetricTimeSeries(query string, orderBy string, limit int,
 
 	finalMTS := &metrics_metadata.MetricTimeSeriesRetrieveResponseModel{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalMTS)
+	err = c.decodeJSON(resp.Body, finalMTS)
 
 	return finalMTS, err
 }
@@ -191,7 +197,7 @@ func (c *Client) SearchTag(query string, orderBy string, limit int, offset int)
 
 	finalTags := &metrics_metadata.TagRetrieveResponseModel{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalTags)
+	err = c.decodeJSON(resp.Body, finalTags)
 
 	return finalTags, err
 }
@@ -205,13 +211,12 @@ func (c *Client) GetTag(name string) (*metrics_metadata.Tag, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Bad status %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalTag := &metrics_metadata.Tag{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalTag)
+	err = c.decodeJSON(resp.Body, finalTag)
 	return finalTag, err
 }
 
@@ -225,8 +230,7 @@ func (c *Client) DeleteTag(id string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return newAPIError(resp)
 	}
 
 	return nil
@@ -234,7 +238,7 @@ func (c *Client) DeleteTag(id string) error {
 
 // CreateUpdateTag creates or updates a dimension.
 func (c *Client) CreateUpdateTag(name string, cutr *metrics_metadata.CreateUpdateTagRequest) (*metrics_metadata.Tag, error) {
-	payload, err := json.Marshal(cutr)
+	payload, err := c.marshal(cutr)
 	if err != nil {
 		return nil, err
 	}
@@ -246,13 +250,12 @@ func (c *Client) CreateUpdateTag(name string, cutr *metrics_metadata.CreateUpdat
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Bad status %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalTag := &metrics_metadata.Tag{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalTag)
+	err = c.decodeJSON(resp.Body, finalTag)
 
 	return finalTag, err
 }
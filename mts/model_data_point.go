@@ -0,0 +1,13 @@
+package mts
+
+import "time"
+
+// DataPoint is a single value of a metric time series at a point in time.
+type DataPoint struct {
+	// When the value was recorded, rounded to the resolution of the query.
+	Timestamp time.Time
+	// The value of the time series, after applying the query's rollup.
+	Value float64
+	// The dimensions that identify the time series the value belongs to.
+	Dimensions map[string]string
+}
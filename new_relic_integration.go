@@ -0,0 +1,94 @@
+package signalfx
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/adampetrovic/signalfx-go/integration"
+)
+
+// CreateNewRelicIntegration creates a New Relic integration.
+func (c *Client) CreateNewRelicIntegration(nri *integration.NewRelicIntegration) (*integration.NewRelicIntegration, error) {
+	payload, err := c.marshal(nri)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest("POST", IntegrationAPIURL, nil, bytes.NewReader(payload))
+
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	finalIntegration := integration.NewRelicIntegration{}
+
+	err = c.decodeJSON(resp.Body, &finalIntegration)
+
+	return &finalIntegration, err
+}
+
+// GetNewRelicIntegration retrieves a New Relic integration.
+func (c *Client) GetNewRelicIntegration(id string) (*integration.NewRelicIntegration, error) {
+	resp, err := c.doRequest("GET", IntegrationAPIURL+"/"+id, nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	finalIntegration := integration.NewRelicIntegration{}
+
+	err = c.decodeJSON(resp.Body, &finalIntegration)
+
+	return &finalIntegration, err
+}
+
+// UpdateNewRelicIntegration updates a New Relic integration.
+func (c *Client) UpdateNewRelicIntegration(id string, nri *integration.NewRelicIntegration) (*integration.NewRelicIntegration, error) {
+	payload, err := c.marshal(nri)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest("PUT", IntegrationAPIURL+"/"+id, nil, bytes.NewReader(payload))
+
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	finalIntegration := integration.NewRelicIntegration{}
+
+	err = c.decodeJSON(resp.Body, &finalIntegration)
+
+	return &finalIntegration, err
+}
+
+// DeleteNewRelicIntegration deletes a New Relic integration.
+func (c *Client) DeleteNewRelicIntegration(id string) error {
+	resp, err := c.doRequest("DELETE", IntegrationAPIURL+"/"+id, nil, nil)
+
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return newAPIError(resp)
+	}
+
+	return err
+}
@@ -0,0 +1,56 @@
+package signalfx
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/adampetrovic/signalfx-go/integration"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateNewRelicIntegration(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/integration", verifyRequest(t, "POST", http.StatusOK, nil, "integration/create_new_relic_success.json"))
+
+	result, err := client.CreateNewRelicIntegration(&integration.NewRelicIntegration{
+		Type: "NewRelic",
+	})
+	assert.NoError(t, err, "Unexpected error creating integration")
+	assert.Equal(t, "string", result.Name, "Name does not match")
+}
+
+func TestGetNewRelicIntegration(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/integration/id", verifyRequest(t, "GET", http.StatusOK, nil, "integration/create_new_relic_success.json"))
+
+	result, err := client.GetNewRelicIntegration("id")
+	assert.NoError(t, err, "Unexpected error getting integration")
+	assert.Equal(t, "string", result.Name, "Name does not match")
+}
+
+func TestUpdateNewRelicIntegration(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/integration/id", verifyRequest(t, "PUT", http.StatusOK, nil, "integration/create_new_relic_success.json"))
+
+	result, err := client.UpdateNewRelicIntegration("id", &integration.NewRelicIntegration{
+		Type: "NewRelic",
+	})
+	assert.NoError(t, err, "Unexpected error creating integration")
+	assert.Equal(t, "string", result.Name, "Name does not match")
+}
+
+func TestDeleteNewRelicIntegration(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/integration/id", verifyRequest(t, "DELETE", http.StatusNoContent, nil, ""))
+
+	err := client.DeleteNewRelicIntegration("id")
+	assert.NoError(t, err, "Unexpected error creating integration")
+}
@@ -0,0 +1,35 @@
+package notification
+
+// A policy that decides where alerts of a given severity are sent.
+type Policy struct {
+	// The ID of the policy
+	ID string `json:"id,omitempty"`
+	// The name of the policy
+	Name string `json:"name,omitempty"`
+	// The alert severity the policy applies to, e.g. "Critical"
+	Severity string `json:"severity,omitempty"`
+	// Where matching alerts are sent
+	Recipients []*Notification `json:"recipients,omitempty"`
+	// The ID of the escalation policy used if nobody responds
+	EscalationPolicyID string `json:"escalationPolicyId,omitempty"`
+}
+
+// The fields of a Policy to change.  Fields left empty aren't changed.
+type PolicyUpdate struct {
+	// The new name of the policy
+	Name string `json:"name,omitempty"`
+	// The new alert severity the policy applies to
+	Severity string `json:"severity,omitempty"`
+	// The new recipients of matching alerts
+	Recipients []*Notification `json:"recipients,omitempty"`
+	// The ID of the new escalation policy
+	EscalationPolicyID string `json:"escalationPolicyId,omitempty"`
+}
+
+// Query results, in the form of a JSON object
+type PolicySearchResults struct {
+	// Number of policies that matched the search
+	Count int32 `json:"count,omitempty"`
+	// The policies in this page of results
+	Results []*Policy `json:"results,omitempty"`
+}
@@ -0,0 +1,54 @@
+package signalfx
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	"github.com/adampetrovic/signalfx-go/notification"
+)
+
+// NotificationPolicyAPIURL is the base URL for interacting with notification
+// policies.
+const NotificationPolicyAPIURL = "/v2/notificationpolicy"
+
+// ListNotificationPolicies gets every notification policy in the
+// organization.
+func (c *Client) ListNotificationPolicies(ctx context.Context) ([]*notification.Policy, error) {
+	var policies []*notification.Policy
+	err := c.forEachPage(ctx, NotificationPolicyAPIURL, nil, func() (interface{}, func() int) {
+		page := &notification.PolicySearchResults{}
+		return page, func() int {
+			policies = append(policies, page.Results...)
+			return len(page.Results)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// UpdateNotificationPolicy updates a notification policy.
+func (c *Client) UpdateNotificationPolicy(ctx context.Context, policyID string, req *notification.PolicyUpdate) (*notification.Policy, error) {
+	payload, err := c.marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequestWithContext(ctx, "PUT", NotificationPolicyAPIURL+"/"+policyID, nil, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	finalPolicy := &notification.Policy{}
+
+	err = c.decodeJSON(resp.Body, finalPolicy)
+
+	return finalPolicy, err
+}
@@ -0,0 +1,62 @@
+package signalfx
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/adampetrovic/signalfx-go/notification"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListNotificationPolicies(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	params := url.Values{}
+	params.Add("limit", "100")
+	params.Add("offset", "0")
+	mux.HandleFunc("/v2/notificationpolicy", verifyRequest(t, "GET", http.StatusOK, params, "notification/list_policies_success.json"))
+
+	result, err := client.ListNotificationPolicies(context.Background())
+	assert.NoError(t, err, "Unexpected error listing policies")
+	assert.Len(t, result, 2, "Should have every policy")
+	assert.Equal(t, "Esc1", result[0].EscalationPolicyID)
+	assert.Equal(t, "oncall@example.com", result[0].Recipients[0].Value.(*notification.EmailNotification).Email)
+}
+
+func TestListNotificationPoliciesBadStatus(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/notificationpolicy", verifyRequest(t, "GET", http.StatusUnauthorized, nil, ""))
+
+	result, err := client.ListNotificationPolicies(context.Background())
+	assert.Error(t, err, "Should get an error from a bad status")
+	assert.Nil(t, result, "Should get nil result")
+}
+
+func TestUpdateNotificationPolicy(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/notificationpolicy/Pol1", verifyRequest(t, "PUT", http.StatusOK, nil, "notification/update_policy_success.json"))
+
+	result, err := client.UpdateNotificationPolicy(context.Background(), "Pol1", &notification.PolicyUpdate{
+		EscalationPolicyID: "Esc2",
+	})
+	assert.NoError(t, err, "Unexpected error updating policy")
+	assert.Equal(t, "Esc2", result.EscalationPolicyID)
+}
+
+func TestUpdateMissingNotificationPolicy(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/notificationpolicy/Pol1", verifyRequest(t, "PUT", http.StatusNotFound, nil, ""))
+
+	result, err := client.UpdateNotificationPolicy(context.Background(), "Pol1", &notification.PolicyUpdate{})
+	assert.Error(t, err, "Should get an error from a missing policy")
+	assert.Nil(t, result, "Should get nil result")
+}
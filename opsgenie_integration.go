@@ -2,9 +2,6 @@ package signalfx
 
 import (
 	"bytes"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
 	"net/http"
 
 	"github.com/adampetrovic/signalfx-go/integration"
@@ -12,7 +9,7 @@ import (
 
 // CreateOpsgenieIntegration creates an Opsgenie integration.
 func (c *Client) CreateOpsgenieIntegration(oi *integration.OpsgenieIntegration) (*integration.OpsgenieIntegration, error) {
-	payload, err := json.Marshal(oi)
+	payload, err := c.marshal(oi)
 	if err != nil {
 		return nil, err
 	}
@@ -25,13 +22,12 @@ func (c *Client) CreateOpsgenieIntegration(oi *integration.OpsgenieIntegration)
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalIntegration := integration.OpsgenieIntegration{}
 
-	err = json.NewDecoder(resp.Body).Decode(&finalIntegration)
+	err = c.decodeJSON(resp.Body, &finalIntegration)
 
 	return &finalIntegration, err
 }
@@ -46,20 +42,19 @@ func (c *Client) GetOpsgenieIntegration(id string) (*integration.OpsgenieIntegra
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalIntegration := integration.OpsgenieIntegration{}
 
-	err = json.NewDecoder(resp.Body).Decode(&finalIntegration)
+	err = c.decodeJSON(resp.Body, &finalIntegration)
 
 	return &finalIntegration, err
 }
 
 // UpdateOpsgenieIntegration updates an Opsgenie integration.
 func (c *Client) UpdateOpsgenieIntegration(id string, oi *integration.OpsgenieIntegration) (*integration.OpsgenieIntegration, error) {
-	payload, err := json.Marshal(oi)
+	payload, err := c.marshal(oi)
 	if err != nil {
 		return nil, err
 	}
@@ -72,13 +67,12 @@ func (c *Client) UpdateOpsgenieIntegration(id string, oi *integration.OpsgenieIn
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalIntegration := integration.OpsgenieIntegration{}
 
-	err = json.NewDecoder(resp.Body).Decode(&finalIntegration)
+	err = c.decodeJSON(resp.Body, &finalIntegration)
 
 	return &finalIntegration, err
 }
@@ -93,8 +87,7 @@ func (c *Client) DeleteOpsgenieIntegration(id string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return newAPIError(resp)
 	}
 
 	return err
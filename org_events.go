@@ -0,0 +1,184 @@
+package signalfx
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/audit"
+)
+
+// OrgEventType is a kind of administrative change to an organization.  It is
+// the audit log event's resource type and action, in the past tense, so that
+// an `UPDATE` of a `detector` is DETECTOR_UPDATED.
+type OrgEventType string
+
+// Some of the changes that can be subscribed to with SubscribeToOrgEvents.
+const (
+	OrgEventUserAdded        OrgEventType = "USER_ADDED"
+	OrgEventUserRemoved      OrgEventType = "USER_REMOVED"
+	OrgEventTokenCreated     OrgEventType = "TOKEN_CREATED"
+	OrgEventTokenUpdated     OrgEventType = "TOKEN_UPDATED"
+	OrgEventTokenDeleted     OrgEventType = "TOKEN_DELETED"
+	OrgEventDetectorCreated  OrgEventType = "DETECTOR_CREATED"
+	OrgEventDetectorUpdated  OrgEventType = "DETECTOR_UPDATED"
+	OrgEventDetectorDeleted  OrgEventType = "DETECTOR_DELETED"
+	OrgEventDashboardCreated OrgEventType = "DASHBOARD_CREATED"
+	OrgEventDashboardUpdated OrgEventType = "DASHBOARD_UPDATED"
+	OrgEventDashboardDeleted OrgEventType = "DASHBOARD_DELETED"
+)
+
+// OrgEvent is a single entry in the organization's audit log.
+type OrgEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	// The ID of the user or token that made the change
+	Actor        string       `json:"actor"`
+	EventType    OrgEventType `json:"eventType"`
+	ResourceID   string       `json:"resourceId"`
+	ResourceType string       `json:"resourceType"`
+	// The fields that changed, in the form the audit log reports them
+	Changes map[string]interface{} `json:"changes,omitempty"`
+}
+
+// SubscribeToOrgEvents streams the organization's audit log as it happens,
+// limited to eventTypes, or every type if it's empty.  The audit log can't be
+// pushed to clients, so it's polled with GetAuditLog every PollInterval for
+// events newer than the last one sent, starting from now.
+//
+// The first poll is made before returning, so that an error such as the
+// token not being allowed to read the audit log is returned.  Later polls
+// that fail, and events that can't be decoded, are logged and skipped; a
+// failed poll's events are picked up by the next one.  The channel is closed
+// when ctx is done.
+func (c *Client) SubscribeToOrgEvents(ctx context.Context, eventTypes []OrgEventType) (<-chan OrgEvent, error) {
+	p := &orgEventPoller{
+		client: c,
+		since:  time.Now(),
+		seen:   map[string]bool{},
+		types:  map[OrgEventType]bool{},
+	}
+	for _, et := range eventTypes {
+		p.types[et] = true
+	}
+
+	pending, err := p.poll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan OrgEvent)
+	go func() {
+		defer close(events)
+		for {
+			for _, ev := range pending {
+				select {
+				case events <- ev:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			t := time.NewTimer(c.pollInterval)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return
+			case <-t.C:
+			}
+
+			pending, err = p.poll(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Polling the audit log for org events failed: %v", err)
+			}
+		}
+	}()
+	return events, nil
+}
+
+type orgEventPoller struct {
+	client *Client
+	// The time of the newest event seen, which the next poll starts from
+	since time.Time
+	// Keys of the events seen at since, which the next poll gets again
+	seen  map[string]bool
+	types map[OrgEventType]bool
+}
+
+// poll gets the events since the last poll, oldest first.
+func (p *orgEventPoller) poll(ctx context.Context) ([]OrgEvent, error) {
+	params := &audit.QueryParams{StartTime: p.since}
+	var found []*audit.Event
+	for {
+		page, err := p.client.GetAuditLog(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, page.Events...)
+		if page.NextOffset == 0 {
+			break
+		}
+		params.Offset = page.NextOffset
+	}
+
+	var out []OrgEvent
+	// Pages are newest first
+	for i := len(found) - 1; i >= 0; i-- {
+		e := found[i]
+		key := auditEventKey(e)
+		if e.Timestamp.Before(p.since) || p.seen[key] {
+			continue
+		}
+		if e.Timestamp.After(p.since) {
+			p.since = e.Timestamp
+			p.seen = map[string]bool{}
+		}
+		p.seen[key] = true
+
+		ev, err := p.client.orgEventFromAudit(e)
+		if err != nil {
+			log.Printf("Skipping malformed org event %q: %v", e.Delta, err)
+			continue
+		}
+		if len(p.types) > 0 && !p.types[ev.EventType] {
+			continue
+		}
+		out = append(out, ev)
+	}
+	return out, nil
+}
+
+// orgEventFromAudit converts an audit log event, decoding its changes with
+// the client's JSON decoder.
+func (c *Client) orgEventFromAudit(e *audit.Event) (OrgEvent, error) {
+	ev := OrgEvent{
+		Timestamp:    e.Timestamp,
+		Actor:        e.Actor,
+		EventType:    orgEventType(e.ResourceType, e.Action),
+		ResourceID:   e.ResourceID,
+		ResourceType: e.ResourceType,
+	}
+	if len(e.Delta) > 0 {
+		if err := c.unmarshal(e.Delta, &ev.Changes); err != nil {
+			return OrgEvent{}, err
+		}
+	}
+	return ev, nil
+}
+
+func orgEventType(resourceType, action string) OrgEventType {
+	action = strings.ToUpper(action)
+	if strings.HasSuffix(action, "E") {
+		action += "D"
+	} else {
+		action += "ED"
+	}
+	return OrgEventType(strings.ToUpper(resourceType) + "_" + action)
+}
+
+// auditEventKey identifies an audit log event, which has no ID of its own.
+func auditEventKey(e *audit.Event) string {
+	return fmt.Sprintf("%d\x00%s\x00%s\x00%s\x00%s\x00%s",
+		e.Timestamp.UnixNano(), e.Actor, e.Action, e.ResourceType, e.ResourceID, e.Delta)
+}
@@ -0,0 +1,121 @@
+package signalfx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribeToOrgEvents(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	// Each poll returns the next response, newest first, and the last one
+	// from then on
+	responses := []string{
+		`{"count": 0, "results": []}`,
+		`{"count": 3, "results": [
+			{"timestamp": 4102444800000, "actor": "AbC", "action": "CREATE", "resourceType": "token", "resourceId": "ToK", "delta": {"name": "ci"}},
+			{"timestamp": 4102444800000, "action": "UPDATE", "resourceType": "chart", "resourceId": "ChT"},
+			{"timestamp": 4102444740000, "action": "ADD", "resourceType": "user", "resourceId": "UsR"}
+		]}`,
+		`{"count": 2, "results": [
+			{"timestamp": 4102444860000, "action": "DELETE", "resourceType": "token", "resourceId": "ToK"},
+			{"timestamp": 4102444800000, "actor": "AbC", "action": "CREATE", "resourceType": "token", "resourceId": "ToK", "delta": {"name": "ci"}}
+		]}`,
+	}
+	var froms []string
+	mux.HandleFunc("/v2/event/audit", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, TestToken, r.Header.Get(AuthHeaderKey))
+		froms = append(froms, r.URL.Query().Get("from"))
+		resp := responses[0]
+		if len(responses) > 1 {
+			responses = responses[1:]
+		}
+		w.Write([]byte(resp))
+	})
+	client.pollInterval = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := client.SubscribeToOrgEvents(ctx, []OrgEventType{OrgEventTokenCreated, OrgEventTokenDeleted, OrgEventUserAdded})
+	assert.NoError(t, err, "Unexpected error subscribing")
+
+	ev := <-events
+	assert.Equal(t, OrgEvent{
+		Timestamp:    time.Unix(4102444740, 0),
+		EventType:    OrgEventUserAdded,
+		ResourceID:   "UsR",
+		ResourceType: "user",
+	}, ev, "Events should be sent oldest first")
+
+	ev = <-events
+	assert.Equal(t, OrgEvent{
+		Timestamp:    time.Unix(4102444800, 0),
+		Actor:        "AbC",
+		EventType:    OrgEventTokenCreated,
+		ResourceID:   "ToK",
+		ResourceType: "token",
+		Changes:      map[string]interface{}{"name": "ci"},
+	}, ev, "Other event types should be skipped")
+
+	ev = <-events
+	assert.Equal(t, OrgEventTokenDeleted, ev.EventType, "Events seen by the last poll should be skipped")
+
+	cancel()
+	for range events {
+	}
+	if assert.True(t, len(froms) >= 3) {
+		assert.Equal(t, "4102444800000", froms[2], "Should poll from the newest event seen")
+	}
+}
+
+func TestSubscribeToOrgEventsUsesDecoder(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	cj := &countingJSON{}
+	assert.NoError(t, WithJSONDecoder(cj)(client))
+	mux.HandleFunc("/v2/event/audit", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"count": 1, "results": [{"timestamp": 4102444800000, "action": "ADD", "resourceType": "user", "delta": {"email": "a@b.c"}}]}`))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := client.SubscribeToOrgEvents(ctx, nil)
+	assert.NoError(t, err, "Unexpected error subscribing")
+	ev := <-events
+	assert.Equal(t, map[string]interface{}{"email": "a@b.c"}, ev.Changes)
+	assert.Equal(t, 2, cj.unmarshals, "Should decode the page and the changes with the client's decoder")
+}
+
+func TestSubscribeToOrgEventsError(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/event/audit", verifyRequest(t, "GET", http.StatusForbidden, nil, ""))
+
+	events, err := client.SubscribeToOrgEvents(context.Background(), nil)
+	assert.Error(t, err, "Should have gotten an error from a forbidden request")
+	assert.Nil(t, events)
+}
+
+func TestOrgEventJSON(t *testing.T) {
+	ev := OrgEvent{
+		Timestamp:    time.Unix(4102444800, 0).UTC(),
+		Actor:        "AbC",
+		EventType:    OrgEventTokenCreated,
+		ResourceID:   "ToK",
+		ResourceType: "token",
+	}
+	b, err := json.Marshal(ev)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"timestamp":"2100-01-01T00:00:00Z"`)
+
+	var decoded OrgEvent
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, ev, decoded, "Events should survive being stored and read back")
+}
@@ -0,0 +1,154 @@
+package signalfx
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/dashboard"
+	"github.com/adampetrovic/signalfx-go/dashboard_group"
+	"github.com/adampetrovic/signalfx-go/detector"
+	"github.com/adampetrovic/signalfx-go/orgtoken"
+	"github.com/adampetrovic/signalfx-go/team"
+)
+
+// ExportOptions selects which resource types ExportOrg includes. A nil
+// *ExportOptions includes everything.
+type ExportOptions struct {
+	Detectors       bool
+	Dashboards      bool
+	DashboardGroups bool
+	Teams           bool
+	Tokens          bool
+}
+
+// OrgExport is a backup of an organization's configuration, as produced by
+// ExportOrg. It's plain JSON-serializable data.
+type OrgExport struct {
+	ExportedAt      time.Time                         `json:"exportedAt"`
+	Detectors       []*detector.Detector              `json:"detectors,omitempty"`
+	Dashboards      []*dashboard.Dashboard            `json:"dashboards,omitempty"`
+	DashboardGroups []*dashboard_group.DashboardGroup `json:"dashboardGroups,omitempty"`
+	Teams           []*team.Team                      `json:"teams,omitempty"`
+	// Org tokens, with their secrets removed
+	Tokens []*orgtoken.Token `json:"tokens,omitempty"`
+}
+
+// ExportOrg fetches every resource of the types selected by opts, for
+// backing up the organization. Token secrets are never included.
+func (c *Client) ExportOrg(ctx context.Context, opts *ExportOptions) (*OrgExport, error) {
+	if opts == nil {
+		opts = &ExportOptions{Detectors: true, Dashboards: true, DashboardGroups: true, Teams: true, Tokens: true}
+	}
+
+	export := &OrgExport{ExportedAt: time.Now().UTC()}
+
+	if opts.Detectors {
+		err := c.forEachPage(ctx, DetectorAPIURL, nil, func() (interface{}, func() int) {
+			page := &detector.SearchResults{}
+			return page, func() int {
+				for i := range page.Results {
+					export.Detectors = append(export.Detectors, &page.Results[i])
+				}
+				return len(page.Results)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.Dashboards {
+		err := c.forEachPage(ctx, DashboardAPIURL, nil, func() (interface{}, func() int) {
+			page := &dashboard.SearchResult{}
+			return page, func() int {
+				for i := range page.Results {
+					export.Dashboards = append(export.Dashboards, &page.Results[i])
+				}
+				return len(page.Results)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.DashboardGroups {
+		err := c.forEachPage(ctx, DashboardGroupAPIURL, nil, func() (interface{}, func() int) {
+			page := &dashboard_group.SearchResult{}
+			return page, func() int {
+				export.DashboardGroups = append(export.DashboardGroups, page.Results...)
+				return len(page.Results)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.Teams {
+		err := c.forEachPage(ctx, TeamAPIURL, nil, func() (interface{}, func() int) {
+			page := &team.SearchResults{}
+			return page, func() int {
+				for i := range page.Results {
+					export.Teams = append(export.Teams, &page.Results[i])
+				}
+				return len(page.Results)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.Tokens {
+		err := c.forEachPage(ctx, TokenAPIURL, nil, func() (interface{}, func() int) {
+			page := &orgtoken.SearchResults{}
+			return page, func() int {
+				for i := range page.Results {
+					token := page.Results[i]
+					token.Secret = ""
+					export.Tokens = append(export.Tokens, &token)
+				}
+				return len(page.Results)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return export, nil
+}
+
+// forEachPage pages through every result of a search endpoint, adding any
+// extra params to each request. newPage returns a value to decode each page
+// into, and a function to call once it's decoded that returns how many results
+// the page had. If the server sends a Link header its next link is followed
+// instead of counting offsets.
+func (c *Client) forEachPage(ctx context.Context, apiURL string, extra url.Values, newPage func() (interface{}, func() int)) error {
+	p := c.newPager(ctx, apiURL, extra)
+	for !p.done {
+		page, done := newPage()
+		if err := p.fetch(page, done); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getJSON GETs path and decodes the response into v.
+func (c *Client) getJSON(ctx context.Context, path string, params url.Values, v interface{}) error {
+	resp, err := c.doRequestWithContext(ctx, "GET", path, params, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(resp)
+	}
+
+	return c.decodeJSON(resp.Body, v)
+}
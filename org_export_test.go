@@ -0,0 +1,65 @@
+package signalfx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportOrg(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	params := url.Values{}
+	params.Add("limit", "100")
+	params.Add("offset", "0")
+	mux.HandleFunc("/v2/detector", verifyRequest(t, "GET", http.StatusOK, params, "detector/search_success.json"))
+	mux.HandleFunc("/v2/dashboard", verifyRequest(t, "GET", http.StatusOK, params, "dashboard/search_success.json"))
+	mux.HandleFunc("/v2/dashboardgroup", verifyRequest(t, "GET", http.StatusOK, params, "dashboardgroup/search_success.json"))
+	mux.HandleFunc("/v2/team", verifyRequest(t, "GET", http.StatusOK, params, "team/search_success.json"))
+	mux.HandleFunc("/v2/token", verifyRequest(t, "GET", http.StatusOK, params, "orgtoken/search_success.json"))
+
+	export, err := client.ExportOrg(context.Background(), nil)
+	assert.NoError(t, err, "Unexpected error exporting org")
+	assert.Len(t, export.Detectors, 1)
+	assert.Len(t, export.Dashboards, 1)
+	assert.Len(t, export.DashboardGroups, 1)
+	assert.Len(t, export.Teams, 1)
+	if assert.Len(t, export.Tokens, 2) {
+		assert.Empty(t, export.Tokens[0].Secret, "Secrets should be redacted")
+		assert.Empty(t, export.Tokens[1].Secret, "Secrets should be redacted")
+	}
+
+	data, err := json.Marshal(export)
+	assert.NoError(t, err, "Export should be serializable")
+	decoded := &OrgExport{}
+	assert.NoError(t, json.Unmarshal(data, decoded))
+	assert.Equal(t, export.Detectors[0].Id, decoded.Detectors[0].Id)
+}
+
+func TestExportOrgSelectedTypes(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/team", verifyRequest(t, "GET", http.StatusOK, nil, "team/search_success.json"))
+
+	export, err := client.ExportOrg(context.Background(), &ExportOptions{Teams: true})
+	assert.NoError(t, err, "Unexpected error exporting org")
+	assert.Len(t, export.Teams, 1)
+	assert.Nil(t, export.Detectors, "Unselected types should be skipped")
+}
+
+func TestExportOrgError(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/detector", verifyRequest(t, "GET", http.StatusForbidden, nil, ""))
+
+	export, err := client.ExportOrg(context.Background(), &ExportOptions{Detectors: true})
+	assert.Error(t, err, "Should get an error from a failed search")
+	assert.Nil(t, export, "Should get nil export")
+}
@@ -0,0 +1,392 @@
+package signalfx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/adampetrovic/signalfx-go/dashboard"
+	"github.com/adampetrovic/signalfx-go/dashboard_group"
+	"github.com/adampetrovic/signalfx-go/detector"
+	"github.com/adampetrovic/signalfx-go/orgtoken"
+	"github.com/adampetrovic/signalfx-go/team"
+)
+
+// Resource types reported in an ImportResult.
+const (
+	ImportTypeTeam           = "team"
+	ImportTypeDashboardGroup = "dashboardgroup"
+	ImportTypeDashboard      = "dashboard"
+	ImportTypeDetector       = "detector"
+	ImportTypeOrgToken       = "orgtoken"
+)
+
+// ConflictMode says what ImportOrg does with a resource whose name is
+// already used by one of the same type. Dashboard names only conflict within
+// the group the dashboard is imported into.
+type ConflictMode int
+
+const (
+	// ConflictSkip leaves the existing resource alone.
+	ConflictSkip ConflictMode = iota
+	// ConflictOverwrite replaces the existing resource with the imported one.
+	ConflictOverwrite
+	// ConflictRename creates the imported resource under a new name.
+	ConflictRename
+)
+
+// ImportOptions controls ImportOrg. A nil *ImportOptions skips conflicts.
+type ImportOptions struct {
+	OnConflict ConflictMode
+	// Work out and report what would happen without changing anything
+	DryRun bool
+}
+
+// ImportedResource is the outcome of importing one resource.
+type ImportedResource struct {
+	Type string
+	Name string
+	// The resource's ID in the export
+	SourceID string
+	// The resource's ID after the import, empty if it failed. For a dry run
+	// of a create, this is the source ID.
+	ID string
+	// Why the import failed
+	Err error
+}
+
+// ImportResult lists what ImportOrg did with each resource.
+type ImportResult struct {
+	Created []*ImportedResource
+	Updated []*ImportedResource
+	Skipped []*ImportedResource
+	Failed  []*ImportedResource
+}
+
+// ImportOrg restores an OrgExport. Resources are imported in dependency
+// order, teams, then dashboard groups, dashboards, detectors and org tokens,
+// and the IDs they refer to are rewritten to the IDs of the imported
+// resources. A dashboard whose group failed to import fails too.
+//
+// Charts aren't part of an export, so dashboards keep their chart IDs and
+// only restore fully into the org they came from. The same goes for team
+// IDs inside detector notifications.
+//
+// Failures of individual resources are reported in the result; the error is
+// only set if the existing resources couldn't be listed.
+func (c *Client) ImportOrg(ctx context.Context, export *OrgExport, opts *ImportOptions) (*ImportResult, error) {
+	if opts == nil {
+		opts = &ImportOptions{}
+	}
+
+	existing, err := c.ExportOrg(ctx, &ExportOptions{
+		Teams:           len(export.Teams) > 0,
+		DashboardGroups: len(export.DashboardGroups) > 0,
+		Dashboards:      len(export.Dashboards) > 0,
+		Detectors:       len(export.Detectors) > 0,
+		Tokens:          len(export.Tokens) > 0,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	imp := &orgImporter{
+		client: c,
+		ctx:    ctx,
+		opts:   opts,
+		result: &ImportResult{},
+		ids:    map[string]map[string]string{},
+		failed: map[string]map[string]bool{},
+		names:  map[string]map[string]string{},
+	}
+	for _, t := range existing.Teams {
+		imp.addExisting(ImportTypeTeam, "", t.Name, t.Id)
+	}
+	for _, g := range existing.DashboardGroups {
+		imp.addExisting(ImportTypeDashboardGroup, "", g.Name, g.Id)
+	}
+	for _, d := range existing.Dashboards {
+		imp.addExisting(ImportTypeDashboard, d.GroupId, d.Name, d.Id)
+	}
+	for _, d := range existing.Detectors {
+		imp.addExisting(ImportTypeDetector, "", d.Name, d.Id)
+	}
+	for _, t := range existing.Tokens {
+		imp.addExisting(ImportTypeOrgToken, "", t.Name, t.Name)
+	}
+
+	for _, t := range export.Teams {
+		imp.importTeam(t)
+	}
+	for _, g := range export.DashboardGroups {
+		imp.importDashboardGroup(g)
+	}
+	for _, d := range export.Dashboards {
+		imp.importDashboard(d)
+	}
+	for _, d := range export.Detectors {
+		imp.importDetector(d)
+	}
+	for _, t := range export.Tokens {
+		imp.importOrgToken(t)
+	}
+
+	return imp.result, nil
+}
+
+type orgImporter struct {
+	client *Client
+	ctx    context.Context
+	opts   *ImportOptions
+	result *ImportResult
+	// Source ID to imported ID, by type
+	ids map[string]map[string]string
+	// Source IDs that failed to import, by type
+	failed map[string]map[string]bool
+	// Existing names to IDs, by type, keyed by nameKey
+	names map[string]map[string]string
+}
+
+// nameKey is the key of a name in orgImporter.names. Names only conflict
+// with names in the same scope, which for dashboards is their group's ID and
+// for everything else is empty.
+func nameKey(scope, name string) string {
+	return scope + "\x00" + name
+}
+
+func (imp *orgImporter) addExisting(typ, scope, name, id string) {
+	if imp.names[typ] == nil {
+		imp.names[typ] = map[string]string{}
+	}
+	imp.names[typ][nameKey(scope, name)] = id
+}
+
+// importOne imports a single resource into scope, calling create or update as
+// the conflict mode requires. Both return the resulting ID.
+func (imp *orgImporter) importOne(typ, scope, sourceID, name string, create func(name string) (string, error), update func(id string) (string, error)) {
+	res := &ImportedResource{Type: typ, Name: name, SourceID: sourceID}
+	existingID, conflict := imp.names[typ][nameKey(scope, name)]
+
+	var err error
+	switch {
+	case conflict && imp.opts.OnConflict == ConflictSkip:
+		res.ID = existingID
+		imp.result.Skipped = append(imp.result.Skipped, res)
+		imp.mapID(typ, sourceID, res.ID)
+		return
+	case conflict && imp.opts.OnConflict == ConflictOverwrite:
+		res.ID = existingID
+		if !imp.opts.DryRun {
+			res.ID, err = update(existingID)
+		}
+		if err == nil {
+			imp.result.Updated = append(imp.result.Updated, res)
+		}
+	default:
+		if conflict {
+			res.Name = imp.uniqueName(typ, scope, name)
+		}
+		res.ID = sourceID
+		if !imp.opts.DryRun {
+			res.ID, err = create(res.Name)
+		}
+		if err == nil {
+			imp.addExisting(typ, scope, res.Name, res.ID)
+			imp.result.Created = append(imp.result.Created, res)
+		}
+	}
+
+	if err != nil {
+		res.ID = ""
+		res.Err = err
+		imp.result.Failed = append(imp.result.Failed, res)
+		if imp.failed[typ] == nil {
+			imp.failed[typ] = map[string]bool{}
+		}
+		imp.failed[typ][sourceID] = true
+		return
+	}
+	imp.mapID(typ, sourceID, res.ID)
+}
+
+func (imp *orgImporter) fail(typ, sourceID, name string, err error) {
+	imp.result.Failed = append(imp.result.Failed, &ImportedResource{Type: typ, Name: name, SourceID: sourceID, Err: err})
+}
+
+func (imp *orgImporter) mapID(typ, sourceID, id string) {
+	if imp.ids[typ] == nil {
+		imp.ids[typ] = map[string]string{}
+	}
+	imp.ids[typ][sourceID] = id
+}
+
+// uniqueName returns name with a suffix that no existing resource of typ in
+// scope uses.
+func (imp *orgImporter) uniqueName(typ, scope, name string) string {
+	candidate := name + " (imported)"
+	for i := 2; ; i++ {
+		if _, taken := imp.names[typ][nameKey(scope, candidate)]; !taken {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s (imported %d)", name, i)
+	}
+}
+
+// remap rewrites source IDs of typ to their imported IDs. IDs that weren't
+// part of the export are kept as they are.
+func (imp *orgImporter) remap(typ string, ids []string) []string {
+	if ids == nil {
+		return nil
+	}
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		if newID, ok := imp.ids[typ][id]; ok {
+			out[i] = newID
+		} else {
+			out[i] = id
+		}
+	}
+	return out
+}
+
+func (imp *orgImporter) importTeam(t *team.Team) {
+	req := &team.CreateUpdateTeamRequest{
+		Description:       t.Description,
+		Members:           t.Members,
+		NotificationLists: t.NotificationLists,
+	}
+	imp.importOne(ImportTypeTeam, "", t.Id, t.Name, func(name string) (string, error) {
+		req.Name = name
+		created := &team.Team{}
+		err := imp.client.sendJSON(imp.ctx, "POST", TeamAPIURL, nil, req, created)
+		return created.Id, err
+	}, func(id string) (string, error) {
+		req.Name = t.Name
+		updated := &team.Team{}
+		err := imp.client.sendJSON(imp.ctx, "PUT", TeamAPIURL+"/"+id, nil, req, updated)
+		return updated.Id, err
+	})
+}
+
+func (imp *orgImporter) importDashboardGroup(g *dashboard_group.DashboardGroup) {
+	// Dashboards join the group as they're imported, so it starts empty
+	req := &dashboard_group.CreateUpdateDashboardGroupRequest{
+		AuthorizedWriters: g.AuthorizedWriters,
+		Description:       g.Description,
+		Teams:             imp.remap(ImportTypeTeam, g.Teams),
+	}
+	params := url.Values{}
+	params.Add("empty", "true")
+	imp.importOne(ImportTypeDashboardGroup, "", g.Id, g.Name, func(name string) (string, error) {
+		req.Name = name
+		created := &dashboard_group.DashboardGroup{}
+		err := imp.client.sendJSON(imp.ctx, "POST", DashboardGroupAPIURL, params, req, created)
+		return created.Id, err
+	}, func(id string) (string, error) {
+		// Keep the dashboards already in the group, since the PUT
+		// replaces its membership
+		existing, err := imp.client.getDashboardGroup(imp.ctx, id)
+		if err != nil {
+			return "", err
+		}
+		req.Name = g.Name
+		req.Dashboards = existing.Dashboards
+		req.DashboardConfigs = existing.DashboardConfigs
+		updated := &dashboard_group.DashboardGroup{}
+		err = imp.client.sendJSON(imp.ctx, "PUT", DashboardGroupAPIURL+"/"+id, nil, req, updated)
+		return updated.Id, err
+	})
+}
+
+func (imp *orgImporter) importDashboard(d *dashboard.Dashboard) {
+	if imp.failed[ImportTypeDashboardGroup][d.GroupId] {
+		imp.fail(ImportTypeDashboard, d.Id, d.Name, fmt.Errorf("dashboard group %s wasn't imported", d.GroupId))
+		return
+	}
+
+	req := d.UpdateRequest()
+	if groupID, ok := imp.ids[ImportTypeDashboardGroup][d.GroupId]; ok {
+		req.GroupId = groupID
+	}
+	imp.importOne(ImportTypeDashboard, req.GroupId, d.Id, d.Name, func(name string) (string, error) {
+		req.Name = name
+		created := &dashboard.Dashboard{}
+		err := imp.client.sendJSON(imp.ctx, "POST", DashboardAPIURL, nil, req, created)
+		return created.Id, err
+	}, func(id string) (string, error) {
+		req.Name = d.Name
+		updated := &dashboard.Dashboard{}
+		err := imp.client.sendJSON(imp.ctx, "PUT", DashboardAPIURL+"/"+id, nil, req, updated)
+		return updated.Id, err
+	})
+}
+
+func (imp *orgImporter) importDetector(d *detector.Detector) {
+	req, err := detector.CloneRequest(d, d.Name, nil)
+	if err != nil {
+		imp.fail(ImportTypeDetector, d.Id, d.Name, err)
+		return
+	}
+	req.Teams = imp.remap(ImportTypeTeam, d.Teams)
+	if d.AuthorizedWriters != nil {
+		req.AuthorizedWriters = &detector.AuthorizedWriters{
+			Teams: imp.remap(ImportTypeTeam, d.AuthorizedWriters.Teams),
+			Users: d.AuthorizedWriters.Users,
+		}
+	}
+
+	imp.importOne(ImportTypeDetector, "", d.Id, d.Name, func(name string) (string, error) {
+		req.Name = name
+		created := &detector.Detector{}
+		err := imp.client.sendJSON(imp.ctx, "POST", DetectorAPIURL, nil, req, created)
+		return created.Id, err
+	}, func(id string) (string, error) {
+		req.Name = d.Name
+		updated := &detector.Detector{}
+		err := imp.client.sendJSON(imp.ctx, "PUT", DetectorAPIURL+"/"+id, nil, req, updated)
+		return updated.Id, err
+	})
+}
+
+func (imp *orgImporter) importOrgToken(t *orgtoken.Token) {
+	req := &orgtoken.CreateUpdateTokenRequest{
+		Description:   t.Description,
+		Limits:        t.Limits,
+		Notifications: t.Notifications,
+		Disabled:      t.Disabled,
+	}
+	imp.importOne(ImportTypeOrgToken, "", t.Name, t.Name, func(name string) (string, error) {
+		req.Name = name
+		created := &orgtoken.Token{}
+		err := imp.client.sendJSON(imp.ctx, "POST", TokenAPIURL, nil, req, created)
+		return created.Name, err
+	}, func(name string) (string, error) {
+		req.Name = name
+		updated := &orgtoken.Token{}
+		err := imp.client.sendJSON(imp.ctx, "PUT", TokenAPIURL+"/"+url.PathEscape(name), nil, req, updated)
+		return updated.Name, err
+	})
+}
+
+// sendJSON sends in as the body of a request to path and decodes the
+// response into out.
+func (c *Client) sendJSON(ctx context.Context, method string, path string, params url.Values, in interface{}, out interface{}) error {
+	payload, err := c.marshal(in)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.doRequestWithContext(ctx, method, path, params, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(resp)
+	}
+
+	return c.decodeJSON(resp.Body, out)
+}
@@ -0,0 +1,261 @@
+package signalfx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/adampetrovic/signalfx-go/dashboard"
+	"github.com/adampetrovic/signalfx-go/dashboard_group"
+	"github.com/adampetrovic/signalfx-go/detector"
+	"github.com/adampetrovic/signalfx-go/team"
+	"github.com/stretchr/testify/assert"
+)
+
+func testImportExport() *OrgExport {
+	return &OrgExport{
+		Teams:           []*team.Team{{Id: "T1", Name: "SRE"}},
+		DashboardGroups: []*dashboard_group.DashboardGroup{{Id: "G1", Name: "Hosts", Teams: []string{"T1"}}},
+		Dashboards:      []*dashboard.Dashboard{{Id: "D1", Name: "CPU", GroupId: "G1"}},
+	}
+}
+
+func TestImportOrg(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	var groupTeams []string
+	var dashboardGroup string
+	mux.HandleFunc("/v2/team", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Write([]byte(`{"count":0,"results":[]}`))
+			return
+		}
+		w.Write([]byte(`{"id":"NT1","name":"SRE"}`))
+	})
+	mux.HandleFunc("/v2/dashboardgroup", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Write([]byte(`{"count":0,"results":[]}`))
+			return
+		}
+		assert.Equal(t, "true", r.URL.Query().Get("empty"), "Groups should be created empty")
+		req := &dashboard_group.CreateUpdateDashboardGroupRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(req))
+		groupTeams = req.Teams
+		w.Write([]byte(`{"id":"NG1","name":"Hosts"}`))
+	})
+	mux.HandleFunc("/v2/dashboard", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Write([]byte(`{"count":0,"results":[]}`))
+			return
+		}
+		req := &dashboard.CreateUpdateDashboardRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(req))
+		dashboardGroup = req.GroupId
+		w.Write([]byte(`{"id":"ND1","name":"CPU"}`))
+	})
+
+	result, err := client.ImportOrg(context.Background(), testImportExport(), nil)
+	assert.NoError(t, err, "Unexpected error importing org")
+	assert.Len(t, result.Created, 3)
+	assert.Empty(t, result.Failed)
+	assert.Equal(t, []string{"NT1"}, groupTeams, "Team IDs should be remapped")
+	assert.Equal(t, "NG1", dashboardGroup, "Group ID should be remapped")
+}
+
+func TestImportOrgConflicts(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	var created []string
+	mux.HandleFunc("/v2/team", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Write([]byte(`{"count":1,"results":[{"id":"ET1","name":"SRE"}]}`))
+			return
+		}
+		created = append(created, r.URL.Path)
+		w.Write([]byte(`{"id":"NT1","name":"SRE (imported)"}`))
+	})
+	mux.HandleFunc("/v2/dashboardgroup", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Write([]byte(`{"count":1,"results":[{"id":"EG1","name":"Hosts"}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	mux.HandleFunc("/v2/dashboard", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Write([]byte(`{"count":0,"results":[]}`))
+			return
+		}
+		created = append(created, r.URL.Path)
+		w.Write([]byte(`{"id":"ND1","name":"CPU"}`))
+	})
+
+	result, err := client.ImportOrg(context.Background(), testImportExport(), &ImportOptions{OnConflict: ConflictSkip})
+	assert.NoError(t, err, "Unexpected error importing org")
+	if assert.Len(t, result.Skipped, 2) {
+		assert.Equal(t, "ET1", result.Skipped[0].ID)
+	}
+	assert.Len(t, result.Created, 1)
+	assert.Equal(t, []string{"/v2/dashboard"}, created, "Only the dashboard should be created")
+
+	result, err = client.ImportOrg(context.Background(), testImportExport(), &ImportOptions{OnConflict: ConflictRename})
+	assert.NoError(t, err, "Unexpected error importing org")
+	if assert.Len(t, result.Created, 1) {
+		assert.Equal(t, "SRE (imported)", result.Created[0].Name)
+	}
+	if assert.Len(t, result.Failed, 2) {
+		assert.Equal(t, ImportTypeDashboardGroup, result.Failed[0].Type)
+		assert.Equal(t, "Hosts (imported)", result.Failed[0].Name)
+		assert.Error(t, result.Failed[0].Err)
+		assert.Equal(t, ImportTypeDashboard, result.Failed[1].Type, "Dashboard should fail with its group")
+	}
+}
+
+func TestImportOrgDryRun(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	for _, path := range []string{"/v2/team", "/v2/dashboardgroup", "/v2/dashboard"} {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != "GET" {
+				t.Errorf("Dry run shouldn't %s", r.Method)
+			}
+			w.Write([]byte(`{"count":1,"results":[{"id":"E1","name":"SRE"}]}`))
+		})
+	}
+
+	result, err := client.ImportOrg(context.Background(), testImportExport(), &ImportOptions{OnConflict: ConflictOverwrite, DryRun: true})
+	assert.NoError(t, err, "Unexpected error importing org")
+	assert.Len(t, result.Updated, 1)
+	assert.Len(t, result.Created, 2)
+}
+
+func TestImportOrgDashboardNamesConflictWithinGroup(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	var existingDashboards string
+	var created, updated []string
+	mux.HandleFunc("/v2/dashboardgroup", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Write([]byte(`{"count":0,"results":[]}`))
+			return
+		}
+		req := &dashboard_group.CreateUpdateDashboardGroupRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(req))
+		w.Write([]byte(`{"id":"N` + req.Name + `","name":"` + req.Name + `"}`))
+	})
+	mux.HandleFunc("/v2/dashboard", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Write([]byte(existingDashboards))
+			return
+		}
+		req := &dashboard.CreateUpdateDashboardRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(req))
+		created = append(created, req.GroupId+"/"+req.Name)
+		w.Write([]byte(`{"id":"ND` + req.GroupId + `","name":"` + req.Name + `"}`))
+	})
+	mux.HandleFunc("/v2/dashboard/ED1", func(w http.ResponseWriter, r *http.Request) {
+		req := &dashboard.CreateUpdateDashboardRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(req))
+		updated = append(updated, req.GroupId+"/"+req.Name)
+		w.Write([]byte(`{"id":"ED1","name":"` + req.Name + `"}`))
+	})
+
+	export := &OrgExport{
+		DashboardGroups: []*dashboard_group.DashboardGroup{{Id: "G1", Name: "A"}, {Id: "G2", Name: "B"}},
+		Dashboards: []*dashboard.Dashboard{
+			{Id: "D1", Name: "Overview", GroupId: "G1"},
+			{Id: "D2", Name: "Overview", GroupId: "G2"},
+		},
+	}
+
+	existingDashboards = `{"count":0,"results":[]}`
+	result, err := client.ImportOrg(context.Background(), export, nil)
+	assert.NoError(t, err, "Unexpected error importing org")
+	assert.Len(t, result.Created, 4)
+	assert.Empty(t, result.Skipped)
+	assert.Equal(t, []string{"NA/Overview", "NB/Overview"}, created, "Both dashboards should be created")
+
+	// Only the dashboard imported into the group that already has one of
+	// the same name conflicts
+	created = nil
+	existingDashboards = `{"count":1,"results":[{"id":"ED1","name":"Overview","groupId":"NA"}]}`
+	result, err = client.ImportOrg(context.Background(), export, &ImportOptions{OnConflict: ConflictOverwrite})
+	assert.NoError(t, err, "Unexpected error importing org")
+	assert.Empty(t, result.Failed)
+	assert.Equal(t, []string{"NA/Overview"}, updated)
+	assert.Equal(t, []string{"NB/Overview"}, created)
+}
+
+func TestImportOrgOverwriteKeepsGroupDashboards(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/dashboardgroup", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"count":1,"results":[{"id":"EG1","name":"Hosts"}]}`))
+	})
+	var updated dashboard_group.CreateUpdateDashboardGroupRequest
+	mux.HandleFunc("/v2/dashboardgroup/EG1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Write([]byte(`{"id":"EG1","name":"Hosts","dashboards":["ED1","ED2"]}`))
+			return
+		}
+		assert.Equal(t, "PUT", r.Method)
+		json.NewDecoder(r.Body).Decode(&updated)
+		w.Write([]byte(`{"id":"EG1","name":"Hosts","dashboards":["ED1","ED2"]}`))
+	})
+
+	export := &OrgExport{DashboardGroups: []*dashboard_group.DashboardGroup{{Id: "G1", Name: "Hosts", Description: "new"}}}
+	result, err := client.ImportOrg(context.Background(), export, &ImportOptions{OnConflict: ConflictOverwrite})
+	assert.NoError(t, err, "Unexpected error importing org")
+	assert.Len(t, result.Updated, 1)
+	assert.Empty(t, result.Failed)
+	assert.Equal(t, "new", updated.Description)
+	assert.Equal(t, []string{"ED1", "ED2"}, updated.Dashboards, "Existing dashboards should stay in the group")
+}
+
+func TestImportOrgDetectorKeepsAuthorizedWriters(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/team", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Write([]byte(`{"count":0,"results":[]}`))
+			return
+		}
+		w.Write([]byte(`{"id":"NT1","name":"SRE"}`))
+	})
+	var created detector.CreateUpdateDetectorRequest
+	mux.HandleFunc("/v2/detector", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Write([]byte(`{"count":0,"results":[]}`))
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&created)
+		w.Write([]byte(`{"id":"NDT1","name":"CPU"}`))
+	})
+
+	var props interface{} = "owned by sre"
+	export := &OrgExport{
+		Teams: []*team.Team{{Id: "T1", Name: "SRE"}},
+		Detectors: []*detector.Detector{{
+			Id:                "DT1",
+			Name:              "CPU",
+			ProgramText:       "detect(when(data('cpu') > 90)).publish('high')",
+			AuthorizedWriters: &detector.AuthorizedWriters{Teams: []string{"T1"}, Users: []string{"U1"}},
+			CustomProperties:  &props,
+		}},
+	}
+	result, err := client.ImportOrg(context.Background(), export, nil)
+	assert.NoError(t, err, "Unexpected error importing org")
+	assert.Empty(t, result.Failed)
+	if assert.NotNil(t, created.AuthorizedWriters, "Write restrictions should survive the import") {
+		assert.Equal(t, []string{"NT1"}, created.AuthorizedWriters.Teams, "Team IDs should be remapped")
+		assert.Equal(t, []string{"U1"}, created.AuthorizedWriters.Users)
+	}
+	assert.Equal(t, "owned by sre", created.CustomProperties)
+}
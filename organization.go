@@ -2,9 +2,7 @@ package signalfx
 
 import (
 	"bytes"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
+	"context"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -26,33 +24,35 @@ func (c *Client) GetOrganization(id string) (*organization.Organization, error)
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Bad status %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalOrganization := &organization.Organization{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalOrganization)
+	err = c.decodeJSON(resp.Body, finalOrganization)
 
 	return finalOrganization, err
 }
 
 // GetMember gets a member.
 func (c *Client) GetMember(id string) (*organization.Member, error) {
-	resp, err := c.doRequest("GET", OrganizationMemberAPIURL+"/"+id, nil, nil)
+	return c.getMember(c.ctx, id)
+}
+
+func (c *Client) getMember(ctx context.Context, id string) (*organization.Member, error) {
+	resp, err := c.doRequestWithContext(ctx, "GET", OrganizationMemberAPIURL+"/"+id, nil, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Bad status %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalMember := &organization.Member{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalMember)
+	err = c.decodeJSON(resp.Body, finalMember)
 
 	return finalMember, err
 }
@@ -67,8 +67,7 @@ func (c *Client) DeleteMember(id string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return newAPIError(resp)
 	}
 
 	return nil
@@ -76,7 +75,7 @@ func (c *Client) DeleteMember(id string) error {
 
 // InviteMember invites a member to the organization.
 func (c *Client) InviteMember(inviteRequest *organization.CreateUpdateMemberRequest) (*organization.Member, error) {
-	payload, err := json.Marshal(inviteRequest)
+	payload, err := c.marshal(inviteRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -88,20 +87,19 @@ func (c *Client) InviteMember(inviteRequest *organization.CreateUpdateMemberRequ
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Bad status %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalMember := &organization.Member{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalMember)
+	err = c.decodeJSON(resp.Body, finalMember)
 
 	return finalMember, err
 }
 
 // InviteMembers invites many members to the organization.
 func (c *Client) InviteMembers(inviteRequest *organization.InviteMembersRequest) (*organization.InviteMembersRequest, error) {
-	payload, err := json.Marshal(inviteRequest)
+	payload, err := c.marshal(inviteRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -113,13 +111,12 @@ func (c *Client) InviteMembers(inviteRequest *organization.InviteMembersRequest)
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Bad status %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalMembers := &organization.InviteMembersRequest{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalMembers)
+	err = c.decodeJSON(resp.Body, finalMembers)
 
 	return finalMembers, err
 }
@@ -141,7 +138,7 @@ func (c *Client) GetOrganizationMembers(limit int, query string, offset int, ord
 
 	finalMembers := &organization.MemberSearchResults{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalMembers)
+	err = c.decodeJSON(resp.Body, finalMembers)
 
 	return finalMembers, err
 }
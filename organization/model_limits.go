@@ -0,0 +1,16 @@
+package organization
+
+// Limits are the organization-wide usage limits, which apply on top of any
+// per-token limits.  A zero value means there is no limit.
+type Limits struct {
+	// Maximum number of active metric time series
+	MaxMTS int64 `json:"maxMTS,omitempty"`
+	// Maximum datapoints received per minute
+	MaxDPM int64 `json:"maxDPM,omitempty"`
+	// Maximum number of hosts reporting
+	MaxHosts int64 `json:"maxHosts,omitempty"`
+	// Maximum number of containers reporting
+	MaxContainers int64 `json:"maxContainers,omitempty"`
+	// Maximum number of custom metrics
+	MaxCustomMetrics int64 `json:"maxCustomMetrics,omitempty"`
+}
@@ -0,0 +1,50 @@
+package organization
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Usage of the organization over a period of time, sampled at a fixed
+// granularity.
+type Usage struct {
+	// Number of active metric time series
+	ActiveMTSCount []TimestampedValue `json:"activeMTSCount,omitempty"`
+	// Datapoints received per minute
+	DPMRate []TimestampedValue `json:"dpmRate,omitempty"`
+	// Number of hosts reporting
+	ActiveHosts []TimestampedValue `json:"activeHosts,omitempty"`
+	// Number of containers reporting
+	ActiveContainers []TimestampedValue `json:"activeContainers,omitempty"`
+}
+
+// A single usage sample.
+type TimestampedValue struct {
+	Timestamp time.Time
+	Value     float64
+}
+
+type timestampedValueJSON struct {
+	Timestamp int64   `json:"timestamp"`
+	Value     float64 `json:"value"`
+}
+
+// MarshalJSON encodes the sample with its timestamp in Unix milliseconds, as
+// the API does.
+func (tv TimestampedValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(timestampedValueJSON{
+		Timestamp: tv.Timestamp.UnixNano() / int64(time.Millisecond),
+		Value:     tv.Value,
+	})
+}
+
+// UnmarshalJSON decodes a sample with its timestamp in Unix milliseconds.
+func (tv *TimestampedValue) UnmarshalJSON(data []byte) error {
+	var raw timestampedValueJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	tv.Timestamp = time.Unix(0, raw.Timestamp*int64(time.Millisecond)).UTC()
+	tv.Value = raw.Value
+	return nil
+}
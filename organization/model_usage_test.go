@@ -0,0 +1,21 @@
+package organization
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimestampedValueJSON(t *testing.T) {
+	tv := TimestampedValue{Timestamp: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), Value: 1.5}
+
+	data, err := json.Marshal(tv)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"timestamp": 1577934245000, "value": 1.5}`, string(data))
+
+	var decoded TimestampedValue
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, tv, decoded)
+}
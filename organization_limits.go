@@ -0,0 +1,42 @@
+package signalfx
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	"github.com/adampetrovic/signalfx-go/organization"
+)
+
+// OrganizationLimitsAPIURL is the URL for the organization's global limits.
+const OrganizationLimitsAPIURL = "/v2/organization/limits"
+
+// GetOrganizationLimits gets the organization-wide usage limits.
+func (c *Client) GetOrganizationLimits(ctx context.Context) (*organization.Limits, error) {
+	limits := &organization.Limits{}
+	if err := c.getJSON(ctx, OrganizationLimitsAPIURL, nil, limits); err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+// UpdateOrganizationLimits replaces the organization-wide usage limits.  Only
+// admins can change them; anyone else gets an *APIError that unwraps to
+// ErrForbidden.
+func (c *Client) UpdateOrganizationLimits(ctx context.Context, limits *organization.Limits) error {
+	payload, err := c.marshal(limits)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.doRequestWithContext(ctx, "PUT", OrganizationLimitsAPIURL, nil, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return newAPIError(resp)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package signalfx
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/adampetrovic/signalfx-go/organization"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOrganizationLimits(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/organization/limits", verifyRequest(t, "GET", http.StatusOK, nil, "organization/get_limits_success.json"))
+
+	result, err := client.GetOrganizationLimits(context.Background())
+	assert.NoError(t, err, "Unexpected error getting limits")
+	assert.Equal(t, int64(500000), result.MaxMTS)
+	assert.Equal(t, int64(2000000), result.MaxDPM)
+	assert.Equal(t, int64(10000), result.MaxCustomMetrics)
+}
+
+func TestUpdateOrganizationLimits(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/organization/limits", verifyRequest(t, "PUT", http.StatusOK, nil, "organization/get_limits_success.json"))
+
+	err := client.UpdateOrganizationLimits(context.Background(), &organization.Limits{MaxMTS: 500000})
+	assert.NoError(t, err, "Unexpected error updating limits")
+}
+
+func TestUpdateOrganizationLimitsForbidden(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/organization/limits", verifyRequest(t, "PUT", http.StatusForbidden, nil, ""))
+
+	err := client.UpdateOrganizationLimits(context.Background(), &organization.Limits{MaxMTS: 500000})
+	apiErr, ok := err.(*APIError)
+	if assert.True(t, ok, "Should get an *APIError") {
+		assert.Equal(t, ErrForbidden, apiErr.Unwrap())
+	}
+}
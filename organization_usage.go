@@ -0,0 +1,47 @@
+package signalfx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/organization"
+)
+
+// OrganizationUsageAPIURL is the URL for getting an organization's usage.
+const OrganizationUsageAPIURL = "/v2/organization/usage"
+
+// GetOrganizationUsage gets the organization's usage between startTime and
+// endTime, with one sample per granularity.
+func (c *Client) GetOrganizationUsage(ctx context.Context, startTime, endTime time.Time, granularity time.Duration) (*organization.Usage, error) {
+	if !endTime.After(startTime) {
+		return nil, errors.New("endTime must be after startTime")
+	}
+	if granularity < time.Millisecond {
+		return nil, errors.New("granularity must be at least a millisecond")
+	}
+
+	params := url.Values{}
+	params.Add("startTime", strconv.FormatInt(startTime.UnixNano()/int64(time.Millisecond), 10))
+	params.Add("endTime", strconv.FormatInt(endTime.UnixNano()/int64(time.Millisecond), 10))
+	params.Add("resolution", strconv.FormatInt(int64(granularity/time.Millisecond), 10))
+
+	resp, err := c.doRequestWithContext(ctx, "GET", OrganizationUsageAPIURL, params, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	finalUsage := &organization.Usage{}
+
+	err = c.decodeJSON(resp.Body, finalUsage)
+
+	return finalUsage, err
+}
@@ -0,0 +1,54 @@
+package signalfx
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOrganizationUsage(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	params := url.Values{}
+	params.Add("startTime", "1577836800000")
+	params.Add("endTime", "1577844000000")
+	params.Add("resolution", "3600000")
+	mux.HandleFunc("/v2/organization/usage", verifyRequest(t, "GET", http.StatusOK, params, "organization/get_usage_success.json"))
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	result, err := client.GetOrganizationUsage(context.Background(), start, start.Add(2*time.Hour), time.Hour)
+	assert.NoError(t, err, "Unexpected error getting usage")
+	assert.Len(t, result.ActiveMTSCount, 2)
+	assert.Equal(t, start.Add(time.Hour), result.DPMRate[1].Timestamp)
+	assert.Equal(t, float64(185000), result.DPMRate[1].Value)
+	assert.Equal(t, float64(320), result.ActiveContainers[1].Value)
+}
+
+func TestGetOrganizationUsageBadRange(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	start := time.Now()
+	_, err := client.GetOrganizationUsage(context.Background(), start, start.Add(-time.Hour), time.Minute)
+	assert.Error(t, err, "Should reject an end before the start")
+
+	_, err = client.GetOrganizationUsage(context.Background(), start, start.Add(time.Hour), 0)
+	assert.Error(t, err, "Should reject a zero granularity")
+}
+
+func TestGetOrganizationUsageBadStatus(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/organization/usage", verifyRequest(t, "GET", http.StatusForbidden, nil, ""))
+
+	start := time.Now()
+	result, err := client.GetOrganizationUsage(context.Background(), start, start.Add(time.Hour), time.Minute)
+	assert.Error(t, err, "Should get an error from a bad status")
+	assert.Nil(t, result, "Should get nil result")
+}
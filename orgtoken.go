@@ -2,9 +2,6 @@ package signalfx
 
 import (
 	"bytes"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -17,7 +14,7 @@ const TokenAPIURL = "/v2/token"
 
 // CreateOrgToken creates a org token.
 func (c *Client) CreateOrgToken(tokenRequest *orgtoken.CreateUpdateTokenRequest) (*orgtoken.Token, error) {
-	payload, err := json.Marshal(tokenRequest)
+	payload, err := c.marshal(tokenRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -29,13 +26,12 @@ func (c *Client) CreateOrgToken(tokenRequest *orgtoken.CreateUpdateTokenRequest)
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Bad status %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalToken := &orgtoken.Token{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalToken)
+	err = c.decodeJSON(resp.Body, finalToken)
 
 	return finalToken, err
 }
@@ -51,8 +47,7 @@ func (c *Client) DeleteOrgToken(name string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return newAPIError(resp)
 	}
 
 	return nil
@@ -68,20 +63,19 @@ func (c *Client) GetOrgToken(id string) (*orgtoken.Token, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Bad status %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalToken := &orgtoken.Token{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalToken)
+	err = c.decodeJSON(resp.Body, finalToken)
 
 	return finalToken, err
 }
 
 // UpdateToken updates a token.
 func (c *Client) UpdateOrgToken(id string, tokenRequest *orgtoken.CreateUpdateTokenRequest) (*orgtoken.Token, error) {
-	payload, err := json.Marshal(tokenRequest)
+	payload, err := c.marshal(tokenRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -94,13 +88,12 @@ func (c *Client) UpdateOrgToken(id string, tokenRequest *orgtoken.CreateUpdateTo
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Bad status %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalToken := &orgtoken.Token{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalToken)
+	err = c.decodeJSON(resp.Body, finalToken)
 
 	return finalToken, err
 }
@@ -121,7 +114,7 @@ func (c *Client) SearchOrgTokens(limit int, name string, offset int) (*orgtoken.
 
 	finalTokens := &orgtoken.SearchResults{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalTokens)
+	err = c.decodeJSON(resp.Body, finalTokens)
 
 	return finalTokens, err
 }
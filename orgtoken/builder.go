@@ -0,0 +1,110 @@
+package orgtoken
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/adampetrovic/signalfx-go/notification"
+)
+
+// WithDPMLimit limits the token to dpm datapoints per minute, for
+// organizations with DPM pricing. threshold is the fraction of the limit at
+// which a notification is sent, e.g. 0.9; zero sends no notification.
+func (r *CreateUpdateTokenRequest) WithDPMLimit(dpm int, threshold float64) *CreateUpdateTokenRequest {
+	if r.Limits == nil {
+		r.Limits = &Limit{}
+	}
+	quota := int32(dpm)
+	r.Limits.DpmQuota = &quota
+	r.Limits.DpmNotificationThreshold = nil
+	if threshold != 0 {
+		level := int32(float64(dpm) * threshold)
+		r.Limits.DpmNotificationThreshold = &level
+	}
+	return r
+}
+
+// WithHostLimit limits how many hosts, containers, custom metrics and
+// high-resolution metrics the token may be used for, for organizations with
+// host-based pricing. A zero leaves that category unlimited.
+func (r *CreateUpdateTokenRequest) WithHostLimit(hosts, containers, customMetrics, hiResMetrics int) *CreateUpdateTokenRequest {
+	if r.Limits == nil {
+		r.Limits = &Limit{}
+	}
+	r.Limits.CategoryQuota = &UsageLimits{
+		HostThreshold:          optionalLimit(hosts),
+		ContainerThreshold:     optionalLimit(containers),
+		CustomMetricThreshold:  optionalLimit(customMetrics),
+		HighResMetricThreshold: optionalLimit(hiResMetrics),
+	}
+	return r
+}
+
+func optionalLimit(n int) *int64 {
+	if n == 0 {
+		return nil
+	}
+	v := int64(n)
+	return &v
+}
+
+// WithNotification adds a notification sent when the token nears one of its
+// limits.
+func (r *CreateUpdateTokenRequest) WithNotification(n notification.Notification) *CreateUpdateTokenRequest {
+	r.Notifications = append(r.Notifications, &n)
+	return r
+}
+
+// Build validates the request and returns it, so a chain of With calls can
+// end in one error check.
+func (r *CreateUpdateTokenRequest) Build() (*CreateUpdateTokenRequest, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Validate checks that the request has a name and that its limits are
+// consistent. DPM and host-based limits belong to different pricing models,
+// so only one kind can be set.
+func (r *CreateUpdateTokenRequest) Validate() error {
+	if r.Name == "" {
+		return errors.New("name must be set")
+	}
+	if r.Limits == nil {
+		return nil
+	}
+
+	l := r.Limits
+	if l.DpmQuota != nil && l.CategoryQuota != nil {
+		return errors.New("DPM and host-based limits can't both be set")
+	}
+	if l.DpmQuota != nil && *l.DpmQuota <= 0 {
+		return fmt.Errorf("DPM limit must be positive, got %d", *l.DpmQuota)
+	}
+	if t := l.DpmNotificationThreshold; t != nil {
+		if l.DpmQuota == nil {
+			return errors.New("DPM notification threshold needs a DPM limit")
+		}
+		if *t <= 0 || *t > *l.DpmQuota {
+			return fmt.Errorf("DPM notification threshold must be between 0 and the limit of %d, got %d", *l.DpmQuota, *t)
+		}
+	}
+	if q := l.CategoryQuota; q != nil {
+		limits := []struct {
+			name  string
+			value *int64
+		}{
+			{"host", q.HostThreshold},
+			{"container", q.ContainerThreshold},
+			{"custom metric", q.CustomMetricThreshold},
+			{"high resolution metric", q.HighResMetricThreshold},
+		}
+		for _, limit := range limits {
+			if limit.value != nil && *limit.value < 0 {
+				return fmt.Errorf("%s limit cannot be negative, got %d", limit.name, *limit.value)
+			}
+		}
+	}
+	return nil
+}
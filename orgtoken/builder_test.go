@@ -0,0 +1,46 @@
+package orgtoken
+
+import (
+	"testing"
+
+	"github.com/adampetrovic/signalfx-go/notification"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenRequestBuilders(t *testing.T) {
+	req, err := (&CreateUpdateTokenRequest{Name: "team-a"}).
+		WithDPMLimit(10000, 0.9).
+		WithNotification(notification.Notification{Type: "Email", Value: &notification.EmailNotification{Type: "Email", Email: "a@example.com"}}).
+		Build()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(10000), *req.Limits.DpmQuota)
+	assert.Equal(t, int32(9000), *req.Limits.DpmNotificationThreshold)
+	assert.Len(t, req.Notifications, 1)
+
+	req, err = (&CreateUpdateTokenRequest{Name: "team-b"}).WithHostLimit(10, 0, 500, 0).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), *req.Limits.CategoryQuota.HostThreshold)
+	assert.Nil(t, req.Limits.CategoryQuota.ContainerThreshold, "Zero should leave the category unlimited")
+	assert.Equal(t, int64(500), *req.Limits.CategoryQuota.CustomMetricThreshold)
+}
+
+func TestTokenRequestValidate(t *testing.T) {
+	_, err := (&CreateUpdateTokenRequest{}).Build()
+	assert.Error(t, err, "Missing name should fail")
+
+	_, err = (&CreateUpdateTokenRequest{Name: "a"}).WithDPMLimit(100, 0.5).WithHostLimit(1, 1, 1, 1).Build()
+	assert.Error(t, err, "Mixed pricing models should fail")
+
+	_, err = (&CreateUpdateTokenRequest{Name: "a"}).WithDPMLimit(0, 0).Build()
+	assert.Error(t, err, "Zero DPM limit should fail")
+
+	_, err = (&CreateUpdateTokenRequest{Name: "a"}).WithDPMLimit(100, 1.5).Build()
+	assert.Error(t, err, "Threshold above the limit should fail")
+
+	_, err = (&CreateUpdateTokenRequest{Name: "a"}).WithHostLimit(-1, 0, 0, 0).Build()
+	assert.Error(t, err, "Negative limit should fail")
+
+	req, err := (&CreateUpdateTokenRequest{Name: "a"}).WithDPMLimit(100, 0).Build()
+	assert.NoError(t, err)
+	assert.Nil(t, req.Limits.DpmNotificationThreshold, "Zero threshold should send no notification")
+}
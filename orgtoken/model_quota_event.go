@@ -0,0 +1,52 @@
+package orgtoken
+
+// Kinds of quota a token can have
+const (
+	// Datapoints per minute, under DPM-based pricing
+	QuotaTypeDPM = "DPM"
+	// Hosts, under host-based pricing
+	QuotaTypeHosts = "HOSTS"
+	// Containers, under host-based pricing
+	QuotaTypeContainers = "CONTAINERS"
+)
+
+// Reports that a token's use of one of its quotas has risen to or above a
+// threshold, or fallen back below it.
+type QuotaEvent struct {
+	TokenName string `json:"tokenName"`
+	// One of QuotaTypeDPM, QuotaTypeHosts or QuotaTypeContainers
+	QuotaType string `json:"quotaType"`
+	// How much of the quota is in use, where 100 is all of it
+	UtilizationPercent float64 `json:"utilizationPercent"`
+	// The threshold that was crossed
+	ThresholdPercent float64 `json:"thresholdPercent"`
+}
+
+// Exceeded reports whether the event is for the threshold being reached
+// rather than cleared.
+func (e *QuotaEvent) Exceeded() bool {
+	return e.UtilizationPercent >= e.ThresholdPercent
+}
+
+// Utilization works out how much of each of the token's quotas usage uses,
+// as a percentage, keyed by quota type. minutes is how long the usage covers,
+// which is needed to turn datapoints into datapoints per minute. Quotas that
+// aren't set are left out.
+func Utilization(limits *Limit, usage *Usage, minutes float64) map[string]float64 {
+	out := map[string]float64{}
+	if limits == nil || usage == nil {
+		return out
+	}
+	if limits.DpmQuota != nil && *limits.DpmQuota > 0 && minutes > 0 {
+		out[QuotaTypeDPM] = float64(usage.DatapointsReceived) / minutes / float64(*limits.DpmQuota) * 100
+	}
+	if q := limits.CategoryQuota; q != nil {
+		if q.HostThreshold != nil && *q.HostThreshold > 0 {
+			out[QuotaTypeHosts] = float64(usage.HostsMonitored) / float64(*q.HostThreshold) * 100
+		}
+		if q.ContainerThreshold != nil && *q.ContainerThreshold > 0 {
+			out[QuotaTypeContainers] = float64(usage.ContainersMonitored) / float64(*q.ContainerThreshold) * 100
+		}
+	}
+	return out
+}
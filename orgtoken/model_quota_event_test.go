@@ -0,0 +1,18 @@
+package orgtoken
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUtilization(t *testing.T) {
+	dpm := int32(1000)
+	hosts := int64(0)
+	limits := &Limit{DpmQuota: &dpm, CategoryQuota: &UsageLimits{HostThreshold: &hosts}}
+
+	util := Utilization(limits, &Usage{DatapointsReceived: 4500, HostsMonitored: 3}, 5)
+	assert.Equal(t, map[string]float64{QuotaTypeDPM: 90}, util, "Unset and zero quotas should be left out")
+
+	assert.Empty(t, Utilization(nil, &Usage{}, 5))
+}
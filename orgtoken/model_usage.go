@@ -0,0 +1,13 @@
+package orgtoken
+
+// What was sent to SignalFx with a token over a period of time.
+type Usage struct {
+	// Number of datapoints received
+	DatapointsReceived int64 `json:"datapointsReceived,omitempty"`
+	// Number of new metric time series created
+	MTSCreated int64 `json:"mtsCreated,omitempty"`
+	// Number of distinct hosts reporting
+	HostsMonitored int64 `json:"hostsMonitored,omitempty"`
+	// Number of distinct containers reporting
+	ContainersMonitored int64 `json:"containersMonitored,omitempty"`
+}
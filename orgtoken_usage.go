@@ -0,0 +1,126 @@
+package signalfx
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/orgtoken"
+)
+
+// GetOrgTokenUsage gets what was sent with the named token between startTime
+// and endTime, e.g. to attribute costs to the team that owns the token.
+func (c *Client) GetOrgTokenUsage(ctx context.Context, tokenName string, startTime, endTime time.Time) (*orgtoken.Usage, error) {
+	if !endTime.After(startTime) {
+		return nil, errors.New("endTime must be after startTime")
+	}
+
+	params := url.Values{}
+	params.Add("startTime", strconv.FormatInt(startTime.UnixNano()/int64(time.Millisecond), 10))
+	params.Add("endTime", strconv.FormatInt(endTime.UnixNano()/int64(time.Millisecond), 10))
+
+	resp, err := c.doRequestWithContext(ctx, "GET", TokenAPIURL+"/"+url.PathEscape(tokenName)+"/usage", params, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	finalUsage := &orgtoken.Usage{}
+
+	err = c.decodeJSON(resp.Body, finalUsage)
+
+	return finalUsage, err
+}
+
+// WatchOrgTokenQuota checks the named token's usage every interval against
+// the DPM, host and container quotas configured on it, and sends a
+// QuotaEvent when utilization of one reaches thresholdPercent or falls back
+// below it.
+//
+// The first check is made before returning, so that an error such as a
+// missing token or one that can't be read is returned. Later checks that fail
+// are logged and skipped. The channel is closed once ctx is done.
+func (c *Client) WatchOrgTokenQuota(ctx context.Context, tokenName string, thresholdPercent float64, interval time.Duration) (<-chan orgtoken.QuotaEvent, error) {
+	if thresholdPercent <= 0 {
+		return nil, errors.New("thresholdPercent must be > 0")
+	}
+	if interval <= 0 {
+		return nil, errors.New("interval must be > 0")
+	}
+
+	utilization, err := c.orgTokenUtilization(ctx, tokenName, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan orgtoken.QuotaEvent)
+	go func() {
+		defer close(events)
+
+		exceeded := map[string]bool{}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			quotaTypes := make([]string, 0, len(utilization))
+			for qt := range utilization {
+				quotaTypes = append(quotaTypes, qt)
+			}
+			sort.Strings(quotaTypes)
+			for _, qt := range quotaTypes {
+				over := utilization[qt] >= thresholdPercent
+				if over == exceeded[qt] {
+					continue
+				}
+				exceeded[qt] = over
+				select {
+				case events <- orgtoken.QuotaEvent{
+					TokenName:          tokenName,
+					QuotaType:          qt,
+					UtilizationPercent: utilization[qt],
+					ThresholdPercent:   thresholdPercent,
+				}:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+
+			utilization, err = c.orgTokenUtilization(ctx, tokenName, interval)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Could not check quota of token %s: %v", tokenName, err)
+			}
+		}
+	}()
+	return events, nil
+}
+
+// orgTokenUtilization gets how much of each of a token's quotas it used over
+// the last period.
+func (c *Client) orgTokenUtilization(ctx context.Context, tokenName string, period time.Duration) (map[string]float64, error) {
+	token := &orgtoken.Token{}
+	if err := c.getJSON(ctx, TokenAPIURL+"/"+url.PathEscape(tokenName), nil, token); err != nil {
+		return nil, err
+	}
+
+	end := time.Now()
+	usage, err := c.GetOrgTokenUsage(ctx, tokenName, end.Add(-period), end)
+	if err != nil {
+		return nil, err
+	}
+
+	return orgtoken.Utilization(token.Limits, usage, period.Minutes()), nil
+}
@@ -0,0 +1,96 @@
+package signalfx
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/adampetrovic/signalfx-go/orgtoken"
+)
+
+func TestGetOrgTokenUsage(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	params := url.Values{}
+	params.Add("startTime", "1577836800000")
+	params.Add("endTime", "1577923200000")
+	mux.HandleFunc("/v2/token/team-a/usage", verifyRequest(t, "GET", http.StatusOK, params, "orgtoken/usage_success.json"))
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	result, err := client.GetOrgTokenUsage(context.Background(), "team-a", start, start.Add(24*time.Hour))
+	assert.NoError(t, err, "Unexpected error getting token usage")
+	assert.Equal(t, int64(1250000), result.DatapointsReceived)
+	assert.Equal(t, int64(85), result.ContainersMonitored)
+}
+
+func TestGetOrgTokenUsageErrors(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/token/missing/usage", verifyRequest(t, "GET", http.StatusNotFound, nil, ""))
+
+	start := time.Now()
+	_, err := client.GetOrgTokenUsage(context.Background(), "missing", start, start)
+	assert.Error(t, err, "Should reject an empty range")
+
+	result, err := client.GetOrgTokenUsage(context.Background(), "missing", start, start.Add(time.Hour))
+	assert.Error(t, err, "Should get an error for a missing token")
+	assert.Nil(t, result, "Should get nil result")
+}
+
+func TestWatchOrgTokenQuota(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/token/team-a", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name": "team-a", "limits": {"categoryQuota": {"1": 10, "2": 100}}}`))
+	})
+	var polls int32
+	mux.HandleFunc("/v2/token/team-a/usage", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&polls, 1) == 1 {
+			w.Write([]byte(`{"hostsMonitored": 12, "containersMonitored": 50}`))
+			return
+		}
+		w.Write([]byte(`{"hostsMonitored": 5, "containersMonitored": 50}`))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := client.WatchOrgTokenQuota(ctx, "team-a", 80, time.Millisecond)
+	assert.NoError(t, err, "Unexpected error watching quota")
+
+	ev := <-events
+	assert.Equal(t, orgtoken.QuotaEvent{TokenName: "team-a", QuotaType: orgtoken.QuotaTypeHosts, UtilizationPercent: 120, ThresholdPercent: 80}, ev)
+	assert.True(t, ev.Exceeded())
+
+	ev = <-events
+	assert.Equal(t, orgtoken.QuotaTypeHosts, ev.QuotaType, "Should report the threshold being cleared")
+	assert.Equal(t, float64(50), ev.UtilizationPercent)
+	assert.False(t, ev.Exceeded())
+
+	cancel()
+	for range events {
+		assert.Fail(t, "Utilization under the threshold shouldn't be reported again")
+	}
+}
+
+func TestWatchOrgTokenQuotaErrors(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	_, err := client.WatchOrgTokenQuota(context.Background(), "team-a", 0, time.Minute)
+	assert.Error(t, err, "Should reject a zero threshold")
+
+	_, err = client.WatchOrgTokenQuota(context.Background(), "team-a", 80, 0)
+	assert.Error(t, err, "Should reject a zero interval")
+
+	mux.HandleFunc("/v2/token/missing", verifyRequest(t, "GET", http.StatusNotFound, nil, ""))
+	events, err := client.WatchOrgTokenQuota(context.Background(), "missing", 80, time.Minute)
+	assert.Error(t, err, "Should return the error from the first check")
+	assert.Nil(t, events)
+}
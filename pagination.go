@@ -0,0 +1,248 @@
+package signalfx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/detector"
+	"github.com/adampetrovic/signalfx-go/orgtoken"
+)
+
+// parseLinkHeader returns the URLs of the next and previous pages from an RFC
+// 8288 Link header, such as `<https://api/v2/detector?cursor=x>; rel="next"`.
+// Either is empty if the header doesn't have that relation.
+func parseLinkHeader(header string) (next, prev string, err error) {
+	rest := strings.TrimSpace(header)
+	for rest != "" {
+		if rest[0] != '<' {
+			return "", "", fmt.Errorf("malformed Link header %q", header)
+		}
+		end := strings.IndexByte(rest, '>')
+		if end < 0 {
+			return "", "", fmt.Errorf("malformed Link header %q", header)
+		}
+		target := rest[1:end]
+		rest = rest[end+1:]
+
+		// The link's parameters run until the comma that starts the next link
+		var params string
+		if comma := strings.IndexByte(rest, ','); comma >= 0 {
+			params, rest = rest[:comma], strings.TrimSpace(rest[comma+1:])
+		} else {
+			params, rest = rest, ""
+		}
+		for _, param := range strings.Split(params, ";") {
+			kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+			if len(kv) != 2 || !strings.EqualFold(strings.TrimSpace(kv[0]), "rel") {
+				continue
+			}
+			for _, rel := range strings.Fields(strings.Trim(strings.TrimSpace(kv[1]), `"`)) {
+				switch strings.ToLower(rel) {
+				case "next":
+					next = target
+				case "prev", "previous":
+					prev = target
+				}
+			}
+		}
+	}
+	return next, prev, nil
+}
+
+// pager fetches consecutive pages of a search endpoint.  It follows the
+// server's Link header if it sends one and counts offsets otherwise.  Page
+// sizes adapt and rate-limited requests are retried as for ThrottledSearch.
+type pager struct {
+	client   *Client
+	ctx      context.Context
+	apiURL   string
+	extra    url.Values
+	throttle *throttler
+
+	offset int
+	next   string
+	done   bool
+}
+
+func (c *Client) newPager(ctx context.Context, apiURL string, extra url.Values) *pager {
+	return &pager{
+		client:   c,
+		ctx:      ctx,
+		apiURL:   apiURL,
+		extra:    extra,
+		throttle: newThrottler(searchPageSize, searchPageSize),
+	}
+}
+
+// fetch decodes the next page into v.  count is called once it's decoded and
+// returns how many results the page had.  A relative next link, such as
+// `<?cursor=x>`, is resolved against the URL of the page it came from.  The
+// host of a next link is ignored, since pages are always fetched from the
+// client's API URL.
+func (p *pager) fetch(v interface{}, count func() int) error {
+	for {
+		limit := p.throttle.pageSize
+		start := time.Now()
+		err := p.fetchPage(v, count, limit)
+		if err == nil {
+			p.throttle.succeeded(time.Since(start))
+			return nil
+		}
+		if err := p.throttle.backOff(p.ctx, err); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *pager) fetchPage(v interface{}, count func() int, limit int) error {
+	path := p.apiURL
+	params := url.Values{}
+	if p.next != "" {
+		u, err := url.Parse(p.next)
+		if err != nil {
+			return err
+		}
+		path, params = u.Path, u.Query()
+	} else {
+		for k, vs := range p.extra {
+			params[k] = vs
+		}
+		params.Add("limit", strconv.Itoa(limit))
+		params.Add("offset", strconv.Itoa(p.offset))
+	}
+
+	resp, err := p.client.doRequestWithContext(p.ctx, "GET", path, params, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(resp)
+	}
+	if err := p.client.decodeJSON(resp.Body, v); err != nil {
+		return err
+	}
+	n := count()
+
+	if link := resp.Header.Get("Link"); link != "" {
+		next, _, err := parseLinkHeader(link)
+		if err != nil {
+			return err
+		}
+		if next != "" && resp.Request != nil {
+			ref, err := url.Parse(next)
+			if err != nil {
+				return err
+			}
+			next = resp.Request.URL.ResolveReference(ref).String()
+		}
+		p.next = next
+		p.done = next == ""
+		return nil
+	}
+	p.next = ""
+	p.offset += n
+	p.done = n < limit
+	return nil
+}
+
+// DetectorCursor pages through the results of a detector search, following
+// the server's pagination links when it sends them. Call Next until it returns
+// false, then check Err.
+type DetectorCursor struct {
+	pager   *pager
+	page    []detector.Detector
+	current *detector.Detector
+	err     error
+}
+
+// SearchDetectorsCursor returns a cursor over every detector matching name
+// and tags, either of which may be empty.
+func (c *Client) SearchDetectorsCursor(ctx context.Context, name string, tags string) *DetectorCursor {
+	params := url.Values{}
+	if name != "" {
+		params.Add("name", name)
+	}
+	if tags != "" {
+		params.Add("tags", tags)
+	}
+	return &DetectorCursor{pager: c.newPager(ctx, DetectorAPIURL, params)}
+}
+
+// Next advances to the next detector, fetching another page if needed. It
+// returns false when there are no more detectors or a request failed.
+func (it *DetectorCursor) Next() bool {
+	for len(it.page) == 0 {
+		if it.err != nil || it.pager.done {
+			return false
+		}
+		results := &detector.SearchResults{}
+		it.err = it.pager.fetch(results, func() int { return len(results.Results) })
+		it.page = results.Results
+	}
+	it.current = &it.page[0]
+	it.page = it.page[1:]
+	return true
+}
+
+// Detector returns the detector Next advanced to.
+func (it *DetectorCursor) Detector() *detector.Detector {
+	return it.current
+}
+
+// Err returns the error that stopped iteration, if any.
+func (it *DetectorCursor) Err() error {
+	return it.err
+}
+
+// OrgTokenCursor pages through the results of an org token search, following
+// the server's pagination links when it sends them. Call Next until it returns
+// false, then check Err.
+type OrgTokenCursor struct {
+	pager   *pager
+	page    []orgtoken.Token
+	current *orgtoken.Token
+	err     error
+}
+
+// SearchOrgTokensCursor returns a cursor over every org token matching name,
+// which may be empty.
+func (c *Client) SearchOrgTokensCursor(ctx context.Context, name string) *OrgTokenCursor {
+	params := url.Values{}
+	if name != "" {
+		params.Add("name", name)
+	}
+	return &OrgTokenCursor{pager: c.newPager(ctx, TokenAPIURL, params)}
+}
+
+// Next advances to the next token, fetching another page if needed. It
+// returns false when there are no more tokens or a request failed.
+func (it *OrgTokenCursor) Next() bool {
+	for len(it.page) == 0 {
+		if it.err != nil || it.pager.done {
+			return false
+		}
+		results := &orgtoken.SearchResults{}
+		it.err = it.pager.fetch(results, func() int { return len(results.Results) })
+		it.page = results.Results
+	}
+	it.current = &it.page[0]
+	it.page = it.page[1:]
+	return true
+}
+
+// Token returns the token Next advanced to.
+func (it *OrgTokenCursor) Token() *orgtoken.Token {
+	return it.current
+}
+
+// Err returns the error that stopped iteration, if any.
+func (it *OrgTokenCursor) Err() error {
+	return it.err
+}
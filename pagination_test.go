@@ -0,0 +1,123 @@
+package signalfx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLinkHeader(t *testing.T) {
+	cases := []struct {
+		header, next, prev string
+	}{
+		{``, "", ""},
+		{`</v2/detector?cursor=b>; rel="next"`, "/v2/detector?cursor=b", ""},
+		{`<https://api.example.com/v2/detector?cursor=a,b>; rel="prev", <https://api.example.com/v2/detector?cursor=c>; rel=next`,
+			"https://api.example.com/v2/detector?cursor=c", "https://api.example.com/v2/detector?cursor=a,b"},
+		{`</first>; rel="first", </2>; title="x"; REL="next last"`, "/2", ""},
+	}
+	for _, c := range cases {
+		next, prev, err := parseLinkHeader(c.header)
+		assert.NoError(t, err, c.header)
+		assert.Equal(t, c.next, next, c.header)
+		assert.Equal(t, c.prev, prev, c.header)
+	}
+
+	_, _, err := parseLinkHeader(`/v2/detector; rel="next"`)
+	assert.Error(t, err, "Should fail without angle brackets")
+	_, _, err = parseLinkHeader(`</v2/detector; rel="next"`)
+	assert.Error(t, err, "Should fail without a closing angle bracket")
+}
+
+func TestSearchDetectorsCursorFollowsLinks(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	var requests []url.Values
+	mux.HandleFunc("/v2/detector", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("cursor") == "" {
+			w.Header().Set("Link", `<https://elsewhere.example.com/v2/detector?cursor=p2&name=cpu>; rel="next"`)
+			fmt.Fprint(w, `{"count": 2, "results": [{"id": "DeT1"}]}`)
+			return
+		}
+		w.Header().Set("Link", `</v2/detector?name=cpu>; rel="prev"`)
+		fmt.Fprint(w, `{"count": 2, "results": [{"id": "DeT2"}]}`)
+	})
+
+	it := client.SearchDetectorsCursor(context.Background(), "cpu", "")
+	ids := []string{}
+	for it.Next() {
+		ids = append(ids, it.Detector().Id)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"DeT1", "DeT2"}, ids)
+	if assert.Len(t, requests, 2) {
+		assert.Equal(t, "0", requests[0].Get("offset"), "The first page should be requested by offset")
+		assert.Equal(t, "p2", requests[1].Get("cursor"), "The next link should be followed")
+		assert.Equal(t, "", requests[1].Get("offset"))
+	}
+}
+
+func TestSearchDetectorsCursorRelativeLink(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	var requests []url.Values
+	mux.HandleFunc("/v2/detector", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("cursor") == "" {
+			w.Header().Set("Link", `<?cursor=p2>; rel="next"`)
+			fmt.Fprint(w, `{"count": 2, "results": [{"id": "DeT1"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"count": 2, "results": [{"id": "DeT2"}]}`)
+	})
+
+	it := client.SearchDetectorsCursor(context.Background(), "cpu", "")
+	ids := []string{}
+	for it.Next() {
+		ids = append(ids, it.Detector().Id)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"DeT1", "DeT2"}, ids, "The relative link should be resolved against /v2/detector")
+	if assert.Len(t, requests, 2) {
+		assert.Equal(t, "p2", requests[1].Get("cursor"))
+	}
+}
+
+func TestSearchOrgTokensCursorByOffset(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	params := url.Values{}
+	params.Add("limit", "100")
+	params.Add("name", "foo")
+	params.Add("offset", "0")
+	mux.HandleFunc("/v2/token", verifyRequest(t, "GET", http.StatusOK, params, "orgtoken/search_success.json"))
+
+	it := client.SearchOrgTokensCursor(context.Background(), "foo")
+	count := 0
+	for it.Next() {
+		count++
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, 2, count, "Should have both tokens")
+}
+
+func TestSearchDetectorsCursorError(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/detector", verifyRequest(t, "GET", http.StatusForbidden, nil, ""))
+
+	it := client.SearchDetectorsCursor(context.Background(), "", "")
+	assert.False(t, it.Next())
+	assert.Error(t, it.Err())
+}
@@ -0,0 +1,46 @@
+package permission
+
+// Kinds of actor a permission can be granted to
+const (
+	ActorTypeUser = "USER"
+	ActorTypeTeam = "TEAM"
+	ActorTypeOrg  = "ORG"
+)
+
+// Things an actor can be allowed to do with a resource
+const (
+	Read  = "READ"
+	Write = "WRITE"
+)
+
+// The access control list of a resource such as a detector or dashboard
+type PermissionSet struct {
+	// Who is allowed to do what. Actors that aren't listed have no access.
+	Actions []Action `json:"acl"`
+}
+
+// The permissions granted to a single actor
+type Action struct {
+	// The ID of the user or team, or of the organization for everyone in it
+	Actor string `json:"principalId"`
+	// One of ActorTypeUser, ActorTypeTeam or ActorTypeOrg
+	ActorType string `json:"principalType"`
+	// What the actor is allowed to do, e.g. Read and Write
+	Actions []string `json:"actions"`
+}
+
+// Can reports whether the set grants action to actor directly. Access the
+// actor has through a team or the organization isn't considered.
+func (ps *PermissionSet) Can(actor string, action string) bool {
+	for _, a := range ps.Actions {
+		if a.Actor != actor {
+			continue
+		}
+		for _, granted := range a.Actions {
+			if granted == action {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,211 @@
+package signalfx
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AWSCredentials are the keys that requests are signed with.
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	// Only set for temporary credentials
+	SessionToken string
+}
+
+// AWSCredentialsProvider supplies the credentials for signing each request.
+// It is called for every request, so it should cache credentials that are
+// expensive to get.  Providers from the AWS SDK can be adapted with a small
+// wrapper that copies their credentials into AWSCredentials.
+type AWSCredentialsProvider interface {
+	Retrieve(ctx context.Context) (AWSCredentials, error)
+}
+
+// StaticAWSCredentials is an AWSCredentialsProvider that always returns the
+// same credentials.
+type StaticAWSCredentials AWSCredentials
+
+// Retrieve returns the credentials.
+func (s StaticAWSCredentials) Retrieve(ctx context.Context) (AWSCredentials, error) {
+	return AWSCredentials(s), nil
+}
+
+// WithRequestSigning signs every request with AWS Signature Version 4, for
+// when the API is reached through an AWS API Gateway that uses IAM
+// authorization.  service is usually "execute-api".
+func WithRequestSigning(region, service string, creds AWSCredentialsProvider) ClientParam {
+	return func(client *Client) error {
+		if region == "" || service == "" {
+			return errors.New("request signing needs a region and service")
+		}
+		if creds == nil {
+			return errors.New("request signing needs credentials")
+		}
+		client.signer = &requestSigner{
+			region:  region,
+			service: service,
+			creds:   creds,
+			now:     time.Now,
+		}
+		return nil
+	}
+}
+
+type requestSigner struct {
+	region  string
+	service string
+	creds   AWSCredentialsProvider
+	now     func() time.Time
+}
+
+// These headers can be changed by proxies or the transport, so they aren't
+// signed.
+var unsignedHeaders = map[string]bool{
+	"authorization":   true,
+	"user-agent":      true,
+	"x-amzn-trace-id": true,
+}
+
+// sign adds the X-Amz-Date and Authorization headers to req, and the session
+// token if there is one, replacing its body with one that can be read again.
+func (s *requestSigner) sign(req *http.Request) error {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return err
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	creds, err := s.creds.Retrieve(req.Context())
+	if err != nil {
+		return fmt.Errorf("getting AWS credentials: %v", err)
+	}
+
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	canonicalHeaders, signedHeaders := canonicalizeHeaders(req)
+	payloadHash := sha256.Sum256(body)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalPath(req.URL.EscapedPath()),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	date := now.Format("20060102")
+	scope := date + "/" + s.region + "/" + s.service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := []byte("AWS4" + creds.SecretAccessKey)
+	for _, part := range []string{date, s.region, s.service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.AccessKeyID, scope, signedHeaders, signature))
+	return nil
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// canonicalizeHeaders returns the canonical headers of req, including Host,
+// and the list of their names.
+func canonicalizeHeaders(req *http.Request) (string, string) {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	values := map[string]string{"host": host}
+	for name, vs := range req.Header {
+		name = strings.ToLower(name)
+		if unsignedHeaders[name] {
+			continue
+		}
+		trimmed := make([]string, len(vs))
+		for i, v := range vs {
+			trimmed[i] = strings.Join(strings.Fields(v), " ")
+		}
+		values[name] = strings.Join(trimmed, ",")
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonical strings.Builder
+	for _, name := range names {
+		canonical.WriteString(name + ":" + values[name] + "\n")
+	}
+	return canonical.String(), strings.Join(names, ";")
+}
+
+// canonicalPath encodes each segment of an already escaped path again, as
+// SigV4 requires for every service but S3.
+func canonicalPath(escaped string) string {
+	if escaped == "" {
+		return "/"
+	}
+	segments := strings.Split(escaped, "/")
+	for i, seg := range segments {
+		segments[i] = sigV4Escape(seg)
+	}
+	return strings.Join(segments, "/")
+}
+
+func canonicalQuery(query map[string][]string) string {
+	var pairs []string
+	for k, vs := range query {
+		for _, v := range vs {
+			pairs = append(pairs, sigV4Escape(k)+"="+sigV4Escape(v))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// sigV4Escape percent-encodes everything but the unreserved characters of
+// RFC 3986.
+func sigV4Escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
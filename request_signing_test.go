@@ -0,0 +1,82 @@
+package signalfx
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Cases from the AWS Signature Version 4 test suite.
+func TestRequestSignerVectors(t *testing.T) {
+	signer := &requestSigner{
+		region:  "us-east-1",
+		service: "service",
+		creds: StaticAWSCredentials{
+			AccessKeyID:     "AKIDEXAMPLE",
+			SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		},
+		now: func() time.Time { return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC) },
+	}
+	cases := []struct {
+		method, url, signature string
+	}{
+		{"GET", "https://example.amazonaws.com/", "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"},
+		{"GET", "https://example.amazonaws.com/?Param2=value2&Param1=value1", "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500"},
+		{"POST", "https://example.amazonaws.com/", "5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b"},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest(c.method, c.url, nil)
+		assert.NoError(t, signer.sign(req))
+		assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+		assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature="+c.signature,
+			req.Header.Get("Authorization"), c.method+" "+c.url)
+	}
+}
+
+func TestWithRequestSigning(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	signed, err := NewClient("token",
+		APIUrl(server.URL),
+		WithRequestSigning("us-west-2", "execute-api", StaticAWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "session"}))
+	assert.NoError(t, err)
+
+	mux.HandleFunc("/v2/detector/string", func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		assert.True(t, strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/"), auth)
+		assert.Contains(t, auth, "/us-west-2/execute-api/aws4_request")
+		assert.Contains(t, auth, "SignedHeaders=content-type;host;x-amz-date;x-amz-security-token;x-sf-token,")
+		assert.Equal(t, "session", r.Header.Get("X-Amz-Security-Token"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(fixture("detector/get_success.json")))
+	})
+
+	_, err = signed.GetDetector("string")
+	assert.NoError(t, err, "Unexpected error getting a detector with signing")
+
+	_, err = NewClient("token", WithRequestSigning("", "execute-api", StaticAWSCredentials{}))
+	assert.Error(t, err, "Should need a region")
+	_, err = NewClient("token", WithRequestSigning("us-west-2", "execute-api", nil))
+	assert.Error(t, err, "Should need credentials")
+}
+
+type failingCredentials struct{}
+
+func (failingCredentials) Retrieve(ctx context.Context) (AWSCredentials, error) {
+	return AWSCredentials{}, assert.AnError
+}
+
+func TestRequestSigningCredentialsError(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	signed, err := NewClient("token", APIUrl(server.URL), WithRequestSigning("us-west-2", "execute-api", failingCredentials{}))
+	assert.NoError(t, err)
+	_, err = signed.GetDetector("string")
+	assert.Error(t, err, "Should fail when credentials can't be retrieved")
+}
@@ -0,0 +1,27 @@
+package resource
+
+// Resource types that share the custom property and tag metadata model.
+const (
+	Dimension        = "dimension"
+	Metric           = "metric"
+	MetricTimeSeries = "metrictimeseries"
+)
+
+// The custom properties, tags and description attached to a metadata
+// resource.
+type Metadata struct {
+	// The custom properties for the resource, as property name and value pairs
+	CustomProperties map[string]string `json:"customProperties,omitempty"`
+	// The tags for the resource
+	Tags []string `json:"tags,omitempty"`
+	// The description of the resource
+	Description string `json:"description,omitempty"`
+	// The SignalFx user ID of the user that created the resource
+	Creator string `json:"creator,omitempty"`
+	// Creation timestamp, in Unix time UTC-relative
+	Created int64 `json:"created,omitempty"`
+	// The SignalFx user ID of the user that last updated the resource
+	LastUpdatedBy string `json:"lastUpdatedBy,omitempty"`
+	// Last updated timestamp, in Unix time UTC-relative
+	LastUpdated int64 `json:"lastUpdated,omitempty"`
+}
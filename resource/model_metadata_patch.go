@@ -0,0 +1,61 @@
+package resource
+
+// A set of changes to make to a resource's metadata.  Anything not mentioned
+// in the patch is left as it is.
+type MetadataPatch struct {
+	// Custom properties to add or overwrite
+	SetProperties map[string]string
+	// Names of custom properties to remove
+	RemoveProperties []string
+	// Tags to add
+	AddTags []string
+	// Tags to remove
+	RemoveTags []string
+	// The new description, if it should change
+	Description *string
+}
+
+// Apply makes the changes in the patch to m.  Properties are removed before
+// they're set and tags are removed before they're added, so a patch that
+// does both ends up with the value being present.
+func (p *MetadataPatch) Apply(m *Metadata) {
+	if len(p.RemoveProperties) > 0 || len(p.SetProperties) > 0 {
+		props := make(map[string]string, len(m.CustomProperties)+len(p.SetProperties))
+		for k, v := range m.CustomProperties {
+			props[k] = v
+		}
+		for _, k := range p.RemoveProperties {
+			delete(props, k)
+		}
+		for k, v := range p.SetProperties {
+			props[k] = v
+		}
+		m.CustomProperties = props
+	}
+
+	if len(p.RemoveTags) > 0 || len(p.AddTags) > 0 {
+		remove := make(map[string]bool, len(p.RemoveTags))
+		for _, t := range p.RemoveTags {
+			remove[t] = true
+		}
+		tags := []string{}
+		seen := map[string]bool{}
+		for _, t := range m.Tags {
+			if !remove[t] && !seen[t] {
+				tags = append(tags, t)
+				seen[t] = true
+			}
+		}
+		for _, t := range p.AddTags {
+			if !seen[t] {
+				tags = append(tags, t)
+				seen[t] = true
+			}
+		}
+		m.Tags = tags
+	}
+
+	if p.Description != nil {
+		m.Description = *p.Description
+	}
+}
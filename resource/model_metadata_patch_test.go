@@ -0,0 +1,36 @@
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadataPatchApply(t *testing.T) {
+	m := &Metadata{
+		CustomProperties: map[string]string{"team": "infra", "owner": "bob"},
+		Tags:             []string{"prod", "legacy"},
+		Description:      "old",
+	}
+	desc := "new"
+	(&MetadataPatch{
+		SetProperties:    map[string]string{"team": "sre", "tier": "1"},
+		RemoveProperties: []string{"owner"},
+		AddTags:          []string{"critical", "prod"},
+		RemoveTags:       []string{"legacy"},
+		Description:      &desc,
+	}).Apply(m)
+
+	assert.Equal(t, map[string]string{"team": "sre", "tier": "1"}, m.CustomProperties)
+	assert.Equal(t, []string{"prod", "critical"}, m.Tags)
+	assert.Equal(t, "new", m.Description)
+}
+
+func TestEmptyMetadataPatchApply(t *testing.T) {
+	m := &Metadata{Tags: []string{"prod"}, Description: "d"}
+	(&MetadataPatch{}).Apply(m)
+
+	assert.Nil(t, m.CustomProperties, "Properties shouldn't be touched")
+	assert.Equal(t, []string{"prod"}, m.Tags)
+	assert.Equal(t, "d", m.Description)
+}
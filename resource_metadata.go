@@ -0,0 +1,103 @@
+package signalfx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/adampetrovic/signalfx-go/resource"
+)
+
+var resourceAPIURLs = map[string]string{
+	resource.Dimension:        DimensionAPIURL,
+	resource.Metric:           MetricAPIURL,
+	resource.MetricTimeSeries: MetricTimeSeriesAPIURL,
+}
+
+func resourceURL(resourceType, resourceID string) (string, error) {
+	base, ok := resourceAPIURLs[resourceType]
+	if !ok {
+		return "", fmt.Errorf("unsupported resource type %q", resourceType)
+	}
+	return base + "/" + resourceID, nil
+}
+
+// GetResourceMetadata gets the custom properties, tags and description of a
+// resource.  resourceType is one of the types in the resource package, and
+// resourceID is whatever identifies the resource in its own API: the metric
+// name, the MTS ID, or "key/value" for a dimension.
+func (c *Client) GetResourceMetadata(ctx context.Context, resourceType, resourceID string) (*resource.Metadata, error) {
+	raw, err := c.getRawResource(ctx, resourceType, resourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	finalMetadata := &resource.Metadata{}
+
+	err = c.unmarshal(raw, finalMetadata)
+	return finalMetadata, err
+}
+
+// UpdateResourceMetadata changes the custom properties, tags and description
+// of a resource.  The API only accepts the whole resource, so the current
+// version is fetched, patched and written back; the rest of the resource is
+// sent back unchanged.
+func (c *Client) UpdateResourceMetadata(ctx context.Context, resourceType, resourceID string, patch *resource.MetadataPatch) error {
+	raw, err := c.getRawResource(ctx, resourceType, resourceID)
+	if err != nil {
+		return err
+	}
+
+	fields := map[string]interface{}{}
+	if err := c.unmarshal(raw, &fields); err != nil {
+		return err
+	}
+	metadata := &resource.Metadata{}
+	if err := c.unmarshal(raw, metadata); err != nil {
+		return err
+	}
+
+	patch.Apply(metadata)
+	fields["customProperties"] = metadata.CustomProperties
+	fields["tags"] = metadata.Tags
+	fields["description"] = metadata.Description
+
+	payload, err := c.marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	targetURL, _ := resourceURL(resourceType, resourceID)
+	resp, err := c.doRequestWithContext(ctx, "PUT", targetURL, nil, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(resp)
+	}
+
+	return nil
+}
+
+func (c *Client) getRawResource(ctx context.Context, resourceType, resourceID string) ([]byte, error) {
+	targetURL, err := resourceURL(resourceType, resourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequestWithContext(ctx, "GET", targetURL, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
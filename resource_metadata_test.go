@@ -0,0 +1,67 @@
+package signalfx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/adampetrovic/signalfx-go/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetResourceMetadata(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/dimension/host/web-1", verifyRequest(t, "GET", http.StatusOK, nil, "resource/get_dimension_success.json"))
+
+	result, err := client.GetResourceMetadata(context.Background(), resource.Dimension, "host/web-1")
+	assert.NoError(t, err, "Unexpected error getting metadata")
+	assert.Equal(t, map[string]string{"team": "infra"}, result.CustomProperties)
+	assert.Equal(t, []string{"prod"}, result.Tags)
+}
+
+func TestGetUnsupportedResourceMetadata(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	result, err := client.GetResourceMetadata(context.Background(), "detector", "abc")
+	assert.Error(t, err, "Should fail with an unsupported type")
+	assert.Nil(t, result, "Should get nil result")
+}
+
+func TestUpdateResourceMetadata(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/dimension/host/web-1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			verifyRequest(t, "GET", http.StatusOK, nil, "resource/get_dimension_success.json")(w, r)
+			return
+		}
+		fields := map[string]interface{}{}
+		err := json.NewDecoder(r.Body).Decode(&fields)
+		assert.NoError(t, err, "Unexpected error decoding body")
+		assert.Equal(t, "host", fields["key"], "Other fields should be kept")
+		assert.Equal(t, map[string]interface{}{"team": "infra", "owner": "me"}, fields["customProperties"])
+		assert.Equal(t, []interface{}{"prod", "web"}, fields["tags"])
+		verifyRequest(t, "PUT", http.StatusOK, nil, "resource/get_dimension_success.json")(w, r)
+	})
+
+	err := client.UpdateResourceMetadata(context.Background(), resource.Dimension, "host/web-1", &resource.MetadataPatch{
+		SetProperties: map[string]string{"owner": "me"},
+		AddTags:       []string{"web"},
+	})
+	assert.NoError(t, err, "Unexpected error updating metadata")
+}
+
+func TestUpdateMissingResourceMetadata(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/metrictimeseries/AAAA", verifyRequest(t, "GET", http.StatusNotFound, nil, ""))
+
+	err := client.UpdateResourceMetadata(context.Background(), resource.MetricTimeSeries, "AAAA", &resource.MetadataPatch{})
+	assert.Error(t, err, "Should fail when the resource doesn't exist")
+}
@@ -0,0 +1,54 @@
+package signalfx
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	"github.com/adampetrovic/signalfx-go/permission"
+)
+
+// ResourcePermissionsAPIURL is the base URL for the permissions of individual
+// resources, followed by the resource type and ID, e.g.
+// "/v2/resourcepermissions/detector/abc".
+const ResourcePermissionsAPIURL = "/v2/resourcepermissions"
+
+// GetResourcePermissions gets who can read and write a resource. resourceType
+// is the API name of the type, e.g. "detector" or "dashboard".
+func (c *Client) GetResourcePermissions(ctx context.Context, resourceType, resourceID string) (*permission.PermissionSet, error) {
+	resp, err := c.doRequestWithContext(ctx, "GET", ResourcePermissionsAPIURL+"/"+resourceType+"/"+resourceID, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	finalPermissions := &permission.PermissionSet{}
+
+	err = c.decodeJSON(resp.Body, finalPermissions)
+
+	return finalPermissions, err
+}
+
+// SetResourcePermissions replaces who can read and write a resource.
+func (c *Client) SetResourcePermissions(ctx context.Context, resourceType, resourceID string, perms *permission.PermissionSet) error {
+	payload, err := c.marshal(perms)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.doRequestWithContext(ctx, "PUT", ResourcePermissionsAPIURL+"/"+resourceType+"/"+resourceID, nil, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return newAPIError(resp)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package signalfx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/adampetrovic/signalfx-go/permission"
+)
+
+func TestGetResourcePermissions(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/resourcepermissions/detector/string", verifyRequest(t, "GET", http.StatusOK, nil, "permission/get_success.json"))
+
+	result, err := client.GetResourcePermissions(context.Background(), "detector", "string")
+	assert.NoError(t, err, "Unexpected error getting permissions")
+	if assert.Len(t, result.Actions, 2) {
+		assert.Equal(t, permission.ActorTypeUser, result.Actions[0].ActorType)
+	}
+	assert.True(t, result.Can("UsR", permission.Write), "User should be able to write")
+	assert.False(t, result.Can("OrG", permission.Write), "Org should only be able to read")
+	assert.False(t, result.Can("nobody", permission.Read), "Unlisted actors have no access")
+}
+
+func TestGetMissingResourcePermissions(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/resourcepermissions/detector/string", verifyRequest(t, "GET", http.StatusNotFound, nil, ""))
+
+	result, err := client.GetResourcePermissions(context.Background(), "detector", "string")
+	assert.Error(t, err, "Should get an error from a missing resource")
+	assert.Nil(t, result, "Should get a nil result from a missing resource")
+}
+
+func TestSetResourcePermissions(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/resourcepermissions/dashboard/string", func(w http.ResponseWriter, r *http.Request) {
+		perms := &permission.PermissionSet{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(perms))
+		assert.True(t, perms.Can("TeAm", permission.Write), "Permissions do not match")
+		verifyRequest(t, "PUT", http.StatusNoContent, nil, "")(w, r)
+	})
+
+	err := client.SetResourcePermissions(context.Background(), "dashboard", "string", &permission.PermissionSet{
+		Actions: []permission.Action{{Actor: "TeAm", ActorType: permission.ActorTypeTeam, Actions: []string{permission.Read, permission.Write}}},
+	})
+	assert.NoError(t, err, "Unexpected error setting permissions")
+}
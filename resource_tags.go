@@ -0,0 +1,51 @@
+package signalfx
+
+import (
+	"context"
+
+	"github.com/adampetrovic/signalfx-go/resource"
+)
+
+// ResourceTagsOptions changes how SetResourceTags works.
+type ResourceTagsOptions struct {
+	// Replace the resource's tags with the given ones, instead of adding to
+	// them
+	Replace bool
+}
+
+// GetResourceTags gets the tags of a resource.  resourceType and resourceID
+// are as for GetResourceMetadata.
+func (c *Client) GetResourceTags(ctx context.Context, resourceType, resourceID string) ([]string, error) {
+	metadata, err := c.GetResourceMetadata(ctx, resourceType, resourceID)
+	if err != nil {
+		return nil, err
+	}
+	if metadata.Tags == nil {
+		return []string{}, nil
+	}
+	return metadata.Tags, nil
+}
+
+// SetResourceTags adds tags to a resource, keeping the ones it already has.
+// If opts.Replace is set, the resource's other tags are removed.  opts may
+// be nil.
+func (c *Client) SetResourceTags(ctx context.Context, resourceType, resourceID string, tags []string, opts *ResourceTagsOptions) error {
+	patch := &resource.MetadataPatch{AddTags: tags}
+	if opts != nil && opts.Replace {
+		current, err := c.GetResourceTags(ctx, resourceType, resourceID)
+		if err != nil {
+			return err
+		}
+		// Tags are removed before they're added, so ones in both are kept
+		patch.RemoveTags = current
+	}
+	return c.UpdateResourceMetadata(ctx, resourceType, resourceID, patch)
+}
+
+// RemoveResourceTag removes a single tag from a resource.  Removing a tag the
+// resource doesn't have isn't an error.
+func (c *Client) RemoveResourceTag(ctx context.Context, resourceType, resourceID, tag string) error {
+	return c.UpdateResourceMetadata(ctx, resourceType, resourceID, &resource.MetadataPatch{
+		RemoveTags: []string{tag},
+	})
+}
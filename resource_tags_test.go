@@ -0,0 +1,66 @@
+package signalfx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/adampetrovic/signalfx-go/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+// handleTagUpdate serves the test dimension, checking that a PUT sets its tags
+// to expected.
+func handleTagUpdate(t *testing.T, expected []interface{}) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			fields := map[string]interface{}{}
+			err := json.NewDecoder(r.Body).Decode(&fields)
+			assert.NoError(t, err, "Unexpected error decoding body")
+			assert.Equal(t, expected, fields["tags"])
+		}
+		verifyRequest(t, r.Method, http.StatusOK, nil, "resource/get_dimension_success.json")(w, r)
+	}
+}
+
+func TestGetResourceTags(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/dimension/host/web-1", verifyRequest(t, "GET", http.StatusOK, nil, "resource/get_dimension_success.json"))
+
+	result, err := client.GetResourceTags(context.Background(), resource.Dimension, "host/web-1")
+	assert.NoError(t, err, "Unexpected error getting tags")
+	assert.Equal(t, []string{"prod"}, result)
+}
+
+func TestSetResourceTags(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/dimension/host/web-1", handleTagUpdate(t, []interface{}{"prod", "team:infra"}))
+
+	err := client.SetResourceTags(context.Background(), resource.Dimension, "host/web-1", []string{"team:infra"}, nil)
+	assert.NoError(t, err, "Unexpected error setting tags")
+}
+
+func TestSetResourceTagsReplace(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/dimension/host/web-1", handleTagUpdate(t, []interface{}{"team:infra"}))
+
+	err := client.SetResourceTags(context.Background(), resource.Dimension, "host/web-1", []string{"team:infra"}, &ResourceTagsOptions{Replace: true})
+	assert.NoError(t, err, "Unexpected error replacing tags")
+}
+
+func TestRemoveResourceTag(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/dimension/host/web-1", handleTagUpdate(t, []interface{}{}))
+
+	err := client.RemoveResourceTag(context.Background(), resource.Dimension, "host/web-1", "prod")
+	assert.NoError(t, err, "Unexpected error removing tag")
+}
@@ -0,0 +1,147 @@
+package signalfx
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"github.com/adampetrovic/signalfx-go/chart"
+	"github.com/adampetrovic/signalfx-go/dashboard"
+	"github.com/adampetrovic/signalfx-go/dashboard_group"
+	"github.com/adampetrovic/signalfx-go/detector"
+)
+
+// ResourceType names a kind of resource that SearchAcrossResourceTypes can
+// search.
+type ResourceType string
+
+const (
+	ResourceTypeDetector       ResourceType = "detector"
+	ResourceTypeDashboard      ResourceType = "dashboard"
+	ResourceTypeDashboardGroup ResourceType = "dashboardgroup"
+	ResourceTypeChart          ResourceType = "chart"
+)
+
+// AllResourceTypes are the types SearchAcrossResourceTypes searches when it's
+// given none.
+var AllResourceTypes = []ResourceType{
+	ResourceTypeDetector,
+	ResourceTypeDashboard,
+	ResourceTypeDashboardGroup,
+	ResourceTypeChart,
+}
+
+// CrossResourceSearchResult holds the matches for each resource type searched
+// by SearchAcrossResourceTypes. Types that weren't searched are left empty.
+type CrossResourceSearchResult struct {
+	Detectors       []*detector.Detector              `json:"detectors,omitempty"`
+	Dashboards      []*dashboard.Dashboard            `json:"dashboards,omitempty"`
+	DashboardGroups []*dashboard_group.DashboardGroup `json:"dashboardGroups,omitempty"`
+	Charts          []*chart.Chart                    `json:"charts,omitempty"`
+}
+
+// SearchAcrossResourceTypes searches each of resourceTypes for resources whose
+// name matches query, running the searches concurrently. An empty
+// resourceTypes searches all of AllResourceTypes. If any search fails, the
+// first error is returned.
+func (c *Client) SearchAcrossResourceTypes(ctx context.Context, query string, resourceTypes []ResourceType) (*CrossResourceSearchResult, error) {
+	if len(resourceTypes) == 0 {
+		resourceTypes = AllResourceTypes
+	}
+
+	result := &CrossResourceSearchResult{}
+	searches := make([]func(context.Context) error, 0, len(resourceTypes))
+	seen := make(map[ResourceType]bool, len(resourceTypes))
+	for _, rt := range resourceTypes {
+		if seen[rt] {
+			continue
+		}
+		seen[rt] = true
+
+		search, err := c.resourceSearch(rt, query, result)
+		if err != nil {
+			return nil, err
+		}
+		searches = append(searches, search)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var once sync.Once
+	var firstErr error
+	c.forEachConcurrently(len(searches), func(i int) {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := searches[i](ctx); err != nil {
+			once.Do(func() {
+				firstErr = err
+				cancel()
+			})
+		}
+	})
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return result, nil
+}
+
+// resourceSearch returns a function that pages through every resource of type
+// rt matching query, storing them in the matching field of result. Each
+// function writes to a different field, so they can run concurrently.
+func (c *Client) resourceSearch(rt ResourceType, query string, result *CrossResourceSearchResult) (func(context.Context) error, error) {
+	params := url.Values{}
+	params.Add("name", query)
+
+	switch rt {
+	case ResourceTypeDetector:
+		return func(ctx context.Context) error {
+			return c.forEachPage(ctx, DetectorAPIURL, params, func() (interface{}, func() int) {
+				page := &detector.SearchResults{}
+				return page, func() int {
+					for i := range page.Results {
+						result.Detectors = append(result.Detectors, &page.Results[i])
+					}
+					return len(page.Results)
+				}
+			})
+		}, nil
+	case ResourceTypeDashboard:
+		return func(ctx context.Context) error {
+			return c.forEachPage(ctx, DashboardAPIURL, params, func() (interface{}, func() int) {
+				page := &dashboard.SearchResult{}
+				return page, func() int {
+					for i := range page.Results {
+						result.Dashboards = append(result.Dashboards, &page.Results[i])
+					}
+					return len(page.Results)
+				}
+			})
+		}, nil
+	case ResourceTypeDashboardGroup:
+		return func(ctx context.Context) error {
+			return c.forEachPage(ctx, DashboardGroupAPIURL, params, func() (interface{}, func() int) {
+				page := &dashboard_group.SearchResult{}
+				return page, func() int {
+					result.DashboardGroups = append(result.DashboardGroups, page.Results...)
+					return len(page.Results)
+				}
+			})
+		}, nil
+	case ResourceTypeChart:
+		return func(ctx context.Context) error {
+			return c.forEachPage(ctx, ChartAPIURL, params, func() (interface{}, func() int) {
+				page := &chart.SearchResult{}
+				return page, func() int {
+					result.Charts = append(result.Charts, page.Results...)
+					return len(page.Results)
+				}
+			})
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown resource type %q", rt)
+}
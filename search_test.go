@@ -0,0 +1,58 @@
+package signalfx
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchAcrossResourceTypes(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	params := url.Values{}
+	params.Add("limit", "100")
+	params.Add("name", "production")
+	params.Add("offset", "0")
+	mux.HandleFunc("/v2/detector", verifyRequest(t, "GET", http.StatusOK, params, "detector/search_success.json"))
+	mux.HandleFunc("/v2/dashboard", verifyRequest(t, "GET", http.StatusOK, params, "dashboard/search_success.json"))
+	mux.HandleFunc("/v2/dashboardgroup", verifyRequest(t, "GET", http.StatusOK, params, "dashboardgroup/search_success.json"))
+	mux.HandleFunc("/v2/chart", verifyRequest(t, "GET", http.StatusOK, params, "chart/search_success.json"))
+
+	result, err := client.SearchAcrossResourceTypes(context.Background(), "production", nil)
+	assert.NoError(t, err, "Unexpected error searching")
+	assert.Len(t, result.Detectors, 1)
+	assert.Len(t, result.Dashboards, 1)
+	assert.Len(t, result.DashboardGroups, 1)
+	assert.Len(t, result.Charts, 1)
+}
+
+func TestSearchAcrossResourceTypesSelected(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/chart", verifyRequest(t, "GET", http.StatusOK, nil, "chart/search_success.json"))
+
+	result, err := client.SearchAcrossResourceTypes(context.Background(), "production", []ResourceType{ResourceTypeChart, ResourceTypeChart})
+	assert.NoError(t, err, "Unexpected error searching")
+	assert.Len(t, result.Charts, 1, "Duplicate types should only be searched once")
+	assert.Nil(t, result.Detectors, "Unselected types should be skipped")
+}
+
+func TestSearchAcrossResourceTypesError(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/detector", verifyRequest(t, "GET", http.StatusOK, nil, "detector/search_success.json"))
+	mux.HandleFunc("/v2/dashboard", verifyRequest(t, "GET", http.StatusForbidden, nil, ""))
+
+	result, err := client.SearchAcrossResourceTypes(context.Background(), "production", []ResourceType{ResourceTypeDetector, ResourceTypeDashboard})
+	assert.Error(t, err, "Should have gotten an error from the failed search")
+	assert.Nil(t, result)
+
+	_, err = client.SearchAcrossResourceTypes(context.Background(), "production", []ResourceType{"bogus"})
+	assert.Error(t, err, "Should reject unknown resource types")
+}
@@ -2,9 +2,6 @@ package signalfx
 
 import (
 	"bytes"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
 	"net/http"
 
 	"github.com/adampetrovic/signalfx-go/sessiontoken"
@@ -13,44 +10,44 @@ import (
 // SessionTokenAPIURL is the base URL for interacting with org tokens.
 const SessionTokenAPIURL = "/v2/session"
 
-// CreateOrgToken creates a org token.
+// CreateSessionToken logs in with an email and password, creating a session
+// token.  It doesn't need the client to have a token, so it can be called on
+// a client from NewLoginClient.
 func (c *Client) CreateSessionToken(tokenRequest *sessiontoken.CreateTokenRequest) (*sessiontoken.Token, error) {
-	payload, err := json.Marshal(tokenRequest)
+	payload, err := c.marshal(tokenRequest)
 	if err != nil {
 		return nil, err
 	}
 
 	// we need to explicitly pass an empty token (which means it wont get set in the header)
 	// the API accepts either no token or a valid token, but not an empty token.
-	resp, err := c.doRequestWithToken("POST", SessionTokenAPIURL, nil, bytes.NewReader(payload), "")
+	resp, err := c.doRequestWithToken(c.ctx, "POST", SessionTokenAPIURL, nil, bytes.NewReader(payload), "")
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Bad status %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	sessionToken := &sessiontoken.Token{}
 
-	err = json.NewDecoder(resp.Body).Decode(sessionToken)
+	err = c.decodeJSON(resp.Body, sessionToken)
 
 	return sessionToken, err
 }
 
 // DeleteOrgToken deletes a token.
 func (c *Client) DeleteSessionToken(token string) error {
-	resp, err := c.doRequestWithToken("DELETE", SessionTokenAPIURL, nil, nil, token)
+	resp, err := c.doRequestWithToken(c.ctx, "DELETE", SessionTokenAPIURL, nil, nil, token)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return newAPIError(resp)
 	}
 
 	return nil
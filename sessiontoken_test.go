@@ -46,7 +46,7 @@ func TestCreateSessionToken(t *testing.T) {
 	mux = http.NewServeMux()
 	server = httptest.NewServer(mux)
 
-	client, _ = NewClient("", APIUrl(server.URL))
+	client, _ = NewLoginClient(APIUrl(server.URL))
 	defer server.Close()
 
 	mux.HandleFunc("/v2/session", verifyNoTokenRequest(t, "POST", http.StatusOK, nil, "sessiontoken/create_success.json"))
@@ -61,10 +61,8 @@ func TestCreateSessionToken(t *testing.T) {
 }
 
 func TestCreateBadCredentials(t *testing.T) {
-	mux = http.NewServeMux()
-	server = httptest.NewServer(mux)
-	client, _ = NewClient("", APIUrl(server.URL))
-	defer server.Close()
+	teardown := setup()
+	defer teardown()
 
 	mux.HandleFunc("/v2/session", verifyNoTokenRequest(t, "POST", http.StatusBadRequest, nil, ""))
 
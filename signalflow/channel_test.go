@@ -6,16 +6,71 @@ import (
 
 	"github.com/adampetrovic/signalfx-go/idtool"
 	"github.com/adampetrovic/signalfx-go/signalflow/messages"
+	sftesting "github.com/adampetrovic/signalfx-go/signalflow/testing"
 	"github.com/stretchr/testify/require"
 )
 
 func TestChannel(t *testing.T) {
-	ch := newChannel(context.Background(), "test-ch")
+	server := sftesting.NewMockServer()
+	defer server.Stop()
 
-	msg := messages.MetadataMessage{
-		TSID: idtool.ID(4000),
+	program := "data('cpu.utilization').publish()"
+	server.AddProgramTSIDs(program, []idtool.ID{4000})
+	server.AddTSIDMetadata(idtool.ID(4000), &messages.MetadataProperties{Metric: "cpu.utilization"})
+
+	c, err := NewClient(StreamURL(server.URL()))
+	require.Nil(t, err)
+	defer c.Close()
+
+	// Channels are closed on each (re)connect, so register after connecting
+	require.Nil(t, c.WaitForState(context.Background(), Connected))
+	ch := c.registerChannel("test-ch")
+	require.Nil(t, c.sendMessage(&ExecuteRequest{Program: program, Channel: "test-ch"}))
+
+	for msg := range ch.Messages() {
+		if md, ok := msg.(*messages.MetadataMessage); ok {
+			require.Equal(t, idtool.ID(4000), md.TSID)
+			return
+		}
 	}
-	go ch.AcceptMessage(&msg)
+	t.Fatal("Channel closed before the metadata message")
+}
+
+func TestChannelMessagesFromServer(t *testing.T) {
+	server := sftesting.NewMockServer()
+	defer server.Stop()
+
+	program := "data('cpu.utilization').publish()"
+	server.AddProgramTSIDs(program, []idtool.ID{4000})
+	server.AddTSIDMetadata(idtool.ID(4000), &messages.MetadataProperties{Metric: "cpu.utilization"})
+
+	c, err := NewClient(StreamURL(server.URL()))
+	require.Nil(t, err)
+	defer c.Close()
+
+	// Channels are closed on each (re)connect, so register after connecting
+	require.Nil(t, c.WaitForState(context.Background(), Connected))
+	ch := c.registerChannel("test-ch")
+	require.Nil(t, c.sendMessage(&ExecuteRequest{Program: program, Channel: "test-ch"}))
+
+	next := func() messages.Message {
+		return <-ch.Messages()
+	}
+
+	require.Equal(t, messages.StreamStartEvent, next().(*messages.BaseControlMessage).Event)
+	require.Equal(t, "handle-0", next().(*messages.JobStartControlMessage).Handle)
+	require.IsType(t, &messages.InfoMessage{}, next())
+
+	md := next().(*messages.MetadataMessage)
+	require.Equal(t, idtool.ID(4000), md.TSID)
+	require.Equal(t, "cpu.utilization", md.Properties.Metric)
+
+	server.PushData(idtool.ID(4000), 42)
+	data := next().(*messages.DataMessage)
+	require.Len(t, data.Payloads, 1)
+	require.Equal(t, 42.0, data.Payloads[0].Float64())
 
-	require.Equal(t, &msg, (<-ch.Messages()).(*messages.MetadataMessage))
+	server.EndJobs()
+	require.Equal(t, messages.EndOfChannelEvent, next().(*messages.BaseControlMessage).Event)
+	require.Equal(t, 0, server.RunningJobs())
 }
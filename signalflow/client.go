@@ -1,6 +1,7 @@
 package signalflow
 
 import (
+	"compress/flate"
 	"context"
 	"encoding/json"
 	"errors"
@@ -11,8 +12,8 @@ import (
 	"sync/atomic"
 	"time"
 
-	"github.com/gorilla/websocket"
 	"github.com/adampetrovic/signalfx-go/signalflow/messages"
+	"github.com/gorilla/websocket"
 )
 
 // Client for SignalFlow via websockets (SSE is not currently supported).
@@ -25,16 +26,42 @@ type Client struct {
 	conn                   *wsConn
 	readTimeout            time.Duration
 	// How long to wait for writes to the websocket to finish
-	writeTimeout   time.Duration
-	streamURL      *url.URL
-	channelsByName map[string]*Channel
-	outgoingCh     chan *clientMessageRequest
+	writeTimeout time.Duration
+	// The flate level to compress messages with, if compression is enabled
+	compressionLevel *int
+	streamURL        *url.URL
+	channelsByName   map[string]*Channel
+	outgoingCh       chan *clientMessageRequest
+	// Holds a slot for each running computation, if they are limited
+	computationSlots chan struct{}
+	// Accessed atomically
+	activeComputations int64
+	// How often to ping the backend, if at all
+	heartbeatInterval time.Duration
+	// Unix nanoseconds of the last successful ping, accessed atomically
+	lastHeartbeat int64
+
+	// Accessed atomically
+	state           int32
+	stateLock       sync.Mutex
+	stateSubs       map[chan ConnectionState]struct{}
+	stateSubsClosed bool
 
 	ctx    context.Context
 	cancel context.CancelFunc
 	sync.Mutex
 }
 
+// Executor runs SignalFlow programs.  It is implemented by Client and, for
+// tests, by FakeClient.
+type Executor interface {
+	Execute(req *ExecuteRequest) (*Computation, error)
+	Stop(req *StopRequest) error
+	Close()
+}
+
+var _ Executor = &Client{}
+
 type clientMessageRequest struct {
 	msg      interface{}
 	resultCh chan error
@@ -121,6 +148,51 @@ func WriteTimeout(timeout time.Duration) ClientParam {
 	}
 }
 
+// WithWebSocketCompression negotiates permessage-deflate compression with the
+// SignalFlow backend, which greatly reduces the bandwidth used by programs
+// that return many time series.  level is a compress/flate level from -2
+// (Huffman only) to 9 (best compression); flate.DefaultCompression (-1) is a
+// good choice.  If the backend doesn't agree to compression the connection
+// is still made, uncompressed.
+func WithWebSocketCompression(level int) ClientParam {
+	return func(c *Client) error {
+		if level < flate.HuffmanOnly || level > flate.BestCompression {
+			return fmt.Errorf("WithWebSocketCompression level must be between %d and %d, got %d", flate.HuffmanOnly, flate.BestCompression, level)
+		}
+		c.compressionLevel = &level
+		return nil
+	}
+}
+
+// WithMaxConcurrentComputations limits how many computations the client runs
+// at once.  Calls to Execute beyond the limit block until an earlier
+// computation ends, as described for ActiveComputations.  0 means no limit, which is the default.
+func WithMaxConcurrentComputations(n int) ClientParam {
+	return func(c *Client) error {
+		if n < 0 {
+			return errors.New("WithMaxConcurrentComputations cannot be < 0")
+		}
+		if n > 0 {
+			c.computationSlots = make(chan struct{}, n)
+		}
+		return nil
+	}
+}
+
+// WithHeartbeatInterval makes the client ping the SignalFlow backend every
+// interval, to detect a connection that has gone stale without an error.  If
+// HeartbeatFailureLimit pings in a row get no pong within interval, the
+// client reconnects.  Heartbeats are off by default.
+func WithHeartbeatInterval(interval time.Duration) ClientParam {
+	return func(c *Client) error {
+		if interval <= 0 {
+			return errors.New("WithHeartbeatInterval cannot be <= 0")
+		}
+		c.heartbeatInterval = interval
+		return nil
+	}
+}
+
 // NewClient makes a new SignalFlow client that will immediately try and
 // connect to the SignalFlow backend.
 func NewClient(options ...ClientParam) (*Client, error) {
@@ -135,6 +207,8 @@ func NewClient(options ...ClientParam) (*Client, error) {
 		channelsByName:         make(map[string]*Channel),
 		defaultMetadataTimeout: 5 * time.Second,
 		outgoingCh:             make(chan *clientMessageRequest),
+		state:                  int32(Connecting),
+		stateSubs:              make(map[chan ConnectionState]struct{}),
 	}
 
 	for i := range options {
@@ -148,9 +222,14 @@ func NewClient(options ...ClientParam) (*Client, error) {
 	c.conn = newWebsocketConn(c.ctx, c.streamURL)
 	c.conn.ReadTimeout = c.readTimeout
 	c.conn.WriteTimeout = c.writeTimeout
+	if c.compressionLevel != nil {
+		c.conn.Compression = true
+		c.conn.CompressionLevel = *c.compressionLevel
+	}
 	c.conn.PostDisconnectCallback = func() {
 		c.closeRegisteredChannels()
 	}
+	c.conn.StateCallback = c.setState
 
 	c.conn.PostConnectMessage = func() []byte {
 		bytes, err := c.makeAuthRequest()
@@ -164,6 +243,9 @@ func NewClient(options ...ClientParam) (*Client, error) {
 
 	go c.conn.Run()
 	go c.run()
+	if c.heartbeatInterval > 0 {
+		go c.heartbeat()
+	}
 
 	return c, nil
 }
@@ -274,16 +356,66 @@ func (c *Client) makeAuthRequest() ([]byte, error) {
 // Execute a SignalFlow job and return a channel upon which informational
 // messages and data will flow.
 func (c *Client) Execute(req *ExecuteRequest) (*Computation, error) {
+	return c.ExecuteWithContext(context.Background(), req)
+}
+
+// ExecuteWithContext is like Execute, but if the client is limited by
+// WithMaxConcurrentComputations, ctx can cancel waiting for a slot.  ctx
+// doesn't affect the computation once it has started.
+func (c *Client) ExecuteWithContext(ctx context.Context, req *ExecuteRequest) (*Computation, error) {
 	if req.Channel == "" {
 		req.Channel = c.newUniqueChannelName()
 	}
 
+	if err := c.acquireComputationSlot(ctx); err != nil {
+		return nil, err
+	}
+
 	err := c.sendMessage(req)
 	if err != nil {
+		c.releaseComputationSlot()
 		return nil, err
 	}
 
-	return newComputation(c.ctx, c.registerChannel(req.Channel), c), nil
+	comp := newComputation(c.ctx, c.registerChannel(req.Channel), c, c.defaultMetadataTimeout)
+	go func() {
+		// The slot is free as soon as the computation stops producing data,
+		// rather than once its data has all been read, so that computations
+		// that are stopped or whose data isn't read don't hold it.
+		<-comp.ended
+		c.releaseComputationSlot()
+	}()
+	return comp, nil
+}
+
+// ActiveComputations returns the number of computations started by Execute
+// that haven't ended yet.  A computation ends at the end of its channel, when
+// it is stopped or when it fails, even if its data hasn't all been read.
+func (c *Client) ActiveComputations() int {
+	return int(atomic.LoadInt64(&c.activeComputations))
+}
+
+// acquireComputationSlot blocks until the client is allowed to start another
+// computation, ctx is done or the client is closed.
+func (c *Client) acquireComputationSlot(ctx context.Context) error {
+	if c.computationSlots != nil {
+		select {
+		case c.computationSlots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.ctx.Done():
+			return errors.New("client closed while waiting to start computation")
+		}
+	}
+	atomic.AddInt64(&c.activeComputations, 1)
+	return nil
+}
+
+func (c *Client) releaseComputationSlot() {
+	atomic.AddInt64(&c.activeComputations, -1)
+	if c.computationSlots != nil {
+		<-c.computationSlots
+	}
 }
 
 // Stop sends a job stop request message to the backend.  It does not wait for
@@ -322,4 +454,5 @@ func (c *Client) Close() {
 		c.cancel()
 	}
 	c.closeRegisteredChannels()
+	c.closeStateSubs()
 }
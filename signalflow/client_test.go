@@ -1,6 +1,7 @@
 package signalflow
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"math/rand"
@@ -39,7 +40,7 @@ func TestAuthenticationFlow(t *testing.T) {
 			"start":      0.,
 			"stop":       0.,
 			"timezone":   ""},
-	}, fakeBackend.received)
+	}, fakeBackend.Received())
 }
 
 func TestBasicComputation(t *testing.T) {
@@ -156,7 +157,7 @@ func TestReconnect(t *testing.T) {
 			"start":      0.,
 			"stop":       0.,
 			"timezone":   ""},
-	}, fakeBackend.received)
+	}, fakeBackend.Received())
 
 	fakeBackend.KillExistingConnections()
 
@@ -169,7 +170,7 @@ func TestReconnect(t *testing.T) {
 
 	require.Equal(t, 1*time.Second, comp.Resolution())
 
-	log.Printf("%v", fakeBackend.received)
+	log.Printf("%v", fakeBackend.Received())
 	require.Equal(t, []map[string]interface{}{
 		{"type": "authenticate",
 			"token": fakeBackend.AccessToken},
@@ -193,7 +194,7 @@ func TestReconnect(t *testing.T) {
 			"start":      0.,
 			"stop":       0.,
 			"timezone":   ""},
-	}, fakeBackend.received)
+	}, fakeBackend.Received())
 }
 
 func TestReconnectAfterBackendDown(t *testing.T) {
@@ -224,7 +225,7 @@ func TestReconnectAfterBackendDown(t *testing.T) {
 			"start":      0.,
 			"stop":       0.,
 			"timezone":   ""},
-	}, fakeBackend.received)
+	}, fakeBackend.Received())
 
 	fakeBackend.Stop()
 	<-comp.Done()
@@ -262,7 +263,7 @@ func TestReconnectAfterBackendDown(t *testing.T) {
 			"start":      0.,
 			"stop":       0.,
 			"timezone":   ""},
-	}, fakeBackend.received)
+	}, fakeBackend.Received())
 }
 
 func Example() {
@@ -308,3 +309,78 @@ func Example() {
 		log.Printf("Job completed")
 	}
 }
+
+func TestMaxConcurrentComputations(t *testing.T) {
+	fakeBackend := NewRunningFakeBackend()
+	defer fakeBackend.Stop()
+
+	c, err := NewClient(StreamURL(fakeBackend.URL()), AccessToken(fakeBackend.AccessToken), WithMaxConcurrentComputations(1))
+	require.Nil(t, err)
+	defer c.Close()
+
+	comp, err := c.Execute(&ExecuteRequest{
+		Program: "data('cpu.utilization').publish()",
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, c.ActiveComputations())
+
+	started := make(chan *Computation)
+	go func() {
+		comp, err := c.Execute(&ExecuteRequest{
+			Program: "data('cpu.utilization').publish()",
+		})
+		require.Nil(t, err)
+		started <- comp
+	}()
+
+	select {
+	case <-started:
+		t.Fatal("second computation should wait for the first")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	fakeBackend.KillExistingConnections()
+	<-comp.Done()
+
+	select {
+	case comp := <-started:
+		require.False(t, comp.IsFinished())
+	case <-time.After(5 * time.Second):
+		t.Fatal("second computation should start once the first finishes")
+	}
+	require.Equal(t, 1, c.ActiveComputations())
+}
+
+func TestMaxConcurrentComputationsStop(t *testing.T) {
+	fakeBackend := NewRunningFakeBackend()
+	defer fakeBackend.Stop()
+
+	c, err := NewClient(StreamURL(fakeBackend.URL()), AccessToken(fakeBackend.AccessToken), WithMaxConcurrentComputations(1))
+	require.Nil(t, err)
+	defer c.Close()
+
+	comp, err := c.Execute(&ExecuteRequest{
+		Program: "data('cpu.utilization').publish()",
+	})
+	require.Nil(t, err)
+	require.NotEqual(t, "", comp.Handle())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = c.ExecuteWithContext(ctx, &ExecuteRequest{
+		Program: "data('cpu.utilization').publish()",
+	})
+	require.Equal(t, context.DeadlineExceeded, err, "Waiting for a slot should be cancellable")
+
+	// Stopping frees the slot even though Data is never read
+	require.Nil(t, comp.Stop())
+	require.Eventually(t, func() bool { return c.ActiveComputations() == 0 }, time.Second, time.Millisecond)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	comp, err = c.ExecuteWithContext(ctx, &ExecuteRequest{
+		Program: "data('cpu.utilization').publish()",
+	})
+	require.Nil(t, err)
+	require.False(t, comp.IsFinished())
+}
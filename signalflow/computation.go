@@ -4,27 +4,48 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/signalfx/golib/v3/pointer"
 	"github.com/adampetrovic/signalfx-go/idtool"
 	"github.com/adampetrovic/signalfx-go/signalflow/messages"
+	"github.com/signalfx/golib/v3/pointer"
 )
 
+// How many event messages a computation holds for Events before dropping the
+// oldest, so that a caller that never reads them doesn't run out of memory.
+const maxBufferedEvents = 10000
+
 // Computation is a single running SignalFlow job
 type Computation struct {
+	// How many events were dropped because too many were unread.  Accessed
+	// atomically, so it comes first to be 64-bit aligned.
+	droppedEvents int64
+	// Set to 1 by the first call to Events.  Accessed atomically.
+	eventsRead int32
+
 	ctx     context.Context
 	cancel  context.CancelFunc
 	channel *Channel
-	client  *Client
+	client  Executor
 	dataCh  chan *messages.DataMessage
 	// An intermediate channel for data messages where they can be buffered if
 	// nothing is currently pulling data messages.
-	dataChBuffer       chan *messages.DataMessage
+	dataChBuffer chan *messages.DataMessage
+	// Set once the end of the channel is seen, after which dataChBuffer is
+	// closed and the computation finishes once buffered data is read.
+	dataEnded          bool
 	expirationCh       chan *messages.ExpiredTSIDMessage
 	expirationChBuffer chan *messages.ExpiredTSIDMessage
+	eventCh            chan *messages.EventMessage
+	eventChBuffer      chan *messages.EventMessage
 	updateSignal       updateSignal
 	lastError          error
+	// Closed once the computation stops producing new data, at the end of
+	// the channel, when it is stopped or when it fails, even if data is still
+	// waiting to be read from Data.
+	ended     chan struct{}
+	endedOnce sync.Once
 
 	resolutionMS *int
 	lagMS        *int
@@ -40,7 +61,7 @@ type Computation struct {
 	MetadataTimeout time.Duration
 }
 
-func newComputation(ctx context.Context, channel *Channel, client *Client) *Computation {
+func newComputation(ctx context.Context, channel *Channel, client Executor, metadataTimeout time.Duration) *Computation {
 	newCtx, cancel := context.WithCancel(ctx)
 	comp := &Computation{
 		ctx:                newCtx,
@@ -51,13 +72,17 @@ func newComputation(ctx context.Context, channel *Channel, client *Client) *Comp
 		dataChBuffer:       make(chan *messages.DataMessage),
 		expirationCh:       make(chan *messages.ExpiredTSIDMessage),
 		expirationChBuffer: make(chan *messages.ExpiredTSIDMessage),
+		eventCh:            make(chan *messages.EventMessage),
+		eventChBuffer:      make(chan *messages.EventMessage),
 		tsidMetadata:       make(map[idtool.ID]*messages.MetadataProperties),
 		updateSignal:       updateSignal{},
-		MetadataTimeout:    client.defaultMetadataTimeout,
+		ended:              make(chan struct{}),
+		MetadataTimeout:    metadataTimeout,
 	}
 
 	go comp.bufferDataMessages()
 	go comp.bufferExpirationMessages()
+	go comp.bufferEventMessages(ctx)
 	go comp.watchMessages()
 	return comp
 }
@@ -142,10 +167,10 @@ func (c *Computation) Err() error {
 }
 
 func (c *Computation) watchMessages() {
+	defer c.markEnded()
 	for {
 		select {
 		case <-c.ctx.Done():
-			close(c.dataCh)
 			return
 		case m, ok := <-c.channel.Messages():
 			if !ok {
@@ -164,12 +189,26 @@ func (c *Computation) processMessage(m messages.Message) {
 	case *messages.JobStartControlMessage:
 		c.handle = v.Handle
 	case *messages.BaseControlMessage:
-		switch v.Type() {
+		switch v.Event {
 		case messages.ChannelAbortEvent, messages.EndOfChannelEvent:
-			c.cancel()
+			c.endData()
 		}
 	case *messages.DataMessage:
-		c.dataChBuffer <- v
+		if c.dataEnded {
+			return
+		}
+		select {
+		case c.dataChBuffer <- v:
+		case <-c.ctx.Done():
+		}
+	case *messages.EventMessage:
+		if c.dataEnded {
+			return
+		}
+		select {
+		case c.eventChBuffer <- v:
+		case <-c.ctx.Done():
+		}
 	case *messages.ExpiredTSIDMessage:
 		delete(c.tsidMetadata, idtool.IDFromString(v.TSID))
 		c.expirationChBuffer <- v
@@ -190,26 +229,60 @@ func (c *Computation) processMessage(m messages.Message) {
 	}
 }
 
+// endData stops accepting data messages.  The computation finishes once the
+// data already received has been read from Data.
+func (c *Computation) endData() {
+	c.markEnded()
+	if !c.dataEnded {
+		c.dataEnded = true
+		close(c.dataChBuffer)
+		close(c.eventChBuffer)
+	}
+}
+
+// markEnded records that the computation won't produce any more data.
+func (c *Computation) markEnded() {
+	c.endedOnce.Do(func() {
+		close(c.ended)
+	})
+}
+
 // Buffer up data messages indefinitely until another goroutine reads them off of
-// c.messages, which is an unbuffered channel.
+// c.messages, which is an unbuffered channel.  Once the end of the channel has
+// been seen and the buffer is drained, the computation is finished.  c.dataCh
+// is closed when this returns.
 func (c *Computation) bufferDataMessages() {
+	defer close(c.dataCh)
+
 	buffer := make([]*messages.DataMessage, 0)
 	var nextMessage *messages.DataMessage
+	input := c.dataChBuffer
 	for {
-		if len(buffer) > 0 {
-			if nextMessage == nil {
-				nextMessage, buffer = buffer[0], buffer[1:]
-			}
-			select {
-			case <-c.ctx.Done():
-				return
-			case c.dataCh <- nextMessage:
-				nextMessage = nil
-			case msg := <-c.dataChBuffer:
-				buffer = append(buffer, msg)
+		if nextMessage == nil && len(buffer) > 0 {
+			nextMessage, buffer = buffer[0], buffer[1:]
+		}
+		if nextMessage == nil && input == nil {
+			c.cancel()
+			return
+		}
+
+		// Sends to a nil channel block, which disables that case
+		var out chan<- *messages.DataMessage
+		if nextMessage != nil {
+			out = c.dataCh
+		}
+
+		select {
+		case <-c.ctx.Done():
+			return
+		case out <- nextMessage:
+			nextMessage = nil
+		case msg, ok := <-input:
+			if !ok {
+				input = nil
+				continue
 			}
-		} else {
-			buffer = append(buffer, <-c.dataChBuffer)
+			buffer = append(buffer, msg)
 		}
 	}
 }
@@ -238,6 +311,62 @@ func (c *Computation) bufferExpirationMessages() {
 	}
 }
 
+// Buffer up to maxBufferedEvents event messages until another goroutine reads
+// them off of c.eventCh, which is an unbuffered channel, dropping the oldest
+// beyond that.  If Events has been called, events that arrived before the
+// computation finished are still delivered after it has, so that none
+// published at the very end are lost; c.eventCh is closed once they have all
+// been read, and anything left unread is dropped when clientCtx is done.
+// Otherwise nobody is reading them, so they are dropped as soon as the
+// computation finishes.
+func (c *Computation) bufferEventMessages(clientCtx context.Context) {
+	defer close(c.eventCh)
+
+	buffer := make([]*messages.EventMessage, 0)
+	input := c.eventChBuffer
+	finished := c.ctx.Done()
+	for {
+		if len(buffer) == 0 && input == nil {
+			return
+		}
+
+		// Sends to a nil channel block, which disables that case
+		var out chan<- *messages.EventMessage
+		var next *messages.EventMessage
+		if len(buffer) > 0 {
+			out = c.eventCh
+			next = buffer[0]
+		}
+
+		select {
+		case <-clientCtx.Done():
+			return
+		case <-finished:
+			if atomic.LoadInt32(&c.eventsRead) == 0 {
+				return
+			}
+			input = nil
+			finished = nil
+		case out <- next:
+			buffer = buffer[1:]
+		case msg, ok := <-input:
+			if !ok {
+				if atomic.LoadInt32(&c.eventsRead) == 0 {
+					return
+				}
+				input = nil
+				finished = nil
+				continue
+			}
+			if len(buffer) >= maxBufferedEvents {
+				buffer = buffer[1:]
+				atomic.AddInt64(&c.droppedEvents, 1)
+			}
+			buffer = append(buffer, msg)
+		}
+	}
+}
+
 // Data returns the channel on which data messages come.
 func (c *Computation) Data() <-chan *messages.DataMessage {
 	return c.dataCh
@@ -249,6 +378,21 @@ func (c *Computation) Expirations() <-chan *messages.ExpiredTSIDMessage {
 	return c.expirationCh
 }
 
+// Events returns the channel on which event messages come, such as those
+// published by the detect blocks of a detector program.  It is closed once
+// the computation has finished and every event has been read.  Events are
+// only kept after the computation finishes if this was called before then.
+func (c *Computation) Events() <-chan *messages.EventMessage {
+	atomic.StoreInt32(&c.eventsRead, 1)
+	return c.eventCh
+}
+
+// DroppedEvents returns how many event messages were dropped because more
+// than 10000 were waiting to be read from Events, oldest first.
+func (c *Computation) DroppedEvents() int64 {
+	return atomic.LoadInt64(&c.droppedEvents)
+}
+
 // IsFinished returns true if the computation is done and no more data should
 // be expected from it.
 func (c *Computation) IsFinished() bool {
@@ -264,10 +408,14 @@ func (c *Computation) Stop() error {
 // StopWithReason stops the computation with a given reason. This reason will
 // be reflected in the control message that signals the end of the job/channel.
 func (c *Computation) StopWithReason(reason string) error {
-	return c.client.Stop(&StopRequest{
+	err := c.client.Stop(&StopRequest{
 		Reason: reason,
 		Handle: c.handle,
 	})
+	if err == nil {
+		c.markEnded()
+	}
+	return err
 }
 
 // Simple struct that allows one goroutine to signal a bunch of other
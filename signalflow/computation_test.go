@@ -25,9 +25,7 @@ func waitForDataMsg(t *testing.T, comp *Computation) messages.Message {
 
 func TestBuffersDataMessages(t *testing.T) {
 	ch := newChannel(context.Background(), "ch1")
-	comp := newComputation(context.Background(), ch, &Client{
-		defaultMetadataTimeout: 1 * time.Second,
-	})
+	comp := newComputation(context.Background(), ch, &Client{}, 1*time.Second)
 	defer comp.cancel()
 	ch.AcceptMessage(&messages.DataMessage{
 		Payloads: []messages.DataPayload{
@@ -60,9 +58,7 @@ func TestBuffersDataMessages(t *testing.T) {
 
 func TestBuffersExpiryMessages(t *testing.T) {
 	ch := newChannel(context.Background(), "ch1")
-	comp := newComputation(context.Background(), ch, &Client{
-		defaultMetadataTimeout: 1 * time.Second,
-	})
+	comp := newComputation(context.Background(), ch, &Client{}, 1*time.Second)
 	defer comp.cancel()
 	ch.AcceptMessage(&messages.DataMessage{
 		Payloads: []messages.DataPayload{
@@ -102,9 +98,7 @@ func mustParse(m messages.Message, err error) messages.Message {
 
 func TestResolutionMetadata(t *testing.T) {
 	ch := newChannel(context.Background(), "ch1")
-	comp := newComputation(context.Background(), ch, &Client{
-		defaultMetadataTimeout: 1 * time.Second,
-	})
+	comp := newComputation(context.Background(), ch, &Client{}, 1*time.Second)
 	defer comp.cancel()
 
 	wg := sync.WaitGroup{}
@@ -133,9 +127,7 @@ func TestResolutionMetadata(t *testing.T) {
 
 func TestMaxDelayMetadata(t *testing.T) {
 	ch := newChannel(context.Background(), "ch1")
-	comp := newComputation(context.Background(), ch, &Client{
-		defaultMetadataTimeout: 1 * time.Second,
-	})
+	comp := newComputation(context.Background(), ch, &Client{}, 1*time.Second)
 	defer comp.cancel()
 	ch.AcceptMessage(mustParse(messages.ParseMessage([]byte(`{
 		"type": "message",
@@ -152,9 +144,7 @@ func TestMaxDelayMetadata(t *testing.T) {
 
 func TestLagMetadata(t *testing.T) {
 	ch := newChannel(context.Background(), "ch1")
-	comp := newComputation(context.Background(), ch, &Client{
-		defaultMetadataTimeout: 1 * time.Second,
-	})
+	comp := newComputation(context.Background(), ch, &Client{}, 1*time.Second)
 	defer comp.cancel()
 	ch.AcceptMessage(mustParse(messages.ParseMessage([]byte(`{
 		"type": "message",
@@ -171,9 +161,7 @@ func TestLagMetadata(t *testing.T) {
 
 func TestHandle(t *testing.T) {
 	ch := newChannel(context.Background(), "ch1")
-	comp := newComputation(context.Background(), ch, &Client{
-		defaultMetadataTimeout: 1 * time.Second,
-	})
+	comp := newComputation(context.Background(), ch, &Client{}, 1*time.Second)
 	defer comp.cancel()
 	ch.AcceptMessage(mustParse(messages.ParseMessage([]byte(`{
 		"type": "control-message",
@@ -183,3 +171,90 @@ func TestHandle(t *testing.T) {
 
 	require.Equal(t, "AAAABBBB", comp.Handle())
 }
+
+func TestEndOfChannelFinishesComputation(t *testing.T) {
+	ch := newChannel(context.Background(), "ch1")
+	comp := newComputation(context.Background(), ch, &Client{}, 1*time.Second)
+	defer comp.cancel()
+	ch.AcceptMessage(&messages.DataMessage{})
+	ch.AcceptMessage(mustParse(messages.ParseMessage([]byte(`{
+		"type": "control-message",
+		"event": "END_OF_CHANNEL"
+	}`), true)))
+
+	n := 0
+	for range comp.Data() {
+		n++
+	}
+	require.Equal(t, 1, n, "Data received before the end of the channel should still be read")
+	require.True(t, comp.IsFinished())
+}
+
+func TestEventsDeliveredAfterEnd(t *testing.T) {
+	ch := newChannel(context.Background(), "ch1")
+	comp := newComputation(context.Background(), ch, &Client{}, 1*time.Second)
+	defer comp.cancel()
+	eventCh := comp.Events()
+	ch.AcceptMessage(mustParse(messages.ParseMessage([]byte(`{
+		"type": "event",
+		"tsId": "AAAAAAAAAAE",
+		"timestampMs": 1557936000000,
+		"properties": {"is": "anomalous", "was": "ok", "incidentId": "InC"}
+	}`), true)))
+	ch.AcceptMessage(&messages.EventMessage{Properties: map[string]interface{}{"is": "ok"}})
+	ch.AcceptMessage(&messages.BaseControlMessage{Event: messages.EndOfChannelEvent})
+
+	// Nothing reads the data, so the computation finishes straight away.
+	_, ok := <-comp.Data()
+	require.False(t, ok)
+
+	var events []*messages.EventMessage
+	for ev := range eventCh {
+		events = append(events, ev)
+	}
+	require.Len(t, events, 2)
+	require.Equal(t, idtool.ID(1), events[0].TSID)
+	require.Equal(t, time.Unix(1557936000, 0), events[0].Timestamp())
+	require.True(t, events[0].IsAnomalous())
+	require.False(t, events[1].IsAnomalous())
+}
+
+func TestEventBufferDropsOldest(t *testing.T) {
+	ch := newChannel(context.Background(), "ch1")
+	comp := newComputation(context.Background(), ch, &Client{}, 1*time.Second)
+	defer comp.cancel()
+	eventCh := comp.Events()
+	for i := 0; i < maxBufferedEvents+5; i++ {
+		ch.AcceptMessage(&messages.EventMessage{Properties: map[string]interface{}{"i": i}})
+	}
+	ch.AcceptMessage(&messages.BaseControlMessage{Event: messages.EndOfChannelEvent})
+
+	var events []*messages.EventMessage
+	for ev := range eventCh {
+		events = append(events, ev)
+	}
+	require.Len(t, events, maxBufferedEvents)
+	require.Equal(t, 5, events[0].Properties["i"], "The oldest events should be dropped")
+	require.Equal(t, int64(5), comp.DroppedEvents())
+}
+
+func TestUnreadEventsReleasedWhenFinished(t *testing.T) {
+	ch := newChannel(context.Background(), "ch1")
+	comp := newComputation(context.Background(), ch, &Client{}, 1*time.Second)
+	defer comp.cancel()
+	ch.AcceptMessage(&messages.EventMessage{Properties: map[string]interface{}{"is": "ok"}})
+	ch.AcceptMessage(&messages.BaseControlMessage{Event: messages.EndOfChannelEvent})
+
+	// Events was never called, so the buffered event is dropped and the
+	// channel closed once the computation finishes, without the client's
+	// context being done.
+	_, ok := <-comp.Data()
+	require.False(t, ok)
+	time.Sleep(100 * time.Millisecond)
+	select {
+	case _, ok := <-comp.eventCh:
+		require.False(t, ok, "Unread events shouldn't be delivered")
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "Event buffer wasn't released")
+	}
+}
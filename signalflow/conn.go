@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
 	"net/url"
 	"path"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -26,11 +29,28 @@ type wsConn struct {
 	readErrCh          chan error
 	readCh             chan struct{}
 	connectedCh        chan struct{}
+	pingCh             chan *outgoingPing
+	reconnectCh        chan struct{}
+
+	// Accessed atomically
+	lastPingNum int64
+	// Closed when the pong for the ping with the keyed payload arrives.
+	// Guarded by the embedded mutex.
+	pongWaiters map[string]chan struct{}
 
 	ReadTimeout            time.Duration
 	WriteTimeout           time.Duration
 	PostDisconnectCallback func()
 	PostConnectMessage     func() []byte
+	// Called with the new state of the connection each time it changes
+	StateCallback func(ConnectionState)
+	// Whether to negotiate permessage-deflate, and the flate level to
+	// compress outgoing messages with if so
+	Compression      bool
+	CompressionLevel int
+
+	// Overrides how the underlying TCP connection is made, for tests
+	netDialContext func(ctx context.Context, network, addr string) (net.Conn, error)
 }
 
 type outgoingMessage struct {
@@ -38,6 +58,11 @@ type outgoingMessage struct {
 	resultCh chan error
 }
 
+type outgoingPing struct {
+	payload  string
+	resultCh chan error
+}
+
 func newWebsocketConn(ctx context.Context, streamURL *url.URL) *wsConn {
 	ws := &wsConn{
 		ctx:                ctx,
@@ -48,6 +73,9 @@ func newWebsocketConn(ctx context.Context, streamURL *url.URL) *wsConn {
 		readErrCh:          make(chan error),
 		readCh:             make(chan struct{}),
 		connectedCh:        make(chan struct{}),
+		pingCh:             make(chan *outgoingPing),
+		reconnectCh:        make(chan struct{}),
+		pongWaiters:        make(map[string]chan struct{}),
 		ReadTimeout:        1 * time.Minute,
 		WriteTimeout:       20 * time.Second,
 	}
@@ -70,10 +98,17 @@ func (c *wsConn) ConnectedSignal() <-chan struct{} {
 	return c.connectedCh
 }
 
+func (c *wsConn) setState(state ConnectionState) {
+	if c.StateCallback != nil {
+		c.StateCallback(state)
+	}
+}
+
 // Run keeps the connection alive and puts all incoming messages into a channel
 // as needed.
 func (c *wsConn) Run() {
 	var conn *websocket.Conn
+	c.setState(Connecting)
 
 	for {
 		if conn == nil {
@@ -99,12 +134,14 @@ func (c *wsConn) Run() {
 				continue
 			}
 
+			c.setState(Connected)
 			go c.readNextMessage(conn)
 		}
 
 		select {
 		case <-c.ctx.Done():
 			conn.Close()
+			c.setState(Disconnected)
 			return
 		case <-c.readCh:
 			go c.readNextMessage(conn)
@@ -112,32 +149,110 @@ func (c *wsConn) Run() {
 			log.Printf("Error reading from SignalFlow websocket: %v", err)
 			conn.Close()
 			conn = nil
+			c.setState(Reconnecting)
 			time.Sleep(ReconnectDelay)
 		case msg := <-c.outgoingTextMsgs:
 			err := c.writeMessage(conn, msg.bytes)
 			msg.resultCh <- err
 			if err != nil {
-				// Force the connection closed if it isn't already and wait for
-				// the read goroutine to finish.
-				conn.Close()
-				select {
-				case <-c.readErrCh:
-				case <-c.readCh:
-				}
+				c.abandon(conn)
 				conn = nil
 				time.Sleep(ReconnectDelay)
 			}
+		case ping := <-c.pingCh:
+			ping.resultCh <- conn.WriteControl(websocket.PingMessage, []byte(ping.payload), time.Now().Add(c.WriteTimeout))
+		case <-c.reconnectCh:
+			log.Printf("Reconnecting unresponsive SignalFlow websocket")
+			c.abandon(conn)
+			conn = nil
 		}
 	}
 }
 
+// abandon forces conn closed if it isn't already and waits for the read
+// goroutine to finish, so that a new connection can be made.
+func (c *wsConn) abandon(conn *websocket.Conn) {
+	conn.Close()
+	select {
+	case <-c.readErrCh:
+	case <-c.readCh:
+	}
+	c.setState(Reconnecting)
+}
+
+// Reconnect drops the current connection and makes a new one.  It does nothing
+// if the connection isn't currently up.
+func (c *wsConn) Reconnect() {
+	select {
+	case c.reconnectCh <- struct{}{}:
+	default:
+	}
+}
+
+// Ping sends a ping frame and waits for the matching pong, until ctx is done.
+func (c *wsConn) Ping(ctx context.Context) error {
+	payload := strconv.FormatInt(atomic.AddInt64(&c.lastPingNum, 1), 10)
+	pong := make(chan struct{})
+	c.Lock()
+	c.pongWaiters[payload] = pong
+	c.Unlock()
+	defer func() {
+		c.Lock()
+		delete(c.pongWaiters, payload)
+		c.Unlock()
+	}()
+
+	resultCh := make(chan error, 1)
+	select {
+	case c.pingCh <- &outgoingPing{payload: payload, resultCh: resultCh}:
+	case <-ctx.Done():
+		return fmt.Errorf("could not send SignalFlow ping: %v", ctx.Err())
+	case <-c.ctx.Done():
+		return c.ctx.Err()
+	}
+	if err := <-resultCh; err != nil {
+		return err
+	}
+
+	select {
+	case <-pong:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("no pong received from SignalFlow: %v", ctx.Err())
+	}
+}
+
+func (c *wsConn) handlePong(payload string) error {
+	c.Lock()
+	defer c.Unlock()
+	if pong, ok := c.pongWaiters[payload]; ok {
+		close(pong)
+		delete(c.pongWaiters, payload)
+	}
+	return nil
+}
+
 func (c *wsConn) connect() (*websocket.Conn, error) {
 	connectURL := *c.streamURL
 	connectURL.Path = path.Join(c.streamURL.Path, "connect")
-	conn, _, err := websocket.DefaultDialer.DialContext(c.ctx, connectURL.String(), nil)
+	dialer := *websocket.DefaultDialer
+	dialer.EnableCompression = c.Compression
+	dialer.NetDialContext = c.netDialContext
+
+	conn, _, err := dialer.DialContext(c.ctx, connectURL.String(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("could not connect Signalflow websocket: %v", err)
 	}
+	conn.SetPongHandler(c.handlePong)
+
+	if c.Compression {
+		// Only takes effect if the server agreed to compression
+		conn.EnableWriteCompression(true)
+		if err := conn.SetCompressionLevel(c.CompressionLevel); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
 	return conn, nil
 }
 
@@ -0,0 +1,118 @@
+package signalflow
+
+import (
+	"compress/flate"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/idtool"
+	sftesting "github.com/adampetrovic/signalfx-go/signalflow/testing"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+// Compression is only used if the client asks for it
+var upgrader = websocket.Upgrader{EnableCompression: true}
+
+func TestWithWebSocketCompression(t *testing.T) {
+	fakeBackend := NewRunningFakeBackend()
+	defer fakeBackend.Stop()
+
+	c, err := NewClient(StreamURL(fakeBackend.URL()), AccessToken(fakeBackend.AccessToken), WithWebSocketCompression(flate.BestSpeed))
+	require.Nil(t, err)
+	defer c.Close()
+
+	comp, err := c.Execute(&ExecuteRequest{
+		Program: "data('cpu.utilization').publish()",
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1*time.Second, comp.Resolution())
+}
+
+func TestWithWebSocketCompressionInvalidLevel(t *testing.T) {
+	_, err := NewClient(WithWebSocketCompression(10))
+	require.Error(t, err)
+	_, err = NewClient(WithWebSocketCompression(-3))
+	require.Error(t, err)
+}
+
+// throttledConn limits how fast data can be read from the connection, to
+// simulate a slow link.
+type throttledConn struct {
+	net.Conn
+	bitsPerSecond float64
+}
+
+func (c *throttledConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	time.Sleep(time.Duration(float64(n*8) / c.bitsPerSecond * float64(time.Second)))
+	return n, err
+}
+
+// benchmarkDataThroughput measures how long it takes to receive data messages
+// for a 100 TSID program over a 10 Mbps link.
+func benchmarkDataThroughput(b *testing.B, compress bool) {
+	const numTSIDs = 100
+
+	// Minute resolution gauge values, which tend to repeat between TSIDs
+	vals := make([]sftesting.TSIDValue, numTSIDs)
+	for i := range vals {
+		vals[i] = sftesting.TSIDValue{TSID: idtool.ID(1000000 + i), Val: float64(i%10) * 12.5}
+	}
+	msg := sftesting.DataMessage("ch-1", vals)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for i := 0; i < b.N; i++ {
+			if err := conn.WriteMessage(websocket.BinaryMessage, msg); err != nil {
+				return
+			}
+		}
+		conn.ReadMessage()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	streamURL, err := url.Parse(strings.Replace(server.URL, "http", "ws", 1))
+	if err != nil {
+		b.Fatal(err)
+	}
+	ws := newWebsocketConn(ctx, streamURL)
+	ws.Compression = compress
+	ws.CompressionLevel = flate.DefaultCompression
+	ws.netDialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := (&net.Dialer{}).DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		return &throttledConn{Conn: conn, bitsPerSecond: 10e6}, nil
+	}
+
+	b.SetBytes(int64(len(msg)))
+	b.ResetTimer()
+
+	go ws.Run()
+	for i := 0; i < b.N; i++ {
+		<-ws.IncomingBinaryMessages()
+	}
+}
+
+func BenchmarkDataThroughputUncompressed(b *testing.B) {
+	benchmarkDataThroughput(b, false)
+}
+
+func BenchmarkDataThroughputCompressed(b *testing.B) {
+	benchmarkDataThroughput(b, true)
+}
@@ -0,0 +1,139 @@
+package signalflow
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/signalflow/messages"
+)
+
+// How many injected messages can be queued for a channel before InjectMessage
+// blocks.
+const fakeChannelQueueSize = 1000
+
+// FakeClient is an in-process Executor for testing code that runs SignalFlow
+// programs.  Nothing is sent over the network: executions are recorded, and
+// the messages that computations see are whatever the test injects.
+type FakeClient struct {
+	sync.Mutex
+
+	ctx            context.Context
+	cancel         context.CancelFunc
+	nextChannelNum int
+	executions     []*ExecuteRequest
+	stops          []*StopRequest
+	queues         map[string]chan messages.Message
+	channels       map[string]*Channel
+
+	// The MetadataTimeout given to computations started by this client
+	MetadataTimeout time.Duration
+}
+
+var _ Executor = &FakeClient{}
+
+// NewFakeClient makes a FakeClient.
+func NewFakeClient() *FakeClient {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &FakeClient{
+		ctx:             ctx,
+		cancel:          cancel,
+		queues:          make(map[string]chan messages.Message),
+		channels:        make(map[string]*Channel),
+		MetadataTimeout: 1 * time.Second,
+	}
+}
+
+// Execute records the request and returns a computation that receives the
+// messages injected for the request's channel.  Channels are named ch-1, ch-2
+// and so on if the request doesn't set one, like Client does.  As with
+// Client, executing again with the same channel name takes over the channel,
+// so later messages only go to the newest computation.
+func (f *FakeClient) Execute(req *ExecuteRequest) (*Computation, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.ctx.Err() != nil {
+		return nil, fmt.Errorf("FakeClient is closed")
+	}
+	if req.Channel == "" {
+		f.nextChannelNum++
+		req.Channel = fmt.Sprintf("ch-%d", f.nextChannelNum)
+	}
+	recorded := *req
+	f.executions = append(f.executions, &recorded)
+
+	ch := newChannel(f.ctx, req.Channel)
+	if _, ok := f.channels[req.Channel]; !ok {
+		go f.forward(req.Channel, f.queue(req.Channel))
+	}
+	f.channels[req.Channel] = ch
+
+	return newComputation(f.ctx, ch, f, f.MetadataTimeout), nil
+}
+
+// forward delivers the messages injected for a channel name to the latest
+// channel executed with it.
+func (f *FakeClient) forward(name string, queue chan messages.Message) {
+	for {
+		select {
+		case <-f.ctx.Done():
+			return
+		case msg := <-queue:
+			f.Lock()
+			ch := f.channels[name]
+			f.Unlock()
+			ch.AcceptMessage(msg)
+		}
+	}
+}
+
+// Stop records the request.
+func (f *FakeClient) Stop(req *StopRequest) error {
+	f.Lock()
+	defer f.Unlock()
+	recorded := *req
+	f.stops = append(f.stops, &recorded)
+	return nil
+}
+
+// Close stops all computations started by the client.
+func (f *FakeClient) Close() {
+	f.cancel()
+}
+
+// InjectMessage queues msg for the named channel, in order.  Messages
+// injected before the channel is executed are delivered once it is.
+func (f *FakeClient) InjectMessage(channel string, msg messages.Message) {
+	f.Lock()
+	queue := f.queue(channel)
+	f.Unlock()
+
+	queue <- msg
+}
+
+// queue must be called with the lock held.
+func (f *FakeClient) queue(channel string) chan messages.Message {
+	q, ok := f.queues[channel]
+	if !ok {
+		q = make(chan messages.Message, fakeChannelQueueSize)
+		f.queues[channel] = q
+	}
+	return q
+}
+
+// RecordedExecutions returns copies of the requests passed to Execute, in
+// order.
+func (f *FakeClient) RecordedExecutions() []*ExecuteRequest {
+	f.Lock()
+	defer f.Unlock()
+	return append([]*ExecuteRequest{}, f.executions...)
+}
+
+// RecordedStops returns copies of the requests passed to Stop, in order.
+func (f *FakeClient) RecordedStops() []*StopRequest {
+	f.Lock()
+	defer f.Unlock()
+	return append([]*StopRequest{}, f.stops...)
+}
@@ -0,0 +1,92 @@
+package signalflow
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/idtool"
+	"github.com/adampetrovic/signalfx-go/signalflow/messages"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeClient(t *testing.T) {
+	var c Executor = NewFakeClient()
+	fake := c.(*FakeClient)
+	defer c.Close()
+
+	var val [8]byte
+	binary.BigEndian.PutUint64(val[:], math.Float64bits(5))
+
+	// Injected before execution, since the channel name is predictable
+	fake.InjectMessage("ch-1", &messages.JobStartControlMessage{Handle: "abc"})
+	fake.InjectMessage("ch-1", &messages.MetadataMessage{
+		TSID:       idtool.ID(4000),
+		Properties: messages.MetadataProperties{Metric: "cpu.utilization"},
+	})
+
+	comp, err := c.Execute(&ExecuteRequest{
+		Program:    "data('cpu.utilization').publish()",
+		Resolution: time.Minute,
+	})
+	require.Nil(t, err)
+	require.Equal(t, "abc", comp.Handle())
+	require.Equal(t, "cpu.utilization", comp.TSIDMetadata(idtool.ID(4000)).Metric)
+
+	fake.InjectMessage("ch-1", &messages.DataMessage{
+		Payloads: []messages.DataPayload{{Type: messages.ValTypeDouble, TSID: idtool.ID(4000), Val: val}},
+	})
+	require.Equal(t, float64(5), (<-comp.Data()).Payloads[0].Float64())
+
+	require.Nil(t, comp.Stop())
+
+	executions := fake.RecordedExecutions()
+	require.Len(t, executions, 1)
+	require.Equal(t, "data('cpu.utilization').publish()", executions[0].Program)
+	require.Equal(t, time.Minute, executions[0].Resolution)
+	require.Equal(t, []*StopRequest{{Handle: "abc"}}, fake.RecordedStops())
+}
+
+func TestFakeClientReusedChannel(t *testing.T) {
+	c := NewFakeClient()
+	defer c.Close()
+
+	for _, handle := range []string{"first", "second"} {
+		comp, err := c.Execute(&ExecuteRequest{Program: "data('x').publish()", Channel: "ch"})
+		require.Nil(t, err)
+		c.InjectMessage("ch", &messages.JobStartControlMessage{Handle: handle})
+		require.Equal(t, handle, comp.Handle(), "Each execution should get the messages injected after it")
+	}
+}
+
+func TestFakeClientClosed(t *testing.T) {
+	c := NewFakeClient()
+	c.Close()
+
+	_, err := c.Execute(&ExecuteRequest{Program: "data('x').publish()"})
+	require.Error(t, err)
+}
+
+func TestComputationDrainsDataAtEndOfChannel(t *testing.T) {
+	c := NewFakeClient()
+	defer c.Close()
+
+	for i := 0; i < 3; i++ {
+		c.InjectMessage("ch-1", &messages.DataMessage{})
+	}
+	c.InjectMessage("ch-1", &messages.BaseControlMessage{Event: messages.EndOfChannelEvent})
+
+	comp, err := c.Execute(&ExecuteRequest{Program: "data('x').publish()"})
+	require.Nil(t, err)
+
+	// Give the end of channel message time to arrive before reading data
+	time.Sleep(50 * time.Millisecond)
+
+	n := 0
+	for range comp.Data() {
+		n++
+	}
+	require.Equal(t, 3, n)
+	require.True(t, comp.IsFinished())
+}
@@ -0,0 +1,70 @@
+package signalflow
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+// HeartbeatFailureLimit is how many heartbeat pings in a row can fail before
+// the client reconnects.
+const HeartbeatFailureLimit = 3
+
+// Ping sends a WebSocket ping to the SignalFlow backend and waits for the
+// pong, until ctx is done.  It fails if the client isn't connected.
+func (c *Client) Ping(ctx context.Context) error {
+	if err := c.conn.Ping(ctx); err != nil {
+		return err
+	}
+	atomic.StoreInt64(&c.lastHeartbeat, time.Now().UnixNano())
+	return nil
+}
+
+// LastHeartbeatAt returns when a ping, whether from Ping or the heartbeat
+// enabled by WithHeartbeatInterval, last got a pong.  It is the zero time if
+// none has.
+func (c *Client) LastHeartbeatAt() time.Time {
+	ns := atomic.LoadInt64(&c.lastHeartbeat)
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns)
+}
+
+// heartbeat pings the backend every heartbeatInterval while connected, and
+// reconnects if too many pings in a row fail.
+func (c *Client) heartbeat() {
+	ticker := time.NewTicker(c.heartbeatInterval)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if c.State() != Connected {
+			// A connection is already being made
+			failures = 0
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(c.ctx, c.heartbeatInterval)
+		err := c.Ping(ctx)
+		cancel()
+		if err == nil {
+			failures = 0
+			continue
+		}
+
+		failures++
+		log.Printf("SignalFlow heartbeat failed (%d in a row): %v", failures, err)
+		if failures >= HeartbeatFailureLimit {
+			c.conn.Reconnect()
+			failures = 0
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package signalflow
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPing(t *testing.T) {
+	fakeBackend := NewRunningFakeBackend()
+	defer fakeBackend.Stop()
+
+	c, err := NewClient(StreamURL(fakeBackend.URL()), AccessToken(fakeBackend.AccessToken))
+	require.Nil(t, err)
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.Nil(t, c.WaitForState(ctx, Connected))
+	require.True(t, c.LastHeartbeatAt().IsZero())
+
+	require.Nil(t, c.Ping(ctx))
+	require.False(t, c.LastHeartbeatAt().IsZero())
+}
+
+func TestHeartbeatReconnects(t *testing.T) {
+	var connections int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		atomic.AddInt64(&connections, 1)
+
+		// Never answer pings, like a connection that has silently died
+		conn.SetPingHandler(func(string) error { return nil })
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewClient(StreamURL(strings.Replace(server.URL, "http", "ws", 1)), WithHeartbeatInterval(20*time.Millisecond))
+	require.Nil(t, err)
+	defer c.Close()
+
+	require.Eventually(t, func() bool { return atomic.LoadInt64(&connections) >= 2 }, 2*time.Second, 10*time.Millisecond)
+	require.True(t, c.LastHeartbeatAt().IsZero())
+}
+
+func TestHeartbeatIntervalInvalid(t *testing.T) {
+	_, err := NewClient(WithHeartbeatInterval(0))
+	require.Error(t, err)
+}
@@ -43,9 +43,24 @@ func (dp *DataPayload) Float64() float64 {
 }
 
 func (dp *DataPayload) Int32() int32 {
-	var n int32
-	_ = binary.Read(bytes.NewBuffer(dp.Val[:]), binary.BigEndian, &n)
-	return n
+	return int32(binary.BigEndian.Uint32(dp.Val[:4]))
+}
+
+// float64Value returns the numeric value as a float64, and false if the
+// payload is of an unknown type or is NaN.
+func (dp *DataPayload) float64Value() (float64, bool) {
+	var v float64
+	switch dp.Type {
+	case ValTypeLong:
+		v = float64(dp.Int64())
+	case ValTypeDouble:
+		v = dp.Float64()
+	case ValTypeInt:
+		v = float64(dp.Int32())
+	default:
+		return 0, false
+	}
+	return v, !math.IsNaN(v)
 }
 
 // DataMessage is a set of datapoints that share a common timestamp
@@ -56,6 +71,35 @@ type DataMessage struct {
 	Payloads []DataPayload
 }
 
+// ForEach calls fn with the TSID and value of each payload that has a value,
+// in the order they appear in the message.  Payloads of an unknown type or
+// with a NaN value are skipped.
+func (dm *DataMessage) ForEach(fn func(tsid idtool.ID, value float64)) {
+	for i := range dm.Payloads {
+		if v, ok := dm.Payloads[i].float64Value(); ok {
+			fn(dm.Payloads[i].TSID, v)
+		}
+	}
+}
+
+// ForEachWithError is like ForEach but calls fn for every payload, with
+// hasValue false for those that ForEach would skip.
+func (dm *DataMessage) ForEachWithError(fn func(tsid idtool.ID, value float64, hasValue bool)) {
+	for i := range dm.Payloads {
+		v, ok := dm.Payloads[i].float64Value()
+		fn(dm.Payloads[i].TSID, v, ok)
+	}
+}
+
+// ToMap returns the value of each TSID in the message that has one.
+func (dm *DataMessage) ToMap() map[idtool.ID]float64 {
+	out := make(map[idtool.ID]float64, len(dm.Payloads))
+	dm.ForEach(func(tsid idtool.ID, value float64) {
+		out[tsid] = value
+	})
+	return out
+}
+
 func (dm *DataMessage) String() string {
 	pls := make([]map[string]interface{}, 0)
 	for _, pl := range dm.Payloads {
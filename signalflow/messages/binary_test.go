@@ -2,6 +2,8 @@ package messages
 
 import (
 	"encoding/base64"
+	"encoding/binary"
+	"math"
 	"testing"
 	"time"
 
@@ -35,3 +37,71 @@ func TestDecodeBinaryMessage(t *testing.T) {
 	assert.Equal(t, dm.Payloads[0].Value(), 691.1)
 	assert.Equal(t, dm.Payloads[0].TSID, idtool.ID(3079061720))
 }
+
+func decodeTestDataMessage(tb testing.TB) *DataMessage {
+	rawMsg, err := base64.StdEncoding.DecodeString(binaryMsgBase64)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	msg, err := parseBinaryMessage(rawMsg)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return msg.(*DataMessage)
+}
+
+func TestDataMessageForEach(t *testing.T) {
+	long := DataPayload{Type: ValTypeLong, TSID: 1}
+	binary.BigEndian.PutUint64(long.Val[:], 7)
+	integer := DataPayload{Type: ValTypeInt, TSID: 2}
+	binary.BigEndian.PutUint32(integer.Val[:4], uint32(0xFFFFFFFE))
+	double := DataPayload{Type: ValTypeDouble, TSID: 3}
+	binary.BigEndian.PutUint64(double.Val[:], math.Float64bits(1.5))
+	nan := DataPayload{Type: ValTypeDouble, TSID: 4}
+	binary.BigEndian.PutUint64(nan.Val[:], math.Float64bits(math.NaN()))
+	unknown := DataPayload{Type: 9, TSID: 5}
+	dm := &DataMessage{Payloads: []DataPayload{long, integer, double, nan, unknown}}
+
+	var tsids []idtool.ID
+	dm.ForEach(func(tsid idtool.ID, value float64) {
+		tsids = append(tsids, tsid)
+	})
+	assert.Equal(t, []idtool.ID{1, 2, 3}, tsids)
+
+	var hasValues []bool
+	dm.ForEachWithError(func(tsid idtool.ID, value float64, hasValue bool) {
+		hasValues = append(hasValues, hasValue)
+	})
+	assert.Equal(t, []bool{true, true, true, false, false}, hasValues)
+
+	assert.Equal(t, map[idtool.ID]float64{1: 7, 2: -2, 3: 1.5}, dm.ToMap())
+	assert.Equal(t, int32(-2), integer.Int32())
+
+	m := decodeTestDataMessage(t).ToMap()
+	assert.Len(t, m, 16)
+	assert.Equal(t, 691.1, m[3079061720])
+}
+
+func TestDataMessageForEachDoesNotAllocate(t *testing.T) {
+	dm := decodeTestDataMessage(t)
+	var sum float64
+	fn := func(tsid idtool.ID, value float64) {
+		sum += value
+	}
+	assert.Equal(t, 0.0, testing.AllocsPerRun(100, func() {
+		dm.ForEach(fn)
+	}))
+}
+
+func BenchmarkDataMessageForEach(b *testing.B) {
+	dm := decodeTestDataMessage(b)
+	var sum float64
+	fn := func(tsid idtool.ID, value float64) {
+		sum += value
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dm.ForEach(fn)
+	}
+}
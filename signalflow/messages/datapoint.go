@@ -0,0 +1,68 @@
+package messages
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+
+	"github.com/adampetrovic/signalfx-go/idtool"
+	"github.com/signalfx/golib/v3/datapoint"
+)
+
+// ToDatapoints converts the values in a data message to datapoints, taking the
+// metric name, type and dimensions of each from meta.  The type is the time
+// series' MetricType, so values without one are gauges.  Dimensions are
+// the properties named in the time series' sf_key property, or all of its
+// custom properties if it has none.  NaN and infinite values can't be sent
+// as datapoints, so they are left out with a warning.  It is an error for a
+// value to have no metadata.
+func ToDatapoints(data *DataMessage, meta map[idtool.ID]*MetadataMessage) ([]*datapoint.Datapoint, error) {
+	out := make([]*datapoint.Datapoint, 0, len(data.Payloads))
+	for i := range data.Payloads {
+		pl := &data.Payloads[i]
+
+		md := meta[pl.TSID]
+		if md == nil {
+			return nil, fmt.Errorf("no metadata for time series %s", pl.TSID)
+		}
+
+		var value datapoint.Value
+		switch pl.Type {
+		case ValTypeLong:
+			value = datapoint.NewIntValue(pl.Int64())
+		case ValTypeInt:
+			value = datapoint.NewIntValue(int64(pl.Int32()))
+		case ValTypeDouble:
+			f := pl.Float64()
+			if math.IsNaN(f) || math.IsInf(f, 0) {
+				log.Printf("Skipping non-finite value %v for time series %s", f, pl.TSID)
+				continue
+			}
+			value = datapoint.NewFloatValue(f)
+		default:
+			return nil, fmt.Errorf("unknown value type %d for time series %s", pl.Type, pl.TSID)
+		}
+
+		metric := md.Properties.Metric
+		if metric == "" {
+			metric = md.Properties.OriginatingMetric
+		}
+
+		out = append(out, datapoint.New(metric, md.Properties.Dimensions(), value, datapointType(md.MetricType()), data.Timestamp()))
+	}
+	return out, nil
+}
+
+// datapointType converts an sf_type such as "Gauge" or "CUMULATIVE_COUNTER"
+// to the datapoint metric type, treating unknown types as gauges.
+func datapointType(sfType string) datapoint.MetricType {
+	switch strings.ToUpper(strings.Replace(sfType, "_", "", -1)) {
+	case "COUNTER":
+		return datapoint.Count
+	case "CUMULATIVECOUNTER":
+		return datapoint.Counter
+	default:
+		return datapoint.Gauge
+	}
+}
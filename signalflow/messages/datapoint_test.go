@@ -0,0 +1,71 @@
+package messages
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/adampetrovic/signalfx-go/idtool"
+	"github.com/signalfx/golib/v3/datapoint"
+	"github.com/stretchr/testify/assert"
+)
+
+func testPayload(tsid idtool.ID, typ ValType, val uint64) DataPayload {
+	pl := DataPayload{Type: typ, TSID: tsid}
+	for i := 0; i < 8; i++ {
+		pl.Val[i] = byte(val >> uint(56-8*i))
+	}
+	return pl
+}
+
+func TestToDatapoints(t *testing.T) {
+	var md MetadataMessage
+	err := json.Unmarshal([]byte(`{"type": "metadata", "tsId": "AAAAAAAAAAE", "channel": "ch-1", "properties": {
+		"sf_metric": "cpu.utilization", "sf_key": ["host", "sf_metric"], "host": "a", "team": "sre"}}`), &md)
+	assert.NoError(t, err)
+
+	data := &DataMessage{
+		Payloads: []DataPayload{
+			testPayload(md.TSID, ValTypeDouble, math.Float64bits(12.5)),
+			testPayload(md.TSID, ValTypeLong, 7),
+			testPayload(md.TSID, ValTypeDouble, math.Float64bits(math.NaN())),
+		},
+	}
+	data.TimestampMillis = 1504064040000
+
+	dps, err := ToDatapoints(data, map[idtool.ID]*MetadataMessage{md.TSID: &md})
+	assert.NoError(t, err)
+	if assert.Len(t, dps, 2, "NaN should be skipped") {
+		assert.Equal(t, "cpu.utilization", dps[0].Metric)
+		assert.Equal(t, map[string]string{"host": "a"}, dps[0].Dimensions)
+		assert.Equal(t, datapoint.NewFloatValue(12.5), dps[0].Value)
+		assert.Equal(t, data.Timestamp(), dps[0].Timestamp)
+		assert.Equal(t, datapoint.Gauge, dps[0].MetricType, "Time series without an sf_type should be gauges")
+		assert.Equal(t, datapoint.NewIntValue(7), dps[1].Value)
+	}
+
+	_, err = ToDatapoints(data, nil)
+	assert.Error(t, err, "Missing metadata should be an error")
+}
+
+func TestToDatapointsCounters(t *testing.T) {
+	meta := map[idtool.ID]*MetadataMessage{}
+	data := &DataMessage{}
+	for i, sfType := range []string{"Counter", "CUMULATIVE_COUNTER", "Gauge"} {
+		var md MetadataMessage
+		err := json.Unmarshal([]byte(fmt.Sprintf(`{"type": "metadata", "tsId": "%s", "channel": "ch-1", "properties": {
+			"sf_metric": "requests", "sf_type": "%s"}}`, idtool.ID(i+1), sfType)), &md)
+		assert.NoError(t, err)
+		meta[md.TSID] = &md
+		data.Payloads = append(data.Payloads, testPayload(md.TSID, ValTypeLong, 3))
+	}
+
+	dps, err := ToDatapoints(data, meta)
+	assert.NoError(t, err)
+	if assert.Len(t, dps, 3) {
+		assert.Equal(t, datapoint.Count, dps[0].MetricType)
+		assert.Equal(t, datapoint.Counter, dps[1].MetricType)
+		assert.Equal(t, datapoint.Gauge, dps[2].MetricType)
+	}
+}
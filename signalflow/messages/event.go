@@ -1,5 +1,23 @@
 package messages
 
+import (
+	"github.com/adampetrovic/signalfx-go/idtool"
+)
+
+// EventMessage reports a change in the state of a detect block for one time
+// series, e.g. it becoming anomalous when the condition is met.
 type EventMessage struct {
 	BaseJSONChannelMessage
+	TimestampedMessage
+	TSID idtool.ID `json:"tsId"`
+	// Details of the event, including "is" and "was" for the new and previous
+	// states, and "incidentId".
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// IsAnomalous reports whether the event is for the detect condition being
+// met, rather than clearing.
+func (em *EventMessage) IsAnomalous() bool {
+	is, _ := em.Properties["is"].(string)
+	return is == "anomalous"
 }
@@ -14,6 +14,28 @@ type MetadataMessage struct {
 	Properties MetadataProperties `json:"properties"`
 }
 
+// IsHighResolution reports whether the time series is high resolution, going
+// by its sf_isPreQuantized property.
+func (mm *MetadataMessage) IsHighResolution() bool {
+	hr, _ := mm.Properties.InternalProperties["sf_isPreQuantized"].(bool)
+	return hr
+}
+
+// MetricType returns the time series' sf_type property, or "Gauge" if it
+// doesn't have one.
+func (mm *MetadataMessage) MetricType() string {
+	if typ, ok := mm.Properties.InternalProperties["sf_type"].(string); ok && typ != "" {
+		return typ
+	}
+	return "Gauge"
+}
+
+// OriginatingMetric returns the name of the metric the time series was
+// computed from.
+func (mm *MetadataMessage) OriginatingMetric() string {
+	return mm.Properties.OriginatingMetric
+}
+
 type MetadataProperties struct {
 	Metric            string `json:"sf_metric"`
 	OriginatingMetric string `json:"sf_originatingMetric"`
@@ -75,3 +97,25 @@ func (mp *MetadataProperties) MarshalJSON() ([]byte, error) {
 
 	return json.Marshal(out)
 }
+
+// Dimensions returns the dimensions of the time series: the properties named
+// in its sf_key property, or all of its custom properties if it has none.
+func (mp *MetadataProperties) Dimensions() map[string]string {
+	keys, ok := mp.InternalProperties["sf_key"].([]interface{})
+	if !ok {
+		dims := make(map[string]string, len(mp.CustomProperties))
+		for k, v := range mp.CustomProperties {
+			dims[k] = v
+		}
+		return dims
+	}
+
+	dims := make(map[string]string, len(keys))
+	for _, k := range keys {
+		name, _ := k.(string)
+		if v, ok := mp.CustomProperties[name]; ok {
+			dims[name] = v
+		}
+	}
+	return dims
+}
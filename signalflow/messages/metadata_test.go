@@ -0,0 +1,24 @@
+package messages
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadataMessageHelpers(t *testing.T) {
+	var md MetadataMessage
+	err := json.Unmarshal([]byte(`{"type": "metadata", "tsId": "AAAAAAAAAAE", "properties": {
+		"sf_metric": "_SF_COMP_1", "sf_originatingMetric": "cpu.utilization", "sf_isPreQuantized": true,
+		"sf_type": "Counter"}}`), &md)
+	assert.NoError(t, err)
+	assert.True(t, md.IsHighResolution())
+	assert.Equal(t, "Counter", md.MetricType())
+	assert.Equal(t, "cpu.utilization", md.OriginatingMetric())
+
+	var empty MetadataMessage
+	assert.False(t, empty.IsHighResolution())
+	assert.Equal(t, "Gauge", empty.MetricType())
+	assert.Equal(t, "", empty.OriginatingMetric())
+}
@@ -0,0 +1,134 @@
+package signalflow
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// ConnectionState is the state of the client's WebSocket connection to the
+// SignalFlow backend.
+type ConnectionState int32
+
+// The states a client's connection moves through.  A client starts out
+// Connecting, moves to Connected once the WebSocket is up and the
+// authentication request is sent, and to Reconnecting whenever the connection
+// drops.  It is Disconnected once it has been closed.
+const (
+	Disconnected ConnectionState = iota
+	Connecting
+	Connected
+	Reconnecting
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case Disconnected:
+		return "Disconnected"
+	case Connecting:
+		return "Connecting"
+	case Connected:
+		return "Connected"
+	case Reconnecting:
+		return "Reconnecting"
+	}
+	return "Unknown"
+}
+
+// How many state changes are buffered for each StateChanges channel before the
+// oldest are dropped.
+const stateChangeBufferSize = 10
+
+// State returns the current state of the client's connection.
+func (c *Client) State() ConnectionState {
+	return ConnectionState(atomic.LoadInt32(&c.state))
+}
+
+// StateChanges returns a channel that receives the client's connection state
+// each time it changes.  Sending never blocks the client: if the last 10
+// changes haven't been received, the oldest is dropped to make room.  The
+// channel is closed when the client is closed.
+func (c *Client) StateChanges() <-chan ConnectionState {
+	return c.subscribeState()
+}
+
+// WaitForState blocks until the client's connection is in the given state or
+// ctx is done, in which case it returns the context's error.
+func (c *Client) WaitForState(ctx context.Context, state ConnectionState) error {
+	ch := c.subscribeState()
+	defer c.unsubscribeState(ch)
+
+	// Check after subscribing so that a change in between isn't missed
+	if c.State() == state {
+		return nil
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case s, ok := <-ch:
+			if !ok {
+				return context.Canceled
+			}
+			if s == state {
+				return nil
+			}
+		}
+	}
+}
+
+func (c *Client) subscribeState() chan ConnectionState {
+	ch := make(chan ConnectionState, stateChangeBufferSize)
+
+	c.stateLock.Lock()
+	defer c.stateLock.Unlock()
+	if c.stateSubsClosed {
+		close(ch)
+		return ch
+	}
+	c.stateSubs[ch] = struct{}{}
+	return ch
+}
+
+func (c *Client) unsubscribeState(ch chan ConnectionState) {
+	c.stateLock.Lock()
+	delete(c.stateSubs, ch)
+	c.stateLock.Unlock()
+}
+
+func (c *Client) setState(state ConnectionState) {
+	c.stateLock.Lock()
+	defer c.stateLock.Unlock()
+
+	if c.stateSubsClosed || ConnectionState(atomic.SwapInt32(&c.state, int32(state))) == state {
+		return
+	}
+	for ch := range c.stateSubs {
+		select {
+		case ch <- state:
+		default:
+			// Drop the oldest change to make room
+			select {
+			case <-ch:
+			default:
+			}
+			ch <- state
+		}
+	}
+}
+
+// closeStateSubs moves the client to Disconnected and closes every
+// StateChanges channel.
+func (c *Client) closeStateSubs() {
+	c.setState(Disconnected)
+
+	c.stateLock.Lock()
+	defer c.stateLock.Unlock()
+	if c.stateSubsClosed {
+		return
+	}
+	c.stateSubsClosed = true
+	for ch := range c.stateSubs {
+		close(ch)
+	}
+	c.stateSubs = nil
+}
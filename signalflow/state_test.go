@@ -0,0 +1,63 @@
+package signalflow
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectionState(t *testing.T) {
+	fakeBackend := NewRunningFakeBackend()
+	defer fakeBackend.Stop()
+
+	c, err := NewClient(StreamURL(fakeBackend.URL()), AccessToken(fakeBackend.AccessToken))
+	require.Nil(t, err)
+
+	changes := c.StateChanges()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.Nil(t, c.WaitForState(ctx, Connected))
+	require.Equal(t, Connected, c.State())
+
+	fakeBackend.KillExistingConnections()
+	require.Nil(t, c.WaitForState(ctx, Reconnecting))
+
+	c.Close()
+	require.Equal(t, Disconnected, c.State())
+
+	var seen []ConnectionState
+	for s := range changes {
+		seen = append(seen, s)
+	}
+	require.Equal(t, []ConnectionState{Connected, Reconnecting, Disconnected}, seen)
+}
+
+func TestWaitForStateTimeout(t *testing.T) {
+	c, err := NewClient(StreamURL("ws://127.0.0.1:1/v2/signalflow"))
+	require.Nil(t, err)
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	require.Equal(t, context.DeadlineExceeded, c.WaitForState(ctx, Connected))
+	require.Equal(t, Connecting, c.State())
+}
+
+func TestStateChangesDropOldest(t *testing.T) {
+	c := &Client{stateSubs: make(map[chan ConnectionState]struct{})}
+	ch := c.StateChanges()
+	for i := 0; i < stateChangeBufferSize+1; i++ {
+		c.setState(Connected)
+		c.setState(Reconnecting)
+	}
+	require.Len(t, ch, stateChangeBufferSize)
+	require.Equal(t, Connected, <-ch, "Oldest changes should have been dropped")
+}
+
+func TestConnectionStateString(t *testing.T) {
+	require.Equal(t, "Reconnecting", Reconnecting.String())
+	require.Equal(t, "Unknown", ConnectionState(42).String())
+}
@@ -0,0 +1,83 @@
+package signalflow
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var publishRegexp = regexp.MustCompile(`\bpublish\s*\(`)
+
+// Matches data('name'), data("name") and data(metric='name') in a program.
+var dataCallRegexp = regexp.MustCompile(`\bdata\s*\(\s*(?:metric\s*=\s*)?['"]([^'"]+)['"]`)
+
+// MetricNames returns the metrics a SignalFlow program reads with data(), in
+// the order they first appear.  Wildcard names are returned as written.
+func MetricNames(program string) []string {
+	seen := map[string]bool{}
+	names := []string{}
+	for _, match := range dataCallRegexp.FindAllStringSubmatch(program, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+// CheckSyntax does a quick local check of a SignalFlow program for common
+// mistakes: unbalanced brackets, unterminated strings and programs that never
+// publish anything.  It is not a parser, so a program that passes may still
+// be rejected by the backend.  It returns a message for each problem found.
+func CheckSyntax(program string) []string {
+	if strings.TrimSpace(program) == "" {
+		return []string{"program is empty"}
+	}
+
+	var problems []string
+	closing := map[byte]byte{')': '(', ']': '[', '}': '{'}
+	var stack []byte
+	line := 1
+
+	for i := 0; i < len(program); i++ {
+		switch c := program[i]; c {
+		case '\n':
+			line++
+		case '#':
+			for i+1 < len(program) && program[i+1] != '\n' {
+				i++
+			}
+		case '\'', '"':
+			start := line
+			i++
+			for i < len(program) && program[i] != c && program[i] != '\n' {
+				if program[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(program) || program[i] != c {
+				problems = append(problems, fmt.Sprintf("line %d: unterminated string", start))
+				if i < len(program) {
+					line++
+				}
+			}
+		case '(', '[', '{':
+			stack = append(stack, c)
+		case ')', ']', '}':
+			if len(stack) == 0 || stack[len(stack)-1] != closing[c] {
+				problems = append(problems, fmt.Sprintf("line %d: unexpected '%c'", line, c))
+				continue
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	for _, open := range stack {
+		problems = append(problems, fmt.Sprintf("unclosed '%c'", open))
+	}
+
+	if !publishRegexp.MatchString(program) {
+		problems = append(problems, "program does not publish any streams")
+	}
+	return problems
+}
@@ -0,0 +1,23 @@
+package signalflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSyntax(t *testing.T) {
+	require.Empty(t, CheckSyntax("A = data('cpu.utilization', filter=filter('host', 'a')).mean(by=['host'])\nA.publish('A') # it's fine"))
+
+	require.Equal(t, []string{"program is empty"}, CheckSyntax("  \n"))
+	require.Equal(t, []string{"unclosed '('"}, CheckSyntax("data('cpu').publish("))
+	require.Equal(t, []string{"line 2: unexpected ']'"}, CheckSyntax("A = data('cpu')\nA].publish()"))
+	require.Equal(t, []string{"line 1: unterminated string", "unclosed '('"}, CheckSyntax("data('cpu).publish()\n"))
+	require.Equal(t, []string{"program does not publish any streams"}, CheckSyntax("data('cpu').mean()"))
+}
+
+func TestMetricNames(t *testing.T) {
+	program := "A = data('cpu.utilization').mean()\nB = data(metric=\"memory.used\", filter=filter('host', 'a'))\nC = data( 'cpu.utilization' )\n(A/B).publish()"
+	require.Equal(t, []string{"cpu.utilization", "memory.used"}, MetricNames(program))
+	require.Empty(t, MetricNames("detector('abc').publish()"))
+}
@@ -0,0 +1,452 @@
+// Package testing provides a mock SignalFlow server for unit testing code that
+// consumes SignalFlow without a real SignalFx endpoint.  Since its name
+// clashes with the standard library, import it under an alias, e.g.
+//
+//	import sftesting "github.com/adampetrovic/signalfx-go/signalflow/testing"
+package testing
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/idtool"
+	"github.com/adampetrovic/signalfx-go/signalflow/messages"
+	"github.com/gorilla/websocket"
+)
+
+// Compression is only used if the client asks for it
+var upgrader = websocket.Upgrader{EnableCompression: true}
+
+// TSIDValue is a datapoint for a time series in a data message.
+type TSIDValue struct {
+	TSID idtool.ID
+	Val  float64
+}
+
+// MockServer speaks the SignalFlow WebSocket protocol.  When a client
+// executes a program it replies with the stream start, job start, resolution
+// and metadata messages a real backend would.  Data for the program's time
+// series is sent every second from the values set with SetTSIDFloatData, and
+// straight away when the test calls PushData.  EndJobs ends every running job.
+type MockServer struct {
+	sync.Mutex
+
+	// If set, clients must authenticate with this token
+	AccessToken string
+
+	conns map[*websocket.Conn]bool
+
+	received       []map[string]interface{}
+	metadataByTSID map[idtool.ID]*messages.MetadataProperties
+	dataByTSID     map[idtool.ID]*float64
+	tsidsByProgram map[string][]idtool.ID
+	programErrors  map[string]string
+	jobsByHandle   map[string]*mockJob
+	server         *httptest.Server
+	handleIdx      int
+}
+
+// mockConn is a client connection.  Messages sent to it are written by a
+// single goroutine, and dropped once the connection is closed.
+type mockConn struct {
+	ctx           context.Context
+	textMsgs      chan string
+	binMsgs       chan []byte
+	authenticated bool
+}
+
+type mockJob struct {
+	program string
+	channel string
+	conn    *mockConn
+	cancel  context.CancelFunc
+}
+
+// NewMockServer starts a mock server that accepts any access token.  Call
+// Stop when done with it.
+func NewMockServer() *MockServer {
+	s := &MockServer{}
+	s.Start()
+	return s
+}
+
+func (s *MockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		panic(err)
+	}
+	s.registerConn(c)
+	defer c.Close()
+
+	conn := &mockConn{
+		ctx:      ctx,
+		textMsgs: make(chan string),
+		binMsgs:  make(chan []byte),
+	}
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		for {
+			var err error
+			select {
+			case m := <-conn.textMsgs:
+				err = c.WriteMessage(websocket.TextMessage, []byte(m))
+			case m := <-conn.binMsgs:
+				err = c.WriteMessage(websocket.BinaryMessage, m)
+			case <-ctx.Done():
+				s.unregisterConn(c)
+				return
+			}
+			if err != nil {
+				log.Printf("Could not write message: %v", err)
+			}
+		}
+	}()
+	// Let the last message sent, such as an auth error, be written before
+	// closing the connection
+	defer func() {
+		cancel()
+		<-writerDone
+	}()
+
+	for {
+		_, message, err := c.ReadMessage()
+		if err != nil {
+			log.Println("read err:", err)
+			break
+		}
+
+		var in map[string]interface{}
+		if err := json.Unmarshal(message, &in); err != nil {
+			log.Println("error unmarshalling: ", err)
+		}
+		s.Lock()
+		s.received = append(s.received, in)
+		s.Unlock()
+
+		err = s.handleMessage(conn, in)
+		if err != nil {
+			log.Printf("Error handling mock server message, closing connection: %v", err)
+			return
+		}
+	}
+}
+
+func (s *MockServer) registerConn(conn *websocket.Conn) {
+	s.Lock()
+	s.conns[conn] = true
+	s.Unlock()
+}
+
+func (s *MockServer) unregisterConn(conn *websocket.Conn) {
+	s.Lock()
+	delete(s.conns, conn)
+	s.Unlock()
+}
+
+func (s *MockServer) handleMessage(conn *mockConn, message map[string]interface{}) error {
+	s.Lock()
+	defer s.Unlock()
+
+	typ, ok := message["type"].(string)
+	if !ok {
+		conn.sendText(`{"type": "error"}`)
+		return nil
+	}
+
+	switch typ {
+	case "authenticate":
+		token, _ := message["token"].(string)
+		if s.AccessToken == "" || token == s.AccessToken {
+			conn.sendText(`{"type": "authenticated"}`)
+			conn.authenticated = true
+		} else {
+			conn.sendText(`{"type": "error", "message": "Invalid auth token"}`)
+			return errors.New("bad auth token")
+		}
+	case "stop":
+		handle, _ := message["handle"].(string)
+		if job := s.jobsByHandle[handle]; job != nil {
+			job.cancel()
+		}
+	case "execute":
+		if !conn.authenticated {
+			return errors.New("not authenticated")
+		}
+		s.execute(conn, message)
+	}
+	return nil
+}
+
+func (s *MockServer) execute(conn *mockConn, message map[string]interface{}) {
+	program, _ := message["program"].(string)
+	ch, _ := message["channel"].(string)
+
+	if errMsg := s.programErrors[program]; errMsg != "" {
+		conn.sendText(fmt.Sprintf(`{"type": "error", "message": "%s"}`, errMsg))
+	}
+
+	resMs, _ := message["resolution"].(float64)
+	if resMs == 0 {
+		resMs = 1000
+	}
+
+	programTSIDs := s.tsidsByProgram[program]
+
+	handle := fmt.Sprintf("handle-%d", s.handleIdx)
+	s.handleIdx++
+
+	execCtx, cancel := context.WithCancel(conn.ctx)
+	s.jobsByHandle[handle] = &mockJob{
+		program: program,
+		channel: ch,
+		conn:    conn,
+		cancel:  cancel,
+	}
+
+	log.Printf("Executing SignalFlow program %s with tsids %v and handle %s", program, programTSIDs, handle)
+
+	conn.sendText(fmt.Sprintf(`{"type": "control-message", "channel": "%s", "event": "STREAM_START"}`, ch))
+	conn.sendText(fmt.Sprintf(`{"type": "control-message", "channel": "%s", "event": "JOB_START", "handle": "%s"}`, ch, handle))
+	conn.sendText(fmt.Sprintf(`{"type": "message", "channel": "%s", "logicalTimestampMs": 1464736034000, "message": {"contents": {"resolutionMs" : %d}, "messageCode": "JOB_RUNNING_RESOLUTION", "timestampMs": 1464736033000}}`, ch, int64(resMs)))
+	for _, tsid := range programTSIDs {
+		if md := s.metadataByTSID[tsid]; md != nil {
+			propJSON, err := json.Marshal(md)
+			if err != nil {
+				log.Printf("Error serializing metadata to json: %v", err)
+				continue
+			}
+			conn.sendText(fmt.Sprintf(`{"type": "metadata", "tsId": "%s", "channel": "%s", "properties": %s}`, tsid, ch, propJSON))
+		}
+	}
+	// Send data periodically until the job is stopped or ended, or the
+	// connection is closed.
+	go func() {
+		t := time.NewTicker(1 * time.Second)
+		defer t.Stop()
+		for {
+			select {
+			case <-execCtx.Done():
+				s.Lock()
+				delete(s.jobsByHandle, handle)
+				s.Unlock()
+				return
+			case <-t.C:
+				s.Lock()
+				if execCtx.Err() == nil {
+					valsWithTSID := []TSIDValue{}
+					for _, tsid := range programTSIDs {
+						if data := s.dataByTSID[tsid]; data != nil {
+							valsWithTSID = append(valsWithTSID, TSIDValue{TSID: tsid, Val: *data})
+						}
+					}
+					conn.sendBinary(DataMessage(ch, valsWithTSID))
+				}
+				s.Unlock()
+			}
+		}
+	}()
+}
+
+func (c *mockConn) sendText(m string) {
+	select {
+	case c.textMsgs <- m:
+	case <-c.ctx.Done():
+	}
+}
+
+func (c *mockConn) sendBinary(m []byte) {
+	select {
+	case c.binMsgs <- m:
+	case <-c.ctx.Done():
+	}
+}
+
+// DataMessage encodes a binary data message for channel, timestamped now,
+// with a double value for each time series in valsWithTSID.
+func DataMessage(channel string, valsWithTSID []TSIDValue) []byte {
+	var ch [16]byte
+	copy(ch[:], channel)
+	header := messages.BinaryMessageHeader{
+		Version:     1,
+		MessageType: 5,
+		Flags:       0,
+		Reserved:    0,
+		Channel:     ch,
+	}
+	w := new(bytes.Buffer)
+	binary.Write(w, binary.BigEndian, &header)
+
+	dataHeader := messages.DataMessageHeader{
+		TimestampMillis: uint64(time.Now().UnixNano() / int64(time.Millisecond)),
+		ElementCount:    uint32(len(valsWithTSID)),
+	}
+	binary.Write(w, binary.BigEndian, &dataHeader)
+
+	for i := range valsWithTSID {
+		var valBytes [8]byte
+		buf := new(bytes.Buffer)
+		binary.Write(buf, binary.BigEndian, valsWithTSID[i].Val)
+		copy(valBytes[:], buf.Bytes())
+
+		payload := messages.DataPayload{
+			Type: messages.ValTypeDouble,
+			TSID: valsWithTSID[i].TSID,
+			Val:  valBytes,
+		}
+
+		binary.Write(w, binary.BigEndian, &payload)
+	}
+
+	return w.Bytes()
+}
+
+// Start starts the server on a new port.  NewMockServer calls it, so it is
+// only needed for a MockServer made some other way.
+func (s *MockServer) Start() {
+	s.metadataByTSID = map[idtool.ID]*messages.MetadataProperties{}
+	s.dataByTSID = map[idtool.ID]*float64{}
+	s.tsidsByProgram = map[string][]idtool.ID{}
+	s.programErrors = map[string]string{}
+	s.jobsByHandle = map[string]*mockJob{}
+	s.conns = map[*websocket.Conn]bool{}
+	s.server = httptest.NewServer(s)
+}
+
+// Stop disconnects all clients and stops the server.
+func (s *MockServer) Stop() {
+	s.KillExistingConnections()
+	s.server.Close()
+}
+
+// Restart starts a stopped server again on the same port, so that clients
+// can reconnect to it.
+func (s *MockServer) Restart() {
+	l, err := net.Listen("tcp", s.server.Listener.Addr().String())
+	if err != nil {
+		panic("Could not relisten: " + err.Error())
+	}
+	s.server = httptest.NewUnstartedServer(s)
+	s.server.Listener = l
+	s.server.Start()
+}
+
+// AddProgramError makes executing program send an error message with
+// errorMsg.
+func (s *MockServer) AddProgramError(program string, errorMsg string) {
+	s.Lock()
+	s.programErrors[program] = errorMsg
+	s.Unlock()
+}
+
+// AddProgramTSIDs sets the time series that a program matches.  Their
+// metadata is sent when the program is executed, and only their data is sent
+// to jobs running the program.
+func (s *MockServer) AddProgramTSIDs(program string, tsids []idtool.ID) {
+	s.Lock()
+	s.tsidsByProgram[program] = tsids
+	s.Unlock()
+}
+
+// AddTSIDMetadata sets the metadata sent for a time series.
+func (s *MockServer) AddTSIDMetadata(tsid idtool.ID, props *messages.MetadataProperties) {
+	s.Lock()
+	s.metadataByTSID[tsid] = props
+	s.Unlock()
+}
+
+// SetTSIDFloatData sets the value sent for a time series every second.
+func (s *MockServer) SetTSIDFloatData(tsid idtool.ID, val float64) {
+	s.Lock()
+	s.dataByTSID[tsid] = &val
+	s.Unlock()
+}
+
+// RemoveTSIDData stops sending a value for a time series every second.
+func (s *MockServer) RemoveTSIDData(tsid idtool.ID) {
+	s.Lock()
+	delete(s.dataByTSID, tsid)
+	s.Unlock()
+}
+
+// PushData sends a data message with a single datapoint, timestamped now, to
+// every running job whose program matches tsid.
+func (s *MockServer) PushData(tsid idtool.ID, value float64) {
+	s.Lock()
+	defer s.Unlock()
+	for _, job := range s.jobsByHandle {
+		for _, id := range s.tsidsByProgram[job.program] {
+			if id == tsid {
+				job.conn.sendBinary(DataMessage(job.channel, []TSIDValue{{TSID: tsid, Val: value}}))
+				break
+			}
+		}
+	}
+}
+
+// EndJobs sends END_OF_CHANNEL to every running job and stops them.
+func (s *MockServer) EndJobs() {
+	s.Lock()
+	defer s.Unlock()
+	for handle, job := range s.jobsByHandle {
+		job.conn.sendText(fmt.Sprintf(`{"type": "control-message", "channel": "%s", "event": "END_OF_CHANNEL"}`, job.channel))
+		job.cancel()
+		delete(s.jobsByHandle, handle)
+	}
+}
+
+// URL is the stream URL to give to signalflow.StreamURL.
+func (s *MockServer) URL() string {
+	return strings.Replace(s.server.URL, "http", "ws", 1)
+}
+
+// KillExistingConnections closes every client connection, leaving the server
+// running so that clients can reconnect.
+func (s *MockServer) KillExistingConnections() {
+	s.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.Unlock()
+}
+
+// Received returns every request the server has received, decoded from JSON.
+func (s *MockServer) Received() []map[string]interface{} {
+	s.Lock()
+	defer s.Unlock()
+	return append([]map[string]interface{}{}, s.received...)
+}
+
+// RunningJobs returns how many jobs are currently running.
+func (s *MockServer) RunningJobs() int {
+	s.Lock()
+	defer s.Unlock()
+	return len(s.jobsByHandle)
+}
+
+// RunningJobsForProgram returns how many currently executing jobs there are
+// for a particular program text.
+func (s *MockServer) RunningJobsForProgram(program string) int {
+	s.Lock()
+	defer s.Unlock()
+	n := 0
+	for _, job := range s.jobsByHandle {
+		if job.program == program {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,120 @@
+package testing_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/idtool"
+	"github.com/adampetrovic/signalfx-go/signalflow/messages"
+	sftesting "github.com/adampetrovic/signalfx-go/signalflow/testing"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+func dial(t *testing.T, server *sftesting.MockServer) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial(server.URL(), nil)
+	require.Nil(t, err)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	return conn
+}
+
+func readJSON(t *testing.T, conn *websocket.Conn) map[string]interface{} {
+	typ, msg, err := conn.ReadMessage()
+	require.Nil(t, err)
+	require.Equal(t, websocket.TextMessage, typ)
+
+	var out map[string]interface{}
+	require.Nil(t, json.Unmarshal(msg, &out))
+	return out
+}
+
+func readData(t *testing.T, conn *websocket.Conn) *messages.DataMessage {
+	typ, msg, err := conn.ReadMessage()
+	require.Nil(t, err)
+	require.Equal(t, websocket.BinaryMessage, typ)
+
+	parsed, err := messages.ParseMessage(msg, false)
+	require.Nil(t, err)
+	return parsed.(*messages.DataMessage)
+}
+
+func TestMockServer(t *testing.T) {
+	server := sftesting.NewMockServer()
+	defer server.Stop()
+	server.AccessToken = "abcd"
+
+	program := "data('cpu.utilization').publish()"
+	server.AddProgramTSIDs(program, []idtool.ID{4000, 4001})
+	server.AddTSIDMetadata(idtool.ID(4000), &messages.MetadataProperties{Metric: "cpu.utilization"})
+
+	conn := dial(t, server)
+	defer conn.Close()
+
+	require.Nil(t, conn.WriteJSON(map[string]interface{}{"type": "authenticate", "token": "abcd"}))
+	require.Equal(t, "authenticated", readJSON(t, conn)["type"])
+
+	require.Nil(t, conn.WriteJSON(map[string]interface{}{"type": "execute", "program": program, "channel": "ch-1", "resolution": 60000}))
+	require.Equal(t, "STREAM_START", readJSON(t, conn)["event"])
+	require.Equal(t, "handle-0", readJSON(t, conn)["handle"])
+	res := readJSON(t, conn)["message"].(map[string]interface{})
+	require.Equal(t, 60000.0, res["contents"].(map[string]interface{})["resolutionMs"])
+
+	md := readJSON(t, conn)
+	require.Equal(t, "metadata", md["type"])
+	require.Equal(t, idtool.ID(4000).String(), md["tsId"])
+	require.Equal(t, 1, server.RunningJobsForProgram(program))
+
+	server.PushData(idtool.ID(4001), 42)
+	data := readData(t, conn)
+	require.Equal(t, "ch-1", data.Channel())
+	require.Len(t, data.Payloads, 1)
+	require.Equal(t, idtool.ID(4001), data.Payloads[0].TSID)
+	require.Equal(t, 42.0, data.Payloads[0].Float64())
+
+	// Time series that the program doesn't match aren't sent
+	server.PushData(idtool.ID(5000), 1)
+
+	server.EndJobs()
+	eoc := readJSON(t, conn)
+	require.Equal(t, "ch-1", eoc["channel"])
+	require.Equal(t, "END_OF_CHANNEL", eoc["event"])
+	require.Equal(t, 0, server.RunningJobs())
+
+	received := server.Received()
+	require.Len(t, received, 2)
+	require.Equal(t, "execute", received[1]["type"])
+}
+
+func TestMockServerBadToken(t *testing.T) {
+	server := sftesting.NewMockServer()
+	defer server.Stop()
+	server.AccessToken = "abcd"
+
+	conn := dial(t, server)
+	defer conn.Close()
+
+	require.Nil(t, conn.WriteJSON(map[string]interface{}{"type": "authenticate", "token": "nope"}))
+	require.Equal(t, "error", readJSON(t, conn)["type"])
+	_, _, err := conn.ReadMessage()
+	require.Error(t, err, "Connection should be closed after a bad token")
+}
+
+func TestMockServerStop(t *testing.T) {
+	server := sftesting.NewMockServer()
+	defer server.Stop()
+
+	conn := dial(t, server)
+	defer conn.Close()
+
+	require.Nil(t, conn.WriteJSON(map[string]interface{}{"type": "authenticate"}))
+	readJSON(t, conn)
+	require.Nil(t, conn.WriteJSON(map[string]interface{}{"type": "execute", "program": "p", "channel": "ch-1"}))
+	for i := 0; i < 3; i++ {
+		readJSON(t, conn)
+	}
+	require.Equal(t, 1, server.RunningJobs())
+
+	require.Nil(t, conn.WriteJSON(map[string]interface{}{"type": "stop", "handle": "handle-0"}))
+	require.Eventually(t, func() bool { return server.RunningJobs() == 0 }, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,78 @@
+package signalflow
+
+import (
+	"sort"
+
+	"github.com/adampetrovic/signalfx-go/idtool"
+	"github.com/adampetrovic/signalfx-go/signalflow/messages"
+)
+
+// Topology describes how the time series of a computation relate to each
+// other, keyed by TSID.
+type Topology map[idtool.ID]*TopologyNode
+
+// TopologyNode is a single time series in a Topology.
+type TopologyNode struct {
+	TSID       idtool.ID         `json:"tsId"`
+	Dimensions map[string]string `json:"dimensions"`
+	// The time series this one is computed from
+	DependsOn []idtool.ID `json:"dependsOn"`
+}
+
+// TopologyFromMetadata derives a Topology from the metadata of a
+// computation's time series.  SignalFlow metadata doesn't say which series an
+// aggregate was computed from, so a series is taken to depend on the series
+// of the same metric whose dimensions are the closest strict superset of its
+// own, e.g. a sum by service depends on the per-host series of each service.
+func TopologyFromMetadata(meta map[idtool.ID]*messages.MetadataProperties) Topology {
+	top := make(Topology, len(meta))
+	metrics := make(map[idtool.ID]string, len(meta))
+	for tsid, props := range meta {
+		top[tsid] = &TopologyNode{
+			TSID:       tsid,
+			Dimensions: props.Dimensions(),
+			DependsOn:  []idtool.ID{},
+		}
+		metrics[tsid] = props.OriginatingMetric
+		if metrics[tsid] == "" {
+			metrics[tsid] = props.Metric
+		}
+	}
+
+	for tsid, node := range top {
+		var supersets []*TopologyNode
+		for other, candidate := range top {
+			if other != tsid && metrics[other] == metrics[tsid] && isStrictSubset(node.Dimensions, candidate.Dimensions) {
+				supersets = append(supersets, candidate)
+			}
+		}
+
+		// Only keep the closest supersets, so that the graph has an edge to
+		// an intermediate aggregate rather than to everything below it too.
+	outer:
+		for _, candidate := range supersets {
+			for _, between := range supersets {
+				if isStrictSubset(between.Dimensions, candidate.Dimensions) {
+					continue outer
+				}
+			}
+			node.DependsOn = append(node.DependsOn, candidate.TSID)
+		}
+		sort.Slice(node.DependsOn, func(i, j int) bool { return node.DependsOn[i] < node.DependsOn[j] })
+	}
+	return top
+}
+
+// isStrictSubset reports whether every dimension in a has the same value in
+// b, and b has more dimensions than a.
+func isStrictSubset(a, b map[string]string) bool {
+	if len(a) >= len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,33 @@
+package signalflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/adampetrovic/signalfx-go/idtool"
+	"github.com/adampetrovic/signalfx-go/signalflow/messages"
+)
+
+func TestTopologyFromMetadata(t *testing.T) {
+	props := func(metric string, dims map[string]string) *messages.MetadataProperties {
+		return &messages.MetadataProperties{OriginatingMetric: metric, CustomProperties: dims}
+	}
+	top := TopologyFromMetadata(map[idtool.ID]*messages.MetadataProperties{
+		1: props("cpu", map[string]string{"service": "web", "host": "a"}),
+		2: props("cpu", map[string]string{"service": "web", "host": "b"}),
+		3: props("cpu", map[string]string{"service": "db", "host": "c"}),
+		4: props("cpu", map[string]string{"service": "web"}),
+		5: props("cpu", map[string]string{"service": "db"}),
+		6: props("cpu", map[string]string{}),
+		7: props("mem", map[string]string{"service": "web", "host": "a"}),
+	})
+
+	require.Len(t, top, 7)
+	require.Equal(t, map[string]string{"service": "web"}, top[4].Dimensions)
+	require.Equal(t, []idtool.ID{}, top[1].DependsOn)
+	require.Equal(t, []idtool.ID{1, 2}, top[4].DependsOn)
+	require.Equal(t, []idtool.ID{3}, top[5].DependsOn)
+	require.Equal(t, []idtool.ID{4, 5}, top[6].DependsOn, "Should only depend on the closest aggregates")
+	require.Equal(t, []idtool.ID{}, top[7].DependsOn, "Other metrics should be unrelated")
+}
@@ -0,0 +1,28 @@
+package signalflow
+
+// UsageWarningThreshold is the fraction of the computation limit above which
+// UsageInfo.NearLimit reports true.
+const UsageWarningThreshold = 0.9
+
+// UsageInfo describes how much of the organization's SignalFlow capacity is
+// in use.
+type UsageInfo struct {
+	// The number of computations currently running
+	ActiveComputations int `json:"activeComputations"`
+	// The number of computations that can run at once
+	MaxAllowed int `json:"maxAllowed"`
+	// The number of metric time series the running computations are using
+	ActiveMTSCount int `json:"activeMTSCount"`
+	// The number of metric time series computations can use at once
+	MaxMTSAllowed int `json:"maxMTSAllowed"`
+}
+
+// NearLimit reports whether starting more computations is likely to be
+// throttled, i.e. the active computations or MTS are at or above
+// UsageWarningThreshold of their limits.
+func (u *UsageInfo) NearLimit() bool {
+	near := func(active, max int) bool {
+		return max > 0 && float64(active) >= float64(max)*UsageWarningThreshold
+	}
+	return near(u.ActiveComputations, u.MaxAllowed) || near(u.ActiveMTSCount, u.MaxMTSAllowed)
+}
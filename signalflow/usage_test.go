@@ -0,0 +1,14 @@
+package signalflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsageInfoNearLimit(t *testing.T) {
+	assert.False(t, (&UsageInfo{ActiveComputations: 89, MaxAllowed: 100}).NearLimit())
+	assert.True(t, (&UsageInfo{ActiveComputations: 90, MaxAllowed: 100}).NearLimit())
+	assert.True(t, (&UsageInfo{ActiveMTSCount: 9500, MaxMTSAllowed: 10000}).NearLimit())
+	assert.False(t, (&UsageInfo{ActiveComputations: 5}).NearLimit(), "Unknown limits should not be near")
+}
@@ -0,0 +1,144 @@
+package signalflow
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/idtool"
+	"github.com/adampetrovic/signalfx-go/signalflow/messages"
+)
+
+// AggregateFunc reduces the values of a time series within a window to a
+// single value.  It is never called with an empty slice.
+type AggregateFunc func(values []float64) float64
+
+// Mean is the arithmetic mean of the values.
+func Mean(values []float64) float64 {
+	return Sum(values) / float64(len(values))
+}
+
+// Sum is the sum of the values.
+func Sum(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum
+}
+
+// Min is the smallest of the values.
+func Min(values []float64) float64 {
+	min := values[0]
+	for _, v := range values[1:] {
+		min = math.Min(min, v)
+	}
+	return min
+}
+
+// Max is the largest of the values.
+func Max(values []float64) float64 {
+	max := values[0]
+	for _, v := range values[1:] {
+		max = math.Max(max, v)
+	}
+	return max
+}
+
+// StdDev is the population standard deviation of the values.
+func StdDev(values []float64) float64 {
+	mean := Mean(values)
+	var sq float64
+	for _, v := range values {
+		sq += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(sq / float64(len(values)))
+}
+
+type windowSample struct {
+	timestamp time.Time
+	value     float64
+}
+
+// WindowAggregator computes rolling aggregations of the time series in a
+// stream of data messages.  The window is measured back from the latest
+// message's logical timestamp rather than the wall clock, so that delayed or
+// replayed data is aggregated the same way as live data.  It is safe for
+// concurrent use.
+type WindowAggregator struct {
+	window time.Duration
+	fn     AggregateFunc
+
+	lock    sync.RWMutex
+	latest  time.Time
+	samples map[idtool.ID][]windowSample
+}
+
+// NewWindowAggregator makes an aggregator that applies fn to the values of
+// each time series within window of the latest data message.
+func NewWindowAggregator(window time.Duration, fn AggregateFunc) *WindowAggregator {
+	return &WindowAggregator{
+		window:  window,
+		fn:      fn,
+		samples: make(map[idtool.ID][]windowSample),
+	}
+}
+
+// Add the values in a data message to the window, dropping any that are now
+// older than the window.  Messages that are already older than the window, and
+// payloads without a value, are ignored.
+func (a *WindowAggregator) Add(msg *messages.DataMessage) {
+	ts := msg.Timestamp()
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if !a.latest.IsZero() && !ts.After(a.latest.Add(-a.window)) {
+		return
+	}
+
+	msg.ForEach(func(tsid idtool.ID, value float64) {
+		a.samples[tsid] = append(a.samples[tsid], windowSample{timestamp: ts, value: value})
+	})
+
+	if ts.After(a.latest) {
+		a.latest = ts
+		a.evict()
+	}
+}
+
+// evict drops samples at or before the start of the window, and time series
+// that have no samples left.
+func (a *WindowAggregator) evict() {
+	start := a.latest.Add(-a.window)
+	for tsid, samples := range a.samples {
+		keep := samples[:0]
+		for _, s := range samples {
+			if s.timestamp.After(start) {
+				keep = append(keep, s)
+			}
+		}
+		if len(keep) == 0 {
+			delete(a.samples, tsid)
+			continue
+		}
+		a.samples[tsid] = keep
+	}
+}
+
+// Aggregate returns the aggregated value of each time series that has data
+// within the window.
+func (a *WindowAggregator) Aggregate() map[idtool.ID]float64 {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	out := make(map[idtool.ID]float64, len(a.samples))
+	for tsid, samples := range a.samples {
+		values := make([]float64, len(samples))
+		for i, s := range samples {
+			values[i] = s.value
+		}
+		out[tsid] = a.fn(values)
+	}
+	return out
+}
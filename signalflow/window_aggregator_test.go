@@ -0,0 +1,84 @@
+package signalflow
+
+import (
+	"encoding/binary"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/idtool"
+	"github.com/adampetrovic/signalfx-go/signalflow/messages"
+	"github.com/stretchr/testify/require"
+)
+
+func doublePayload(tsid idtool.ID, v float64) messages.DataPayload {
+	pl := messages.DataPayload{Type: messages.ValTypeDouble, TSID: tsid}
+	binary.BigEndian.PutUint64(pl.Val[:], math.Float64bits(v))
+	return pl
+}
+
+func dataMessageAt(ms uint64, payloads ...messages.DataPayload) *messages.DataMessage {
+	return &messages.DataMessage{
+		TimestampedMessage: messages.TimestampedMessage{TimestampMillis: ms},
+		Payloads:           payloads,
+	}
+}
+
+func TestAggregateFuncs(t *testing.T) {
+	values := []float64{2, 4, 4, 4, 5, 5, 7, 9}
+	require.Equal(t, 5.0, Mean(values))
+	require.Equal(t, 40.0, Sum(values))
+	require.Equal(t, 2.0, Min(values))
+	require.Equal(t, 9.0, Max(values))
+	require.Equal(t, 2.0, StdDev(values))
+	require.Equal(t, 0.0, StdDev([]float64{3}))
+}
+
+func TestWindowAggregator(t *testing.T) {
+	agg := NewWindowAggregator(3*time.Second, Mean)
+	require.Empty(t, agg.Aggregate())
+
+	agg.Add(dataMessageAt(1000, doublePayload(1, 10), doublePayload(2, 100)))
+	agg.Add(dataMessageAt(2000, doublePayload(1, 20)))
+	agg.Add(dataMessageAt(3000, doublePayload(1, 30)))
+	require.Equal(t, map[idtool.ID]float64{1: 20, 2: 100}, agg.Aggregate())
+
+	agg.Add(dataMessageAt(4000, doublePayload(1, 60)))
+	require.Equal(t, map[idtool.ID]float64{1: 110.0 / 3}, agg.Aggregate(), "Samples at the start of the window should be dropped")
+
+	// Late data is kept while it falls within the window.
+	agg.Add(dataMessageAt(2500, doublePayload(2, 50)))
+	require.Equal(t, 50.0, agg.Aggregate()[2])
+}
+
+func TestWindowAggregatorValueTypes(t *testing.T) {
+	agg := NewWindowAggregator(time.Hour, Sum)
+	long := messages.DataPayload{Type: messages.ValTypeLong, TSID: 1}
+	binary.BigEndian.PutUint64(long.Val[:], 5)
+	unknown := messages.DataPayload{Type: 99, TSID: 2}
+	agg.Add(dataMessageAt(1000, long, doublePayload(1, 1.5), unknown))
+	require.Equal(t, map[idtool.ID]float64{1: 6.5}, agg.Aggregate())
+}
+
+func TestWindowAggregatorConcurrent(t *testing.T) {
+	agg := NewWindowAggregator(time.Hour, Max)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			agg.Add(dataMessageAt(uint64(1000+i), doublePayload(1, float64(i))))
+			agg.Aggregate()
+		}(i)
+	}
+	wg.Wait()
+	require.Equal(t, 9.0, agg.Aggregate()[1])
+}
+
+func TestWindowAggregatorIgnoresStaleMessages(t *testing.T) {
+	agg := NewWindowAggregator(time.Second, Sum)
+	agg.Add(dataMessageAt(5000, doublePayload(1, 1)))
+	agg.Add(dataMessageAt(3000, doublePayload(1, 100)))
+	require.Equal(t, map[idtool.ID]float64{1: 1}, agg.Aggregate())
+}
@@ -0,0 +1,100 @@
+package signalfx
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/idtool"
+	"github.com/adampetrovic/signalfx-go/signalflow"
+	"github.com/adampetrovic/signalfx-go/signalflow/messages"
+)
+
+// SignalFlowTopologyAPIURL is the URL for getting the time series graph of a
+// SignalFlow program.
+const SignalFlowTopologyAPIURL = "/v2/signalflow/topology"
+
+// GetSignalFlowTopology returns the time series of program at time at and
+// which series each is computed from.  It asks the topology API first.  If
+// that isn't available, it runs the program at that time and derives the
+// topology from the metadata of the published series with
+// signalflow.TopologyFromMetadata.
+func (c *Client) GetSignalFlowTopology(ctx context.Context, program string, at time.Time) (*signalflow.Topology, error) {
+	params := url.Values{}
+	params.Add("at", strconv.FormatInt(at.UnixNano()/int64(time.Millisecond), 10))
+
+	resp, err := c.doRequestWithContext(ctx, "POST", SignalFlowTopologyAPIURL, params, strings.NewReader(program))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var found struct {
+			Nodes []*signalflow.TopologyNode `json:"nodes"`
+		}
+		if err := c.decodeJSON(resp.Body, &found); err != nil {
+			return nil, err
+		}
+		top := make(signalflow.Topology, len(found.Nodes))
+		for _, node := range found.Nodes {
+			top[node.TSID] = node
+		}
+		return &top, nil
+	case http.StatusNotFound, http.StatusNotImplemented:
+		return c.deriveSignalFlowTopology(ctx, program, at)
+	default:
+		return nil, newAPIError(resp)
+	}
+}
+
+// deriveSignalFlowTopology runs program over the moment at and builds the
+// topology from the metadata of every series it publishes.
+func (c *Client) deriveSignalFlowTopology(ctx context.Context, program string, at time.Time) (*signalflow.Topology, error) {
+	sf, err := c.signalFlowExecutor()
+	if err != nil {
+		return nil, err
+	}
+	defer sf.Close()
+
+	comp, err := sf.Execute(&signalflow.ExecuteRequest{
+		Program:   program,
+		Start:     at,
+		Stop:      at.Add(time.Millisecond),
+		Immediate: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	meta := map[idtool.ID]*messages.MetadataProperties{}
+	for {
+		select {
+		case <-ctx.Done():
+			comp.Stop()
+			return nil, ctx.Err()
+		case msg, ok := <-comp.Data():
+			if !ok {
+				if err := comp.Err(); err != nil {
+					return nil, err
+				}
+				top := signalflow.TopologyFromMetadata(meta)
+				return &top, nil
+			}
+			for _, pl := range msg.Payloads {
+				if _, ok := meta[pl.TSID]; ok {
+					continue
+				}
+				props := comp.TSIDMetadata(pl.TSID)
+				if props == nil {
+					props = &messages.MetadataProperties{}
+				}
+				meta[pl.TSID] = props
+			}
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package signalfx
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/idtool"
+	"github.com/adampetrovic/signalfx-go/signalflow"
+	"github.com/adampetrovic/signalfx-go/signalflow/messages"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSignalFlowTopology(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	at := time.Unix(1557936000, 0)
+	params := url.Values{}
+	params.Add("at", "1557936000000")
+	mux.HandleFunc("/v2/signalflow/topology", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.Equal(t, "data('cpu').sum().publish()", string(body), "Program does not match")
+		verifyRequest(t, "POST", http.StatusOK, params, "signalflow/topology_success.json")(w, r)
+	})
+
+	top, err := client.GetSignalFlowTopology(context.Background(), "data('cpu').sum().publish()", at)
+	assert.NoError(t, err, "Unexpected error getting topology")
+	if assert.Len(t, *top, 2) {
+		node := (*top)[idtool.IDFromString("AAAAAAAAAAE")]
+		if assert.NotNil(t, node) {
+			assert.Equal(t, map[string]string{"host": "a"}, node.Dimensions)
+		}
+		node = (*top)[idtool.IDFromString("AAAAAAAAAAI")]
+		if assert.NotNil(t, node) {
+			assert.Equal(t, []idtool.ID{idtool.IDFromString("AAAAAAAAAAE")}, node.DependsOn)
+		}
+	}
+}
+
+func TestGetSignalFlowTopologyFallback(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/signalflow/topology", verifyRequest(t, "POST", http.StatusNotFound, nil, ""))
+
+	fake := signalflow.NewFakeClient()
+	fake.MetadataTimeout = 100 * time.Millisecond
+	client.signalFlowExecutor = func() (signalflow.Executor, error) {
+		return fake, nil
+	}
+	fake.InjectMessage("ch-1", &messages.MetadataMessage{TSID: idtool.ID(1), Properties: messages.MetadataProperties{
+		Metric:           "cpu",
+		CustomProperties: map[string]string{"host": "a", "service": "web"},
+	}})
+	fake.InjectMessage("ch-1", &messages.MetadataMessage{TSID: idtool.ID(2), Properties: messages.MetadataProperties{
+		Metric:           "cpu",
+		CustomProperties: map[string]string{"service": "web"},
+	}})
+	fake.InjectMessage("ch-1", &messages.DataMessage{
+		TimestampedMessage: messages.TimestampedMessage{TimestampMillis: 1557936000000},
+		Payloads: []messages.DataPayload{
+			{Type: messages.ValTypeLong, TSID: idtool.ID(1)},
+			{Type: messages.ValTypeLong, TSID: idtool.ID(2)},
+		},
+	})
+	fake.InjectMessage("ch-1", &messages.BaseControlMessage{Event: messages.EndOfChannelEvent})
+
+	at := time.Unix(1557936000, 0)
+	top, err := client.GetSignalFlowTopology(context.Background(), "data('cpu').sum(by=['service']).publish()", at)
+	assert.NoError(t, err, "Unexpected error deriving topology")
+	if assert.Len(t, *top, 2) {
+		assert.Equal(t, []idtool.ID{1}, (*top)[2].DependsOn)
+	}
+
+	executions := fake.RecordedExecutions()
+	if assert.Len(t, executions, 1) {
+		assert.Equal(t, at, executions[0].Start)
+	}
+}
+
+func TestGetSignalFlowTopologyError(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/signalflow/topology", verifyRequest(t, "POST", http.StatusBadRequest, nil, ""))
+
+	top, err := client.GetSignalFlowTopology(context.Background(), "dat('cpu')", time.Now())
+	assert.Error(t, err, "Should have gotten an error from a bad program")
+	assert.Nil(t, top)
+}
@@ -0,0 +1,237 @@
+package signalfx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/idtool"
+	"github.com/adampetrovic/signalfx-go/organization"
+	"github.com/adampetrovic/signalfx-go/signalflow"
+	"github.com/adampetrovic/signalfx-go/signalflow/messages"
+	"github.com/signalfx/golib/v3/datapoint"
+)
+
+// BackfillAPIURL is the ingest URL for sending historical datapoints.
+const BackfillAPIURL = "/v1/backfill"
+
+// TransformMetricPrefix is prepended to the metric name of the data
+// ApplyTransform uploads, so that the uploaded inputs are kept apart from the
+// org's own time series and charts and detectors on the original metric don't
+// see them.
+const TransformMetricPrefix = "sfx_go_transform."
+
+// The resolution used to count the backfilled datapoints that are visible
+const transformPollResolution = time.Second
+
+// ApplyTransform runs a SignalFlow program over `inputs` and returns the
+// datapoints it publishes.  The program refers to the inputs as the stream
+// `input`, e.g. `input.mean(over='1h').publish()`.  All inputs must be non-nil
+// and have the same metric name and a finite numeric value.
+//
+// SignalFx has no way to run a program over data that hasn't been ingested,
+// so the inputs are backfilled under their metric name with
+// TransformMetricPrefix prepended, e.g. `sfx_go_transform.cpu.utilization`.
+// The backfilled data persists like any other data and its time series count
+// towards the org's MTS limits, but calling this again with the same time
+// series reuses them rather than creating new ones.  Backfilled data takes a
+// while to become visible, so the program is only run once it is, going by
+// polls every PollInterval.  Calls running at the same time with inputs for
+// the same time series and timestamps overwrite each other's values.
+func (c *Client) ApplyTransform(ctx context.Context, program string, inputs []*datapoint.Datapoint) ([]*datapoint.Datapoint, error) {
+	if len(inputs) == 0 {
+		return nil, errors.New("no input datapoints")
+	}
+	for i, dp := range inputs {
+		if dp == nil {
+			return nil, fmt.Errorf("input datapoint %d is nil", i)
+		}
+	}
+
+	metric := inputs[0].Metric
+	start, end := inputs[0].Timestamp, inputs[0].Timestamp
+	for _, dp := range inputs {
+		if v, ok := dp.Value.(datapoint.FloatValue); ok && (math.IsNaN(v.Float()) || math.IsInf(v.Float(), 0)) {
+			return nil, fmt.Errorf("datapoint %s has non-finite value %v", dp.Metric, v.Float())
+		}
+		if dp.Metric != metric {
+			return nil, fmt.Errorf("all inputs must have the same metric, got %s and %s", metric, dp.Metric)
+		}
+		if dp.Timestamp.Before(start) {
+			start = dp.Timestamp
+		}
+		if dp.Timestamp.After(end) {
+			end = dp.Timestamp
+		}
+	}
+
+	if err := c.backfill(ctx, inputs); err != nil {
+		return nil, err
+	}
+	if err := c.waitForBackfill(ctx, inputs, start, end); err != nil {
+		return nil, err
+	}
+
+	sf, err := c.signalFlowExecutor()
+	if err != nil {
+		return nil, err
+	}
+	defer sf.Close()
+
+	comp, err := sf.Execute(&signalflow.ExecuteRequest{
+		Program:   fmt.Sprintf("input = data(%s)\n%s", strconv.Quote(TransformMetricPrefix+metric), program),
+		Start:     start,
+		Stop:      end.Add(time.Millisecond),
+		Immediate: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := map[idtool.ID]*messages.MetadataMessage{}
+	var out []*datapoint.Datapoint
+	for {
+		select {
+		case <-ctx.Done():
+			comp.Stop()
+			return nil, ctx.Err()
+		case msg, ok := <-comp.Data():
+			if !ok {
+				if err := comp.Err(); err != nil {
+					return nil, err
+				}
+				for _, dp := range out {
+					dp.Metric = strings.TrimPrefix(dp.Metric, TransformMetricPrefix)
+				}
+				return out, nil
+			}
+			for _, pl := range msg.Payloads {
+				if _, ok := metadata[pl.TSID]; !ok {
+					md := &messages.MetadataMessage{TSID: pl.TSID}
+					if props := comp.TSIDMetadata(pl.TSID); props != nil {
+						md.Properties = *props
+					}
+					metadata[pl.TSID] = md
+				}
+			}
+			dps, err := messages.ToDatapoints(msg, metadata)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, dps...)
+		}
+	}
+}
+
+// backfill sends inputs to the backfill endpoint, one request per time
+// series, with TransformMetricPrefix prepended to their metric name.
+func (c *Client) backfill(ctx context.Context, inputs []*datapoint.Datapoint) error {
+	org := &organization.Organization{}
+	if err := c.getJSON(ctx, OrganizationAPIURL, nil, org); err != nil {
+		return fmt.Errorf("could not get organization ID for backfill: %v", err)
+	}
+
+	type series struct {
+		params url.Values
+		body   bytes.Buffer
+	}
+	bySeries := map[string]*series{}
+	var keys []string
+
+	for _, dp := range inputs {
+		var value string
+		switch v := dp.Value.(type) {
+		case datapoint.IntValue:
+			value = strconv.FormatInt(v.Int(), 10)
+		case datapoint.FloatValue:
+			value = strconv.FormatFloat(v.Float(), 'g', -1, 64)
+		default:
+			return fmt.Errorf("datapoint %s has non-numeric value %v", dp.Metric, dp.Value)
+		}
+
+		key := seriesKey(dp)
+		s := bySeries[key]
+		if s == nil {
+			s = &series{params: url.Values{}}
+			s.params.Add("orgid", org.Id)
+			s.params.Add("metric", TransformMetricPrefix+dp.Metric)
+			s.params.Add("metric_type", backfillMetricType(dp.MetricType))
+			for k, v := range dp.Dimensions {
+				s.params.Add("sfxdim_"+k, v)
+			}
+			bySeries[key] = s
+			keys = append(keys, key)
+		}
+		fmt.Fprintf(&s.body, "{\"timestamp\":%d,\"value\":%s}\n", dp.Timestamp.UnixNano()/int64(time.Millisecond), value)
+	}
+
+	for _, key := range keys {
+		s := bySeries[key]
+		resp, err := c.doRequestToBase(ctx, c.ingestURL, "POST", BackfillAPIURL, s.params, &s.body, c.authToken)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+			err = newAPIError(resp)
+		}
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitForBackfill polls until every backfilled input between start and end
+// is visible, counting each time series' datapoints at one timestamp once,
+// since the last one sent replaces the others.
+func (c *Client) waitForBackfill(ctx context.Context, inputs []*datapoint.Datapoint, start, end time.Time) error {
+	unique := map[string]bool{}
+	for _, dp := range inputs {
+		unique[seriesKey(dp)+"\x00"+strconv.FormatInt(dp.Timestamp.UnixNano()/int64(time.Millisecond), 10)] = true
+	}
+
+	query := "sf_metric:" + strconv.Quote(TransformMetricPrefix+inputs[0].Metric)
+	return c.poll(ctx, func() (bool, error) {
+		data, err := c.getTimeSeriesWindow(ctx, query, start, end.Add(time.Millisecond), transformPollResolution, RollupCount)
+		if err != nil {
+			return false, err
+		}
+		visible := 0.0
+		for _, pairs := range data {
+			for _, pair := range pairs {
+				visible += pair[1]
+			}
+		}
+		return visible >= float64(len(unique)), nil
+	})
+}
+
+func backfillMetricType(mt datapoint.MetricType) string {
+	switch mt {
+	case datapoint.Count:
+		return "counter"
+	case datapoint.Counter:
+		return "cumulative_counter"
+	default:
+		return "gauge"
+	}
+}
+
+// seriesKey identifies the time series a datapoint belongs to.
+func seriesKey(dp *datapoint.Datapoint) string {
+	dims := make([]string, 0, len(dp.Dimensions))
+	for k, v := range dp.Dimensions {
+		dims = append(dims, k+"="+v)
+	}
+	sort.Strings(dims)
+	return dp.Metric + "\x00" + strings.Join(dims, "\x00")
+}
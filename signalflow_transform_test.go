@@ -0,0 +1,122 @@
+package signalfx
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/idtool"
+	"github.com/adampetrovic/signalfx-go/signalflow"
+	"github.com/adampetrovic/signalfx-go/signalflow/messages"
+	"github.com/signalfx/golib/v3/datapoint"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyTransform(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/organization", verifyRequest(t, "GET", http.StatusOK, nil, "organization/get_success.json"))
+
+	var backfills []string
+	mux.HandleFunc("/v1/backfill", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, TransformMetricPrefix+"cpu.utilization", q.Get("metric"))
+		assert.Equal(t, "gauge", q.Get("metric_type"))
+		assert.NotEmpty(t, q.Get("orgid"))
+		body, _ := ioutil.ReadAll(r.Body)
+		backfills = append(backfills, q.Get("sfxdim_host")+" "+string(body))
+	})
+
+	// Only two of the three inputs are visible at first
+	polls := 0
+	mux.HandleFunc("/v1/timeserieswindow", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		assert.Equal(t, `sf_metric:"`+TransformMetricPrefix+`cpu.utilization"`, r.URL.Query().Get("query"))
+		assert.Equal(t, "count", r.URL.Query().Get("rollup"))
+		if polls == 1 {
+			w.Write([]byte(`{"data": {"A": [[1557936000000, 1]], "B": [[1557936000000, 1]]}}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"A": [[1557936000000, 1], [1557936060000, 1]], "B": [[1557936000000, 1]]}}`))
+	})
+	client.pollInterval = time.Millisecond
+
+	fake := signalflow.NewFakeClient()
+	fake.MetadataTimeout = 100 * time.Millisecond
+	client.signalFlowExecutor = func() (signalflow.Executor, error) {
+		return fake, nil
+	}
+	fake.InjectMessage("ch-1", &messages.MetadataMessage{TSID: idtool.ID(1), Properties: messages.MetadataProperties{
+		Metric:           TransformMetricPrefix + "cpu.utilization",
+		CustomProperties: map[string]string{"host": "a"},
+	}})
+	var val [8]byte
+	binary.BigEndian.PutUint64(val[:], math.Float64bits(15))
+	fake.InjectMessage("ch-1", &messages.DataMessage{
+		TimestampedMessage: messages.TimestampedMessage{TimestampMillis: 1557936000000},
+		Payloads:           []messages.DataPayload{{Type: messages.ValTypeDouble, TSID: idtool.ID(1), Val: val}},
+	})
+	fake.InjectMessage("ch-1", &messages.BaseControlMessage{Event: messages.EndOfChannelEvent})
+
+	start := time.Unix(1557936000, 0)
+	inputs := []*datapoint.Datapoint{
+		datapoint.New("cpu.utilization", map[string]string{"host": "a"}, datapoint.NewFloatValue(10), datapoint.Gauge, start),
+		datapoint.New("cpu.utilization", map[string]string{"host": "a"}, datapoint.NewIntValue(20), datapoint.Gauge, start.Add(time.Minute)),
+		datapoint.New("cpu.utilization", map[string]string{"host": "b"}, datapoint.NewIntValue(30), datapoint.Gauge, start),
+	}
+	result, err := client.ApplyTransform(context.Background(), "input.mean(by=['host']).publish()", inputs)
+	assert.NoError(t, err, "Unexpected error applying transform")
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, datapoint.NewFloatValue(15), result[0].Value)
+		assert.Equal(t, "cpu.utilization", result[0].Metric, "Metric prefix should be removed")
+		assert.Equal(t, map[string]string{"host": "a"}, result[0].Dimensions)
+	}
+
+	assert.Equal(t, []string{
+		"a {\"timestamp\":1557936000000,\"value\":10}\n{\"timestamp\":1557936060000,\"value\":20}\n",
+		"b {\"timestamp\":1557936000000,\"value\":30}\n",
+	}, backfills, "Should backfill each time series once")
+	assert.Equal(t, 2, polls, "Should wait until all of the inputs are visible")
+
+	executions := fake.RecordedExecutions()
+	if assert.Len(t, executions, 1) {
+		assert.True(t, strings.HasPrefix(executions[0].Program, `input = data("`+TransformMetricPrefix+`cpu.utilization")`))
+		assert.Equal(t, start, executions[0].Start)
+		assert.True(t, executions[0].Stop.After(start.Add(time.Minute)))
+	}
+}
+
+func TestApplyTransformBadInputs(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	_, err := client.ApplyTransform(context.Background(), "input.publish()", nil)
+	assert.Error(t, err, "Should need inputs")
+
+	now := time.Now()
+	_, err = client.ApplyTransform(context.Background(), "input.publish()", []*datapoint.Datapoint{
+		datapoint.New("a", nil, datapoint.NewIntValue(1), datapoint.Gauge, now),
+		datapoint.New("b", nil, datapoint.NewIntValue(1), datapoint.Gauge, now),
+	})
+	assert.Error(t, err, "Should need a single metric")
+
+	_, err = client.ApplyTransform(context.Background(), "input.publish()", []*datapoint.Datapoint{
+		datapoint.New("a", nil, datapoint.NewIntValue(1), datapoint.Gauge, now),
+		nil,
+	})
+	assert.EqualError(t, err, "input datapoint 1 is nil")
+
+	for _, v := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		_, err = client.ApplyTransform(context.Background(), "input.publish()", []*datapoint.Datapoint{
+			datapoint.New("a", nil, datapoint.NewFloatValue(v), datapoint.Gauge, now),
+		})
+		assert.EqualError(t, err, fmt.Sprintf("datapoint a has non-finite value %v", v))
+	}
+}
@@ -0,0 +1,33 @@
+package signalfx
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/adampetrovic/signalfx-go/signalflow"
+)
+
+// SignalFlowUsageAPIURL is the URL for the organization's SignalFlow usage.
+const SignalFlowUsageAPIURL = "/v2/signalflow/usage"
+
+// GetSignalFlowProgramUsage gets how many computations and MTS the
+// organization's SignalFlow programs are using, and the limits on each. Check
+// the result's NearLimit before starting computations to avoid being
+// throttled.
+func (c *Client) GetSignalFlowProgramUsage(ctx context.Context) (*signalflow.UsageInfo, error) {
+	resp, err := c.doRequestWithContext(ctx, "GET", SignalFlowUsageAPIURL, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	finalUsage := &signalflow.UsageInfo{}
+
+	err = c.decodeJSON(resp.Body, finalUsage)
+
+	return finalUsage, err
+}
@@ -0,0 +1,33 @@
+package signalfx
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSignalFlowProgramUsage(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/signalflow/usage", verifyRequest(t, "GET", http.StatusOK, nil, "signalflow/usage_success.json"))
+
+	result, err := client.GetSignalFlowProgramUsage(context.Background())
+	assert.NoError(t, err, "Unexpected error getting SignalFlow usage")
+	assert.Equal(t, 46, result.ActiveComputations, "ActiveComputations does not match")
+	assert.Equal(t, 100000, result.MaxMTSAllowed, "MaxMTSAllowed does not match")
+	assert.True(t, result.NearLimit(), "Should be near the computation limit")
+}
+
+func TestGetSignalFlowProgramUsageBadStatus(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/signalflow/usage", verifyRequest(t, "GET", http.StatusForbidden, nil, ""))
+
+	result, err := client.GetSignalFlowProgramUsage(context.Background())
+	assert.Error(t, err, "Should have gotten an error from a bad status")
+	assert.Nil(t, result, "Should have gotten a nil result from a bad status")
+}
@@ -0,0 +1,76 @@
+package signalfx
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/adampetrovic/signalfx-go/signalflow"
+)
+
+// SignalFlowPreflightAPIURL is the URL for checking a SignalFlow program
+// without running it.
+const SignalFlowPreflightAPIURL = "/v2/signalflow/preflight"
+
+// ValidateSignalFlowProgram checks whether program is valid SignalFlow, and
+// returns a human-readable message for each problem found, or none if it is
+// valid.  The program is checked by the preflight API.  If that isn't
+// available, because the request fails or the API responds with a 404 or a
+// 5xx status, it falls back to signalflow.CheckSyntax, which only catches
+// obvious mistakes.  Any other unexpected status, such as a 401 or 429, is
+// returned as an *APIError, as is ctx's error if ctx is done.
+func (c *Client) ValidateSignalFlowProgram(ctx context.Context, program string) ([]string, error) {
+	resp, err := c.doRequestWithContext(ctx, "POST", SignalFlowPreflightAPIURL, nil, strings.NewReader(program))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return localSignalFlowCheck(program), nil
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return []string{}, nil
+	case resp.StatusCode == http.StatusBadRequest:
+		body, _ := ioutil.ReadAll(resp.Body)
+		return preflightErrors(body), nil
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode >= 500:
+		return localSignalFlowCheck(program), nil
+	default:
+		return nil, newAPIError(resp)
+	}
+}
+
+func localSignalFlowCheck(program string) []string {
+	problems := signalflow.CheckSyntax(program)
+	if problems == nil {
+		return []string{}
+	}
+	return problems
+}
+
+// preflightErrors pulls the messages out of a rejected preflight response,
+// which is either a single API error or a list of program errors.
+func preflightErrors(body []byte) []string {
+	var resp struct {
+		Message string `json:"message"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil || (resp.Message == "" && len(resp.Errors) == 0) {
+		return []string{strings.TrimSpace(string(body))}
+	}
+
+	var messages []string
+	for _, e := range resp.Errors {
+		messages = append(messages, e.Message)
+	}
+	if len(messages) == 0 {
+		messages = append(messages, resp.Message)
+	}
+	return messages
+}
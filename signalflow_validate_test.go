@@ -0,0 +1,81 @@
+package signalfx
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSignalFlowProgram(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/signalflow/preflight", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.Equal(t, "data('cpu').publish()", string(body), "Program does not match")
+		verifyRequest(t, "POST", http.StatusOK, nil, "")(w, r)
+	})
+
+	problems, err := client.ValidateSignalFlowProgram(context.Background(), "data('cpu').publish()")
+	assert.NoError(t, err, "Unexpected error validating program")
+	assert.Empty(t, problems, "Valid program should have no problems")
+	assert.NotNil(t, problems, "Problems should be empty rather than nil")
+}
+
+func TestValidateInvalidSignalFlowProgram(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/signalflow/preflight", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"errors": [{"message": "Unknown function 'dat'"}, {"message": "No publish"}]}`))
+	})
+
+	problems, err := client.ValidateSignalFlowProgram(context.Background(), "dat('cpu')")
+	assert.NoError(t, err, "Unexpected error validating program")
+	assert.Equal(t, []string{"Unknown function 'dat'", "No publish"}, problems)
+
+	assert.Equal(t, []string{"Bad program"}, preflightErrors([]byte(`{"code": 400, "message": "Bad program"}`)))
+	assert.Equal(t, []string{"nope"}, preflightErrors([]byte("nope\n")))
+}
+
+func TestValidateSignalFlowProgramFallback(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/signalflow/preflight", verifyRequest(t, "POST", http.StatusNotFound, nil, ""))
+
+	problems, err := client.ValidateSignalFlowProgram(context.Background(), "data('cpu').publish(")
+	assert.NoError(t, err, "Unexpected error validating program")
+	assert.Equal(t, []string{"unclosed '('"}, problems, "Should have fallen back to the local check")
+}
+
+func TestValidateSignalFlowProgramServerErrorFallback(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/signalflow/preflight", verifyRequest(t, "POST", http.StatusServiceUnavailable, nil, ""))
+
+	problems, err := client.ValidateSignalFlowProgram(context.Background(), "data('cpu').publish()")
+	assert.NoError(t, err, "Unexpected error validating program")
+	assert.Equal(t, []string{}, problems, "Should have fallen back to the local check")
+}
+
+func TestValidateSignalFlowProgramAPIError(t *testing.T) {
+	for _, status := range []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests} {
+		teardown := setup()
+
+		mux.HandleFunc("/v2/signalflow/preflight", verifyRequest(t, "POST", status, nil, ""))
+
+		problems, err := client.ValidateSignalFlowProgram(context.Background(), "data('cpu').publish()")
+		if assert.IsType(t, &APIError{}, err, "Should get an API error for status %d", status) {
+			assert.Equal(t, status, err.(*APIError).StatusCode)
+		}
+		assert.Nil(t, problems, "A %d shouldn't report the program as valid", status)
+
+		teardown()
+	}
+}
@@ -2,14 +2,15 @@ package signalfx
 
 import (
 	"bytes"
-	"encoding/json"
-	"errors"
+	"context"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
+	"sync"
 
+	"github.com/adampetrovic/signalfx-go/dashboard"
+	"github.com/adampetrovic/signalfx-go/dashboard_group"
 	"github.com/adampetrovic/signalfx-go/team"
 )
 
@@ -18,7 +19,7 @@ const TeamAPIURL = "/v2/team"
 
 // CreateTeam creates a team.
 func (c *Client) CreateTeam(t *team.CreateUpdateTeamRequest) (*team.Team, error) {
-	payload, err := json.Marshal(t)
+	payload, err := c.marshal(t)
 	if err != nil {
 		return nil, err
 	}
@@ -30,13 +31,12 @@ func (c *Client) CreateTeam(t *team.CreateUpdateTeamRequest) (*team.Team, error)
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalTeam := &team.Team{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalTeam)
+	err = c.decodeJSON(resp.Body, finalTeam)
 
 	return finalTeam, err
 }
@@ -51,7 +51,7 @@ func (c *Client) DeleteTeam(id string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		return errors.New("Unexpected status code: " + resp.Status)
+		return newAPIError(resp)
 	}
 
 	return nil
@@ -59,27 +59,30 @@ func (c *Client) DeleteTeam(id string) error {
 
 // GetTeam gets a team.
 func (c *Client) GetTeam(id string) (*team.Team, error) {
-	resp, err := c.doRequest("GET", TeamAPIURL+"/"+id, nil, nil)
+	return c.getTeam(c.ctx, id)
+}
+
+func (c *Client) getTeam(ctx context.Context, id string) (*team.Team, error) {
+	resp, err := c.doRequestWithContext(ctx, "GET", TeamAPIURL+"/"+id, nil, nil)
 
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalTeam := &team.Team{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalTeam)
+	err = c.decodeJSON(resp.Body, finalTeam)
 
 	return finalTeam, err
 }
 
 // UpdateTeam updates a team.
 func (c *Client) UpdateTeam(id string, t *team.CreateUpdateTeamRequest) (*team.Team, error) {
-	payload, err := json.Marshal(t)
+	payload, err := c.marshal(t)
 	if err != nil {
 		return nil, err
 	}
@@ -90,13 +93,12 @@ func (c *Client) UpdateTeam(id string, t *team.CreateUpdateTeamRequest) (*team.T
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		message, _ := ioutil.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Unexpected status code: %d: %s", resp.StatusCode, message)
+		return nil, newAPIError(resp)
 	}
 
 	finalTeam := &team.Team{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalTeam)
+	err = c.decodeJSON(resp.Body, finalTeam)
 
 	return finalTeam, err
 }
@@ -118,7 +120,194 @@ func (c *Client) SearchTeam(limit int, name string, offset int, tags string) (*t
 
 	finalTeams := &team.SearchResults{}
 
-	err = json.NewDecoder(resp.Body).Decode(finalTeams)
+	err = c.decodeJSON(resp.Body, finalTeams)
 
 	return finalTeams, err
 }
+
+// GetTeamNotificationPolicy gets the policy that decides where a team's alerts
+// are sent.
+func (c *Client) GetTeamNotificationPolicy(ctx context.Context, teamID string) (*team.NotificationPolicy, error) {
+	resp, err := c.doRequestWithContext(ctx, "GET", TeamAPIURL+"/"+teamID+"/notificationpolicy", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	finalPolicy := &team.NotificationPolicy{}
+
+	err = c.decodeJSON(resp.Body, finalPolicy)
+
+	return finalPolicy, err
+}
+
+// UpdateTeamNotificationPolicy replaces the policy that decides where a team's
+// alerts are sent.
+func (c *Client) UpdateTeamNotificationPolicy(ctx context.Context, teamID string, policy *team.NotificationPolicy) (*team.NotificationPolicy, error) {
+	payload, err := c.marshal(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequestWithContext(ctx, "PUT", TeamAPIURL+"/"+teamID+"/notificationpolicy", nil, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	finalPolicy := &team.NotificationPolicy{}
+
+	err = c.decodeJSON(resp.Body, finalPolicy)
+
+	return finalPolicy, err
+}
+
+// SetTeamLandingDashboard makes a dashboard group the landing page for a
+// team.
+func (c *Client) SetTeamLandingDashboard(ctx context.Context, teamID string, dashboardGroupID string) error {
+	payload, err := c.marshal(&team.LandingDashboard{DashboardGroupId: dashboardGroupID})
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.doRequestWithContext(ctx, "PUT", TeamAPIURL+"/"+teamID+"/landingdashboard", nil, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return newAPIError(resp)
+	}
+
+	return nil
+}
+
+// GetTeamLandingDashboard gets the first dashboard of a team's landing
+// dashboard group, or nil if the team has no landing page.
+func (c *Client) GetTeamLandingDashboard(ctx context.Context, teamID string) (*dashboard.Dashboard, error) {
+	landing := &team.LandingDashboard{}
+	if err := c.getJSON(ctx, TeamAPIURL+"/"+teamID+"/landingdashboard", nil, landing); err != nil {
+		return nil, err
+	}
+	if landing.DashboardGroupId == "" {
+		return nil, nil
+	}
+
+	group, err := c.getDashboardGroup(ctx, landing.DashboardGroupId)
+	if err != nil {
+		return nil, err
+	}
+	if len(group.Dashboards) == 0 {
+		return nil, fmt.Errorf("landing dashboard group %s has no dashboards", landing.DashboardGroupId)
+	}
+
+	return c.getDashboard(ctx, group.Dashboards[0], false)
+}
+
+// UnsetTeamLandingDashboard removes a team's landing page.
+func (c *Client) UnsetTeamLandingDashboard(ctx context.Context, teamID string) error {
+	resp, err := c.doRequestWithContext(ctx, "DELETE", TeamAPIURL+"/"+teamID+"/landingdashboard", nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return newAPIError(resp)
+	}
+
+	return nil
+}
+
+// AddDashboardGroupToTeam associates a dashboard group with a team.
+func (c *Client) AddDashboardGroupToTeam(ctx context.Context, teamID, groupID string) error {
+	return c.changeTeamDashboardGroup(ctx, "PUT", teamID, groupID)
+}
+
+// RemoveDashboardGroupFromTeam removes the association between a dashboard
+// group and a team.  Neither is deleted.
+func (c *Client) RemoveDashboardGroupFromTeam(ctx context.Context, teamID, groupID string) error {
+	return c.changeTeamDashboardGroup(ctx, "DELETE", teamID, groupID)
+}
+
+func (c *Client) changeTeamDashboardGroup(ctx context.Context, method string, teamID, groupID string) error {
+	resp, err := c.doRequestWithContext(ctx, method, TeamAPIURL+"/"+teamID+"/dashboardgroup/"+groupID, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return newAPIError(resp)
+	}
+
+	return nil
+}
+
+// GetTeamDashboardGroups gets every dashboard group associated with a team.
+func (c *Client) GetTeamDashboardGroups(ctx context.Context, teamID string) ([]*dashboard_group.DashboardGroup, error) {
+	groups := []*dashboard_group.DashboardGroup{}
+	err := c.forEachPage(ctx, TeamAPIURL+"/"+teamID+"/dashboardgroup", nil, func() (interface{}, func() int) {
+		page := &dashboard_group.SearchResult{}
+		return page, func() int {
+			groups = append(groups, page.Results...)
+			return len(page.Results)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// GetDashboardGroupsByTeam gets the current version of every dashboard group
+// associated with a team.  Unlike GetTeamDashboardGroups, which returns the
+// copies in the team's association list, it looks up each group itself,
+// concurrently.  Groups that have been deleted since they were associated are
+// skipped.  If some lookups fail, the groups that were found are returned
+// along with a ResourceErrors for the others.
+func (c *Client) GetDashboardGroupsByTeam(ctx context.Context, teamID string) ([]*dashboard_group.DashboardGroup, error) {
+	associated, err := c.GetTeamDashboardGroups(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make([]*dashboard_group.DashboardGroup, len(associated))
+	failed := ResourceErrors{}
+	var lock sync.Mutex
+	c.forEachConcurrently(len(associated), func(i int) {
+		id := associated[i].Id
+		group, err := c.getDashboardGroup(ctx, id)
+		if err == nil {
+			resolved[i] = group
+			return
+		}
+		if apiErr, ok := err.(*APIError); ok && apiErr.StatusCode == http.StatusNotFound {
+			return
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+		failed[id] = err
+	})
+
+	groups := make([]*dashboard_group.DashboardGroup, 0, len(resolved))
+	for _, group := range resolved {
+		if group != nil {
+			groups = append(groups, group)
+		}
+	}
+	if len(failed) > 0 {
+		return groups, failed
+	}
+	return groups, nil
+}
@@ -0,0 +1,7 @@
+package team
+
+// The dashboard group that team members see when they open the team's page
+type LandingDashboard struct {
+	// ID of the dashboard group, or empty if the team has none
+	DashboardGroupId string `json:"dashboardGroupId,omitempty"`
+}
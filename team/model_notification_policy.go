@@ -0,0 +1,17 @@
+package team
+
+import (
+	"github.com/adampetrovic/signalfx-go/notification"
+)
+
+// Specifies where alerts sent to the team go, depending on how severe they
+// are
+type NotificationPolicy struct {
+	// Notification destinations for alerts not covered by the other lists
+	DefaultNotificationList []*notification.Notification `json:"defaultNotificationList,omitempty"`
+	// Notification destinations for non-critical issues, e.g. **major**,
+	// **minor** and **warning** alerts
+	IssueNotificationList []*notification.Notification `json:"issueNotificationList,omitempty"`
+	// Notification destinations for **critical** alerts
+	CriticalNotificationList []*notification.Notification `json:"criticalNotificationList,omitempty"`
+}
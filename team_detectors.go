@@ -0,0 +1,77 @@
+package signalfx
+
+import (
+	"context"
+	"net/url"
+	"sync"
+
+	"github.com/adampetrovic/signalfx-go/detector"
+)
+
+// GetDetectorsByTeam gets every detector that belongs to a team.
+func (c *Client) GetDetectorsByTeam(ctx context.Context, teamID string) ([]*detector.Detector, error) {
+	params := url.Values{}
+	params.Add("teamId", teamID)
+
+	detectors := []*detector.Detector{}
+	err := c.forEachPage(ctx, DetectorAPIURL, params, func() (interface{}, func() int) {
+		page := &detector.SearchResults{}
+		return page, func() int {
+			for i := range page.Results {
+				detectors = append(detectors, &page.Results[i])
+			}
+			return len(page.Results)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return detectors, nil
+}
+
+// GetAlertsByTeam gets the incidents of every detector that belongs to a
+// team.  If active is set, only the incidents that are still firing are
+// returned, otherwise resolved ones are included too.  Each detector's
+// incidents are fetched concurrently, and are returned grouped by detector in
+// the order GetDetectorsByTeam returns them.  If any request fails, the first
+// error is returned.
+func (c *Client) GetAlertsByTeam(ctx context.Context, teamID string, active bool) ([]*detector.Incident, error) {
+	detectors, err := c.GetDetectorsByTeam(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	perDetector := make([][]*detector.Incident, len(detectors))
+	var once sync.Once
+	var firstErr error
+	c.forEachConcurrently(len(detectors), func(i int) {
+		if ctx.Err() != nil {
+			return
+		}
+		incidents, err := c.GetDetectorIncidents(ctx, detectors[i].Id, !active)
+		if err != nil {
+			once.Do(func() {
+				firstErr = err
+				cancel()
+			})
+			return
+		}
+		perDetector[i] = incidents
+	})
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	alerts := []*detector.Incident{}
+	for _, incidents := range perDetector {
+		for _, inc := range incidents {
+			if !active || inc.Active {
+				alerts = append(alerts, inc)
+			}
+		}
+	}
+	return alerts, nil
+}
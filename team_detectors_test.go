@@ -0,0 +1,54 @@
+package signalfx
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDetectorsByTeam(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	params := url.Values{}
+	params.Add("limit", "100")
+	params.Add("offset", "0")
+	params.Add("teamId", "TeAm1")
+	mux.HandleFunc("/v2/detector", verifyRequest(t, "GET", http.StatusOK, params, "detector/search_success.json"))
+
+	result, err := client.GetDetectorsByTeam(context.Background(), "TeAm1")
+	assert.NoError(t, err, "Unexpected error getting team detectors")
+	assert.Len(t, result, 1, "Should have one detector")
+}
+
+func TestGetAlertsByTeam(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/detector", verifyRequest(t, "GET", http.StatusOK, nil, "detector/search_success.json"))
+	// Resolved incidents shouldn't be requested
+	incidentParams := url.Values{}
+	incidentParams.Add("includeResolved", "")
+	mux.HandleFunc("/v2/detector/string/incidents", verifyRequest(t, "GET", http.StatusOK, incidentParams, "detector/incidents_success.json"))
+
+	result, err := client.GetAlertsByTeam(context.Background(), "TeAm1", true)
+	assert.NoError(t, err, "Unexpected error getting team alerts")
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, "InC1", result[0].IncidentId)
+	}
+}
+
+func TestGetAlertsByTeamError(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/detector", verifyRequest(t, "GET", http.StatusOK, nil, "detector/search_success.json"))
+	mux.HandleFunc("/v2/detector/string/incidents", verifyRequest(t, "GET", http.StatusForbidden, nil, ""))
+
+	result, err := client.GetAlertsByTeam(context.Background(), "TeAm1", false)
+	assert.Error(t, err, "Should get an error when an incident lookup fails")
+	assert.Nil(t, result)
+}
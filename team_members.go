@@ -0,0 +1,176 @@
+package signalfx
+
+import (
+	"context"
+
+	"github.com/adampetrovic/signalfx-go/organization"
+	"github.com/adampetrovic/signalfx-go/team"
+)
+
+// DefaultTeamMemberPageSize is how many members TeamMembersIterator fetches
+// at once.
+const DefaultTeamMemberPageSize = 100
+
+// GetTeamMembers gets the full organization member record of everyone on a
+// team.
+func (c *Client) GetTeamMembers(ctx context.Context, teamID string) ([]*organization.Member, error) {
+	members := []*organization.Member{}
+	it := c.TeamMembers(ctx, teamID)
+	for it.Next() {
+		members = append(members, it.Member())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// TeamMembersIterator pages through the members of a team, fetching up to
+// DefaultTeamMemberPageSize member records at a time, concurrently. Call Next
+// until it returns false, then check Err.
+type TeamMembersIterator struct {
+	client *Client
+	ctx    context.Context
+	teamID string
+
+	ids     []string
+	fetched bool
+	page    []*organization.Member
+	current *organization.Member
+	err     error
+}
+
+// TeamMembers returns an iterator over the members of a team.
+func (c *Client) TeamMembers(ctx context.Context, teamID string) *TeamMembersIterator {
+	return &TeamMembersIterator{
+		client: c,
+		ctx:    ctx,
+		teamID: teamID,
+	}
+}
+
+// Next advances to the next member, fetching another page if needed. It
+// returns false when there are no more members or a request failed.
+func (it *TeamMembersIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if !it.fetched {
+		t, err := it.client.getTeam(it.ctx, it.teamID)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.ids = t.Members
+		it.fetched = true
+	}
+	if len(it.page) == 0 {
+		if len(it.ids) == 0 {
+			return false
+		}
+		ids := it.ids
+		if len(ids) > DefaultTeamMemberPageSize {
+			ids = ids[:DefaultTeamMemberPageSize]
+		}
+		it.ids = it.ids[len(ids):]
+
+		page := make([]*organization.Member, len(ids))
+		errs := make([]error, len(ids))
+		it.client.forEachConcurrently(len(ids), func(i int) {
+			page[i], errs[i] = it.client.getMember(it.ctx, ids[i])
+		})
+		for _, err := range errs {
+			if err != nil {
+				it.err = err
+				return false
+			}
+		}
+		it.page = page
+	}
+	it.current = it.page[0]
+	it.page = it.page[1:]
+	return true
+}
+
+// Member returns the member Next advanced to.
+func (it *TeamMembersIterator) Member() *organization.Member {
+	return it.current
+}
+
+// Err returns the error that stopped iteration, if any.
+func (it *TeamMembersIterator) Err() error {
+	return it.err
+}
+
+// ListTeamMemberships gets every team that the organization member memberID
+// belongs to.
+func (c *Client) ListTeamMemberships(ctx context.Context, memberID string) ([]*team.Team, error) {
+	teams := []*team.Team{}
+	it := c.TeamMemberships(ctx, memberID)
+	for it.Next() {
+		teams = append(teams, it.Team())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
+
+// TeamMembershipsIterator pages through the teams in the organization,
+// searchPageSize at a time, stopping at those a member belongs to. Call Next
+// until it returns false, then check Err.
+type TeamMembershipsIterator struct {
+	pager    *pager
+	memberID string
+
+	page    []team.Team
+	current *team.Team
+	err     error
+}
+
+// TeamMemberships returns an iterator over the teams that the organization
+// member memberID belongs to.
+func (c *Client) TeamMemberships(ctx context.Context, memberID string) *TeamMembershipsIterator {
+	return &TeamMembershipsIterator{
+		pager:    c.newPager(ctx, TeamAPIURL, nil),
+		memberID: memberID,
+	}
+}
+
+// Next advances to the next of the member's teams, fetching more teams if
+// needed. It returns false when there are no more teams or a request failed.
+func (it *TeamMembershipsIterator) Next() bool {
+	for it.err == nil {
+		for len(it.page) > 0 {
+			t := it.page[0]
+			it.page = it.page[1:]
+			for _, id := range t.Members {
+				if id == it.memberID {
+					it.current = &t
+					return true
+				}
+			}
+		}
+		if it.pager.done {
+			return false
+		}
+
+		results := &team.SearchResults{}
+		if err := it.pager.fetch(results, func() int { return len(results.Results) }); err != nil {
+			it.err = err
+			return false
+		}
+		it.page = results.Results
+	}
+	return false
+}
+
+// Team returns the team Next advanced to.
+func (it *TeamMembershipsIterator) Team() *team.Team {
+	return it.current
+}
+
+// Err returns the error that stopped iteration, if any.
+func (it *TeamMembershipsIterator) Err() error {
+	return it.err
+}
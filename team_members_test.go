@@ -0,0 +1,106 @@
+package signalfx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/adampetrovic/signalfx-go/organization"
+	"github.com/adampetrovic/signalfx-go/team"
+)
+
+func TestGetTeamMembers(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/team/string", verifyRequest(t, "GET", http.StatusOK, nil, "team/get_success.json"))
+	mux.HandleFunc("/v2/organization/member/string", verifyRequest(t, "GET", http.StatusOK, nil, "organization/get_member_success.json"))
+
+	members, err := client.GetTeamMembers(context.Background(), "string")
+	assert.NoError(t, err, "Unexpected error getting team members")
+	if assert.Len(t, members, 1) {
+		assert.Equal(t, "string", members[0].Id, "Member does not match")
+	}
+}
+
+func TestTeamMembersIterator(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	ids := make([]string, DefaultTeamMemberPageSize+5)
+	for i := range ids {
+		ids[i] = "m" + strconv.Itoa(i)
+	}
+	mux.HandleFunc("/v2/team/big", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&team.Team{Id: "big", Members: ids})
+	})
+	mux.HandleFunc("/v2/organization/member/", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&organization.Member{Id: strings.TrimPrefix(r.URL.Path, "/v2/organization/member/")})
+	})
+
+	var got []string
+	it := client.TeamMembers(context.Background(), "big")
+	for it.Next() {
+		got = append(got, it.Member().Id)
+	}
+	assert.NoError(t, it.Err(), "Unexpected error iterating team members")
+	assert.Equal(t, ids, got, "Members should be returned in team order")
+}
+
+func TestGetMissingTeamMembers(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/team/string", verifyRequest(t, "GET", http.StatusOK, nil, "team/get_success.json"))
+	mux.HandleFunc("/v2/organization/member/string", verifyRequest(t, "GET", http.StatusNotFound, nil, ""))
+
+	members, err := client.GetTeamMembers(context.Background(), "string")
+	assert.Error(t, err, "Should get an error from a missing member")
+	assert.Nil(t, members)
+}
+
+func TestListTeamMemberships(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/team", func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		results := &team.SearchResults{}
+		n := searchPageSize
+		if offset > 0 {
+			n = 2
+		}
+		for i := 0; i < n; i++ {
+			tm := team.Team{Id: "t" + strconv.Itoa(offset+i), Members: []string{"other"}}
+			if (offset+i)%50 == 0 {
+				tm.Members = append(tm.Members, "me")
+			}
+			results.Results = append(results.Results, tm)
+		}
+		json.NewEncoder(w).Encode(results)
+	})
+
+	teams, err := client.ListTeamMemberships(context.Background(), "me")
+	assert.NoError(t, err, "Unexpected error listing team memberships")
+	var ids []string
+	for _, tm := range teams {
+		ids = append(ids, tm.Id)
+	}
+	assert.Equal(t, []string{"t0", "t50", "t100"}, ids)
+}
+
+func TestListTeamMembershipsError(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/team", verifyRequest(t, "GET", http.StatusForbidden, nil, ""))
+
+	teams, err := client.ListTeamMemberships(context.Background(), "me")
+	assert.Error(t, err, "Should get an error listing teams")
+	assert.Nil(t, teams)
+}
@@ -1,11 +1,14 @@
 package signalfx
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/url"
 	"strconv"
 	"testing"
 
+	"github.com/adampetrovic/signalfx-go/notification"
 	"github.com/adampetrovic/signalfx-go/team"
 	"github.com/stretchr/testify/assert"
 )
@@ -124,3 +127,168 @@ func TestUpdateMissingTeam(t *testing.T) {
 	assert.Error(t, err, "Should've gotten an error from a missing team update")
 	assert.Nil(t, result, "Should've gotten a nil dashboard from a missing team update")
 }
+
+func TestGetTeamNotificationPolicy(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/team/string/notificationpolicy", verifyRequest(t, "GET", http.StatusOK, nil, "team/notification_policy_success.json"))
+
+	result, err := client.GetTeamNotificationPolicy(context.Background(), "string")
+	assert.NoError(t, err, "Unexpected error getting team notification policy")
+	if assert.Len(t, result.CriticalNotificationList, 1) {
+		assert.Equal(t, "pager@example.com", result.CriticalNotificationList[0].Value.(*notification.EmailNotification).Email)
+	}
+	assert.Len(t, result.DefaultNotificationList, 1)
+	assert.Empty(t, result.IssueNotificationList)
+}
+
+func TestGetMissingTeamNotificationPolicy(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/team/string/notificationpolicy", verifyRequest(t, "GET", http.StatusNotFound, nil, ""))
+
+	result, err := client.GetTeamNotificationPolicy(context.Background(), "string")
+	assert.Error(t, err, "Should get an error from missing team")
+	assert.Nil(t, result, "Should get nil result from missing team")
+}
+
+func TestUpdateTeamNotificationPolicy(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/team/string/notificationpolicy", verifyRequest(t, "PUT", http.StatusOK, nil, "team/notification_policy_success.json"))
+
+	result, err := client.UpdateTeamNotificationPolicy(context.Background(), "string", &team.NotificationPolicy{
+		CriticalNotificationList: []*notification.Notification{{
+			Type:  "Email",
+			Value: &notification.EmailNotification{Type: "Email", Email: "pager@example.com"},
+		}},
+	})
+	assert.NoError(t, err, "Unexpected error updating team notification policy")
+	assert.Len(t, result.CriticalNotificationList, 1)
+}
+
+func TestSetTeamLandingDashboard(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/team/string/landingdashboard", func(w http.ResponseWriter, r *http.Request) {
+		landing := &team.LandingDashboard{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(landing))
+		assert.Equal(t, "group", landing.DashboardGroupId, "Dashboard group does not match")
+		verifyRequest(t, "PUT", http.StatusNoContent, nil, "")(w, r)
+	})
+
+	err := client.SetTeamLandingDashboard(context.Background(), "string", "group")
+	assert.NoError(t, err, "Unexpected error setting landing dashboard")
+}
+
+func TestGetTeamLandingDashboard(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/team/string/landingdashboard", verifyRequest(t, "GET", http.StatusOK, nil, "team/landing_dashboard_success.json"))
+	mux.HandleFunc("/v2/dashboardgroup/string", verifyRequest(t, "GET", http.StatusOK, nil, "dashboardgroup/get_success.json"))
+	mux.HandleFunc("/v2/dashboard/string", verifyRequest(t, "GET", http.StatusOK, nil, "dashboard/get_success.json"))
+
+	result, err := client.GetTeamLandingDashboard(context.Background(), "string")
+	assert.NoError(t, err, "Unexpected error getting landing dashboard")
+	assert.Equal(t, "string", result.Id, "Dashboard does not match")
+}
+
+func TestGetUnsetTeamLandingDashboard(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/team/string/landingdashboard", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	})
+
+	result, err := client.GetTeamLandingDashboard(context.Background(), "string")
+	assert.NoError(t, err, "Unexpected error getting unset landing dashboard")
+	assert.Nil(t, result, "Should get nil result when there's no landing dashboard")
+}
+
+func TestUnsetTeamLandingDashboard(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/team/string/landingdashboard", verifyRequest(t, "DELETE", http.StatusNoContent, nil, ""))
+
+	err := client.UnsetTeamLandingDashboard(context.Background(), "string")
+	assert.NoError(t, err, "Unexpected error unsetting landing dashboard")
+}
+
+func TestAddDashboardGroupToTeam(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/team/string/dashboardgroup/group", verifyRequest(t, "PUT", http.StatusNoContent, nil, ""))
+
+	err := client.AddDashboardGroupToTeam(context.Background(), "string", "group")
+	assert.NoError(t, err, "Unexpected error adding dashboard group to team")
+}
+
+func TestRemoveDashboardGroupFromTeam(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/team/string/dashboardgroup/group", verifyRequest(t, "DELETE", http.StatusNoContent, nil, ""))
+
+	err := client.RemoveDashboardGroupFromTeam(context.Background(), "string", "group")
+	assert.NoError(t, err, "Unexpected error removing dashboard group from team")
+}
+
+func TestRemoveMissingDashboardGroupFromTeam(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/team/string/dashboardgroup/group", verifyRequest(t, "DELETE", http.StatusNotFound, nil, ""))
+
+	err := client.RemoveDashboardGroupFromTeam(context.Background(), "string", "group")
+	assert.Error(t, err, "Should get an error removing a missing dashboard group")
+}
+
+func TestGetTeamDashboardGroups(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/team/string/dashboardgroup", verifyRequest(t, "GET", http.StatusOK, nil, "dashboardgroup/search_success.json"))
+
+	result, err := client.GetTeamDashboardGroups(context.Background(), "string")
+	assert.NoError(t, err, "Unexpected error getting team dashboard groups")
+	assert.Len(t, result, 1, "Should have one dashboard group")
+}
+
+func TestGetDashboardGroupsByTeam(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/team/string/dashboardgroup", verifyRequest(t, "GET", http.StatusOK, nil, "dashboardgroup/search_success.json"))
+	mux.HandleFunc("/v2/dashboardgroup/string", verifyRequest(t, "GET", http.StatusOK, nil, "dashboardgroup/get_success.json"))
+
+	result, err := client.GetDashboardGroupsByTeam(context.Background(), "string")
+	assert.NoError(t, err, "Unexpected error getting team dashboard groups")
+	assert.Len(t, result, 1, "Should have one dashboard group")
+}
+
+func TestGetDashboardGroupsByTeamPartial(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/team/string/dashboardgroup", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"count": 3, "results": [{"id": "ok"}, {"id": "gone"}, {"id": "broken"}]}`))
+	})
+	mux.HandleFunc("/v2/dashboardgroup/ok", verifyRequest(t, "GET", http.StatusOK, nil, "dashboardgroup/get_success.json"))
+	mux.HandleFunc("/v2/dashboardgroup/gone", verifyRequest(t, "GET", http.StatusNotFound, nil, ""))
+	mux.HandleFunc("/v2/dashboardgroup/broken", verifyRequest(t, "GET", http.StatusInternalServerError, nil, ""))
+
+	result, err := client.GetDashboardGroupsByTeam(context.Background(), "string")
+	assert.Len(t, result, 1, "Should still return the group that was found")
+	if assert.IsType(t, ResourceErrors{}, err) {
+		assert.Len(t, err, 1, "Deleted groups shouldn't be errors")
+		assert.Contains(t, err.(ResourceErrors), "broken")
+	}
+}
@@ -0,0 +1,126 @@
+package signalfx
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Bounds on the page size that ThrottledSearch adapts as it goes.  The
+// client's paging search methods never go above their usual page size, since
+// they take a short page to mean the last one.
+const (
+	ThrottledSearchMinPageSize = 10
+	ThrottledSearchMaxPageSize = 1000
+)
+
+// ThrottledSearchMaxRetries is how many rate-limited responses in a row
+// ThrottledSearch and the paging search methods retry before giving up.
+const ThrottledSearchMaxRetries = 5
+
+// rateLimitBackoff is how long to wait after a rate-limited response without a
+// Retry-After header, doubling for each one in a row.
+var rateLimitBackoff = time.Second
+
+// throttler adapts the page size of a search to how quickly pages come back,
+// and backs off when the API rate limits it.
+type throttler struct {
+	pageSize    int
+	maxPageSize int
+	retries     int
+}
+
+func newThrottler(pageSize, maxPageSize int) *throttler {
+	return &throttler{pageSize: pageSize, maxPageSize: maxPageSize}
+}
+
+// succeeded records that a page took latency to fetch.  The page size is
+// halved after a slow page and doubled after one that took less than a
+// quarter of SlowAPICallThreshold.
+func (t *throttler) succeeded(latency time.Duration) {
+	t.retries = 0
+	switch {
+	case latency > SlowAPICallThreshold:
+		t.resize(t.pageSize / 2)
+	case latency < SlowAPICallThreshold/4:
+		t.resize(t.pageSize * 2)
+	}
+}
+
+// backOff halves the page size and waits before a request that failed with
+// err is retried, for as long as the API's Retry-After header asked or
+// exponentially longer otherwise.  It returns err instead if err isn't a rate
+// limit or there have been ThrottledSearchMaxRetries in a row, or ctx's error
+// if ctx is done first.
+func (t *throttler) backOff(ctx context.Context, err error) error {
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.StatusCode != http.StatusTooManyRequests || t.retries >= ThrottledSearchMaxRetries {
+		return err
+	}
+
+	wait := apiErr.RetryAfter
+	if wait == 0 {
+		wait = rateLimitBackoff << uint(t.retries)
+	}
+	t.retries++
+	t.resize(t.pageSize / 2)
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (t *throttler) resize(pageSize int) {
+	if pageSize < ThrottledSearchMinPageSize {
+		pageSize = ThrottledSearchMinPageSize
+	}
+	if pageSize > t.maxPageSize {
+		pageSize = t.maxPageSize
+	}
+	t.pageSize = pageSize
+}
+
+// ThrottledSearch pages through a search with searchFn, which fetches limit
+// results starting at offset and returns the total number of results.  The
+// next page starts at offset+limit, so searchFn must not get fewer than limit
+// results unless it has reached the end.  The page size adapts to how long
+// searchFn takes, and a searchFn error that is an *APIError for a 429 Too
+// Many Requests is retried after backing off, up to ThrottledSearchMaxRetries
+// times in a row; searchFn should return errors from the client's methods
+// unchanged so that they are recognised.  totalHint is the expected number of
+// results, if known, so that small searches can be done in a single page.
+// Any other error stops the search and is returned.
+func (c *Client) ThrottledSearch(ctx context.Context, searchFn func(offset, limit int) (int, error), totalHint int) error {
+	pageSize := searchPageSize
+	if totalHint > 0 && totalHint < pageSize {
+		pageSize = totalHint
+	}
+	t := newThrottler(pageSize, ThrottledSearchMaxPageSize)
+
+	for offset := 0; ; {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		limit := t.pageSize
+		start := time.Now()
+		total, err := searchFn(offset, limit)
+		if err != nil {
+			if err := t.backOff(ctx, err); err != nil {
+				return err
+			}
+			continue
+		}
+		t.succeeded(time.Since(start))
+
+		offset += limit
+		if offset >= total {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package signalfx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThrottledSearch(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	var offsets, limits []int
+	err := client.ThrottledSearch(context.Background(), func(offset, limit int) (int, error) {
+		offsets = append(offsets, offset)
+		limits = append(limits, limit)
+		return 250, nil
+	}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 100}, offsets)
+	assert.Equal(t, []int{100, 200}, limits, "Fast pages should grow")
+
+	limits = nil
+	err = client.ThrottledSearch(context.Background(), func(offset, limit int) (int, error) {
+		limits = append(limits, limit)
+		return 30, nil
+	}, 30)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{30}, limits, "A small search should take a single page")
+}
+
+func TestThrottledSearchRateLimit(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	defer func(backoff time.Duration) { rateLimitBackoff = backoff }(rateLimitBackoff)
+	rateLimitBackoff = time.Millisecond
+
+	var limits []int
+	err := client.ThrottledSearch(context.Background(), func(offset, limit int) (int, error) {
+		limits = append(limits, limit)
+		if len(limits) == 1 {
+			return 0, &APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Millisecond}
+		}
+		return 50, nil
+	}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{100, 50}, limits, "Rate limits should shrink the page and retry")
+
+	calls := 0
+	err = client.ThrottledSearch(context.Background(), func(offset, limit int) (int, error) {
+		calls++
+		return 0, &APIError{StatusCode: http.StatusTooManyRequests}
+	}, 0)
+	assert.Equal(t, ErrRateLimit, err.(*APIError).Unwrap())
+	assert.Equal(t, ThrottledSearchMaxRetries+1, calls, "Should give up after too many rate limits")
+
+	failed := errors.New("failed")
+	calls = 0
+	err = client.ThrottledSearch(context.Background(), func(offset, limit int) (int, error) {
+		calls++
+		return 0, failed
+	}, 0)
+	assert.Equal(t, failed, err)
+	assert.Equal(t, 1, calls, "Other errors shouldn't be retried")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = client.ThrottledSearch(ctx, func(offset, limit int) (int, error) {
+		return 0, &APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Hour}
+	}, 0)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestPagerRetriesRateLimits(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+	defer func(backoff time.Duration) { rateLimitBackoff = backoff }(rateLimitBackoff)
+	rateLimitBackoff = time.Millisecond
+
+	var limits []string
+	mux.HandleFunc("/v2/detector", func(w http.ResponseWriter, r *http.Request) {
+		limits = append(limits, r.URL.Query().Get("limit"))
+		if len(limits) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"count": 1, "results": [{"id": "DeT1"}]}`)
+	})
+
+	it := client.SearchDetectorsCursor(context.Background(), "", "")
+	ids := []string{}
+	for it.Next() {
+		ids = append(ids, it.Detector().Id)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"DeT1"}, ids)
+	assert.Equal(t, []string{"100", "50"}, limits)
+}
@@ -0,0 +1,125 @@
+package signalfx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/mts"
+)
+
+// TimeSeriesWindowAPIURL is the URL for fetching historical time series data.
+const TimeSeriesWindowAPIURL = "/v1/timeserieswindow"
+
+// The rollups that can be applied to the values in each resolution window.
+const (
+	RollupSum   = "sum"
+	RollupMean  = "mean"
+	RollupMin   = "min"
+	RollupMax   = "max"
+	RollupCount = "count"
+)
+
+type timeSeriesWindowResponse struct {
+	Data   map[string][][2]float64 `json:"data"`
+	Errors []interface{}           `json:"errors"`
+}
+
+// GetMetricTimeSeriesData fetches historical data for all of the metric time
+// series that match `query` (e.g. `sf_metric:cpu.utilization AND host:web1`)
+// between `startTime` and `endTime`, without having to run a SignalFlow job.
+// Values are rolled up to `resolution` using `rollup`, which must be one of
+// the `Rollup*` constants.  The dimensions of each time series are looked up
+// separately, with up to the client's MaxConcurrent requests at once.
+func (c *Client) GetMetricTimeSeriesData(ctx context.Context, query string, startTime, endTime time.Time, resolution time.Duration, rollup string) ([]*mts.DataPoint, error) {
+	switch rollup {
+	case RollupSum, RollupMean, RollupMin, RollupMax, RollupCount:
+	default:
+		return nil, fmt.Errorf("unknown rollup %q", rollup)
+	}
+	if resolution <= 0 {
+		return nil, fmt.Errorf("resolution %v must be positive", resolution)
+	}
+	if !endTime.After(startTime) {
+		return nil, fmt.Errorf("endTime %v must be after startTime %v", endTime, startTime)
+	}
+
+	data, err := c.getTimeSeriesWindow(ctx, query, startTime, endTime, resolution, rollup)
+	if err != nil {
+		return nil, err
+	}
+
+	tsids := make([]string, 0, len(data))
+	for tsid := range data {
+		tsids = append(tsids, tsid)
+	}
+	sort.Strings(tsids)
+
+	dims := make([]map[string]string, len(tsids))
+	errs := make([]error, len(tsids))
+	c.forEachConcurrently(len(tsids), func(i int) {
+		metadata, err := c.getMetricTimeSeries(ctx, tsids[i])
+		if err != nil {
+			errs[i] = fmt.Errorf("could not get dimensions of time series %s: %v", tsids[i], err)
+			return
+		}
+		dims[i] = make(map[string]string, len(metadata.Dimensions))
+		for k, v := range metadata.Dimensions {
+			dims[i][k] = fmt.Sprintf("%v", v)
+		}
+	})
+
+	var out []*mts.DataPoint
+	for i, tsid := range tsids {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		for _, pair := range data[tsid] {
+			out = append(out, &mts.DataPoint{
+				Timestamp:  time.Unix(0, int64(pair[0])*int64(time.Millisecond)),
+				Value:      pair[1],
+				Dimensions: dims[i],
+			})
+		}
+	}
+	return out, nil
+}
+
+// getTimeSeriesWindow returns the [timestamp, value] pairs of each time series
+// matching query, keyed by time series ID.
+func (c *Client) getTimeSeriesWindow(ctx context.Context, query string, startTime, endTime time.Time, resolution time.Duration, rollup string) (map[string][][2]float64, error) {
+	params := url.Values{}
+	params.Add("query", query)
+	params.Add("startMS", strconv.FormatInt(startTime.UnixNano()/int64(time.Millisecond), 10))
+	params.Add("endMS", strconv.FormatInt(endTime.UnixNano()/int64(time.Millisecond), 10))
+	params.Add("resolution", strconv.FormatInt(resolution.Nanoseconds()/int64(time.Millisecond), 10))
+	params.Add("rollup", rollup)
+
+	resp, err := c.doRequestWithContext(ctx, "GET", TimeSeriesWindowAPIURL, params, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	window := &timeSeriesWindowResponse{}
+	if err := c.decodeJSON(resp.Body, window); err != nil {
+		return nil, err
+	}
+	if len(window.Errors) > 0 {
+		errs := make([]string, len(window.Errors))
+		for i := range window.Errors {
+			errs[i] = fmt.Sprintf("%v", window.Errors[i])
+		}
+		return nil, fmt.Errorf("error fetching time series data: %s", strings.Join(errs, "; "))
+	}
+	return window.Data, nil
+}
@@ -0,0 +1,67 @@
+package signalfx
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMetricTimeSeriesData(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	params := url.Values{}
+	params.Add("query", "sf_metric:cpu.utilization")
+	params.Add("startMS", "1557936000000")
+	params.Add("endMS", "1557939600000")
+	params.Add("resolution", "60000")
+	params.Add("rollup", "mean")
+
+	mux.HandleFunc("/v1/timeserieswindow", verifyRequest(t, "GET", http.StatusOK, params, "metrics_metadata/get_time_series_window_success.json"))
+	mux.HandleFunc("/v2/metrictimeseries/AAAAAIhxuJ8", verifyRequest(t, "GET", http.StatusOK, nil, "metrics_metadata/get_metric_time_series_with_dimensions_success.json"))
+
+	start := time.Unix(1557936000, 0)
+	result, err := client.GetMetricTimeSeriesData(context.Background(), "sf_metric:cpu.utilization", start, start.Add(time.Hour), time.Minute, RollupMean)
+	assert.NoError(t, err, "Unexpected error getting time series data")
+	assert.Len(t, result, 2, "Should have two datapoints")
+	assert.Equal(t, start, result[0].Timestamp, "Timestamp does not match")
+	assert.Equal(t, 12.5, result[0].Value, "Value does not match")
+	assert.Equal(t, "web1", result[0].Dimensions["host"], "Dimensions do not match")
+	assert.Equal(t, start.Add(time.Minute), result[1].Timestamp, "Timestamp does not match")
+}
+
+func TestGetMetricTimeSeriesDataBadRollup(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	start := time.Unix(1557936000, 0)
+	result, err := client.GetMetricTimeSeriesData(context.Background(), "sf_metric:cpu.utilization", start, start.Add(time.Hour), time.Minute, "median")
+	assert.Error(t, err, "Should have gotten an error from an unknown rollup")
+	assert.Nil(t, result, "Should have gotten a nil result from an unknown rollup")
+}
+
+func TestGetMetricTimeSeriesDataBadQuery(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/timeserieswindow", verifyRequest(t, "GET", http.StatusBadRequest, nil, ""))
+
+	start := time.Unix(1557936000, 0)
+	result, err := client.GetMetricTimeSeriesData(context.Background(), "sf_metric:", start, start.Add(time.Hour), time.Minute, RollupSum)
+	assert.Error(t, err, "Should have gotten an error from a bad query")
+	assert.Nil(t, result, "Should have gotten a nil result from a bad query")
+}
+
+func TestGetMetricTimeSeriesDataBadResolution(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	start := time.Unix(1557936000, 0)
+	result, err := client.GetMetricTimeSeriesData(context.Background(), "sf_metric:cpu.utilization", start, start.Add(time.Hour), 0, RollupMean)
+	assert.Error(t, err, "Should have gotten an error from a zero resolution")
+	assert.Nil(t, result, "Should have gotten a nil result from a zero resolution")
+}
@@ -0,0 +1,52 @@
+package signalfx
+
+import (
+	"context"
+	"net/http"
+)
+
+// TokenPermissionsAPIURL is the URL for introspecting the permissions of the
+// token making the request.
+const TokenPermissionsAPIURL = "/v2/tokenpermissions"
+
+// TokenPermissions describes what the client's token is allowed to do.
+type TokenPermissions struct {
+	CanReadDetectors   bool `json:"canReadDetectors"`
+	CanWriteDetectors  bool `json:"canWriteDetectors"`
+	CanReadDashboards  bool `json:"canReadDashboards"`
+	CanWriteDashboards bool `json:"canWriteDashboards"`
+	CanManageTokens    bool `json:"canManageTokens"`
+	IsAdmin            bool `json:"isAdmin"`
+	// The token's auth scopes, e.g. "API", "INGEST" or "INGEST_SPANS"
+	AuthScopes []string `json:"authScopes,omitempty"`
+}
+
+// HasScope reports whether scope is one of the token's auth scopes.
+func (tp *TokenPermissions) HasScope(scope string) bool {
+	for _, s := range tp.AuthScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTokenPermissions gets what the client's token is allowed to do, so
+// tools can check it's suitable before relying on it.
+func (c *Client) GetTokenPermissions(ctx context.Context) (*TokenPermissions, error) {
+	resp, err := c.doRequestWithContext(ctx, "GET", TokenPermissionsAPIURL, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	finalPermissions := &TokenPermissions{}
+
+	err = c.decodeJSON(resp.Body, finalPermissions)
+
+	return finalPermissions, err
+}
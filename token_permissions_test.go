@@ -0,0 +1,34 @@
+package signalfx
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTokenPermissions(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/tokenpermissions", verifyRequest(t, "GET", http.StatusOK, nil, "tokenpermissions/get_success.json"))
+
+	result, err := client.GetTokenPermissions(context.Background())
+	assert.NoError(t, err, "Unexpected error getting token permissions")
+	assert.True(t, result.CanWriteDetectors)
+	assert.False(t, result.CanWriteDashboards)
+	assert.True(t, result.HasScope("INGEST"))
+	assert.False(t, result.HasScope("INGEST_SPANS"))
+}
+
+func TestGetTokenPermissionsUnauthorized(t *testing.T) {
+	teardown := setup()
+	defer teardown()
+
+	mux.HandleFunc("/v2/tokenpermissions", verifyRequest(t, "GET", http.StatusUnauthorized, nil, ""))
+
+	result, err := client.GetTokenPermissions(context.Background())
+	assert.Error(t, err, "Should get an error with a bad token")
+	assert.Nil(t, result, "Should get nil result")
+}
@@ -20,3 +20,95 @@ func (sos *StringOrSlice) UnmarshalJSON(b []byte) error {
 	}
 	return nil
 }
+
+// Filter returns the values for which fn returns true.
+func (sos StringOrSlice) Filter(fn func(string) bool) StringOrSlice {
+	var out StringOrSlice
+	for _, s := range sos {
+		if fn(s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Map returns the result of calling fn on each value.
+func (sos StringOrSlice) Map(fn func(string) string) StringOrSlice {
+	if sos == nil {
+		return nil
+	}
+	out := make(StringOrSlice, len(sos))
+	for i, s := range sos {
+		out[i] = fn(s)
+	}
+	return out
+}
+
+// Contains reports whether s is one of the values.
+func (sos StringOrSlice) Contains(s string) bool {
+	for _, v := range sos {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the number of values.
+func (sos StringOrSlice) Len() int {
+	return len(sos)
+}
+
+// Merge returns the values in either sos or other, without duplicates, in
+// the order they first appear.
+func (sos StringOrSlice) Merge(other StringOrSlice) StringOrSlice {
+	var out StringOrSlice
+	seen := make(map[string]bool, len(sos)+len(other))
+	for _, list := range []StringOrSlice{sos, other} {
+		for _, s := range list {
+			if !seen[s] {
+				seen[s] = true
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+// AsSlice returns a copy of the values as a plain slice.  A single string
+// from JSON is a one-element slice.
+func (sos StringOrSlice) AsSlice() []string {
+	if sos == nil {
+		return nil
+	}
+	return append([]string{}, sos...)
+}
+
+// AsString returns the value if there's exactly one, and false if there are
+// none or several.  A one-element list in JSON is treated the same as a
+// single string.
+func (sos StringOrSlice) AsString() (string, bool) {
+	if len(sos) != 1 {
+		return "", false
+	}
+	return sos[0], true
+}
+
+// IsEmpty reports whether there are no values.
+func (sos StringOrSlice) IsEmpty() bool {
+	return len(sos) == 0
+}
+
+// Equal reports whether sos and other have the same values in the same
+// order.  Nil and empty are equal.
+func (sos StringOrSlice) Equal(other StringOrSlice) bool {
+	if len(sos) != len(other) {
+		return false
+	}
+	for i := range sos {
+		if sos[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
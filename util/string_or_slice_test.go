@@ -0,0 +1,58 @@
+package util
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStringOrSliceUnmarshal(t *testing.T) {
+	var sos StringOrSlice
+	assert.NoError(t, json.Unmarshal([]byte(`"foo"`), &sos))
+	assert.Equal(t, StringOrSlice{"foo"}, sos)
+
+	assert.NoError(t, json.Unmarshal([]byte(`["foo", "bar"]`), &sos))
+	assert.Equal(t, StringOrSlice{"foo", "bar"}, sos)
+}
+
+func TestStringOrSliceHelpers(t *testing.T) {
+	sos := StringOrSlice{"prod-east", "prod-west", "dev"}
+
+	assert.Equal(t, StringOrSlice{"prod-east", "prod-west"}, sos.Filter(func(s string) bool {
+		return strings.HasPrefix(s, "prod")
+	}))
+	assert.Nil(t, sos.Filter(func(string) bool { return false }))
+	assert.Equal(t, StringOrSlice{"PROD-EAST", "PROD-WEST", "DEV"}, sos.Map(strings.ToUpper))
+	assert.True(t, sos.Contains("dev"))
+	assert.False(t, sos.Contains("staging"))
+	assert.Equal(t, 3, sos.Len())
+	assert.Equal(t, StringOrSlice{"prod-east", "prod-west", "dev", "staging"}, sos.Merge(StringOrSlice{"dev", "staging", "staging"}))
+	assert.Equal(t, 0, StringOrSlice(nil).Len())
+}
+
+func TestStringOrSliceAccessors(t *testing.T) {
+	sos := StringOrSlice{"foo"}
+	s, ok := sos.AsString()
+	assert.True(t, ok)
+	assert.Equal(t, "foo", s)
+	assert.Equal(t, []string{"foo"}, sos.AsSlice())
+
+	slice := sos.AsSlice()
+	slice[0] = "changed"
+	assert.Equal(t, StringOrSlice{"foo"}, sos, "AsSlice should return a copy")
+
+	_, ok = StringOrSlice{"foo", "bar"}.AsString()
+	assert.False(t, ok, "Several values aren't a string")
+	_, ok = StringOrSlice{}.AsString()
+	assert.False(t, ok, "No values aren't a string")
+
+	assert.True(t, StringOrSlice(nil).IsEmpty())
+	assert.False(t, sos.IsEmpty())
+
+	assert.True(t, StringOrSlice(nil).Equal(StringOrSlice{}))
+	assert.True(t, StringOrSlice{"a", "b"}.Equal(StringOrSlice{"a", "b"}))
+	assert.False(t, StringOrSlice{"a", "b"}.Equal(StringOrSlice{"b", "a"}))
+	assert.False(t, StringOrSlice{"a"}.Equal(StringOrSlice{"a", "b"}))
+}
@@ -0,0 +1,103 @@
+// Package webhook receives the alert notifications SignalFx sends to webhook
+// integrations.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// SignatureHeader is the header that carries the hex-encoded HMAC-SHA256 of
+// the request body, keyed with the webhook's shared secret.
+const SignatureHeader = "X-SF-Webhook-Signature"
+
+// MaxBodySize is the largest request body, in bytes, that a Receiver's
+// handler reads.  Notifications are far smaller than this.
+const MaxBodySize = 1 << 20
+
+// The body of an alert notification.
+type Payload struct {
+	// The kind of message, e.g. "ALERT" or "TEST"
+	MessageType string `json:"messageType,omitempty"`
+	// The name of the detector that fired
+	Detector string `json:"detector,omitempty"`
+	// The description of the rule that fired
+	RuleDescription string `json:"description,omitempty"`
+	// The severity of the rule, e.g. "Critical"
+	Severity string `json:"severity,omitempty"`
+	// The state of the alert, e.g. "anomalous" or "ok"
+	Status string `json:"status,omitempty"`
+	// The dimensions of the time series that triggered the alert
+	Dimensions map[string]string `json:"dimensions,omitempty"`
+	// When the alert's state changed
+	Timestamp time.Time `json:"timestamp,omitempty"`
+}
+
+// Receiver handles incoming webhook notifications.
+type Receiver struct {
+	// Called with each verified notification. It's called on the request's
+	// goroutine, so should return quickly.
+	OnAlert func(payload *Payload)
+}
+
+// Sign returns the signature SignalFx sends for body, keyed with secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the correct signature of body.
+func Verify(secret string, body []byte, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// Handler returns an http.Handler that verifies each request's signature
+// against secret, decodes it and passes it to OnAlert. Requests with a
+// missing or wrong signature, a body larger than MaxBodySize, or a body that
+// isn't a payload, get a 400.  It panics if secret is empty, since anyone
+// could sign requests with it.
+func (r *Receiver) Handler(secret string) http.Handler {
+	if secret == "" {
+		panic("webhook: secret cannot be empty")
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != "POST" {
+			w.Header().Set("Allow", "POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, MaxBodySize))
+		if err != nil {
+			http.Error(w, "could not read body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !Verify(secret, body, req.Header.Get(SignatureHeader)) {
+			http.Error(w, "bad signature", http.StatusBadRequest)
+			return
+		}
+
+		payload := &Payload{}
+		if err := json.Unmarshal(body, payload); err != nil {
+			http.Error(w, "bad payload: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if r.OnAlert != nil {
+			r.OnAlert(payload)
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
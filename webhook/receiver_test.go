@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSecret = "s3cret"
+
+func post(h http.Handler, body []byte, signature string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/alerts", bytes.NewReader(body))
+	if signature != "" {
+		req.Header.Set(SignatureHeader, signature)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestReceiverHandler(t *testing.T) {
+	var received []*Payload
+	h := (&Receiver{OnAlert: func(p *Payload) { received = append(received, p) }}).Handler(testSecret)
+
+	body := []byte(`{"messageType": "ALERT", "detector": "CPU", "description": "CPU > 90", "severity": "Critical", "status": "anomalous", "dimensions": {"host": "web-1"}}`)
+	rec := post(h, body, Sign(testSecret, body))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	if assert.Len(t, received, 1) {
+		assert.Equal(t, &Payload{
+			MessageType:     "ALERT",
+			Detector:        "CPU",
+			RuleDescription: "CPU > 90",
+			Severity:        "Critical",
+			Status:          "anomalous",
+			Dimensions:      map[string]string{"host": "web-1"},
+		}, received[0])
+	}
+
+	assert.Equal(t, http.StatusBadRequest, post(h, body, "").Code, "Missing signature should fail")
+	assert.Equal(t, http.StatusBadRequest, post(h, body, Sign("other", body)).Code, "Wrong secret should fail")
+	assert.Equal(t, http.StatusBadRequest, post(h, body, "zz").Code, "Garbage signature should fail")
+	bad := []byte("not json")
+	assert.Equal(t, http.StatusBadRequest, post(h, bad, Sign(testSecret, bad)).Code, "Bad payload should fail")
+	big := []byte(`{"detector": "` + strings.Repeat("x", MaxBodySize) + `"}`)
+	assert.Equal(t, http.StatusBadRequest, post(h, big, Sign(testSecret, big)).Code, "Oversized body should fail")
+	assert.Len(t, received, 1, "Rejected requests shouldn't reach OnAlert")
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/alerts", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestReceiverHandlerEmptySecret(t *testing.T) {
+	assert.Panics(t, func() { (&Receiver{}).Handler("") }, "Should reject an empty secret")
+}
@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// TestSecret is the secret NewTestPayload signs with unless WithSecret is
+// used.
+const TestSecret = "test-secret"
+
+// PayloadOption customizes the payload built by NewTestPayload.
+type PayloadOption func(*testPayload)
+
+type testPayload struct {
+	payload Payload
+	secret  string
+}
+
+// WithMessageType sets the payload's message type, which defaults to
+// "ALERT".
+func WithMessageType(messageType string) PayloadOption {
+	return func(tp *testPayload) {
+		tp.payload.MessageType = messageType
+	}
+}
+
+// WithSeverity sets the payload's severity, which defaults to "Critical".
+func WithSeverity(severity string) PayloadOption {
+	return func(tp *testPayload) {
+		tp.payload.Severity = severity
+	}
+}
+
+// WithDimensions sets the dimensions of the alerting time series.
+func WithDimensions(dims map[string]string) PayloadOption {
+	return func(tp *testPayload) {
+		tp.payload.Dimensions = dims
+	}
+}
+
+// WithTimestamp sets the payload's timestamp, which defaults to now.
+func WithTimestamp(ts time.Time) PayloadOption {
+	return func(tp *testPayload) {
+		tp.payload.Timestamp = ts
+	}
+}
+
+// WithSecret sets the secret the payload is signed with, which defaults to
+// TestSecret.
+func WithSecret(secret string) PayloadOption {
+	return func(tp *testPayload) {
+		tp.secret = secret
+	}
+}
+
+// NewTestPayload builds a webhook body and its signature for testing code
+// that consumes webhooks, such as a Receiver's OnAlert callback. The
+// signature is made with Sign, so it's accepted by a Receiver using the same
+// secret.
+func NewTestPayload(opts ...PayloadOption) (body []byte, signature string, err error) {
+	tp := &testPayload{
+		payload: Payload{
+			MessageType:     "ALERT",
+			Detector:        "Test detector",
+			RuleDescription: "Test rule",
+			Severity:        "Critical",
+			Status:          "anomalous",
+			Timestamp:       time.Now().UTC(),
+		},
+		secret: TestSecret,
+	}
+	for _, opt := range opts {
+		opt(tp)
+	}
+
+	body, err = json.Marshal(tp.payload)
+	if err != nil {
+		return nil, "", err
+	}
+	return body, Sign(tp.secret, body), nil
+}
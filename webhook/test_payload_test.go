@@ -0,0 +1,35 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTestPayload(t *testing.T) {
+	ts := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	body, signature, err := NewTestPayload(
+		WithMessageType("TEST"),
+		WithSeverity("Minor"),
+		WithDimensions(map[string]string{"host": "web-1"}),
+		WithTimestamp(ts),
+	)
+	assert.NoError(t, err)
+	assert.True(t, Verify(TestSecret, body, signature))
+
+	var received *Payload
+	h := (&Receiver{OnAlert: func(p *Payload) { received = p }}).Handler(TestSecret)
+	assert.Equal(t, 200, post(h, body, signature).Code)
+	if assert.NotNil(t, received) {
+		assert.Equal(t, "TEST", received.MessageType)
+		assert.Equal(t, "Minor", received.Severity)
+		assert.Equal(t, map[string]string{"host": "web-1"}, received.Dimensions)
+		assert.Equal(t, ts, received.Timestamp)
+	}
+
+	body, signature, err = NewTestPayload(WithSecret(testSecret))
+	assert.NoError(t, err)
+	assert.True(t, Verify(testSecret, body, signature))
+	assert.False(t, Verify(TestSecret, body, signature))
+}
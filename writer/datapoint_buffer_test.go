@@ -36,6 +36,47 @@ func TestDatapointBuffer(t *testing.T) {
 		}
 	})
 
+	t.Run("Reports stats", func(t *testing.T) {
+		t.Parallel()
+		buffer := NewDatapointRingBuffer(10)
+
+		for i := 0; i < 15; i++ {
+			buffer.Add(&datapoint.Datapoint{})
+		}
+		buffer.NextBatch(2)
+
+		require.Equal(t, DatapointRingBufferStats{
+			Capacity:         10,
+			Unprocessed:      8,
+			TotalWritten:     15,
+			TotalOverwritten: 5,
+			Utilization:      0.8,
+		}, buffer.Stats())
+	})
+
+	t.Run("Reports consistent stats while adding", func(t *testing.T) {
+		t.Parallel()
+		buffer := NewDatapointRingBuffer(10)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 100000; i++ {
+				buffer.Add(&datapoint.Datapoint{})
+			}
+		}()
+
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			stats := buffer.Stats()
+			require.Equal(t, stats.TotalWritten, int64(stats.Unprocessed)+stats.TotalOverwritten)
+		}
+	})
+
 	t.Run("Overwrites older elements", func(t *testing.T) {
 		t.Parallel()
 		buffer := NewDatapointRingBuffer(100)
@@ -68,11 +109,12 @@ func TestDatapointBuffer(t *testing.T) {
 		// Overwrite elements [9..59).  This drags the read cursor to the
 		// first non-overwritten element, 59.
 		for i := 0; i < 50; i++ {
-			overwrote := buffer.Add(&datapoint.Datapoint{
+			overwrote, overwritten := buffer.Push(&datapoint.Datapoint{
 				Meta: map[interface{}]interface{}{"i": i + 109},
 			})
 
 			require.True(t, overwrote)
+			require.Equal(t, i+9, overwritten.Meta["i"].(int))
 		}
 
 		var rest []*datapoint.Datapoint
@@ -0,0 +1,120 @@
+package writer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	sfxproto "github.com/signalfx/com_signalfx_metrics_protobuf"
+	"github.com/signalfx/golib/v3/datapoint"
+)
+
+// DatapointProtobufCodec encodes a batch of datapoints as a SignalFx
+// DataPointUploadMessage, the protobuf format accepted by the /v2/datapoint
+// ingest endpoint.  Spans have no protobuf ingest format, so there is no
+// span equivalent.
+type DatapointProtobufCodec struct{}
+
+var _ DatapointCodec = DatapointProtobufCodec{}
+
+// Encode a batch of datapoints
+func (DatapointProtobufCodec) Encode(datapoints []*datapoint.Datapoint) ([]byte, error) {
+	msg := &sfxproto.DataPointUploadMessage{
+		Datapoints: make([]*sfxproto.DataPoint, 0, len(datapoints)),
+	}
+	for _, dp := range datapoints {
+		pdp, err := datapointToProtobuf(dp)
+		if err != nil {
+			return nil, err
+		}
+		msg.Datapoints = append(msg.Datapoints, pdp)
+	}
+	return proto.Marshal(msg)
+}
+
+// Decode a batch of datapoints
+func (DatapointProtobufCodec) Decode(data []byte) ([]*datapoint.Datapoint, error) {
+	msg := &sfxproto.DataPointUploadMessage{}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return nil, err
+	}
+
+	out := make([]*datapoint.Datapoint, 0, len(msg.Datapoints))
+	for _, pdp := range msg.Datapoints {
+		out = append(out, datapointFromProtobuf(pdp))
+	}
+	return out, nil
+}
+
+func datapointToProtobuf(dp *datapoint.Datapoint) (*sfxproto.DataPoint, error) {
+	var datum sfxproto.Datum
+	switch v := dp.Value.(type) {
+	case datapoint.IntValue:
+		datum.IntValue = proto.Int64(v.Int())
+	case datapoint.FloatValue:
+		datum.DoubleValue = proto.Float64(v.Float())
+	case nil:
+		return nil, fmt.Errorf("datapoint %s has no value", dp.Metric)
+	default:
+		datum.StrValue = proto.String(v.String())
+	}
+
+	var ts int64
+	if !dp.Timestamp.IsZero() {
+		ts = dp.Timestamp.UnixNano() / int64(time.Millisecond)
+	}
+
+	mt := sfxproto.MetricType_GAUGE
+	switch dp.MetricType {
+	case datapoint.Counter:
+		mt = sfxproto.MetricType_CUMULATIVE_COUNTER
+	case datapoint.Count:
+		mt = sfxproto.MetricType_COUNTER
+	}
+
+	dims := make([]*sfxproto.Dimension, 0, len(dp.Dimensions))
+	for k, v := range dp.Dimensions {
+		dims = append(dims, &sfxproto.Dimension{Key: proto.String(k), Value: proto.String(v)})
+	}
+
+	return &sfxproto.DataPoint{
+		Metric:     proto.String(dp.Metric),
+		Timestamp:  &ts,
+		Value:      &datum,
+		MetricType: &mt,
+		Dimensions: dims,
+	}, nil
+}
+
+func datapointFromProtobuf(pdp *sfxproto.DataPoint) *datapoint.Datapoint {
+	var value datapoint.Value
+	v := pdp.GetValue()
+	switch {
+	case v.IntValue != nil:
+		value = datapoint.NewIntValue(*v.IntValue)
+	case v.DoubleValue != nil:
+		value = datapoint.NewFloatValue(*v.DoubleValue)
+	default:
+		value = datapoint.NewStringValue(v.GetStrValue())
+	}
+
+	mt := datapoint.Gauge
+	switch pdp.GetMetricType() {
+	case sfxproto.MetricType_CUMULATIVE_COUNTER:
+		mt = datapoint.Counter
+	case sfxproto.MetricType_COUNTER:
+		mt = datapoint.Count
+	}
+
+	var ts time.Time
+	if ms := pdp.GetTimestamp(); ms != 0 {
+		ts = time.Unix(0, ms*int64(time.Millisecond))
+	}
+
+	dims := make(map[string]string, len(pdp.Dimensions))
+	for _, d := range pdp.Dimensions {
+		dims[d.GetKey()] = d.GetValue()
+	}
+
+	return datapoint.New(pdp.GetMetric(), dims, value, mt, ts)
+}
@@ -0,0 +1,38 @@
+package writer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/signalfx/golib/v3/datapoint"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatapointProtobufCodec(t *testing.T) {
+	ts := time.Unix(1500000000, 0)
+	dps := []*datapoint.Datapoint{
+		datapoint.New("cpu.utilization", map[string]string{"host": "a"}, datapoint.NewFloatValue(12.5), datapoint.Gauge, ts),
+		datapoint.New("requests", nil, datapoint.NewIntValue(3), datapoint.Count, ts),
+		datapoint.New("bytes", nil, datapoint.NewIntValue(1024), datapoint.Counter, time.Time{}),
+	}
+
+	codec := DatapointProtobufCodec{}
+	data, err := codec.Encode(dps)
+	require.Nil(t, err)
+
+	decoded, err := codec.Decode(data)
+	require.Nil(t, err)
+	require.Len(t, decoded, 3)
+
+	require.Equal(t, "cpu.utilization", decoded[0].Metric)
+	require.Equal(t, map[string]string{"host": "a"}, decoded[0].Dimensions)
+	require.Equal(t, datapoint.NewFloatValue(12.5), decoded[0].Value)
+	require.True(t, ts.Equal(decoded[0].Timestamp))
+	require.Equal(t, datapoint.Count, decoded[1].MetricType)
+	require.Equal(t, datapoint.NewIntValue(3), decoded[1].Value)
+	require.Equal(t, datapoint.Counter, decoded[2].MetricType)
+	require.True(t, decoded[2].Timestamp.IsZero())
+
+	_, err = codec.Encode([]*datapoint.Datapoint{{Metric: "empty"}})
+	require.Error(t, err, "datapoints without a value can't be encoded")
+}
@@ -3,9 +3,11 @@ package writer
 //go:generate sh -c "sed -e /go:generate/d -e s/datapoint_/trace_span_/g -e s/datapoints_/trace_spans_/ -e s/datapoint/trace/g -e s/Datapoint/Span/g $GOFILE | gofmt -s > span_writer_test.go"
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"math"
 	"sort"
@@ -14,6 +16,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/adampetrovic/signalfx-go/writer/internal/breaker"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/signalfx/golib/v3/datapoint"
 	"github.com/stretchr/testify/require"
@@ -117,6 +120,7 @@ func TestDatapointWriter(t *testing.T) {
 	})
 
 	for _, inBatchSize := range []int{1, 2, 3, 5, 8, 13} {
+		inBatchSize := inBatchSize
 		t.Run(fmt.Sprintf("Should cycle buffer without losing anything (inBatchSize: %d)", inBatchSize), func(t *testing.T) {
 			t.Parallel()
 			ts := setupDatapointTesting(0)
@@ -156,6 +160,7 @@ func TestDatapointWriter(t *testing.T) {
 
 	for _, maxBuff := range []int{100, 2500, 4999, 9997, 9999} {
 		for _, inputSize := range []int{1, 11} {
+			inputSize := inputSize
 			t.Run(fmt.Sprintf("Should overflow cleanly with %d max, inputSize: %d", maxBuff, inputSize), func(t *testing.T) {
 				t.Parallel()
 				ts := setupDatapointTesting(1000)
@@ -181,12 +186,14 @@ func TestDatapointWriter(t *testing.T) {
 
 				go func() {
 					// Wait to let input get processed a bit before letting things
-					// through so that the buffer gets backed up
+					// through so that the buffer gets backed up.  Every request
+					// slot is filled with a full batch, so wait for all of them
+					// to arrive so none are counted after the fact.
 					for {
 						ts.ReceiveLock.Lock()
 						initialReceivedCount = len(ts.Received)
 						ts.ReceiveLock.Unlock()
-						if initialReceivedCount > 0 {
+						if initialReceivedCount >= ts.Writer.MaxRequests*ts.Writer.MaxBatchSize {
 							time.Sleep(1 * time.Second)
 							break
 						}
@@ -286,10 +293,42 @@ func TestDatapointWriter(t *testing.T) {
 		ts.Writer.MaxRequests = 1
 		ts.Writer.MaxBuffered = 100
 
+		var overwrittenLock sync.Mutex
+		var overwritten []int
+		ts.Writer.OverwriteFunc = func(dp *datapoint.Datapoint) {
+			overwrittenLock.Lock()
+			defer overwrittenLock.Unlock()
+			overwritten = append(overwritten, dp.Meta["i"].(int))
+		}
+		// Prevent things from being sent
+		ts.SendLock.Lock()
+
+		ts.Writer.Start(ts.Ctx)
+
+		for i := 0; i < ts.Writer.MaxBuffered+11; i++ {
+			ts.Input <- []*datapoint.Datapoint{{Meta: map[interface{}]interface{}{"i": i}}}
+		}
+
+		require.Eventually(t, func() bool {
+			overwrittenLock.Lock()
+			defer overwrittenLock.Unlock()
+			return len(overwritten) == 10
+		}, 3*time.Second, 50*time.Millisecond)
+		// The first is in flight, so the next oldest are lost
+		require.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, overwritten)
+	})
+
+	t.Run("Should call WithLegacyOverwriteFunc on overwrites", func(t *testing.T) {
+		t.Parallel()
+		ts := setupDatapointTesting(1000)
+		ts.Writer.MaxBatchSize = 1
+		ts.Writer.MaxRequests = 1
+		ts.Writer.MaxBuffered = 100
+
 		overwrittenCount := int64(0)
-		ts.Writer.OverwriteFunc = func() {
+		ts.Writer.WithLegacyOverwriteFunc(func() {
 			atomic.AddInt64(&overwrittenCount, 1)
-		}
+		})
 		// Prevent things from being sent
 		ts.SendLock.Lock()
 
@@ -302,6 +341,31 @@ func TestDatapointWriter(t *testing.T) {
 		require.Eventually(t, func() bool { return atomic.LoadInt64(&overwrittenCount) == 10 }, 3*time.Second, 50*time.Millisecond)
 	})
 
+	t.Run("Should call send callbacks", func(t *testing.T) {
+		t.Parallel()
+		ts := setupDatapointTesting(1000)
+		ts.Writer.MaxBatchSize = 1
+
+		sent := int64(0)
+		failed := int64(0)
+		ts.Writer.OnSendSuccess = func(batch []*datapoint.Datapoint) {
+			atomic.AddInt64(&sent, int64(len(batch)))
+		}
+		ts.Writer.OnSendFailure = func(batch []*datapoint.Datapoint, err error) {
+			require.Error(t, err)
+			atomic.AddInt64(&failed, int64(len(batch)))
+		}
+		ts.Writer.Start(ts.Ctx)
+
+		ts.Input <- []*datapoint.Datapoint{{}, {}}
+		require.Eventually(t, func() bool { return atomic.LoadInt64(&sent) == 2 }, 3*time.Second, 50*time.Millisecond)
+
+		ts.SendShouldFail.Store(true)
+		ts.Input <- []*datapoint.Datapoint{{}}
+		require.Eventually(t, func() bool { return atomic.LoadInt64(&failed) == 1 }, 3*time.Second, 50*time.Millisecond)
+		require.Equal(t, int64(2), atomic.LoadInt64(&sent))
+	})
+
 	t.Run("Can handle big inputs", func(t *testing.T) {
 		t.Parallel()
 		ts := setupDatapointTesting(1000)
@@ -310,7 +374,7 @@ func TestDatapointWriter(t *testing.T) {
 		ts.Writer.MaxBuffered = 100
 
 		overwrittenCount := int64(0)
-		ts.Writer.OverwriteFunc = func() {
+		ts.Writer.OverwriteFunc = func(*datapoint.Datapoint) {
 			atomic.AddInt64(&overwrittenCount, 1)
 		}
 		ts.Writer.Start(ts.Ctx)
@@ -358,6 +422,399 @@ func ExampleDatapointWriter() {
 	writer.WaitForShutdown()
 }
 
+func TestDatapointWriterCodec(t *testing.T) {
+	out := &bytes.Buffer{}
+	in := make(chan []*datapoint.Datapoint)
+	writer := &DatapointWriter{
+		InputChan:    in,
+		Codec:        DatapointJSONCodec{},
+		Output:       out,
+		MaxBatchSize: 10,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	writer.Start(ctx)
+
+	for i := 0; i < 100; i++ {
+		in <- []*datapoint.Datapoint{{}}
+	}
+	cancel()
+	writer.WaitForShutdown()
+
+	decoded := 0
+	for {
+		data, err := ReadFrame(out)
+		if err == io.EOF {
+			break
+		}
+		require.Nil(t, err)
+		batch, err := DatapointJSONCodec{}.Decode(data)
+		require.Nil(t, err)
+		decoded += len(batch)
+	}
+	require.Equal(t, 100, decoded)
+	require.Equal(t, findInternalMetricWithName(writer, "datapoints_sent"), 100)
+}
+
+func TestDatapointWriterCodecValidation(t *testing.T) {
+	writer := &DatapointWriter{
+		InputChan: make(chan []*datapoint.Datapoint),
+		Codec:     DatapointJSONCodec{},
+	}
+	require.Error(t, writer.Validate(), "Should need an Output with a Codec")
+	require.Panics(t, func() { writer.Start(context.Background()) })
+
+	writer.Output = &bytes.Buffer{}
+	writer.SendFunc = func(context.Context, []*datapoint.Datapoint) error { return nil }
+	require.Error(t, writer.Validate(), "Should not have both a SendFunc and a Codec")
+
+	writer.SendFunc = nil
+	require.Nil(t, writer.Validate())
+}
+
+func TestDatapointWriterDeduplication(t *testing.T) {
+	ts := setupDatapointTesting(0)
+	ts.Writer.MaxDeduplicationCacheSize = 2
+	ts.Writer.WithDeduplication(func(inst *datapoint.Datapoint) string {
+		return fmt.Sprint(inst.Meta["i"])
+	})
+	ts.Writer.Start(ts.Ctx)
+
+	for _, i := range []int{0, 1, 0, 2, 1, 2} {
+		ts.Input <- []*datapoint.Datapoint{{Meta: map[interface{}]interface{}{"i": i}}}
+	}
+	ts.Cancel()
+	ts.Writer.WaitForShutdown()
+
+	// Seeing 0 again keeps it in the cache, so 1 is evicted when 2 arrives
+	// and gets through a second time
+	require.Len(t, ts.Received, 4)
+	require.Equal(t, findInternalMetricWithName(ts.Writer, "datapoints_deduplicated"), 2)
+}
+
+func TestDatapointWriterTransformer(t *testing.T) {
+	ts := setupDatapointTesting(0)
+	ts.Writer.WithTransformer(func(inst *datapoint.Datapoint) []*datapoint.Datapoint {
+		i := inst.Meta["i"].(int)
+		if i%2 == 1 {
+			return nil
+		}
+		return []*datapoint.Datapoint{
+			{Meta: map[interface{}]interface{}{"i": i}},
+			{Meta: map[interface{}]interface{}{"i": i + 1}},
+		}
+	})
+	ts.Writer.Start(ts.Ctx)
+
+	for i := 0; i < 10; i++ {
+		ts.Input <- []*datapoint.Datapoint{{Meta: map[interface{}]interface{}{"i": i}}}
+	}
+	ts.Cancel()
+	ts.Writer.WaitForShutdown()
+
+	ts.assertAllReceived(t, 10)
+	require.Equal(t, findInternalMetricWithName(ts.Writer, "datapoints_transformed"), 10)
+	require.Equal(t, findInternalMetricWithName(ts.Writer, "datapoints_filtered"), 5)
+}
+
+func TestDatapointWriterCardinalityLimit(t *testing.T) {
+	ts := setupDatapointTesting(0)
+	var rejected []error
+	ts.Writer.MaxUniqueDatapoints = 100
+	ts.Writer.CardinalityKeyFunc = func(inst *datapoint.Datapoint) string {
+		return fmt.Sprint(inst.Meta["i"].(int) % 1000)
+	}
+	ts.Writer.CardinalityRejectFunc = func(inst *datapoint.Datapoint, err error) {
+		rejected = append(rejected, err)
+	}
+	ts.Writer.Start(ts.Ctx)
+
+	for i := 0; i < 2000; i++ {
+		ts.Input <- []*datapoint.Datapoint{{Meta: map[interface{}]interface{}{"i": i}}}
+	}
+	ts.Cancel()
+	ts.Writer.WaitForShutdown()
+
+	sent := findInternalMetricWithName(ts.Writer, "datapoints_sent")
+	rejectedCount := findInternalMetricWithName(ts.Writer, "datapoints_cardinality_rejected")
+	require.Equal(t, 2000, sent+rejectedCount)
+	require.Len(t, rejected, rejectedCount)
+	require.Equal(t, ErrCardinalityLimitExceeded, rejected[0])
+	// The first 100 or so keys get through twice, and a few later ones may
+	// slip past the sketch
+	require.InDelta(t, 200, sent, 30)
+}
+
+func TestDatapointWriterCardinalityLimitAtScale(t *testing.T) {
+	const limit = 100000
+	ts := setupDatapointTesting(0)
+	ts.Writer.MaxUniqueDatapoints = limit
+	ts.Writer.MaxBuffered = 3 * limit
+	ts.Writer.CardinalityKeyFunc = func(inst *datapoint.Datapoint) string {
+		return fmt.Sprint("key-", inst.Meta["i"].(int))
+	}
+	ts.Writer.Start(ts.Ctx)
+
+	// Twice as many distinct keys as the limit, then the first half again
+	send := func(from, to int) {
+		for i := from; i < to; i += 1000 {
+			insts := make([]*datapoint.Datapoint, 0, 1000)
+			for j := i; j < i+1000; j++ {
+				insts = append(insts, &datapoint.Datapoint{Meta: map[interface{}]interface{}{"i": j}})
+			}
+			ts.Input <- insts
+		}
+	}
+	send(0, 2*limit)
+	send(0, limit)
+	ts.Cancel()
+	ts.Writer.WaitForShutdown()
+
+	sent := findInternalMetricWithName(ts.Writer, "datapoints_sent")
+	require.Equal(t, 3*limit, sent+findInternalMetricWithName(ts.Writer, "datapoints_cardinality_rejected"))
+
+	ts.ReceiveLock.Lock()
+	defer ts.ReceiveLock.Unlock()
+	var accepted, repeats, pastLimit int
+	seen := map[int]bool{}
+	for _, dp := range ts.Received {
+		i := dp.Meta["i"].(int)
+		if seen[i] {
+			repeats++
+			continue
+		}
+		seen[i] = true
+		accepted++
+	}
+	for i := range seen {
+		if i >= limit+limit/50 {
+			pastLimit++
+		}
+	}
+	require.InDelta(t, limit, accepted, 0.03*limit, "The limit should hold to within the sketch's error")
+	require.True(t, pastLimit < limit/50, "%d keys got past the limit", pastLimit)
+	require.True(t, repeats > limit*97/100, "Keys accepted before the limit should still be accepted, got %d", repeats)
+}
+
+func TestDatapointWriterSendDuration(t *testing.T) {
+	ts := setupDatapointTesting(0)
+	sender := ts.Writer.SendFunc
+	ts.Writer.SendFunc = func(ctx context.Context, insts []*datapoint.Datapoint) error {
+		time.Sleep(20 * time.Millisecond)
+		return sender(ctx, insts)
+	}
+	ts.Writer.Start(ts.Ctx)
+
+	for i := 0; i < 10; i++ {
+		ts.Input <- []*datapoint.Datapoint{{Meta: map[interface{}]interface{}{"i": i}}}
+	}
+	ts.Cancel()
+	ts.Writer.WaitForShutdown()
+
+	p50 := findInternalMetricWithName(ts.Writer, "datapoint_send_duration_p50_ms")
+	p99 := findInternalMetricWithName(ts.Writer, "datapoint_send_duration_p99_ms")
+	slowest := findInternalMetricWithName(ts.Writer, "datapoint_send_duration_max_ms")
+	require.True(t, p50 >= 20, "p50 of %dms should include the sleep", p50)
+	require.True(t, p50 <= p99 && p99 <= slowest, "percentiles should be ordered: %d, %d, %d", p50, p99, slowest)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts.Writer.Start(ctx)
+	require.Equal(t, int64(0), atomic.LoadInt64(&ts.Writer.MaxSendDurationMs), "Start should reset the durations")
+}
+
+func TestDatapointWriterInputChan(t *testing.T) {
+	ts := setupDatapointTesting(10)
+	require.Equal(t, 0.0, ts.Writer.InputChannelUtilization())
+
+	for i := 0; i < 3; i++ {
+		ts.Input <- []*datapoint.Datapoint{{Meta: map[interface{}]interface{}{"i": i}}}
+	}
+	require.Equal(t, 3, ts.Writer.InputChanLen())
+	require.Equal(t, 10, ts.Writer.InputChanCap())
+	require.InDelta(t, 0.3, ts.Writer.InputChannelUtilization(), 0.001)
+
+	ts.Writer.Start(ts.Ctx)
+	ts.Cancel()
+	ts.Writer.WaitForShutdown()
+
+	require.Equal(t, 0, findInternalMetricWithName(ts.Writer, "datapoints_input_chan_len"))
+	require.Equal(t, 10, findInternalMetricWithName(ts.Writer, "datapoints_input_chan_cap"))
+	ts.assertAllReceived(t, 3)
+
+	unbuffered := &DatapointWriter{InputChan: make(chan []*datapoint.Datapoint)}
+	require.Equal(t, 0.0, unbuffered.InputChannelUtilization())
+}
+
+func TestDatapointWriterCircuitBreaker(t *testing.T) {
+	ts := setupDatapointTesting(0)
+	var calls int64
+	sender := ts.Writer.SendFunc
+	ts.Writer.SendFunc = func(ctx context.Context, insts []*datapoint.Datapoint) error {
+		atomic.AddInt64(&calls, 1)
+		return sender(ctx, insts)
+	}
+	ts.Writer.MaxRequests = 1
+	ts.Writer.MaxBatchSize = 1
+	ts.Writer.CircuitBreakerThreshold = 0.5
+	ts.Writer.CircuitBreakerWindow = 100 * time.Millisecond
+	ts.SendShouldFail.Store(true)
+	ts.Writer.Start(ts.Ctx)
+	require.Equal(t, CircuitClosed, ts.Writer.CircuitState())
+
+	for i := 0; i < 20; i++ {
+		ts.Input <- []*datapoint.Datapoint{{Meta: map[interface{}]interface{}{"i": i}}}
+	}
+	require.Eventually(t, func() bool { return ts.Writer.CircuitState() != CircuitClosed }, time.Second, 50*time.Millisecond)
+	require.True(t, atomic.LoadInt64(&calls) < 20, "Open circuit should stop sends")
+
+	// Let the next probe succeed, which should send everything buffered
+	ts.SendShouldFail.Store(false)
+	require.Eventually(t, func() bool { return ts.Writer.CircuitState() == CircuitClosed }, time.Second, 50*time.Millisecond)
+	ts.Cancel()
+	ts.Writer.WaitForShutdown()
+
+	failed := findInternalMetricWithName(ts.Writer, "datapoints_failed")
+	require.True(t, failed >= breaker.MinRequests)
+	require.Equal(t, 20, failed+findInternalMetricWithName(ts.Writer, "datapoints_sent"))
+	require.Equal(t, int(CircuitClosed), findInternalMetricWithName(ts.Writer, "datapoint_circuit_state"))
+}
+
+func TestDatapointWriterCircuitBreakerShutdown(t *testing.T) {
+	ts := setupDatapointTesting(0)
+	ts.Writer.MaxRequests = 1
+	ts.Writer.MaxBatchSize = 1
+	ts.Writer.CircuitBreakerThreshold = 0.5
+	// Long enough that no probe is sent before shutdown
+	ts.Writer.CircuitBreakerWindow = time.Hour
+	ts.SendShouldFail.Store(true)
+	ts.Writer.Start(ts.Ctx)
+
+	for i := 0; i < 20; i++ {
+		ts.Input <- []*datapoint.Datapoint{{Meta: map[interface{}]interface{}{"i": i}}}
+	}
+	require.Eventually(t, func() bool { return ts.Writer.CircuitState() == CircuitOpen }, time.Second, 50*time.Millisecond)
+
+	ts.SendShouldFail.Store(false)
+	ts.Cancel()
+	ts.Writer.WaitForShutdown()
+
+	failed := findInternalMetricWithName(ts.Writer, "datapoints_failed")
+	sent := findInternalMetricWithName(ts.Writer, "datapoints_sent")
+	require.True(t, sent > 0, "Buffered datapoints should be sent on shutdown despite the open circuit")
+	require.Equal(t, 20, failed+sent)
+	require.Len(t, ts.Received, sent)
+}
+
+func TestDatapointWriterStalledSend(t *testing.T) {
+	ts := setupDatapointTesting(0)
+	var calls int64
+	var failedErr atomic.Value
+	sender := ts.Writer.SendFunc
+	ts.Writer.SendFunc = func(ctx context.Context, insts []*datapoint.Datapoint) error {
+		if atomic.AddInt64(&calls, 1) == 1 {
+			// Hang until the writer gives up on the send
+			<-ctx.Done()
+			time.Sleep(50 * time.Millisecond)
+			return nil
+		}
+		return sender(ctx, insts)
+	}
+	ts.Writer.OnSendFailure = func(batch []*datapoint.Datapoint, err error) {
+		failedErr.Store(err)
+	}
+	ts.Writer.MaxRequests = 1
+	ts.Writer.MaxBatchSize = 1
+	ts.Writer.StalledSendTimeout = 20 * time.Millisecond
+	ts.Writer.Start(ts.Ctx)
+
+	for i := 0; i < 5; i++ {
+		ts.Input <- []*datapoint.Datapoint{{Meta: map[interface{}]interface{}{"i": i}}}
+	}
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt64(&ts.Writer.TotalSent) == 4
+	}, time.Second, 50*time.Millisecond, "Other sends should go through once the stalled one is abandoned")
+	ts.Cancel()
+	ts.Writer.WaitForShutdown()
+
+	require.Equal(t, ErrSendStalled, failedErr.Load())
+	require.Equal(t, 1, findInternalMetricWithName(ts.Writer, "datapoint_sends_stalled"))
+	require.Equal(t, 1, findInternalMetricWithName(ts.Writer, "datapoints_failed"))
+}
+
+func TestDatapointWriterStalledSendLimit(t *testing.T) {
+	ts := setupDatapointTesting(0)
+	var calls int64
+	hang := make(chan struct{})
+	sender := ts.Writer.SendFunc
+	ts.Writer.SendFunc = func(ctx context.Context, insts []*datapoint.Datapoint) error {
+		if atomic.AddInt64(&calls, 1) <= 2 {
+			// Hang, ignoring the context, until the test lets it go
+			<-hang
+		}
+		return sender(ctx, insts)
+	}
+	ts.Writer.MaxRequests = 1
+	ts.Writer.MaxBatchSize = 1
+	ts.Writer.StalledSendTimeout = 20 * time.Millisecond
+	ts.Writer.Start(ts.Ctx)
+
+	for i := 0; i < 3; i++ {
+		ts.Input <- []*datapoint.Datapoint{{Meta: map[interface{}]interface{}{"i": i}}}
+	}
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt64(&ts.Writer.TotalStalledSends) == 2
+	}, time.Second, 50*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, int64(2), atomic.LoadInt64(&calls), "The second stalled send should be waited for rather than abandoned")
+
+	close(hang)
+	ts.Cancel()
+	ts.Writer.WaitForShutdown()
+
+	require.Equal(t, 2, findInternalMetricWithName(ts.Writer, "datapoint_sends_stalled"))
+	require.Equal(t, 1, findInternalMetricWithName(ts.Writer, "datapoints_failed"))
+	require.Equal(t, 2, findInternalMetricWithName(ts.Writer, "datapoints_sent"))
+}
+
+func TestDatapointWriterSharding(t *testing.T) {
+	ts := setupDatapointTesting(10)
+	var lock sync.Mutex
+	shardOf := map[int]int{}
+	sender := ts.Writer.SendFunc
+	ts.Writer.SendFunc = func(ctx context.Context, insts []*datapoint.Datapoint) error {
+		shard, ok := ShardFromContext(ctx)
+		require.True(t, ok, "Shard should be in the context")
+		lock.Lock()
+		for _, inst := range insts {
+			shardOf[inst.Meta["i"].(int)] = shard
+		}
+		lock.Unlock()
+		return sender(ctx, insts)
+	}
+	ts.Writer.WithShardingFunc(func(inst *datapoint.Datapoint) int {
+		return inst.Meta["i"].(int)
+	}, 3)
+	ts.Writer.Start(ts.Ctx)
+
+	for i := 0; i < 100; i += 10 {
+		var insts []*datapoint.Datapoint
+		for j := i; j < i+10; j++ {
+			insts = append(insts, &datapoint.Datapoint{Meta: map[interface{}]interface{}{"i": j}})
+		}
+		ts.Input <- insts
+	}
+	ts.Cancel()
+	ts.Writer.WaitForShutdown()
+
+	ts.assertAllReceived(t, 100)
+	for i, shard := range shardOf {
+		require.Equal(t, i%3, shard, "instance %d went to the wrong shard", i)
+	}
+	require.Equal(t, 34, findInternalMetricWithName(ts.Writer, "shard_0_datapoints_sent"))
+	require.Equal(t, 33, findInternalMetricWithName(ts.Writer, "shard_2_datapoints_sent"))
+}
+
 func BenchmarkDatapointWriter(b *testing.B) {
 	var doneSignal atomic.Value
 	received := int64(0)
@@ -0,0 +1,25 @@
+package writer
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// ReadFrame reads the next batch written by a writer with a Codec, which
+// prefixes each batch with its length as a 4 byte big-endian integer.  It
+// returns io.EOF once r has no more batches, and io.ErrUnexpectedEOF if r ends
+// part way through one.
+func ReadFrame(r io.Reader) ([]byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	data := make([]byte, binary.BigEndian.Uint32(header[:]))
+	if _, err := io.ReadFull(r, data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,27 @@
+package writer
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFrame(t *testing.T) {
+	r := bytes.NewReader([]byte{0, 0, 0, 2, 'h', 'i', 0, 0, 0, 0, 0, 0, 0, 3, 'x'})
+
+	data, err := ReadFrame(r)
+	require.Nil(t, err)
+	require.Equal(t, []byte("hi"), data)
+
+	data, err = ReadFrame(r)
+	require.Nil(t, err)
+	require.Equal(t, []byte{}, data)
+
+	_, err = ReadFrame(r)
+	require.Equal(t, io.ErrUnexpectedEOF, err, "Should fail on a truncated batch")
+
+	_, err = ReadFrame(r)
+	require.Equal(t, io.EOF, err)
+}
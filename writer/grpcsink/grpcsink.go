@@ -0,0 +1,85 @@
+// Package grpcsink sends batches from the datapoint and span writers over
+// gRPC.  It is separate from the writer package so that only programs that
+// use it depend on gRPC.
+package grpcsink
+
+import (
+	"context"
+	"io"
+
+	"github.com/adampetrovic/signalfx-go/writer"
+	"github.com/signalfx/golib/v3/datapoint"
+	"github.com/signalfx/golib/v3/trace"
+	"google.golang.org/grpc"
+)
+
+// NewDatapointSendFunc returns a SendFunc for a writer.DatapointWriter that
+// encodes each batch with codec and sends it as a single message on a new
+// client stream to the gRPC method on conn, e.g.
+// "/signalfx.ingest.DatapointIngest/Send".  It returns once the server has
+// replied, and the reply's content is ignored.  The message is whatever codec
+// produces, so the server must expect that encoding; proto/ingest.proto
+// defines a service for writer.DatapointProtobufCodec.
+func NewDatapointSendFunc(conn *grpc.ClientConn, method string, codec writer.DatapointCodec) writer.DatapointSender {
+	return func(ctx context.Context, dps []*datapoint.Datapoint) error {
+		payload, err := codec.Encode(dps)
+		if err != nil {
+			return err
+		}
+		return sendGRPC(ctx, conn, method, payload)
+	}
+}
+
+// NewSpanSendFunc is like NewDatapointSendFunc but for a writer.SpanWriter.
+// Spans have no protobuf format, so the server has to decode messages with
+// the same codec as the writer, e.g. writer.SpanJSONCodec.
+func NewSpanSendFunc(conn *grpc.ClientConn, method string, codec writer.SpanCodec) writer.SpanSender {
+	return func(ctx context.Context, spans []*trace.Span) error {
+		payload, err := codec.Encode(spans)
+		if err != nil {
+			return err
+		}
+		return sendGRPC(ctx, conn, method, payload)
+	}
+}
+
+// The stream that gRPC send funcs call: the client sends one message per
+// batch and the server replies once.
+var grpcStreamDesc = &grpc.StreamDesc{ClientStreams: true}
+
+// sendGRPC sends payload, which is already an encoded message, on a new
+// client stream to method and waits for the server to reply.
+func sendGRPC(ctx context.Context, conn *grpc.ClientConn, method string, payload []byte) error {
+	stream, err := conn.NewStream(ctx, grpcStreamDesc, method)
+	if err != nil {
+		return err
+	}
+	msg := rawMessage(payload)
+	// io.EOF means the server ended the stream, and why is returned by RecvMsg
+	if err := stream.SendMsg(&msg); err != nil && err != io.EOF {
+		return err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+	var reply rawMessage
+	return stream.RecvMsg(&reply)
+}
+
+// rawMessage is a message that has already been encoded.  It implements the
+// proto.Marshaler and proto.Unmarshaler interfaces so that gRPC's default
+// codec passes it through untouched.
+type rawMessage []byte
+
+func (m *rawMessage) Reset()         { *m = nil }
+func (m *rawMessage) String() string { return string(*m) }
+func (*rawMessage) ProtoMessage()    {}
+
+func (m *rawMessage) Marshal() ([]byte, error) {
+	return *m, nil
+}
+
+func (m *rawMessage) Unmarshal(data []byte) error {
+	*m = append((*m)[:0], data...)
+	return nil
+}
@@ -0,0 +1,89 @@
+package grpcsink
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/adampetrovic/signalfx-go/writer"
+	"github.com/signalfx/golib/v3/datapoint"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startGRPCServer serves handle for every method on an in-memory listener and
+// returns a connection to it, and a function that stops both.
+func startGRPCServer(t *testing.T, handle func(method string, msgs [][]byte) error) (*grpc.ClientConn, func()) {
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(grpc.UnknownServiceHandler(func(srv interface{}, stream grpc.ServerStream) error {
+		method, _ := grpc.MethodFromServerStream(stream)
+		var msgs [][]byte
+		for {
+			var msg rawMessage
+			if err := stream.RecvMsg(&msg); err != nil {
+				break
+			}
+			msgs = append(msgs, msg)
+		}
+		if err := handle(method, msgs); err != nil {
+			return err
+		}
+		return stream.SendMsg(&rawMessage{})
+	}))
+	go server.Serve(lis)
+
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(), grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.Dial()
+	}))
+	require.Nil(t, err)
+	return conn, func() {
+		conn.Close()
+		server.Stop()
+	}
+}
+
+func TestDatapointSendFunc(t *testing.T) {
+	type call struct {
+		method string
+		msgs   [][]byte
+	}
+	received := make(chan call, 1)
+	conn, stop := startGRPCServer(t, func(method string, msgs [][]byte) error {
+		received <- call{method, msgs}
+		return nil
+	})
+	defer stop()
+
+	send := NewDatapointSendFunc(conn, "/signalfx.ingest.DatapointIngest/Send", writer.DatapointProtobufCodec{})
+	batch := []*datapoint.Datapoint{
+		datapoint.New("cpu.utilization", map[string]string{"host": "a"}, datapoint.NewFloatValue(12.5), datapoint.Gauge, time.Unix(1500000000, 0)),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.Nil(t, send(ctx, batch))
+
+	got := <-received
+	require.Equal(t, "/signalfx.ingest.DatapointIngest/Send", got.method)
+	require.Len(t, got.msgs, 1, "Each batch should be a single message")
+	dps, err := writer.DatapointProtobufCodec{}.Decode(got.msgs[0])
+	require.Nil(t, err)
+	require.Len(t, dps, 1)
+	require.Equal(t, "cpu.utilization", dps[0].Metric)
+}
+
+func TestSpanSendFuncError(t *testing.T) {
+	conn, stop := startGRPCServer(t, func(method string, msgs [][]byte) error {
+		return status.Error(codes.ResourceExhausted, "slow down")
+	})
+	defer stop()
+
+	send := NewSpanSendFunc(conn, "/signalfx.ingest.SpanIngest/Send", writer.SpanJSONCodec{})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := send(ctx, nil)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err), "The server's error should be returned")
+}
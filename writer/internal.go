@@ -27,6 +27,7 @@ package writer
 
 import (
 	"context"
+	"errors"
 
 	"github.com/signalfx/golib/v3/datapoint"
 )
@@ -36,3 +37,68 @@ type Writer interface {
 	InternalMetrics(prefix string) []*datapoint.Datapoint
 	Start(context.Context)
 }
+
+// ErrCardinalityLimitExceeded is passed to a writer's CardinalityRejectFunc
+// for items rejected because of its MaxUniqueDatapoints or MaxUniqueSpans
+// limit.
+var ErrCardinalityLimitExceeded = errors.New("writer cardinality limit exceeded")
+
+// ErrSendStalled is passed to a writer's OnSendFailure for batches whose send
+// was abandoned after taking longer than StalledSendTimeout.
+var ErrSendStalled = errors.New("writer send stalled")
+
+// CircuitState is the state of a writer's circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed means the writer is sending normally.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen means too many sends have failed recently, so the writer
+	// is buffering without sending.
+	CircuitOpen
+	// CircuitHalfOpen means the writer is sending a single item to test
+	// whether sends have started working again.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+type shardContextKey struct{}
+
+// ShardFromContext returns which shard a batch is for, when called with the
+// context passed to the SendFunc of a writer set up with WithShardingFunc.
+func ShardFromContext(ctx context.Context) (int, bool) {
+	shard, ok := ctx.Value(shardContextKey{}).(int)
+	return shard, ok
+}
+
+// shardContext has the values of the context a sharded writer was started
+// with, plus the shard number, but is only canceled once the parent writer
+// has routed all of its input to the shard.
+type shardContext struct {
+	context.Context
+	values context.Context
+	shard  int
+}
+
+func (c *shardContext) Value(key interface{}) interface{} {
+	if key == (shardContextKey{}) {
+		return c.shard
+	}
+	return c.values.Value(key)
+}
+
+func newShardContext(parent context.Context, shard int) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &shardContext{Context: ctx, values: parent, shard: shard}, cancel
+}
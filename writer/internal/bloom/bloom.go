@@ -0,0 +1,83 @@
+// Package bloom implements a Bloom filter for checking whether a string has
+// been seen before using a fixed amount of memory.
+package bloom
+
+import (
+	"hash/fnv"
+	"math"
+)
+
+// Filter is a Bloom filter.  It never reports that a key added to it is
+// missing, but may report that a key is present when it isn't.  It is not
+// thread-safe.
+type Filter struct {
+	bits   []uint64
+	m      uint64
+	hashes int
+}
+
+// New makes a filter that holds up to n keys with a false-positive rate of
+// about p, which must be between 0 and 1.  Past n keys the false-positive
+// rate climbs.
+func New(n int, p float64) *Filter {
+	if n < 1 {
+		n = 1
+	}
+	m := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	if m < 64 {
+		m = 64
+	}
+	k := int(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return &Filter{
+		bits:   make([]uint64, (m+63)/64),
+		m:      m,
+		hashes: k,
+	}
+}
+
+// hash returns two independent hashes of key, combined as h1 + i*h2 to
+// get each of the filter's bit positions.
+func hash(key string) (h1, h2 uint64) {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	x := h.Sum64()
+	h1 = mix(x)
+	// An odd step visits different bits for each hash
+	h2 = mix(h1^0x9e3779b97f4a7c15) | 1
+	return h1, h2
+}
+
+// mix is the splitmix64 finalizer, which spreads FNV's poorly distributed
+// high bits.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// Add key to the filter.
+func (f *Filter) Add(key string) {
+	h1, h2 := hash(key)
+	for i := 0; i < f.hashes; i++ {
+		bit := (h1 + uint64(i)*h2) % f.m
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// Contains returns whether key has probably been added.
+func (f *Filter) Contains(key string) bool {
+	h1, h2 := hash(key)
+	for i := 0; i < f.hashes; i++ {
+		bit := (h1 + uint64(i)*h2) % f.m
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,29 @@
+package bloom
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter(t *testing.T) {
+	for _, n := range []int{1000, 100000} {
+		f := New(n, 0.01)
+		for i := 0; i < n; i++ {
+			f.Add(fmt.Sprintf("host-%d", i))
+		}
+		for i := 0; i < n; i++ {
+			require.True(t, f.Contains(fmt.Sprintf("host-%d", i)), "added keys must always be found")
+		}
+
+		falsePositives := 0
+		for i := n; i < 2*n; i++ {
+			if f.Contains(fmt.Sprintf("host-%d", i)) {
+				falsePositives++
+			}
+		}
+		rate := float64(falsePositives) / float64(n)
+		require.True(t, rate < 0.02, "false-positive rate %.4f too high for %d keys", rate, n)
+	}
+}
@@ -0,0 +1,162 @@
+// Package breaker implements a circuit breaker that trips when too many
+// requests fail within a sliding window of time.
+package breaker
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// State of a circuit breaker.
+type State int32
+
+// The values match those of writer.CircuitState.
+const (
+	// Closed lets all requests through.
+	Closed State = iota
+	// Open stops all requests until the window has passed.
+	Open
+	// HalfOpen lets a single probe request through to test whether the
+	// failures have stopped.
+	HalfOpen
+)
+
+// MinRequests is how many requests must have completed within the window
+// before the breaker can trip, so that a handful of early failures don't open
+// it.
+const MinRequests = 5
+
+// Failures are counted in this many buckets spanning the window.
+const numBuckets = 10
+
+type bucket struct {
+	slot      int64
+	successes int
+	failures  int
+}
+
+// Breaker is a circuit breaker.  It is safe for concurrent use.
+type Breaker struct {
+	threshold float64
+	window    time.Duration
+
+	state int32
+
+	lock     sync.Mutex
+	buckets  [numBuckets]bucket
+	openedAt time.Time
+	probing  bool
+}
+
+// New makes a breaker that opens when at least threshold of the requests,
+// as a fraction between 0 and 1, fail within window.  It stays open for
+// window before letting a probe through.
+func New(threshold float64, window time.Duration) *Breaker {
+	if window < numBuckets {
+		window = numBuckets
+	}
+	return &Breaker{
+		threshold: threshold,
+		window:    window,
+	}
+}
+
+// State returns the current state of the breaker.
+func (b *Breaker) State() State {
+	return State(atomic.LoadInt32(&b.state))
+}
+
+func (b *Breaker) setState(s State) {
+	atomic.StoreInt32(&b.state, int32(s))
+}
+
+// Allow reports whether a request can be made at now, and if so whether it is
+// a probe, which should be kept small since it is likely to fail.  Once an
+// open breaker's window has passed, it becomes half-open and allows exactly
+// one probe until that probe's result is recorded.
+func (b *Breaker) Allow(now time.Time) (allowed, probe bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	switch b.State() {
+	case Open:
+		if now.Sub(b.openedAt) < b.window {
+			return false, false
+		}
+		b.setState(HalfOpen)
+		fallthrough
+	case HalfOpen:
+		if b.probing {
+			return false, false
+		}
+		b.probing = true
+		return true, true
+	}
+	return true, false
+}
+
+// RetryIn returns how long after now an open breaker will allow a probe, or 0
+// if it isn't open.
+func (b *Breaker) RetryIn(now time.Time) time.Duration {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.State() != Open {
+		return 0
+	}
+	if wait := b.window - now.Sub(b.openedAt); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// Record the result of a request that finished at now, and whether it was
+// the probe returned by Allow.  A successful probe closes the breaker and a
+// failed one opens it again.  Results of other requests that finish while the
+// breaker isn't closed are ignored.
+func (b *Breaker) Record(now time.Time, success, probe bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if probe {
+		b.probing = false
+		if success {
+			b.buckets = [numBuckets]bucket{}
+			b.setState(Closed)
+		} else {
+			b.open(now)
+		}
+		return
+	}
+	if b.State() != Closed {
+		return
+	}
+
+	slot := now.UnixNano() / int64(b.window/numBuckets)
+	bu := &b.buckets[slot%numBuckets]
+	if bu.slot != slot {
+		*bu = bucket{slot: slot}
+	}
+	if success {
+		bu.successes++
+	} else {
+		bu.failures++
+	}
+
+	var total, failures int
+	for i := range b.buckets {
+		if b.buckets[i].slot > slot-numBuckets {
+			total += b.buckets[i].successes + b.buckets[i].failures
+			failures += b.buckets[i].failures
+		}
+	}
+	if total >= MinRequests && float64(failures)/float64(total) >= b.threshold {
+		b.open(now)
+	}
+}
+
+func (b *Breaker) open(now time.Time) {
+	b.openedAt = now
+	b.setState(Open)
+}
@@ -0,0 +1,57 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTripAndRecover(t *testing.T) {
+	now := time.Unix(1000, 0)
+	b := New(0.5, 10*time.Second)
+
+	for i := 0; i < MinRequests-1; i++ {
+		b.Record(now, false, false)
+	}
+	require.Equal(t, Closed, b.State(), "Shouldn't trip before MinRequests")
+
+	b.Record(now, false, false)
+	require.Equal(t, Open, b.State())
+	allowed, _ := b.Allow(now.Add(time.Second))
+	require.False(t, allowed)
+	require.Equal(t, 9*time.Second, b.RetryIn(now.Add(time.Second)))
+
+	allowed, probe := b.Allow(now.Add(10 * time.Second))
+	require.True(t, allowed)
+	require.True(t, probe)
+	require.Equal(t, HalfOpen, b.State())
+	allowed, _ = b.Allow(now.Add(10 * time.Second))
+	require.False(t, allowed, "Only one probe at a time")
+
+	b.Record(now.Add(11*time.Second), false, true)
+	require.Equal(t, Open, b.State(), "Failed probe should reopen")
+
+	allowed, probe = b.Allow(now.Add(21 * time.Second))
+	require.True(t, allowed && probe)
+	b.Record(now.Add(21*time.Second), true, true)
+	require.Equal(t, Closed, b.State())
+	allowed, probe = b.Allow(now.Add(21 * time.Second))
+	require.True(t, allowed)
+	require.False(t, probe)
+}
+
+func TestWindowSlides(t *testing.T) {
+	now := time.Unix(1000, 0)
+	b := New(0.5, 10*time.Second)
+
+	for i := 0; i < 4; i++ {
+		b.Record(now, false, false)
+	}
+	// The earlier failures have left the window
+	for i := 0; i < 4; i++ {
+		b.Record(now.Add(20*time.Second), true, false)
+	}
+	b.Record(now.Add(20*time.Second), false, false)
+	require.Equal(t, Closed, b.State())
+}
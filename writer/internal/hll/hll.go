@@ -0,0 +1,98 @@
+// Package hll implements a HyperLogLog sketch for estimating the number of
+// distinct strings seen in a stream using a fixed amount of memory.
+package hll
+
+import (
+	"hash/fnv"
+	"math"
+	"math/bits"
+)
+
+// DefaultPrecision gives 2^14 registers, which uses 16KiB and has a standard
+// error of about 0.8%.
+const DefaultPrecision = 14
+
+// Sketch is a HyperLogLog sketch.  It is not thread-safe.
+type Sketch struct {
+	precision uint8
+	registers []uint8
+
+	// Cached result of Estimate, cleared when a register changes
+	estimate      uint64
+	estimateValid bool
+}
+
+// New makes a sketch with 2^precision registers.  precision must be between
+// 4 and 18.
+func New(precision uint8) *Sketch {
+	if precision < 4 || precision > 18 {
+		panic("hll precision must be between 4 and 18")
+	}
+	return &Sketch{
+		precision:     precision,
+		registers:     make([]uint8, 1<<precision),
+		estimateValid: true,
+	}
+}
+
+func hash(key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	// FNV's high bits are poorly distributed for short keys, so mix them
+	// with the splitmix64 finalizer.
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+func (s *Sketch) register(key string) (idx uint64, rank uint8) {
+	h := hash(key)
+	idx = h >> (64 - s.precision)
+	// Set a guard bit so the rank can't exceed the remaining bits
+	rest := h<<s.precision | 1<<(s.precision-1)
+	return idx, uint8(bits.LeadingZeros64(rest)) + 1
+}
+
+// Add key to the sketch, returning whether that changed the sketch.  A key
+// that doesn't change the sketch has probably been added before.
+func (s *Sketch) Add(key string) bool {
+	idx, rank := s.register(key)
+	if rank <= s.registers[idx] {
+		return false
+	}
+	s.registers[idx] = rank
+	s.estimateValid = false
+	return true
+}
+
+// Estimate the number of distinct keys added.
+func (s *Sketch) Estimate() uint64 {
+	if s.estimateValid {
+		return s.estimate
+	}
+
+	m := float64(len(s.registers))
+	var sum float64
+	var zeros int
+	for _, r := range s.registers {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	alpha := 0.7213 / (1 + 1.079/m)
+	est := alpha * m * m / sum
+	if est <= 2.5*m && zeros > 0 {
+		// Linear counting is more accurate for small cardinalities
+		est = m * math.Log(m/float64(zeros))
+	}
+
+	s.estimate = uint64(est + 0.5)
+	s.estimateValid = true
+	return s.estimate
+}
@@ -0,0 +1,28 @@
+package hll
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimate(t *testing.T) {
+	for _, n := range []int{0, 10, 1000, 100000} {
+		s := New(DefaultPrecision)
+		for i := 0; i < n; i++ {
+			s.Add(fmt.Sprintf("host-%d", i))
+			// Duplicates shouldn't count
+			s.Add(fmt.Sprintf("host-%d", i/2))
+		}
+		errRatio := math.Abs(float64(s.Estimate())-float64(n)) / math.Max(float64(n), 1)
+		require.True(t, errRatio < 0.03, "estimate %d too far from %d", s.Estimate(), n)
+	}
+}
+
+func TestAdd(t *testing.T) {
+	s := New(DefaultPrecision)
+	require.True(t, s.Add("a"))
+	require.False(t, s.Add("a"))
+}
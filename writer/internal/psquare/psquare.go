@@ -0,0 +1,123 @@
+// Package psquare implements the P² algorithm of Jain and Chlamtac for
+// estimating a quantile of a stream of observations without storing them.
+package psquare
+
+import "sort"
+
+// Estimator estimates a single quantile using five markers.  It is not
+// thread-safe.
+type Estimator struct {
+	p     float64
+	count int
+
+	// Marker heights, actual positions, desired positions and the increment
+	// of each desired position per observation.  Positions are 1-based.
+	heights   [5]float64
+	positions [5]int
+	desired   [5]float64
+	increment [5]float64
+}
+
+// New makes an estimator of the p quantile.  p must be between 0 and 1,
+// exclusive.
+func New(p float64) *Estimator {
+	if p <= 0 || p >= 1 {
+		panic("psquare quantile must be between 0 and 1")
+	}
+	e := &Estimator{p: p}
+	e.Reset()
+	return e
+}
+
+// Reset forgets all of the observations.
+func (e *Estimator) Reset() {
+	p := e.p
+	*e = Estimator{
+		p:         p,
+		positions: [5]int{1, 2, 3, 4, 5},
+		desired:   [5]float64{1, 1 + 2*p, 1 + 4*p, 3 + 2*p, 5},
+		increment: [5]float64{0, p / 2, p, (1 + p) / 2, 1},
+	}
+}
+
+// Count returns the number of observations added since the last Reset.
+func (e *Estimator) Count() int {
+	return e.count
+}
+
+// Add an observation.
+func (e *Estimator) Add(x float64) {
+	if e.count < len(e.heights) {
+		e.heights[e.count] = x
+		e.count++
+		if e.count == len(e.heights) {
+			sort.Float64s(e.heights[:])
+		}
+		return
+	}
+	e.count++
+
+	// Find the cell k that x falls in, extending the extremes if needed.
+	var k int
+	switch {
+	case x < e.heights[0]:
+		e.heights[0] = x
+	case x >= e.heights[4]:
+		e.heights[4] = x
+		k = 3
+	default:
+		for k < 3 && x >= e.heights[k+1] {
+			k++
+		}
+	}
+
+	for i := k + 1; i < len(e.positions); i++ {
+		e.positions[i]++
+	}
+	for i := range e.desired {
+		e.desired[i] += e.increment[i]
+	}
+
+	// Move the middle markers towards their desired positions.
+	for i := 1; i <= 3; i++ {
+		d := e.desired[i] - float64(e.positions[i])
+		if (d >= 1 && e.positions[i+1]-e.positions[i] > 1) || (d <= -1 && e.positions[i-1]-e.positions[i] < -1) {
+			step := 1
+			if d < 0 {
+				step = -1
+			}
+			h := e.parabolic(i, float64(step))
+			if e.heights[i-1] >= h || h >= e.heights[i+1] {
+				h = e.linear(i, step)
+			}
+			e.heights[i] = h
+			e.positions[i] += step
+		}
+	}
+}
+
+func (e *Estimator) parabolic(i int, d float64) float64 {
+	n0, n1, n2 := float64(e.positions[i-1]), float64(e.positions[i]), float64(e.positions[i+1])
+	q0, q1, q2 := e.heights[i-1], e.heights[i], e.heights[i+1]
+	return q1 + d/(n2-n0)*((n1-n0+d)*(q2-q1)/(n2-n1)+(n2-n1-d)*(q1-q0)/(n1-n0))
+}
+
+func (e *Estimator) linear(i, step int) float64 {
+	return e.heights[i] + float64(step)*(e.heights[i+step]-e.heights[i])/float64(e.positions[i+step]-e.positions[i])
+}
+
+// Quantile returns the current estimate, or 0 if nothing has been added.
+// Until there are five observations it is the nearest-rank quantile of those
+// seen so far.
+func (e *Estimator) Quantile() float64 {
+	if e.count == 0 {
+		return 0
+	}
+	if e.count < len(e.heights) {
+		seen := make([]float64, e.count)
+		copy(seen, e.heights[:e.count])
+		sort.Float64s(seen)
+		return seen[int(e.p*float64(e.count-1)+0.5)]
+	}
+	return e.heights[2]
+}
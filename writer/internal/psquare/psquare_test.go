@@ -0,0 +1,34 @@
+package psquare
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuantile(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, p := range []float64{0.5, 0.95, 0.99} {
+		e := New(p)
+		for i := 0; i < 100000; i++ {
+			e.Add(r.Float64() * 1000)
+		}
+		require.Equal(t, 100000, e.Count())
+		require.InDelta(t, p*1000, e.Quantile(), 10, "p%v", p*100)
+	}
+}
+
+func TestFewObservations(t *testing.T) {
+	e := New(0.5)
+	require.Equal(t, 0.0, e.Quantile())
+
+	e.Add(30)
+	e.Add(10)
+	e.Add(20)
+	require.Equal(t, 20.0, e.Quantile())
+
+	e.Reset()
+	require.Equal(t, 0, e.Count())
+	require.Equal(t, 0.0, e.Quantile())
+}
@@ -5,7 +5,12 @@
 
 package writer
 
-import "github.com/signalfx/golib/v3/trace"
+import (
+	"runtime"
+	"sync/atomic"
+
+	"github.com/signalfx/golib/v3/trace"
+)
 
 // nolint: dupl
 
@@ -37,6 +42,30 @@ type SpanRingBuffer struct {
 	// Also by precalculating it, we can tell if the buffer was completely
 	// overwritten since the last read.
 	unprocessed int
+
+	// Copies of the counts for Stats, which can be called from other
+	// goroutines.  Accessed atomically.  statsVersion is odd while they are
+	// being updated, so that Stats can retry until it reads all three
+	// without an update in between.
+	statsVersion          uint64
+	statsUnprocessed      int64
+	statsTotalWritten     int64
+	statsTotalOverwritten int64
+}
+
+// SpanRingBufferStats is a snapshot of the state of a ring buffer, as
+// returned by SpanRingBuffer.Stats.
+type SpanRingBufferStats struct {
+	// How many elements fit in the buffer
+	Capacity int
+	// How many elements have been added but not read by NextBatch
+	Unprocessed int
+	// How many elements have been added in total
+	TotalWritten int64
+	// How many unprocessed elements have been overwritten in total
+	TotalOverwritten int64
+	// Unprocessed / Capacity
+	Utilization float64
 }
 
 // NewSpanRingBuffer creates a new initialized buffer ready for use.
@@ -52,17 +81,30 @@ func NewSpanRingBuffer(size int) *SpanRingBuffer {
 // buffer as the buffer wraps around.  Returns whether the new element
 // overwrites an uncommitted element already in the buffer.
 func (b *SpanRingBuffer) Add(inst *trace.Span) (isOverwrite bool) {
+	isOverwrite, _ = b.Push(inst)
+	return isOverwrite
+}
+
+// Push adds an Span:trace.Span to the buffer like Add, and also returns the
+// uncommitted element that it overwrote, if any.
+func (b *SpanRingBuffer) Push(inst *trace.Span) (isOverwrite bool, overwritten *trace.Span) {
+	atomic.AddUint64(&b.statsVersion, 1)
 	if b.unprocessed >= b.bufferLen {
 		isOverwrite = true
+		overwritten = b.buffer[b.nextIdx]
 		// Drag the read cursor along with the overwritten elements
 		b.readHigh++
 		if b.readHigh > b.bufferLen {
 			// Wrap around to cover the 0th element of the buffer
 			b.readHigh = 1
 		}
+		atomic.AddInt64(&b.statsTotalOverwritten, 1)
 	} else {
 		b.unprocessed++
+		atomic.StoreInt64(&b.statsUnprocessed, int64(b.unprocessed))
 	}
+	atomic.AddInt64(&b.statsTotalWritten, 1)
+	atomic.AddUint64(&b.statsVersion, 1)
 
 	b.buffer[b.nextIdx] = inst
 	b.nextIdx++
@@ -72,7 +114,7 @@ func (b *SpanRingBuffer) Add(inst *trace.Span) (isOverwrite bool) {
 		b.writtenCircuits++
 	}
 
-	return isOverwrite
+	return isOverwrite, overwritten
 }
 
 // Size returns how many elements can fit in the buffer at once.
@@ -113,8 +155,36 @@ func (b *SpanRingBuffer) NextBatch(maxSize int) []*trace.Span {
 	}
 
 	b.unprocessed -= b.readHigh - prevReadHigh
+	atomic.AddUint64(&b.statsVersion, 1)
+	atomic.StoreInt64(&b.statsUnprocessed, int64(b.unprocessed))
+	atomic.AddUint64(&b.statsVersion, 1)
 
 	out := b.buffer[prevReadHigh:b.readHigh]
 
 	return out
 }
+
+// Stats returns the current state of the buffer.  Unlike the rest of the
+// buffer's methods, it is safe to call from any goroutine and doesn't block
+// Add or NextBatch.  The counts are a consistent snapshot: a concurrent Add
+// is reflected in all of them or none.
+func (b *SpanRingBuffer) Stats() SpanRingBufferStats {
+	stats := SpanRingBufferStats{Capacity: b.bufferLen}
+	for {
+		version := atomic.LoadUint64(&b.statsVersion)
+		if version%2 == 1 {
+			runtime.Gosched()
+			continue
+		}
+		stats.Unprocessed = int(atomic.LoadInt64(&b.statsUnprocessed))
+		stats.TotalWritten = atomic.LoadInt64(&b.statsTotalWritten)
+		stats.TotalOverwritten = atomic.LoadInt64(&b.statsTotalOverwritten)
+		if atomic.LoadUint64(&b.statsVersion) == version {
+			break
+		}
+	}
+	if b.bufferLen > 0 {
+		stats.Utilization = float64(stats.Unprocessed) / float64(b.bufferLen)
+	}
+	return stats
+}
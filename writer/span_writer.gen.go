@@ -6,9 +6,22 @@
 package writer
 
 import (
+	"container/list"
 	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"strconv"
+	"sync"
 	"sync/atomic"
+	"time"
 
+	"github.com/adampetrovic/signalfx-go/writer/internal/bloom"
+	"github.com/adampetrovic/signalfx-go/writer/internal/breaker"
+	"github.com/adampetrovic/signalfx-go/writer/internal/hll"
+	"github.com/adampetrovic/signalfx-go/writer/internal/psquare"
 	"github.com/signalfx/golib/v3/datapoint"
 	"github.com/signalfx/golib/v3/sfxclient"
 	"github.com/signalfx/golib/v3/trace"
@@ -25,10 +38,35 @@ type SpanPreprocessor func(*trace.Span) bool
 // the spans have been sent, or an error has occurred.
 type SpanSender func(context.Context, []*trace.Span) error
 
+// SpanCodec serializes batches of spans.
+type SpanCodec interface {
+	Encode(spans []*trace.Span) ([]byte, error)
+	Decode(data []byte) ([]*trace.Span, error)
+}
+
+// SpanJSONCodec encodes a batch of spans as a JSON array.
+type SpanJSONCodec struct{}
+
+// Encode a batch of spans
+func (SpanJSONCodec) Encode(spans []*trace.Span) ([]byte, error) {
+	return json.Marshal(spans)
+}
+
+// Decode a batch of spans
+func (SpanJSONCodec) Decode(data []byte) ([]*trace.Span, error) {
+	var out []*trace.Span
+	err := json.Unmarshal(data, &out)
+	return out, err
+}
+
 const (
 	DefaultSpanMaxBuffered  = 10000
 	DefaultSpanMaxRequests  = 10
 	DefaultSpanMaxBatchSize = 1000
+
+	DefaultSpanMaxDeduplicationCacheSize = 10000
+
+	DefaultSpanCircuitBreakerWindow = 30 * time.Second
 )
 
 // SpanWriter is an abstraction that accepts a bunch of spans, buffers
@@ -55,10 +93,27 @@ type SpanWriter struct {
 	// might get reused.
 	SendFunc SpanSender
 
+	// Codec and Output can be set instead of SendFunc, in which case each
+	// batch is encoded with Codec and written to Output, prefixed with its
+	// length as a 4 byte big-endian integer, so that the batches can be read
+	// back with ReadFrame.  Writes to Output are serialized, so it doesn't
+	// need to be safe for concurrent use.
+	Codec  SpanCodec
+	Output io.Writer
+
 	// OverwriteFunc can be set to a function that will be called
 	// whenever an Add call to the underlying ring buffer results in the
-	// overwriting of an unprocessed span.
-	OverwriteFunc func()
+	// overwriting of an unprocessed span, with the span that was
+	// lost.  Use WithLegacyOverwriteFunc to set a function that doesn't take
+	// the span.
+	OverwriteFunc func(overwritten *trace.Span)
+
+	// OnSendSuccess and OnSendFailure can be set to functions that are
+	// called after each call to SendFunc, from the goroutine that made it,
+	// with the batch that was sent and the error if it failed.  The batch
+	// must not be used after they return, as its backing array is reused.
+	OnSendSuccess func(batch []*trace.Span)
+	OnSendFailure func(batch []*trace.Span, err error)
 
 	// The maximum number of Spans that this writer will hold before
 	// overwriting.  You must set this before calling Start.
@@ -69,7 +124,86 @@ type SpanWriter struct {
 	// The biggest batch of Spans the writer will emit to sendFunc at once.
 	// You must set this before calling Start.
 	MaxBatchSize int
-
+	// StalledSendTimeout is how long a call to SendFunc can take before the
+	// writer gives up on it, so that a SendFunc that hangs can't take up one
+	// of the MaxRequests slots forever.  The stalled call's context is
+	// canceled, so a SendFunc that watches it can return early, and its
+	// batch is counted as failed with ErrSendStalled.  The call is abandoned
+	// rather than waited for, so the batch may still get sent if it
+	// eventually returns.  At most MaxRequests calls are abandoned at once;
+	// beyond that the writer keeps the slot and waits for the stalled call
+	// to return, so that a SendFunc that ignores its context can't leak
+	// goroutines without bound.  0, the default, means no timeout.  You must
+	// set this before calling Start.
+	StalledSendTimeout time.Duration
+	// How many recently seen keys are remembered when deduplication is
+	// enabled with WithDeduplication.  You must set this before calling
+	// Start.
+	MaxDeduplicationCacheSize int
+
+	// MaxUniqueSpans limits how many distinct spans, as identified
+	// by CardinalityKeyFunc, the writer accepts over its lifetime.  Once the
+	// limit is reached, spans with keys that haven't been seen are
+	// dropped and passed to CardinalityRejectFunc with
+	// ErrCardinalityLimitExceeded.  Distinct keys are counted with a
+	// HyperLogLog sketch, so the limit is accurate to about 1%, and the keys
+	// accepted are remembered in a Bloom filter sized for the limit with a
+	// false-positive rate of 1%, so about 1% of new keys still get through
+	// after the limit.  The filter takes about 1.2 bytes per key of the
+	// limit.  0, the default, or a nil CardinalityKeyFunc means no limit.
+	// You must set this before calling Start.
+	MaxUniqueSpans int
+	// CardinalityKeyFunc returns the key that identifies each span for
+	// MaxUniqueSpans, e.g. a name and set of dimensions.
+	CardinalityKeyFunc func(*trace.Span) string
+	// CardinalityRejectFunc, if set, is called with each span rejected
+	// by MaxUniqueSpans.
+	CardinalityRejectFunc func(*trace.Span, error)
+
+	// CircuitBreakerThreshold is the fraction of batches, between 0 and 1,
+	// that must fail within CircuitBreakerWindow for the writer to stop
+	// calling SendFunc.  While the circuit is open, spans stay in the
+	// buffer, overwriting the oldest if it fills up.  After each
+	// CircuitBreakerWindow the writer sends a single span as a probe,
+	// and goes back to sending normally once a probe succeeds.  At least
+	// breaker.MinRequests (5) batches must have completed within the window
+	// for the circuit to open.  When the writer shuts down, whatever is
+	// left in the buffer is sent regardless of the circuit, so that it is
+	// either sent or counted as failed rather than dropped.  0, the
+	// default, disables the circuit breaker.  You must set this before
+	// calling Start.
+	CircuitBreakerThreshold float64
+	// CircuitBreakerWindow is how far back failed batches are counted, and
+	// how long to wait between probes while the circuit is open.  It
+	// defaults to DefaultSpanCircuitBreakerWindow.  You must set this
+	// before calling Start.
+	CircuitBreakerWindow time.Duration
+
+	cardinality     *hll.Sketch
+	cardinalitySeen *bloom.Filter
+	breaker         *breaker.Breaker
+	// Fires when an open circuit breaker is ready to let a probe through.
+	// nil when no probe is pending.
+	probeTimer <-chan time.Time
+	// Set while the writer flushes its buffer on shutdown, when the circuit
+	// breaker is ignored.
+	draining bool
+
+	dedupKeyFn  func(*trace.Span) string
+	dedupKeys   *spanKeySet
+	transformFn func(*trace.Span) []*trace.Span
+
+	shardFn   func(*trace.Span) int
+	numShards int
+	shards    []*SpanWriter
+
+	// Estimators of the 50th, 95th and 99th percentile send durations
+	sendDurationLock sync.Mutex
+	sendDurations    [3]*psquare.Estimator
+
+	// SendFunc, or encodeAndWrite if there's a Codec
+	send          SpanSender
+	outputLock    sync.Mutex
 	shutdownFlag  chan struct{}
 	buff          *SpanRingBuffer
 	requestDoneCh chan int64
@@ -80,6 +214,9 @@ type SpanWriter struct {
 	chunkSliceCache chan []*trace.Span
 
 	requestsActive int64
+	// Calls to SendFunc that were abandoned by StalledSendTimeout and
+	// haven't returned yet
+	abandonedSends int64
 	// Spans waiting to be sent but are blocked due to MaxRequests limit
 	totalWaiting int64
 
@@ -91,6 +228,180 @@ type SpanWriter struct {
 	TotalSent         int64
 	TotalFailedToSend int64
 	TotalOverwritten  int64
+	TotalDeduplicated int64
+	TotalTransformed  int64
+
+	TotalCardinalityRejected int64
+	TotalStalledSends        int64
+
+	// How long calls to SendFunc have taken since Start, in milliseconds.
+	// The percentiles are estimated with the P² algorithm as each batch
+	// completes.
+	P50SendDurationMs int64
+	P95SendDurationMs int64
+	P99SendDurationMs int64
+	MaxSendDurationMs int64
+}
+
+// WithTransformer sets a function that maps each span that passes
+// PreprocessFunc to zero or more spans to send in its place.  Returning
+// nil drops the span, and the drop is counted as filtered out.  Because
+// of fan-out, more spans may be sent than were received.  It must be
+// called before Start.
+func (w *SpanWriter) WithTransformer(fn func(*trace.Span) []*trace.Span) *SpanWriter {
+	w.transformFn = fn
+	return w
+}
+
+// WithDeduplication makes the writer drop spans whose key, as returned by
+// keyFn, matches that of a recently received span.  The writer remembers
+// the last MaxDeduplicationCacheSize keys.  It must be called before Start.
+func (w *SpanWriter) WithDeduplication(keyFn func(*trace.Span) string) *SpanWriter {
+	w.dedupKeyFn = keyFn
+	return w
+}
+
+// WithShardingFunc splits the writer into numShards independent writers, and
+// routes each span from InputChan to the one numbered shardFn(span) %
+// numShards.  Each shard has its own buffer and makes its own calls to
+// SendFunc, which can get the shard number with ShardFromContext, e.g. to
+// send to a different endpoint for each.  Shards have all of the writer's
+// other settings, so limits such as MaxBuffered, MaxRequests and
+// MaxUniqueSpans apply to each shard separately.  InternalMetrics reports
+// the metrics of each shard, prefixed with "shard_<n>_".  It must be called
+// before Start.
+func (w *SpanWriter) WithShardingFunc(shardFn func(*trace.Span) int, numShards int) *SpanWriter {
+	if numShards < 1 {
+		panic("writer must have at least one shard")
+	}
+	w.shardFn = shardFn
+	w.numShards = numShards
+	return w
+}
+
+// WithLegacyOverwriteFunc sets OverwriteFunc to call fn, which doesn't take
+// the overwritten span, as OverwriteFunc used to.
+func (w *SpanWriter) WithLegacyOverwriteFunc(fn func()) *SpanWriter {
+	w.OverwriteFunc = func(*trace.Span) { fn() }
+	return w
+}
+
+// newShard makes a writer with the same settings as w, with its own input
+// channel.
+func (w *SpanWriter) newShard() *SpanWriter {
+	shard := &SpanWriter{
+		InputChan:                 make(chan []*trace.Span, cap(w.InputChan)),
+		PreprocessFunc:            w.PreprocessFunc,
+		SendFunc:                  w.SendFunc,
+		OverwriteFunc:             w.OverwriteFunc,
+		OnSendSuccess:             w.OnSendSuccess,
+		OnSendFailure:             w.OnSendFailure,
+		MaxBuffered:               w.MaxBuffered,
+		MaxRequests:               w.MaxRequests,
+		StalledSendTimeout:        w.StalledSendTimeout,
+		MaxBatchSize:              w.MaxBatchSize,
+		MaxDeduplicationCacheSize: w.MaxDeduplicationCacheSize,
+		MaxUniqueSpans:            w.MaxUniqueSpans,
+		CardinalityKeyFunc:        w.CardinalityKeyFunc,
+		CardinalityRejectFunc:     w.CardinalityRejectFunc,
+		CircuitBreakerThreshold:   w.CircuitBreakerThreshold,
+		CircuitBreakerWindow:      w.CircuitBreakerWindow,
+		dedupKeyFn:                w.dedupKeyFn,
+		transformFn:               w.transformFn,
+	}
+	if w.Codec != nil {
+		// Share w's lock so shards don't write to Output at the same time
+		shard.SendFunc = w.encodeAndWrite
+	}
+	return shard
+}
+
+// runShards starts a writer for each shard and routes input to them until
+// ctx is done.  The shards are stopped once everything left in InputChan has
+// been routed, so none of it is lost.
+func (w *SpanWriter) runShards(ctx context.Context) {
+	w.shards = make([]*SpanWriter, w.numShards)
+	cancels := make([]context.CancelFunc, w.numShards)
+	for i := range w.shards {
+		var shardCtx context.Context
+		shardCtx, cancels[i] = newShardContext(ctx, i)
+		w.shards[i] = w.newShard()
+		w.shards[i].Start(shardCtx)
+	}
+
+	go func() {
+		defer close(w.shutdownFlag)
+
+		for ctx.Err() == nil {
+			select {
+			case insts := <-w.InputChan:
+				w.routeToShards(insts)
+			case <-ctx.Done():
+			}
+		}
+
+	drain:
+		for {
+			select {
+			case insts := <-w.InputChan:
+				w.routeToShards(insts)
+			default:
+				break drain
+			}
+		}
+
+		for i, shard := range w.shards {
+			cancels[i]()
+			shard.WaitForShutdown()
+		}
+	}()
+}
+
+func (w *SpanWriter) routeToShards(insts []*trace.Span) {
+	parts := make([][]*trace.Span, len(w.shards))
+	for _, inst := range insts {
+		i := w.shardFn(inst) % len(w.shards)
+		if i < 0 {
+			i += len(w.shards)
+		}
+		parts[i] = append(parts[i], inst)
+	}
+	for i := range parts {
+		if len(parts[i]) > 0 {
+			w.shards[i].InputChan <- parts[i]
+		}
+	}
+}
+
+// spanKeySet is an LRU set of span keys.
+type spanKeySet struct {
+	max   int
+	order *list.List
+	keys  map[string]*list.Element
+}
+
+func newSpanKeySet(max int) *spanKeySet {
+	return &spanKeySet{
+		max:   max,
+		order: list.New(),
+		keys:  make(map[string]*list.Element, max),
+	}
+}
+
+// Seen reports whether key is already in the set, adding it if not.
+func (s *spanKeySet) Seen(key string) bool {
+	if el, ok := s.keys[key]; ok {
+		s.order.MoveToFront(el)
+		return true
+	}
+
+	s.keys[key] = s.order.PushFront(key)
+	if s.order.Len() > s.max {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.keys, oldest.Value.(string))
+	}
+	return false
 }
 
 // WaitForShutdown will block until all of the elements inserted to the writer
@@ -131,7 +442,24 @@ func (w *SpanWriter) tryToSendChunk(ctx context.Context) {
 		return
 	}
 
-	chunk := w.buff.NextBatch(w.MaxBatchSize)
+	batchSize := w.MaxBatchSize
+	probe := false
+	if w.breaker != nil && !w.draining && totalUnprocessed > 0 {
+		var allowed bool
+		allowed, probe = w.breaker.Allow(time.Now())
+		if !allowed {
+			w.totalWaiting = int64(totalUnprocessed)
+			if w.probeTimer == nil && w.breaker.State() == breaker.Open {
+				w.probeTimer = time.After(w.breaker.RetryIn(time.Now()))
+			}
+			return
+		}
+		if probe {
+			batchSize = 1
+		}
+	}
+
+	chunk := w.buff.NextBatch(batchSize)
 
 	count := int64(len(chunk))
 	if count == 0 {
@@ -153,22 +481,127 @@ func (w *SpanWriter) tryToSendChunk(ctx context.Context) {
 	}
 
 	go func() {
-		err := w.SendFunc(ctx, chunkCopy)
+		start := time.Now()
+		err := w.callSendFunc(ctx, chunkCopy)
+		w.recordSendDuration(time.Since(start))
+		if w.breaker != nil {
+			w.breaker.Record(time.Now(), err == nil, probe)
+		}
 		if err != nil {
 			// Use atomic so that internal metrics method doesn't have to
 			// run in the same goroutine.
 			atomic.AddInt64(&w.TotalFailedToSend, count)
+			if w.OnSendFailure != nil {
+				w.OnSendFailure(chunkCopy, err)
+			}
 		} else {
 			atomic.AddInt64(&w.TotalSent, count)
+			if w.OnSendSuccess != nil {
+				w.OnSendSuccess(chunkCopy)
+			}
 		}
 
-		w.chunkSliceCache <- chunkCopy
+		if err == ErrSendStalled {
+			// The abandoned call may still be using the batch, so give
+			// the cache a new slice instead.
+			w.chunkSliceCache <- make([]*trace.Span, 0, w.MaxBatchSize)
+		} else {
+			w.chunkSliceCache <- chunkCopy
+		}
 		w.requestDoneCh <- count
 	}()
 
 	w.totalWaiting = int64(w.buff.UnprocessedCount())
 }
 
+// callSendFunc calls SendFunc, canceling its context if it takes longer than
+// StalledSendTimeout.  The call is then abandoned and ErrSendStalled
+// returned, unless MaxRequests calls are already abandoned, in which case it
+// waits for the call to return.
+func (w *SpanWriter) callSendFunc(ctx context.Context, insts []*trace.Span) error {
+	if w.StalledSendTimeout <= 0 {
+		return w.send(ctx, insts)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var lock sync.Mutex
+	var returned, abandoned bool
+	done := make(chan error, 1)
+	go func() {
+		done <- w.send(ctx, insts)
+		lock.Lock()
+		returned = true
+		if abandoned {
+			atomic.AddInt64(&w.abandonedSends, -1)
+		}
+		lock.Unlock()
+	}()
+
+	timer := time.NewTimer(w.StalledSendTimeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+	}
+
+	atomic.AddInt64(&w.TotalStalledSends, 1)
+	cancel()
+
+	lock.Lock()
+	if returned {
+		// It returned just as it stalled
+		lock.Unlock()
+		return <-done
+	}
+	if !w.reserveAbandonedSend() {
+		lock.Unlock()
+		log.Printf("Waiting for stalled send of %d instances after %v, since %d sends are already abandoned", len(insts), w.StalledSendTimeout, w.MaxRequests)
+		return <-done
+	}
+	abandoned = true
+	lock.Unlock()
+
+	log.Printf("Abandoning stalled send of %d instances after %v", len(insts), w.StalledSendTimeout)
+	return ErrSendStalled
+}
+
+// reserveAbandonedSend counts another abandoned call to SendFunc and returns
+// true, unless MaxRequests calls are already abandoned.
+func (w *SpanWriter) reserveAbandonedSend() bool {
+	for {
+		n := atomic.LoadInt64(&w.abandonedSends)
+		if n >= int64(w.MaxRequests) {
+			return false
+		}
+		if atomic.CompareAndSwapInt64(&w.abandonedSends, n, n+1) {
+			return true
+		}
+	}
+}
+
+// recordSendDuration updates the send duration gauges with how long a batch
+// took to send.
+func (w *SpanWriter) recordSendDuration(d time.Duration) {
+	ms := float64(d) / float64(time.Millisecond)
+
+	w.sendDurationLock.Lock()
+	defer w.sendDurationLock.Unlock()
+
+	for _, e := range w.sendDurations {
+		e.Add(ms)
+	}
+	atomic.StoreInt64(&w.P50SendDurationMs, int64(w.sendDurations[0].Quantile()))
+	atomic.StoreInt64(&w.P95SendDurationMs, int64(w.sendDurations[1].Quantile()))
+	atomic.StoreInt64(&w.P99SendDurationMs, int64(w.sendDurations[2].Quantile()))
+	if int64(ms) > atomic.LoadInt64(&w.MaxSendDurationMs) {
+		atomic.StoreInt64(&w.MaxSendDurationMs, int64(ms))
+	}
+}
+
 func (w *SpanWriter) processInput(ctx context.Context, insts []*trace.Span) {
 	atomic.AddInt64(&w.TotalReceived, int64(len(insts)))
 	for i := range insts {
@@ -177,35 +610,137 @@ func (w *SpanWriter) processInput(ctx context.Context, insts []*trace.Span) {
 			continue
 		}
 
-		if w.buff.Add(insts[i]) {
-			atomic.AddInt64(&w.TotalOverwritten, 1)
-			if w.OverwriteFunc != nil {
-				w.OverwriteFunc()
-			}
+		if w.transformFn == nil {
+			w.bufferSpan(ctx, insts[i])
+			continue
 		}
 
-		// Handle request done cleanup and try to send chunks if the buffer
-		// gets full so that we can avoid overflowing the buffer on big input
-		// slices where len(insts) > w.MaxBuffered.
-		select {
-		case count := <-w.requestDoneCh:
-			w.handleRequestDone(ctx, count)
-		default:
-			// If there isn't any request done then continue on
+		atomic.AddInt64(&w.TotalTransformed, 1)
+		transformed := w.transformFn(insts[i])
+		if len(transformed) == 0 {
+			atomic.AddInt64(&w.TotalFilteredOut, 1)
+		}
+		for j := range transformed {
+			w.bufferSpan(ctx, transformed[j])
 		}
+	}
 
-		if w.buff.UnprocessedCount() >= w.MaxBatchSize {
-			w.tryToSendChunk(ctx)
+}
+
+// bufferSpan adds a single span that has passed preprocessing to the
+// buffer, sending a chunk if enough have accumulated.
+func (w *SpanWriter) bufferSpan(ctx context.Context, inst *trace.Span) {
+	if w.dedupKeys != nil && w.dedupKeys.Seen(w.dedupKeyFn(inst)) {
+		atomic.AddInt64(&w.TotalDeduplicated, 1)
+		return
+	}
+
+	if w.cardinality != nil && !w.withinCardinalityLimit(w.CardinalityKeyFunc(inst)) {
+		atomic.AddInt64(&w.TotalCardinalityRejected, 1)
+		if w.CardinalityRejectFunc != nil {
+			w.CardinalityRejectFunc(inst, ErrCardinalityLimitExceeded)
+		}
+		return
+	}
+
+	if isOverwrite, overwritten := w.buff.Push(inst); isOverwrite {
+		atomic.AddInt64(&w.TotalOverwritten, 1)
+		if w.OverwriteFunc != nil {
+			w.OverwriteFunc(overwritten)
 		}
 	}
 
+	// Handle request done cleanup and try to send chunks if the buffer
+	// gets full so that we can avoid overflowing the buffer on big input
+	// slices where len(insts) > w.MaxBuffered.
+	select {
+	case count := <-w.requestDoneCh:
+		w.handleRequestDone(ctx, count)
+	default:
+		// If there isn't any request done then continue on
+	}
+
+	if w.buff.UnprocessedCount() >= w.MaxBatchSize {
+		w.tryToSendChunk(ctx)
+	}
 }
 
-// Start the writer processing loop
+// withinCardinalityLimit records key and returns whether an span with it
+// can be accepted.  After the limit, only keys that were accepted before the
+// limit, going by the Bloom filter, are accepted.
+func (w *SpanWriter) withinCardinalityLimit(key string) bool {
+	if w.cardinalitySeen.Contains(key) {
+		return true
+	}
+	if w.cardinality.Estimate() >= uint64(w.MaxUniqueSpans) {
+		return false
+	}
+	w.cardinality.Add(key)
+	w.cardinalitySeen.Add(key)
+	return true
+}
+
+// encodeAndWrite is the SendFunc used when a Codec is set.  The length
+// prefix and batch go in a single Write, so a batch is never split.
+func (w *SpanWriter) encodeAndWrite(ctx context.Context, insts []*trace.Span) error {
+	data, err := w.Codec.Encode(insts)
+	if err != nil {
+		return err
+	}
+	frame := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(frame, uint32(len(data)))
+	copy(frame[4:], data)
+
+	w.outputLock.Lock()
+	defer w.outputLock.Unlock()
+	_, err = w.Output.Write(frame)
+	return err
+}
+
+// Validate returns an error if the writer's settings conflict, e.g. if both
+// SendFunc and Codec are set.  Start panics with the error.
+func (w *SpanWriter) Validate() error {
+	if w.Codec != nil {
+		if w.SendFunc != nil {
+			return errors.New("writer can't have both a SendFunc and a Codec")
+		}
+		if w.Output == nil {
+			return errors.New("writer must have an Output to go with its Codec")
+		}
+	}
+	return nil
+}
+
+// Start the writer processing loop.  It panics if Validate returns an error.
 func (w *SpanWriter) Start(ctx context.Context) {
+	if err := w.Validate(); err != nil {
+		panic(err)
+	}
+
 	// Initialize the shutdownFlag in the same goroutine as the one calling
 	// start to avoid data races when calling WaitForShutdown.
 	w.shutdownFlag = make(chan struct{})
+
+	w.sendDurations = [3]*psquare.Estimator{psquare.New(0.5), psquare.New(0.95), psquare.New(0.99)}
+	atomic.StoreInt64(&w.P50SendDurationMs, 0)
+	atomic.StoreInt64(&w.P95SendDurationMs, 0)
+	atomic.StoreInt64(&w.P99SendDurationMs, 0)
+	atomic.StoreInt64(&w.MaxSendDurationMs, 0)
+
+	if w.numShards > 0 {
+		w.runShards(ctx)
+		return
+	}
+
+	w.breaker = nil
+	w.probeTimer = nil
+	if w.CircuitBreakerThreshold > 0 {
+		if w.CircuitBreakerWindow == 0 {
+			w.CircuitBreakerWindow = DefaultSpanCircuitBreakerWindow
+		}
+		w.breaker = breaker.New(w.CircuitBreakerThreshold, w.CircuitBreakerWindow)
+	}
+
 	go func() {
 		w.run(ctx)
 		close(w.shutdownFlag)
@@ -235,6 +770,20 @@ func (w *SpanWriter) run(ctx context.Context) {
 	if w.MaxBatchSize == 0 {
 		w.MaxBatchSize = DefaultSpanMaxBatchSize
 	}
+	w.send = w.SendFunc
+	if w.Codec != nil {
+		w.send = w.encodeAndWrite
+	}
+	if w.MaxUniqueSpans > 0 && w.CardinalityKeyFunc != nil {
+		w.cardinality = hll.New(hll.DefaultPrecision)
+		w.cardinalitySeen = bloom.New(w.MaxUniqueSpans, 0.01)
+	}
+	if w.dedupKeyFn != nil {
+		if w.MaxDeduplicationCacheSize == 0 {
+			w.MaxDeduplicationCacheSize = DefaultSpanMaxDeduplicationCacheSize
+		}
+		w.dedupKeys = newSpanKeySet(w.MaxDeduplicationCacheSize)
+	}
 
 	w.buff = NewSpanRingBuffer(w.MaxBuffered)
 
@@ -253,7 +802,9 @@ func (w *SpanWriter) run(ctx context.Context) {
 		}
 	}
 
+	w.draining = false
 	drainInput := func() {
+		w.draining = true
 		defer waitForRequests()
 		defer w.tryToSendChunk(ctx)
 		for {
@@ -283,6 +834,10 @@ func (w *SpanWriter) run(ctx context.Context) {
 		case count := <-w.requestDoneCh:
 			w.handleRequestDone(ctx, count)
 
+		case <-w.probeTimer:
+			w.probeTimer = nil
+			w.tryToSendChunk(ctx)
+
 		default:
 			// The input chan is exhaused, try to send whatever was there.
 			w.tryToSendChunk(ctx)
@@ -297,6 +852,10 @@ func (w *SpanWriter) run(ctx context.Context) {
 			case count := <-w.requestDoneCh:
 				w.handleRequestDone(ctx, count)
 
+			case <-w.probeTimer:
+				w.probeTimer = nil
+				w.tryToSendChunk(ctx)
+
 			case insts := <-w.InputChan:
 				w.processInput(ctx, insts)
 			}
@@ -304,18 +863,68 @@ func (w *SpanWriter) run(ctx context.Context) {
 	}
 }
 
+// CircuitState returns the state of the writer's circuit breaker, which is
+// always CircuitClosed if CircuitBreakerThreshold isn't set.
+func (w *SpanWriter) CircuitState() CircuitState {
+	if w.breaker == nil {
+		return CircuitClosed
+	}
+	return CircuitState(w.breaker.State())
+}
+
+// InputChanLen returns how many slices of spans are queued in InputChan
+// waiting for the writer to pick them up.
+func (w *SpanWriter) InputChanLen() int {
+	return len(w.InputChan)
+}
+
+// InputChanCap returns the capacity of InputChan.
+func (w *SpanWriter) InputChanCap() int {
+	return cap(w.InputChan)
+}
+
+// InputChannelUtilization returns the fraction of InputChan's capacity that
+// is in use.  A value that stays near 1 means spans are being produced
+// faster than the writer can take them.  It is always 0 for an unbuffered
+// channel.
+func (w *SpanWriter) InputChannelUtilization() float64 {
+	if w.InputChanCap() == 0 {
+		return 0
+	}
+	return float64(w.InputChanLen()) / float64(w.InputChanCap())
+}
+
 // InternalMetrics about the span writer
 func (w *SpanWriter) InternalMetrics(prefix string) []*datapoint.Datapoint {
+	if w.shards != nil {
+		var dps []*datapoint.Datapoint
+		for i, shard := range w.shards {
+			dps = append(dps, shard.InternalMetrics(prefix+"shard_"+strconv.Itoa(i)+"_")...)
+		}
+		return dps
+	}
+
 	return []*datapoint.Datapoint{
 		sfxclient.CumulativeP(prefix+"trace_spans_sent", nil, &w.TotalSent),
 		sfxclient.CumulativeP(prefix+"trace_spans_failed", nil, &w.TotalFailedToSend),
 		sfxclient.CumulativeP(prefix+"trace_spans_filtered", nil, &w.TotalFilteredOut),
 		sfxclient.CumulativeP(prefix+"trace_spans_received", nil, &w.TotalReceived),
 		sfxclient.CumulativeP(prefix+"trace_spans_overwritten", nil, &w.TotalOverwritten),
+		sfxclient.CumulativeP(prefix+"trace_spans_deduplicated", nil, &w.TotalDeduplicated),
+		sfxclient.CumulativeP(prefix+"trace_spans_transformed", nil, &w.TotalTransformed),
+		sfxclient.CumulativeP(prefix+"trace_spans_cardinality_rejected", nil, &w.TotalCardinalityRejected),
+		sfxclient.CumulativeP(prefix+"trace_span_sends_stalled", nil, &w.TotalStalledSends),
 		sfxclient.Gauge(prefix+"trace_spans_buffered", nil, int64(w.buff.UnprocessedCount())),
 		sfxclient.Gauge(prefix+"trace_spans_max_buffered", nil, int64(w.buff.Size())),
 		sfxclient.Gauge(prefix+"trace_spans_in_flight", nil, atomic.LoadInt64(&w.TotalInFlight)),
 		sfxclient.Gauge(prefix+"trace_spans_waiting", nil, atomic.LoadInt64(&w.totalWaiting)),
+		sfxclient.Gauge(prefix+"trace_spans_input_chan_len", nil, int64(w.InputChanLen())),
+		sfxclient.Gauge(prefix+"trace_spans_input_chan_cap", nil, int64(w.InputChanCap())),
 		sfxclient.Gauge(prefix+"trace_span_requests_active", nil, atomic.LoadInt64(&w.requestsActive)),
+		sfxclient.Gauge(prefix+"trace_span_circuit_state", nil, int64(w.CircuitState())),
+		sfxclient.Gauge(prefix+"trace_span_send_duration_p50_ms", nil, atomic.LoadInt64(&w.P50SendDurationMs)),
+		sfxclient.Gauge(prefix+"trace_span_send_duration_p95_ms", nil, atomic.LoadInt64(&w.P95SendDurationMs)),
+		sfxclient.Gauge(prefix+"trace_span_send_duration_p99_ms", nil, atomic.LoadInt64(&w.P99SendDurationMs)),
+		sfxclient.Gauge(prefix+"trace_span_send_duration_max_ms", nil, atomic.LoadInt64(&w.MaxSendDurationMs)),
 	}
 }
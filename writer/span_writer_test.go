@@ -1,9 +1,11 @@
 package writer
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"math"
 	"sort"
@@ -12,6 +14,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/adampetrovic/signalfx-go/writer/internal/breaker"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/signalfx/golib/v3/trace"
 	"github.com/stretchr/testify/require"
@@ -115,6 +118,7 @@ func TestSpanWriter(t *testing.T) {
 	})
 
 	for _, inBatchSize := range []int{1, 2, 3, 5, 8, 13} {
+		inBatchSize := inBatchSize
 		t.Run(fmt.Sprintf("Should cycle buffer without losing anything (inBatchSize: %d)", inBatchSize), func(t *testing.T) {
 			t.Parallel()
 			ts := setupSpanTesting(0)
@@ -154,6 +158,7 @@ func TestSpanWriter(t *testing.T) {
 
 	for _, maxBuff := range []int{100, 2500, 4999, 9997, 9999} {
 		for _, inputSize := range []int{1, 11} {
+			inputSize := inputSize
 			t.Run(fmt.Sprintf("Should overflow cleanly with %d max, inputSize: %d", maxBuff, inputSize), func(t *testing.T) {
 				t.Parallel()
 				ts := setupSpanTesting(1000)
@@ -179,12 +184,14 @@ func TestSpanWriter(t *testing.T) {
 
 				go func() {
 					// Wait to let input get processed a bit before letting things
-					// through so that the buffer gets backed up
+					// through so that the buffer gets backed up.  Every request
+					// slot is filled with a full batch, so wait for all of them
+					// to arrive so none are counted after the fact.
 					for {
 						ts.ReceiveLock.Lock()
 						initialReceivedCount = len(ts.Received)
 						ts.ReceiveLock.Unlock()
-						if initialReceivedCount > 0 {
+						if initialReceivedCount >= ts.Writer.MaxRequests*ts.Writer.MaxBatchSize {
 							time.Sleep(1 * time.Second)
 							break
 						}
@@ -284,10 +291,42 @@ func TestSpanWriter(t *testing.T) {
 		ts.Writer.MaxRequests = 1
 		ts.Writer.MaxBuffered = 100
 
+		var overwrittenLock sync.Mutex
+		var overwritten []int
+		ts.Writer.OverwriteFunc = func(dp *trace.Span) {
+			overwrittenLock.Lock()
+			defer overwrittenLock.Unlock()
+			overwritten = append(overwritten, dp.Meta["i"].(int))
+		}
+		// Prevent things from being sent
+		ts.SendLock.Lock()
+
+		ts.Writer.Start(ts.Ctx)
+
+		for i := 0; i < ts.Writer.MaxBuffered+11; i++ {
+			ts.Input <- []*trace.Span{{Meta: map[interface{}]interface{}{"i": i}}}
+		}
+
+		require.Eventually(t, func() bool {
+			overwrittenLock.Lock()
+			defer overwrittenLock.Unlock()
+			return len(overwritten) == 10
+		}, 3*time.Second, 50*time.Millisecond)
+		// The first is in flight, so the next oldest are lost
+		require.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, overwritten)
+	})
+
+	t.Run("Should call WithLegacyOverwriteFunc on overwrites", func(t *testing.T) {
+		t.Parallel()
+		ts := setupSpanTesting(1000)
+		ts.Writer.MaxBatchSize = 1
+		ts.Writer.MaxRequests = 1
+		ts.Writer.MaxBuffered = 100
+
 		overwrittenCount := int64(0)
-		ts.Writer.OverwriteFunc = func() {
+		ts.Writer.WithLegacyOverwriteFunc(func() {
 			atomic.AddInt64(&overwrittenCount, 1)
-		}
+		})
 		// Prevent things from being sent
 		ts.SendLock.Lock()
 
@@ -300,6 +339,31 @@ func TestSpanWriter(t *testing.T) {
 		require.Eventually(t, func() bool { return atomic.LoadInt64(&overwrittenCount) == 10 }, 3*time.Second, 50*time.Millisecond)
 	})
 
+	t.Run("Should call send callbacks", func(t *testing.T) {
+		t.Parallel()
+		ts := setupSpanTesting(1000)
+		ts.Writer.MaxBatchSize = 1
+
+		sent := int64(0)
+		failed := int64(0)
+		ts.Writer.OnSendSuccess = func(batch []*trace.Span) {
+			atomic.AddInt64(&sent, int64(len(batch)))
+		}
+		ts.Writer.OnSendFailure = func(batch []*trace.Span, err error) {
+			require.Error(t, err)
+			atomic.AddInt64(&failed, int64(len(batch)))
+		}
+		ts.Writer.Start(ts.Ctx)
+
+		ts.Input <- []*trace.Span{{}, {}}
+		require.Eventually(t, func() bool { return atomic.LoadInt64(&sent) == 2 }, 3*time.Second, 50*time.Millisecond)
+
+		ts.SendShouldFail.Store(true)
+		ts.Input <- []*trace.Span{{}}
+		require.Eventually(t, func() bool { return atomic.LoadInt64(&failed) == 1 }, 3*time.Second, 50*time.Millisecond)
+		require.Equal(t, int64(2), atomic.LoadInt64(&sent))
+	})
+
 	t.Run("Can handle big inputs", func(t *testing.T) {
 		t.Parallel()
 		ts := setupSpanTesting(1000)
@@ -308,7 +372,7 @@ func TestSpanWriter(t *testing.T) {
 		ts.Writer.MaxBuffered = 100
 
 		overwrittenCount := int64(0)
-		ts.Writer.OverwriteFunc = func() {
+		ts.Writer.OverwriteFunc = func(*trace.Span) {
 			atomic.AddInt64(&overwrittenCount, 1)
 		}
 		ts.Writer.Start(ts.Ctx)
@@ -356,6 +420,399 @@ func ExampleSpanWriter() {
 	writer.WaitForShutdown()
 }
 
+func TestSpanWriterCodec(t *testing.T) {
+	out := &bytes.Buffer{}
+	in := make(chan []*trace.Span)
+	writer := &SpanWriter{
+		InputChan:    in,
+		Codec:        SpanJSONCodec{},
+		Output:       out,
+		MaxBatchSize: 10,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	writer.Start(ctx)
+
+	for i := 0; i < 100; i++ {
+		in <- []*trace.Span{{}}
+	}
+	cancel()
+	writer.WaitForShutdown()
+
+	decoded := 0
+	for {
+		data, err := ReadFrame(out)
+		if err == io.EOF {
+			break
+		}
+		require.Nil(t, err)
+		batch, err := SpanJSONCodec{}.Decode(data)
+		require.Nil(t, err)
+		decoded += len(batch)
+	}
+	require.Equal(t, 100, decoded)
+	require.Equal(t, findInternalMetricWithName(writer, "trace_spans_sent"), 100)
+}
+
+func TestSpanWriterCodecValidation(t *testing.T) {
+	writer := &SpanWriter{
+		InputChan: make(chan []*trace.Span),
+		Codec:     SpanJSONCodec{},
+	}
+	require.Error(t, writer.Validate(), "Should need an Output with a Codec")
+	require.Panics(t, func() { writer.Start(context.Background()) })
+
+	writer.Output = &bytes.Buffer{}
+	writer.SendFunc = func(context.Context, []*trace.Span) error { return nil }
+	require.Error(t, writer.Validate(), "Should not have both a SendFunc and a Codec")
+
+	writer.SendFunc = nil
+	require.Nil(t, writer.Validate())
+}
+
+func TestSpanWriterDeduplication(t *testing.T) {
+	ts := setupSpanTesting(0)
+	ts.Writer.MaxDeduplicationCacheSize = 2
+	ts.Writer.WithDeduplication(func(inst *trace.Span) string {
+		return fmt.Sprint(inst.Meta["i"])
+	})
+	ts.Writer.Start(ts.Ctx)
+
+	for _, i := range []int{0, 1, 0, 2, 1, 2} {
+		ts.Input <- []*trace.Span{{Meta: map[interface{}]interface{}{"i": i}}}
+	}
+	ts.Cancel()
+	ts.Writer.WaitForShutdown()
+
+	// Seeing 0 again keeps it in the cache, so 1 is evicted when 2 arrives
+	// and gets through a second time
+	require.Len(t, ts.Received, 4)
+	require.Equal(t, findInternalMetricWithName(ts.Writer, "trace_spans_deduplicated"), 2)
+}
+
+func TestSpanWriterTransformer(t *testing.T) {
+	ts := setupSpanTesting(0)
+	ts.Writer.WithTransformer(func(inst *trace.Span) []*trace.Span {
+		i := inst.Meta["i"].(int)
+		if i%2 == 1 {
+			return nil
+		}
+		return []*trace.Span{
+			{Meta: map[interface{}]interface{}{"i": i}},
+			{Meta: map[interface{}]interface{}{"i": i + 1}},
+		}
+	})
+	ts.Writer.Start(ts.Ctx)
+
+	for i := 0; i < 10; i++ {
+		ts.Input <- []*trace.Span{{Meta: map[interface{}]interface{}{"i": i}}}
+	}
+	ts.Cancel()
+	ts.Writer.WaitForShutdown()
+
+	ts.assertAllReceived(t, 10)
+	require.Equal(t, findInternalMetricWithName(ts.Writer, "trace_spans_transformed"), 10)
+	require.Equal(t, findInternalMetricWithName(ts.Writer, "trace_spans_filtered"), 5)
+}
+
+func TestSpanWriterCardinalityLimit(t *testing.T) {
+	ts := setupSpanTesting(0)
+	var rejected []error
+	ts.Writer.MaxUniqueSpans = 100
+	ts.Writer.CardinalityKeyFunc = func(inst *trace.Span) string {
+		return fmt.Sprint(inst.Meta["i"].(int) % 1000)
+	}
+	ts.Writer.CardinalityRejectFunc = func(inst *trace.Span, err error) {
+		rejected = append(rejected, err)
+	}
+	ts.Writer.Start(ts.Ctx)
+
+	for i := 0; i < 2000; i++ {
+		ts.Input <- []*trace.Span{{Meta: map[interface{}]interface{}{"i": i}}}
+	}
+	ts.Cancel()
+	ts.Writer.WaitForShutdown()
+
+	sent := findInternalMetricWithName(ts.Writer, "trace_spans_sent")
+	rejectedCount := findInternalMetricWithName(ts.Writer, "trace_spans_cardinality_rejected")
+	require.Equal(t, 2000, sent+rejectedCount)
+	require.Len(t, rejected, rejectedCount)
+	require.Equal(t, ErrCardinalityLimitExceeded, rejected[0])
+	// The first 100 or so keys get through twice, and a few later ones may
+	// slip past the sketch
+	require.InDelta(t, 200, sent, 30)
+}
+
+func TestSpanWriterCardinalityLimitAtScale(t *testing.T) {
+	const limit = 100000
+	ts := setupSpanTesting(0)
+	ts.Writer.MaxUniqueSpans = limit
+	ts.Writer.MaxBuffered = 3 * limit
+	ts.Writer.CardinalityKeyFunc = func(inst *trace.Span) string {
+		return fmt.Sprint("key-", inst.Meta["i"].(int))
+	}
+	ts.Writer.Start(ts.Ctx)
+
+	// Twice as many distinct keys as the limit, then the first half again
+	send := func(from, to int) {
+		for i := from; i < to; i += 1000 {
+			insts := make([]*trace.Span, 0, 1000)
+			for j := i; j < i+1000; j++ {
+				insts = append(insts, &trace.Span{Meta: map[interface{}]interface{}{"i": j}})
+			}
+			ts.Input <- insts
+		}
+	}
+	send(0, 2*limit)
+	send(0, limit)
+	ts.Cancel()
+	ts.Writer.WaitForShutdown()
+
+	sent := findInternalMetricWithName(ts.Writer, "trace_spans_sent")
+	require.Equal(t, 3*limit, sent+findInternalMetricWithName(ts.Writer, "trace_spans_cardinality_rejected"))
+
+	ts.ReceiveLock.Lock()
+	defer ts.ReceiveLock.Unlock()
+	var accepted, repeats, pastLimit int
+	seen := map[int]bool{}
+	for _, dp := range ts.Received {
+		i := dp.Meta["i"].(int)
+		if seen[i] {
+			repeats++
+			continue
+		}
+		seen[i] = true
+		accepted++
+	}
+	for i := range seen {
+		if i >= limit+limit/50 {
+			pastLimit++
+		}
+	}
+	require.InDelta(t, limit, accepted, 0.03*limit, "The limit should hold to within the sketch's error")
+	require.True(t, pastLimit < limit/50, "%d keys got past the limit", pastLimit)
+	require.True(t, repeats > limit*97/100, "Keys accepted before the limit should still be accepted, got %d", repeats)
+}
+
+func TestSpanWriterSendDuration(t *testing.T) {
+	ts := setupSpanTesting(0)
+	sender := ts.Writer.SendFunc
+	ts.Writer.SendFunc = func(ctx context.Context, insts []*trace.Span) error {
+		time.Sleep(20 * time.Millisecond)
+		return sender(ctx, insts)
+	}
+	ts.Writer.Start(ts.Ctx)
+
+	for i := 0; i < 10; i++ {
+		ts.Input <- []*trace.Span{{Meta: map[interface{}]interface{}{"i": i}}}
+	}
+	ts.Cancel()
+	ts.Writer.WaitForShutdown()
+
+	p50 := findInternalMetricWithName(ts.Writer, "trace_span_send_duration_p50_ms")
+	p99 := findInternalMetricWithName(ts.Writer, "trace_span_send_duration_p99_ms")
+	slowest := findInternalMetricWithName(ts.Writer, "trace_span_send_duration_max_ms")
+	require.True(t, p50 >= 20, "p50 of %dms should include the sleep", p50)
+	require.True(t, p50 <= p99 && p99 <= slowest, "percentiles should be ordered: %d, %d, %d", p50, p99, slowest)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts.Writer.Start(ctx)
+	require.Equal(t, int64(0), atomic.LoadInt64(&ts.Writer.MaxSendDurationMs), "Start should reset the durations")
+}
+
+func TestSpanWriterInputChan(t *testing.T) {
+	ts := setupSpanTesting(10)
+	require.Equal(t, 0.0, ts.Writer.InputChannelUtilization())
+
+	for i := 0; i < 3; i++ {
+		ts.Input <- []*trace.Span{{Meta: map[interface{}]interface{}{"i": i}}}
+	}
+	require.Equal(t, 3, ts.Writer.InputChanLen())
+	require.Equal(t, 10, ts.Writer.InputChanCap())
+	require.InDelta(t, 0.3, ts.Writer.InputChannelUtilization(), 0.001)
+
+	ts.Writer.Start(ts.Ctx)
+	ts.Cancel()
+	ts.Writer.WaitForShutdown()
+
+	require.Equal(t, 0, findInternalMetricWithName(ts.Writer, "trace_spans_input_chan_len"))
+	require.Equal(t, 10, findInternalMetricWithName(ts.Writer, "trace_spans_input_chan_cap"))
+	ts.assertAllReceived(t, 3)
+
+	unbuffered := &SpanWriter{InputChan: make(chan []*trace.Span)}
+	require.Equal(t, 0.0, unbuffered.InputChannelUtilization())
+}
+
+func TestSpanWriterCircuitBreaker(t *testing.T) {
+	ts := setupSpanTesting(0)
+	var calls int64
+	sender := ts.Writer.SendFunc
+	ts.Writer.SendFunc = func(ctx context.Context, insts []*trace.Span) error {
+		atomic.AddInt64(&calls, 1)
+		return sender(ctx, insts)
+	}
+	ts.Writer.MaxRequests = 1
+	ts.Writer.MaxBatchSize = 1
+	ts.Writer.CircuitBreakerThreshold = 0.5
+	ts.Writer.CircuitBreakerWindow = 100 * time.Millisecond
+	ts.SendShouldFail.Store(true)
+	ts.Writer.Start(ts.Ctx)
+	require.Equal(t, CircuitClosed, ts.Writer.CircuitState())
+
+	for i := 0; i < 20; i++ {
+		ts.Input <- []*trace.Span{{Meta: map[interface{}]interface{}{"i": i}}}
+	}
+	require.Eventually(t, func() bool { return ts.Writer.CircuitState() != CircuitClosed }, time.Second, 50*time.Millisecond)
+	require.True(t, atomic.LoadInt64(&calls) < 20, "Open circuit should stop sends")
+
+	// Let the next probe succeed, which should send everything buffered
+	ts.SendShouldFail.Store(false)
+	require.Eventually(t, func() bool { return ts.Writer.CircuitState() == CircuitClosed }, time.Second, 50*time.Millisecond)
+	ts.Cancel()
+	ts.Writer.WaitForShutdown()
+
+	failed := findInternalMetricWithName(ts.Writer, "trace_spans_failed")
+	require.True(t, failed >= breaker.MinRequests)
+	require.Equal(t, 20, failed+findInternalMetricWithName(ts.Writer, "trace_spans_sent"))
+	require.Equal(t, int(CircuitClosed), findInternalMetricWithName(ts.Writer, "trace_span_circuit_state"))
+}
+
+func TestSpanWriterCircuitBreakerShutdown(t *testing.T) {
+	ts := setupSpanTesting(0)
+	ts.Writer.MaxRequests = 1
+	ts.Writer.MaxBatchSize = 1
+	ts.Writer.CircuitBreakerThreshold = 0.5
+	// Long enough that no probe is sent before shutdown
+	ts.Writer.CircuitBreakerWindow = time.Hour
+	ts.SendShouldFail.Store(true)
+	ts.Writer.Start(ts.Ctx)
+
+	for i := 0; i < 20; i++ {
+		ts.Input <- []*trace.Span{{Meta: map[interface{}]interface{}{"i": i}}}
+	}
+	require.Eventually(t, func() bool { return ts.Writer.CircuitState() == CircuitOpen }, time.Second, 50*time.Millisecond)
+
+	ts.SendShouldFail.Store(false)
+	ts.Cancel()
+	ts.Writer.WaitForShutdown()
+
+	failed := findInternalMetricWithName(ts.Writer, "trace_spans_failed")
+	sent := findInternalMetricWithName(ts.Writer, "trace_spans_sent")
+	require.True(t, sent > 0, "Buffered traces should be sent on shutdown despite the open circuit")
+	require.Equal(t, 20, failed+sent)
+	require.Len(t, ts.Received, sent)
+}
+
+func TestSpanWriterStalledSend(t *testing.T) {
+	ts := setupSpanTesting(0)
+	var calls int64
+	var failedErr atomic.Value
+	sender := ts.Writer.SendFunc
+	ts.Writer.SendFunc = func(ctx context.Context, insts []*trace.Span) error {
+		if atomic.AddInt64(&calls, 1) == 1 {
+			// Hang until the writer gives up on the send
+			<-ctx.Done()
+			time.Sleep(50 * time.Millisecond)
+			return nil
+		}
+		return sender(ctx, insts)
+	}
+	ts.Writer.OnSendFailure = func(batch []*trace.Span, err error) {
+		failedErr.Store(err)
+	}
+	ts.Writer.MaxRequests = 1
+	ts.Writer.MaxBatchSize = 1
+	ts.Writer.StalledSendTimeout = 20 * time.Millisecond
+	ts.Writer.Start(ts.Ctx)
+
+	for i := 0; i < 5; i++ {
+		ts.Input <- []*trace.Span{{Meta: map[interface{}]interface{}{"i": i}}}
+	}
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt64(&ts.Writer.TotalSent) == 4
+	}, time.Second, 50*time.Millisecond, "Other sends should go through once the stalled one is abandoned")
+	ts.Cancel()
+	ts.Writer.WaitForShutdown()
+
+	require.Equal(t, ErrSendStalled, failedErr.Load())
+	require.Equal(t, 1, findInternalMetricWithName(ts.Writer, "trace_span_sends_stalled"))
+	require.Equal(t, 1, findInternalMetricWithName(ts.Writer, "trace_spans_failed"))
+}
+
+func TestSpanWriterStalledSendLimit(t *testing.T) {
+	ts := setupSpanTesting(0)
+	var calls int64
+	hang := make(chan struct{})
+	sender := ts.Writer.SendFunc
+	ts.Writer.SendFunc = func(ctx context.Context, insts []*trace.Span) error {
+		if atomic.AddInt64(&calls, 1) <= 2 {
+			// Hang, ignoring the context, until the test lets it go
+			<-hang
+		}
+		return sender(ctx, insts)
+	}
+	ts.Writer.MaxRequests = 1
+	ts.Writer.MaxBatchSize = 1
+	ts.Writer.StalledSendTimeout = 20 * time.Millisecond
+	ts.Writer.Start(ts.Ctx)
+
+	for i := 0; i < 3; i++ {
+		ts.Input <- []*trace.Span{{Meta: map[interface{}]interface{}{"i": i}}}
+	}
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt64(&ts.Writer.TotalStalledSends) == 2
+	}, time.Second, 50*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, int64(2), atomic.LoadInt64(&calls), "The second stalled send should be waited for rather than abandoned")
+
+	close(hang)
+	ts.Cancel()
+	ts.Writer.WaitForShutdown()
+
+	require.Equal(t, 2, findInternalMetricWithName(ts.Writer, "trace_span_sends_stalled"))
+	require.Equal(t, 1, findInternalMetricWithName(ts.Writer, "trace_spans_failed"))
+	require.Equal(t, 2, findInternalMetricWithName(ts.Writer, "trace_spans_sent"))
+}
+
+func TestSpanWriterSharding(t *testing.T) {
+	ts := setupSpanTesting(10)
+	var lock sync.Mutex
+	shardOf := map[int]int{}
+	sender := ts.Writer.SendFunc
+	ts.Writer.SendFunc = func(ctx context.Context, insts []*trace.Span) error {
+		shard, ok := ShardFromContext(ctx)
+		require.True(t, ok, "Shard should be in the context")
+		lock.Lock()
+		for _, inst := range insts {
+			shardOf[inst.Meta["i"].(int)] = shard
+		}
+		lock.Unlock()
+		return sender(ctx, insts)
+	}
+	ts.Writer.WithShardingFunc(func(inst *trace.Span) int {
+		return inst.Meta["i"].(int)
+	}, 3)
+	ts.Writer.Start(ts.Ctx)
+
+	for i := 0; i < 100; i += 10 {
+		var insts []*trace.Span
+		for j := i; j < i+10; j++ {
+			insts = append(insts, &trace.Span{Meta: map[interface{}]interface{}{"i": j}})
+		}
+		ts.Input <- insts
+	}
+	ts.Cancel()
+	ts.Writer.WaitForShutdown()
+
+	ts.assertAllReceived(t, 100)
+	for i, shard := range shardOf {
+		require.Equal(t, i%3, shard, "instance %d went to the wrong shard", i)
+	}
+	require.Equal(t, 34, findInternalMetricWithName(ts.Writer, "shard_0_trace_spans_sent"))
+	require.Equal(t, 33, findInternalMetricWithName(ts.Writer, "shard_2_trace_spans_sent"))
+}
+
 func BenchmarkSpanWriter(b *testing.B) {
 	var doneSignal atomic.Value
 	received := int64(0)
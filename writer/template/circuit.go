@@ -0,0 +1,11 @@
+package template
+
+// CircuitState stands in for the writer package's type of the same name so
+// that the template compiles.  It isn't generated.
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
@@ -0,0 +1,11 @@
+package template
+
+import "errors"
+
+// ErrCardinalityLimitExceeded and ErrSendStalled stand in for the writer
+// package's errors of the same names so that the template compiles.  They
+// aren't generated.
+var (
+	ErrCardinalityLimitExceeded = errors.New("writer cardinality limit exceeded")
+	ErrSendStalled              = errors.New("writer send stalled")
+)
@@ -0,0 +1,9 @@
+package template
+
+import "context"
+
+// newShardContext stands in for the writer package's function of the same
+// name so that the template compiles.  It isn't generated.
+func newShardContext(parent context.Context, shard int) (context.Context, context.CancelFunc) {
+	return context.WithCancel(context.Background())
+}
@@ -3,9 +3,22 @@ package template
 // nolint: dupl
 
 import (
+	"container/list"
 	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"strconv"
+	"sync"
 	"sync/atomic"
+	"time"
 
+	"github.com/adampetrovic/signalfx-go/writer/internal/bloom"
+	"github.com/adampetrovic/signalfx-go/writer/internal/breaker"
+	"github.com/adampetrovic/signalfx-go/writer/internal/hll"
+	"github.com/adampetrovic/signalfx-go/writer/internal/psquare"
 	"github.com/signalfx/golib/v3/datapoint"
 	"github.com/signalfx/golib/v3/sfxclient"
 )
@@ -21,10 +34,35 @@ type InstancePreprocessor func(*Instance) bool
 // the instances have been sent, or an error has occurred.
 type InstanceSender func(context.Context, []*Instance) error
 
+// InstanceCodec serializes batches of instances.
+type InstanceCodec interface {
+	Encode(instances []*Instance) ([]byte, error)
+	Decode(data []byte) ([]*Instance, error)
+}
+
+// InstanceJSONCodec encodes a batch of instances as a JSON array.
+type InstanceJSONCodec struct{}
+
+// Encode a batch of instances
+func (InstanceJSONCodec) Encode(instances []*Instance) ([]byte, error) {
+	return json.Marshal(instances)
+}
+
+// Decode a batch of instances
+func (InstanceJSONCodec) Decode(data []byte) ([]*Instance, error) {
+	var out []*Instance
+	err := json.Unmarshal(data, &out)
+	return out, err
+}
+
 const (
 	DefaultInstanceMaxBuffered  = 10000
 	DefaultInstanceMaxRequests  = 10
 	DefaultInstanceMaxBatchSize = 1000
+
+	DefaultInstanceMaxDeduplicationCacheSize = 10000
+
+	DefaultInstanceCircuitBreakerWindow = 30 * time.Second
 )
 
 // InstanceWriter is an abstraction that accepts a bunch of instances, buffers
@@ -51,10 +89,27 @@ type InstanceWriter struct {
 	// might get reused.
 	SendFunc InstanceSender
 
+	// Codec and Output can be set instead of SendFunc, in which case each
+	// batch is encoded with Codec and written to Output, prefixed with its
+	// length as a 4 byte big-endian integer, so that the batches can be read
+	// back with ReadFrame.  Writes to Output are serialized, so it doesn't
+	// need to be safe for concurrent use.
+	Codec  InstanceCodec
+	Output io.Writer
+
 	// OverwriteFunc can be set to a function that will be called
 	// whenever an Add call to the underlying ring buffer results in the
-	// overwriting of an unprocessed instance.
-	OverwriteFunc func()
+	// overwriting of an unprocessed instance, with the instance that was
+	// lost.  Use WithLegacyOverwriteFunc to set a function that doesn't take
+	// the instance.
+	OverwriteFunc func(overwritten *Instance)
+
+	// OnSendSuccess and OnSendFailure can be set to functions that are
+	// called after each call to SendFunc, from the goroutine that made it,
+	// with the batch that was sent and the error if it failed.  The batch
+	// must not be used after they return, as its backing array is reused.
+	OnSendSuccess func(batch []*Instance)
+	OnSendFailure func(batch []*Instance, err error)
 
 	// The maximum number of Instances that this writer will hold before
 	// overwriting.  You must set this before calling Start.
@@ -65,7 +120,86 @@ type InstanceWriter struct {
 	// The biggest batch of Instances the writer will emit to sendFunc at once.
 	// You must set this before calling Start.
 	MaxBatchSize int
-
+	// StalledSendTimeout is how long a call to SendFunc can take before the
+	// writer gives up on it, so that a SendFunc that hangs can't take up one
+	// of the MaxRequests slots forever.  The stalled call's context is
+	// canceled, so a SendFunc that watches it can return early, and its
+	// batch is counted as failed with ErrSendStalled.  The call is abandoned
+	// rather than waited for, so the batch may still get sent if it
+	// eventually returns.  At most MaxRequests calls are abandoned at once;
+	// beyond that the writer keeps the slot and waits for the stalled call
+	// to return, so that a SendFunc that ignores its context can't leak
+	// goroutines without bound.  0, the default, means no timeout.  You must
+	// set this before calling Start.
+	StalledSendTimeout time.Duration
+	// How many recently seen keys are remembered when deduplication is
+	// enabled with WithDeduplication.  You must set this before calling
+	// Start.
+	MaxDeduplicationCacheSize int
+
+	// MaxUniqueInstances limits how many distinct instances, as identified
+	// by CardinalityKeyFunc, the writer accepts over its lifetime.  Once the
+	// limit is reached, instances with keys that haven't been seen are
+	// dropped and passed to CardinalityRejectFunc with
+	// ErrCardinalityLimitExceeded.  Distinct keys are counted with a
+	// HyperLogLog sketch, so the limit is accurate to about 1%, and the keys
+	// accepted are remembered in a Bloom filter sized for the limit with a
+	// false-positive rate of 1%, so about 1% of new keys still get through
+	// after the limit.  The filter takes about 1.2 bytes per key of the
+	// limit.  0, the default, or a nil CardinalityKeyFunc means no limit.
+	// You must set this before calling Start.
+	MaxUniqueInstances int
+	// CardinalityKeyFunc returns the key that identifies each instance for
+	// MaxUniqueInstances, e.g. a name and set of dimensions.
+	CardinalityKeyFunc func(*Instance) string
+	// CardinalityRejectFunc, if set, is called with each instance rejected
+	// by MaxUniqueInstances.
+	CardinalityRejectFunc func(*Instance, error)
+
+	// CircuitBreakerThreshold is the fraction of batches, between 0 and 1,
+	// that must fail within CircuitBreakerWindow for the writer to stop
+	// calling SendFunc.  While the circuit is open, instances stay in the
+	// buffer, overwriting the oldest if it fills up.  After each
+	// CircuitBreakerWindow the writer sends a single instance as a probe,
+	// and goes back to sending normally once a probe succeeds.  At least
+	// breaker.MinRequests (5) batches must have completed within the window
+	// for the circuit to open.  When the writer shuts down, whatever is
+	// left in the buffer is sent regardless of the circuit, so that it is
+	// either sent or counted as failed rather than dropped.  0, the
+	// default, disables the circuit breaker.  You must set this before
+	// calling Start.
+	CircuitBreakerThreshold float64
+	// CircuitBreakerWindow is how far back failed batches are counted, and
+	// how long to wait between probes while the circuit is open.  It
+	// defaults to DefaultInstanceCircuitBreakerWindow.  You must set this
+	// before calling Start.
+	CircuitBreakerWindow time.Duration
+
+	cardinality     *hll.Sketch
+	cardinalitySeen *bloom.Filter
+	breaker         *breaker.Breaker
+	// Fires when an open circuit breaker is ready to let a probe through.
+	// nil when no probe is pending.
+	probeTimer <-chan time.Time
+	// Set while the writer flushes its buffer on shutdown, when the circuit
+	// breaker is ignored.
+	draining bool
+
+	dedupKeyFn  func(*Instance) string
+	dedupKeys   *instanceKeySet
+	transformFn func(*Instance) []*Instance
+
+	shardFn   func(*Instance) int
+	numShards int
+	shards    []*InstanceWriter
+
+	// Estimators of the 50th, 95th and 99th percentile send durations
+	sendDurationLock sync.Mutex
+	sendDurations    [3]*psquare.Estimator
+
+	// SendFunc, or encodeAndWrite if there's a Codec
+	send          InstanceSender
+	outputLock    sync.Mutex
 	shutdownFlag  chan struct{}
 	buff          *InstanceRingBuffer
 	requestDoneCh chan int64
@@ -76,6 +210,9 @@ type InstanceWriter struct {
 	chunkSliceCache chan []*Instance
 
 	requestsActive int64
+	// Calls to SendFunc that were abandoned by StalledSendTimeout and
+	// haven't returned yet
+	abandonedSends int64
 	// Instances waiting to be sent but are blocked due to MaxRequests limit
 	totalWaiting int64
 
@@ -87,6 +224,180 @@ type InstanceWriter struct {
 	TotalSent         int64
 	TotalFailedToSend int64
 	TotalOverwritten  int64
+	TotalDeduplicated int64
+	TotalTransformed  int64
+
+	TotalCardinalityRejected int64
+	TotalStalledSends        int64
+
+	// How long calls to SendFunc have taken since Start, in milliseconds.
+	// The percentiles are estimated with the P² algorithm as each batch
+	// completes.
+	P50SendDurationMs int64
+	P95SendDurationMs int64
+	P99SendDurationMs int64
+	MaxSendDurationMs int64
+}
+
+// WithTransformer sets a function that maps each instance that passes
+// PreprocessFunc to zero or more instances to send in its place.  Returning
+// nil drops the instance, and the drop is counted as filtered out.  Because
+// of fan-out, more instances may be sent than were received.  It must be
+// called before Start.
+func (w *InstanceWriter) WithTransformer(fn func(*Instance) []*Instance) *InstanceWriter {
+	w.transformFn = fn
+	return w
+}
+
+// WithDeduplication makes the writer drop instances whose key, as returned by
+// keyFn, matches that of a recently received instance.  The writer remembers
+// the last MaxDeduplicationCacheSize keys.  It must be called before Start.
+func (w *InstanceWriter) WithDeduplication(keyFn func(*Instance) string) *InstanceWriter {
+	w.dedupKeyFn = keyFn
+	return w
+}
+
+// WithShardingFunc splits the writer into numShards independent writers, and
+// routes each instance from InputChan to the one numbered shardFn(instance) %
+// numShards.  Each shard has its own buffer and makes its own calls to
+// SendFunc, which can get the shard number with ShardFromContext, e.g. to
+// send to a different endpoint for each.  Shards have all of the writer's
+// other settings, so limits such as MaxBuffered, MaxRequests and
+// MaxUniqueInstances apply to each shard separately.  InternalMetrics reports
+// the metrics of each shard, prefixed with "shard_<n>_".  It must be called
+// before Start.
+func (w *InstanceWriter) WithShardingFunc(shardFn func(*Instance) int, numShards int) *InstanceWriter {
+	if numShards < 1 {
+		panic("writer must have at least one shard")
+	}
+	w.shardFn = shardFn
+	w.numShards = numShards
+	return w
+}
+
+// WithLegacyOverwriteFunc sets OverwriteFunc to call fn, which doesn't take
+// the overwritten instance, as OverwriteFunc used to.
+func (w *InstanceWriter) WithLegacyOverwriteFunc(fn func()) *InstanceWriter {
+	w.OverwriteFunc = func(*Instance) { fn() }
+	return w
+}
+
+// newShard makes a writer with the same settings as w, with its own input
+// channel.
+func (w *InstanceWriter) newShard() *InstanceWriter {
+	shard := &InstanceWriter{
+		InputChan:                 make(chan []*Instance, cap(w.InputChan)),
+		PreprocessFunc:            w.PreprocessFunc,
+		SendFunc:                  w.SendFunc,
+		OverwriteFunc:             w.OverwriteFunc,
+		OnSendSuccess:             w.OnSendSuccess,
+		OnSendFailure:             w.OnSendFailure,
+		MaxBuffered:               w.MaxBuffered,
+		MaxRequests:               w.MaxRequests,
+		StalledSendTimeout:        w.StalledSendTimeout,
+		MaxBatchSize:              w.MaxBatchSize,
+		MaxDeduplicationCacheSize: w.MaxDeduplicationCacheSize,
+		MaxUniqueInstances:        w.MaxUniqueInstances,
+		CardinalityKeyFunc:        w.CardinalityKeyFunc,
+		CardinalityRejectFunc:     w.CardinalityRejectFunc,
+		CircuitBreakerThreshold:   w.CircuitBreakerThreshold,
+		CircuitBreakerWindow:      w.CircuitBreakerWindow,
+		dedupKeyFn:                w.dedupKeyFn,
+		transformFn:               w.transformFn,
+	}
+	if w.Codec != nil {
+		// Share w's lock so shards don't write to Output at the same time
+		shard.SendFunc = w.encodeAndWrite
+	}
+	return shard
+}
+
+// runShards starts a writer for each shard and routes input to them until
+// ctx is done.  The shards are stopped once everything left in InputChan has
+// been routed, so none of it is lost.
+func (w *InstanceWriter) runShards(ctx context.Context) {
+	w.shards = make([]*InstanceWriter, w.numShards)
+	cancels := make([]context.CancelFunc, w.numShards)
+	for i := range w.shards {
+		var shardCtx context.Context
+		shardCtx, cancels[i] = newShardContext(ctx, i)
+		w.shards[i] = w.newShard()
+		w.shards[i].Start(shardCtx)
+	}
+
+	go func() {
+		defer close(w.shutdownFlag)
+
+		for ctx.Err() == nil {
+			select {
+			case insts := <-w.InputChan:
+				w.routeToShards(insts)
+			case <-ctx.Done():
+			}
+		}
+
+	drain:
+		for {
+			select {
+			case insts := <-w.InputChan:
+				w.routeToShards(insts)
+			default:
+				break drain
+			}
+		}
+
+		for i, shard := range w.shards {
+			cancels[i]()
+			shard.WaitForShutdown()
+		}
+	}()
+}
+
+func (w *InstanceWriter) routeToShards(insts []*Instance) {
+	parts := make([][]*Instance, len(w.shards))
+	for _, inst := range insts {
+		i := w.shardFn(inst) % len(w.shards)
+		if i < 0 {
+			i += len(w.shards)
+		}
+		parts[i] = append(parts[i], inst)
+	}
+	for i := range parts {
+		if len(parts[i]) > 0 {
+			w.shards[i].InputChan <- parts[i]
+		}
+	}
+}
+
+// instanceKeySet is an LRU set of instance keys.
+type instanceKeySet struct {
+	max   int
+	order *list.List
+	keys  map[string]*list.Element
+}
+
+func newInstanceKeySet(max int) *instanceKeySet {
+	return &instanceKeySet{
+		max:   max,
+		order: list.New(),
+		keys:  make(map[string]*list.Element, max),
+	}
+}
+
+// Seen reports whether key is already in the set, adding it if not.
+func (s *instanceKeySet) Seen(key string) bool {
+	if el, ok := s.keys[key]; ok {
+		s.order.MoveToFront(el)
+		return true
+	}
+
+	s.keys[key] = s.order.PushFront(key)
+	if s.order.Len() > s.max {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.keys, oldest.Value.(string))
+	}
+	return false
 }
 
 // WaitForShutdown will block until all of the elements inserted to the writer
@@ -127,7 +438,24 @@ func (w *InstanceWriter) tryToSendChunk(ctx context.Context) {
 		return
 	}
 
-	chunk := w.buff.NextBatch(w.MaxBatchSize)
+	batchSize := w.MaxBatchSize
+	probe := false
+	if w.breaker != nil && !w.draining && totalUnprocessed > 0 {
+		var allowed bool
+		allowed, probe = w.breaker.Allow(time.Now())
+		if !allowed {
+			w.totalWaiting = int64(totalUnprocessed)
+			if w.probeTimer == nil && w.breaker.State() == breaker.Open {
+				w.probeTimer = time.After(w.breaker.RetryIn(time.Now()))
+			}
+			return
+		}
+		if probe {
+			batchSize = 1
+		}
+	}
+
+	chunk := w.buff.NextBatch(batchSize)
 
 	count := int64(len(chunk))
 	if count == 0 {
@@ -149,22 +477,127 @@ func (w *InstanceWriter) tryToSendChunk(ctx context.Context) {
 	}
 
 	go func() {
-		err := w.SendFunc(ctx, chunkCopy)
+		start := time.Now()
+		err := w.callSendFunc(ctx, chunkCopy)
+		w.recordSendDuration(time.Since(start))
+		if w.breaker != nil {
+			w.breaker.Record(time.Now(), err == nil, probe)
+		}
 		if err != nil {
 			// Use atomic so that internal metrics method doesn't have to
 			// run in the same goroutine.
 			atomic.AddInt64(&w.TotalFailedToSend, count)
+			if w.OnSendFailure != nil {
+				w.OnSendFailure(chunkCopy, err)
+			}
 		} else {
 			atomic.AddInt64(&w.TotalSent, count)
+			if w.OnSendSuccess != nil {
+				w.OnSendSuccess(chunkCopy)
+			}
 		}
 
-		w.chunkSliceCache <- chunkCopy
+		if err == ErrSendStalled {
+			// The abandoned call may still be using the batch, so give
+			// the cache a new slice instead.
+			w.chunkSliceCache <- make([]*Instance, 0, w.MaxBatchSize)
+		} else {
+			w.chunkSliceCache <- chunkCopy
+		}
 		w.requestDoneCh <- count
 	}()
 
 	w.totalWaiting = int64(w.buff.UnprocessedCount())
 }
 
+// callSendFunc calls SendFunc, canceling its context if it takes longer than
+// StalledSendTimeout.  The call is then abandoned and ErrSendStalled
+// returned, unless MaxRequests calls are already abandoned, in which case it
+// waits for the call to return.
+func (w *InstanceWriter) callSendFunc(ctx context.Context, insts []*Instance) error {
+	if w.StalledSendTimeout <= 0 {
+		return w.send(ctx, insts)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var lock sync.Mutex
+	var returned, abandoned bool
+	done := make(chan error, 1)
+	go func() {
+		done <- w.send(ctx, insts)
+		lock.Lock()
+		returned = true
+		if abandoned {
+			atomic.AddInt64(&w.abandonedSends, -1)
+		}
+		lock.Unlock()
+	}()
+
+	timer := time.NewTimer(w.StalledSendTimeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+	}
+
+	atomic.AddInt64(&w.TotalStalledSends, 1)
+	cancel()
+
+	lock.Lock()
+	if returned {
+		// It returned just as it stalled
+		lock.Unlock()
+		return <-done
+	}
+	if !w.reserveAbandonedSend() {
+		lock.Unlock()
+		log.Printf("Waiting for stalled send of %d instances after %v, since %d sends are already abandoned", len(insts), w.StalledSendTimeout, w.MaxRequests)
+		return <-done
+	}
+	abandoned = true
+	lock.Unlock()
+
+	log.Printf("Abandoning stalled send of %d instances after %v", len(insts), w.StalledSendTimeout)
+	return ErrSendStalled
+}
+
+// reserveAbandonedSend counts another abandoned call to SendFunc and returns
+// true, unless MaxRequests calls are already abandoned.
+func (w *InstanceWriter) reserveAbandonedSend() bool {
+	for {
+		n := atomic.LoadInt64(&w.abandonedSends)
+		if n >= int64(w.MaxRequests) {
+			return false
+		}
+		if atomic.CompareAndSwapInt64(&w.abandonedSends, n, n+1) {
+			return true
+		}
+	}
+}
+
+// recordSendDuration updates the send duration gauges with how long a batch
+// took to send.
+func (w *InstanceWriter) recordSendDuration(d time.Duration) {
+	ms := float64(d) / float64(time.Millisecond)
+
+	w.sendDurationLock.Lock()
+	defer w.sendDurationLock.Unlock()
+
+	for _, e := range w.sendDurations {
+		e.Add(ms)
+	}
+	atomic.StoreInt64(&w.P50SendDurationMs, int64(w.sendDurations[0].Quantile()))
+	atomic.StoreInt64(&w.P95SendDurationMs, int64(w.sendDurations[1].Quantile()))
+	atomic.StoreInt64(&w.P99SendDurationMs, int64(w.sendDurations[2].Quantile()))
+	if int64(ms) > atomic.LoadInt64(&w.MaxSendDurationMs) {
+		atomic.StoreInt64(&w.MaxSendDurationMs, int64(ms))
+	}
+}
+
 func (w *InstanceWriter) processInput(ctx context.Context, insts []*Instance) {
 	atomic.AddInt64(&w.TotalReceived, int64(len(insts)))
 	for i := range insts {
@@ -173,35 +606,137 @@ func (w *InstanceWriter) processInput(ctx context.Context, insts []*Instance) {
 			continue
 		}
 
-		if w.buff.Add(insts[i]) {
-			atomic.AddInt64(&w.TotalOverwritten, 1)
-			if w.OverwriteFunc != nil {
-				w.OverwriteFunc()
-			}
+		if w.transformFn == nil {
+			w.bufferInstance(ctx, insts[i])
+			continue
 		}
 
-		// Handle request done cleanup and try to send chunks if the buffer
-		// gets full so that we can avoid overflowing the buffer on big input
-		// slices where len(insts) > w.MaxBuffered.
-		select {
-		case count := <-w.requestDoneCh:
-			w.handleRequestDone(ctx, count)
-		default:
-			// If there isn't any request done then continue on
+		atomic.AddInt64(&w.TotalTransformed, 1)
+		transformed := w.transformFn(insts[i])
+		if len(transformed) == 0 {
+			atomic.AddInt64(&w.TotalFilteredOut, 1)
+		}
+		for j := range transformed {
+			w.bufferInstance(ctx, transformed[j])
 		}
+	}
 
-		if w.buff.UnprocessedCount() >= w.MaxBatchSize {
-			w.tryToSendChunk(ctx)
+}
+
+// bufferInstance adds a single instance that has passed preprocessing to the
+// buffer, sending a chunk if enough have accumulated.
+func (w *InstanceWriter) bufferInstance(ctx context.Context, inst *Instance) {
+	if w.dedupKeys != nil && w.dedupKeys.Seen(w.dedupKeyFn(inst)) {
+		atomic.AddInt64(&w.TotalDeduplicated, 1)
+		return
+	}
+
+	if w.cardinality != nil && !w.withinCardinalityLimit(w.CardinalityKeyFunc(inst)) {
+		atomic.AddInt64(&w.TotalCardinalityRejected, 1)
+		if w.CardinalityRejectFunc != nil {
+			w.CardinalityRejectFunc(inst, ErrCardinalityLimitExceeded)
+		}
+		return
+	}
+
+	if isOverwrite, overwritten := w.buff.Push(inst); isOverwrite {
+		atomic.AddInt64(&w.TotalOverwritten, 1)
+		if w.OverwriteFunc != nil {
+			w.OverwriteFunc(overwritten)
 		}
 	}
 
+	// Handle request done cleanup and try to send chunks if the buffer
+	// gets full so that we can avoid overflowing the buffer on big input
+	// slices where len(insts) > w.MaxBuffered.
+	select {
+	case count := <-w.requestDoneCh:
+		w.handleRequestDone(ctx, count)
+	default:
+		// If there isn't any request done then continue on
+	}
+
+	if w.buff.UnprocessedCount() >= w.MaxBatchSize {
+		w.tryToSendChunk(ctx)
+	}
 }
 
-// Start the writer processing loop
+// withinCardinalityLimit records key and returns whether an instance with it
+// can be accepted.  After the limit, only keys that were accepted before the
+// limit, going by the Bloom filter, are accepted.
+func (w *InstanceWriter) withinCardinalityLimit(key string) bool {
+	if w.cardinalitySeen.Contains(key) {
+		return true
+	}
+	if w.cardinality.Estimate() >= uint64(w.MaxUniqueInstances) {
+		return false
+	}
+	w.cardinality.Add(key)
+	w.cardinalitySeen.Add(key)
+	return true
+}
+
+// encodeAndWrite is the SendFunc used when a Codec is set.  The length
+// prefix and batch go in a single Write, so a batch is never split.
+func (w *InstanceWriter) encodeAndWrite(ctx context.Context, insts []*Instance) error {
+	data, err := w.Codec.Encode(insts)
+	if err != nil {
+		return err
+	}
+	frame := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(frame, uint32(len(data)))
+	copy(frame[4:], data)
+
+	w.outputLock.Lock()
+	defer w.outputLock.Unlock()
+	_, err = w.Output.Write(frame)
+	return err
+}
+
+// Validate returns an error if the writer's settings conflict, e.g. if both
+// SendFunc and Codec are set.  Start panics with the error.
+func (w *InstanceWriter) Validate() error {
+	if w.Codec != nil {
+		if w.SendFunc != nil {
+			return errors.New("writer can't have both a SendFunc and a Codec")
+		}
+		if w.Output == nil {
+			return errors.New("writer must have an Output to go with its Codec")
+		}
+	}
+	return nil
+}
+
+// Start the writer processing loop.  It panics if Validate returns an error.
 func (w *InstanceWriter) Start(ctx context.Context) {
+	if err := w.Validate(); err != nil {
+		panic(err)
+	}
+
 	// Initialize the shutdownFlag in the same goroutine as the one calling
 	// start to avoid data races when calling WaitForShutdown.
 	w.shutdownFlag = make(chan struct{})
+
+	w.sendDurations = [3]*psquare.Estimator{psquare.New(0.5), psquare.New(0.95), psquare.New(0.99)}
+	atomic.StoreInt64(&w.P50SendDurationMs, 0)
+	atomic.StoreInt64(&w.P95SendDurationMs, 0)
+	atomic.StoreInt64(&w.P99SendDurationMs, 0)
+	atomic.StoreInt64(&w.MaxSendDurationMs, 0)
+
+	if w.numShards > 0 {
+		w.runShards(ctx)
+		return
+	}
+
+	w.breaker = nil
+	w.probeTimer = nil
+	if w.CircuitBreakerThreshold > 0 {
+		if w.CircuitBreakerWindow == 0 {
+			w.CircuitBreakerWindow = DefaultInstanceCircuitBreakerWindow
+		}
+		w.breaker = breaker.New(w.CircuitBreakerThreshold, w.CircuitBreakerWindow)
+	}
+
 	go func() {
 		w.run(ctx)
 		close(w.shutdownFlag)
@@ -231,6 +766,20 @@ func (w *InstanceWriter) run(ctx context.Context) {
 	if w.MaxBatchSize == 0 {
 		w.MaxBatchSize = DefaultInstanceMaxBatchSize
 	}
+	w.send = w.SendFunc
+	if w.Codec != nil {
+		w.send = w.encodeAndWrite
+	}
+	if w.MaxUniqueInstances > 0 && w.CardinalityKeyFunc != nil {
+		w.cardinality = hll.New(hll.DefaultPrecision)
+		w.cardinalitySeen = bloom.New(w.MaxUniqueInstances, 0.01)
+	}
+	if w.dedupKeyFn != nil {
+		if w.MaxDeduplicationCacheSize == 0 {
+			w.MaxDeduplicationCacheSize = DefaultInstanceMaxDeduplicationCacheSize
+		}
+		w.dedupKeys = newInstanceKeySet(w.MaxDeduplicationCacheSize)
+	}
 
 	w.buff = NewInstanceRingBuffer(w.MaxBuffered)
 
@@ -249,7 +798,9 @@ func (w *InstanceWriter) run(ctx context.Context) {
 		}
 	}
 
+	w.draining = false
 	drainInput := func() {
+		w.draining = true
 		defer waitForRequests()
 		defer w.tryToSendChunk(ctx)
 		for {
@@ -279,6 +830,10 @@ func (w *InstanceWriter) run(ctx context.Context) {
 		case count := <-w.requestDoneCh:
 			w.handleRequestDone(ctx, count)
 
+		case <-w.probeTimer:
+			w.probeTimer = nil
+			w.tryToSendChunk(ctx)
+
 		default:
 			// The input chan is exhaused, try to send whatever was there.
 			w.tryToSendChunk(ctx)
@@ -293,6 +848,10 @@ func (w *InstanceWriter) run(ctx context.Context) {
 			case count := <-w.requestDoneCh:
 				w.handleRequestDone(ctx, count)
 
+			case <-w.probeTimer:
+				w.probeTimer = nil
+				w.tryToSendChunk(ctx)
+
 			case insts := <-w.InputChan:
 				w.processInput(ctx, insts)
 			}
@@ -300,18 +859,68 @@ func (w *InstanceWriter) run(ctx context.Context) {
 	}
 }
 
+// CircuitState returns the state of the writer's circuit breaker, which is
+// always CircuitClosed if CircuitBreakerThreshold isn't set.
+func (w *InstanceWriter) CircuitState() CircuitState {
+	if w.breaker == nil {
+		return CircuitClosed
+	}
+	return CircuitState(w.breaker.State())
+}
+
+// InputChanLen returns how many slices of instances are queued in InputChan
+// waiting for the writer to pick them up.
+func (w *InstanceWriter) InputChanLen() int {
+	return len(w.InputChan)
+}
+
+// InputChanCap returns the capacity of InputChan.
+func (w *InstanceWriter) InputChanCap() int {
+	return cap(w.InputChan)
+}
+
+// InputChannelUtilization returns the fraction of InputChan's capacity that
+// is in use.  A value that stays near 1 means instances are being produced
+// faster than the writer can take them.  It is always 0 for an unbuffered
+// channel.
+func (w *InstanceWriter) InputChannelUtilization() float64 {
+	if w.InputChanCap() == 0 {
+		return 0
+	}
+	return float64(w.InputChanLen()) / float64(w.InputChanCap())
+}
+
 // InternalMetrics about the instance writer
 func (w *InstanceWriter) InternalMetrics(prefix string) []*datapoint.Datapoint {
+	if w.shards != nil {
+		var dps []*datapoint.Datapoint
+		for i, shard := range w.shards {
+			dps = append(dps, shard.InternalMetrics(prefix+"shard_"+strconv.Itoa(i)+"_")...)
+		}
+		return dps
+	}
+
 	return []*datapoint.Datapoint{
 		sfxclient.CumulativeP(prefix+"instances_sent", nil, &w.TotalSent),
 		sfxclient.CumulativeP(prefix+"instances_failed", nil, &w.TotalFailedToSend),
 		sfxclient.CumulativeP(prefix+"instances_filtered", nil, &w.TotalFilteredOut),
 		sfxclient.CumulativeP(prefix+"instances_received", nil, &w.TotalReceived),
 		sfxclient.CumulativeP(prefix+"instances_overwritten", nil, &w.TotalOverwritten),
+		sfxclient.CumulativeP(prefix+"instances_deduplicated", nil, &w.TotalDeduplicated),
+		sfxclient.CumulativeP(prefix+"instances_transformed", nil, &w.TotalTransformed),
+		sfxclient.CumulativeP(prefix+"instances_cardinality_rejected", nil, &w.TotalCardinalityRejected),
+		sfxclient.CumulativeP(prefix+"instance_sends_stalled", nil, &w.TotalStalledSends),
 		sfxclient.Gauge(prefix+"instances_buffered", nil, int64(w.buff.UnprocessedCount())),
 		sfxclient.Gauge(prefix+"instances_max_buffered", nil, int64(w.buff.Size())),
 		sfxclient.Gauge(prefix+"instances_in_flight", nil, atomic.LoadInt64(&w.TotalInFlight)),
 		sfxclient.Gauge(prefix+"instances_waiting", nil, atomic.LoadInt64(&w.totalWaiting)),
+		sfxclient.Gauge(prefix+"instances_input_chan_len", nil, int64(w.InputChanLen())),
+		sfxclient.Gauge(prefix+"instances_input_chan_cap", nil, int64(w.InputChanCap())),
 		sfxclient.Gauge(prefix+"instance_requests_active", nil, atomic.LoadInt64(&w.requestsActive)),
+		sfxclient.Gauge(prefix+"instance_circuit_state", nil, int64(w.CircuitState())),
+		sfxclient.Gauge(prefix+"instance_send_duration_p50_ms", nil, atomic.LoadInt64(&w.P50SendDurationMs)),
+		sfxclient.Gauge(prefix+"instance_send_duration_p95_ms", nil, atomic.LoadInt64(&w.P95SendDurationMs)),
+		sfxclient.Gauge(prefix+"instance_send_duration_p99_ms", nil, atomic.LoadInt64(&w.P99SendDurationMs)),
+		sfxclient.Gauge(prefix+"instance_send_duration_max_ms", nil, atomic.LoadInt64(&w.MaxSendDurationMs)),
 	}
 }